@@ -0,0 +1,68 @@
+// Command cardimport loads a Scryfall bulk data file from disk into the cards table. It connects
+// to -dsn, or APP_DB_DSN from the environment or .env, and never touches the network. None of the
+// server's other settings are read or checked.
+//
+//	go run ./cmd/cardimport -file oracle-cards.json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"MtgLeaderwebserver/internal/config"
+	"MtgLeaderwebserver/internal/service"
+	"MtgLeaderwebserver/internal/store/postgres"
+)
+
+func main() {
+	file := flag.String("file", "", "path to a Scryfall bulk data JSON file (Oracle Cards or Default Cards)")
+	dsn := flag.String("dsn", "", "Postgres connection string (default $APP_DB_DSN)")
+	flag.Parse()
+
+	if err := run(*file, *dsn); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "cardimport:", err)
+		os.Exit(1)
+	}
+}
+
+func run(path, dsn string) error {
+	if path == "" {
+		return errors.New("-file is required")
+	}
+	if dsn == "" {
+		dsn = config.DBDSN()
+	}
+	if dsn == "" {
+		return errors.New("-dsn or APP_DB_DSN is required")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := postgres.Open(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	started := time.Now()
+	cards := &service.CardService{Store: postgres.NewCardsStore(pool)}
+	n, err := cards.Import(ctx, f)
+	if err != nil {
+		return fmt.Errorf("imported %d cards before failing: %w", n, err)
+	}
+	fmt.Printf("imported %d cards in %s\n", n, time.Since(started).Round(time.Millisecond))
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"image/color"
 	"image/png"
 	"math"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -53,6 +54,17 @@ func main() {
 		emailSvc   *service.EmailService
 		profileSvc *service.ProfileService
 		notifySvc  *service.NotificationService
+		syncSvc    *service.SyncService
+		ratingSvc  *service.RatingService
+		seasonSvc  *service.SeasonService
+		groupSvc   *service.GroupService
+		claimSvc   *service.GuestClaimService
+		guestSvc   *service.GuestService
+		deckSvc    *service.DeckService
+		cardSvc    *service.CardService
+		formatSvc  *service.FormatService
+		eventSvc   *service.MatchEventService
+		liveSvc    *service.LiveMatchService
 		dbPing     func(context.Context) error
 	)
 
@@ -73,6 +85,15 @@ func main() {
 		adminSettings := postgres.NewAdminSettingsStore(pgPool)
 		passwordResets := postgres.NewPasswordResetStore(pgPool)
 		notificationTokens := postgres.NewNotificationTokensStore(pgPool)
+		syncStore := postgres.NewSyncStore(pgPool)
+		ratings := postgres.NewRatingsStore(pgPool)
+		seasons := postgres.NewSeasonsStore(pgPool)
+		groups := postgres.NewGroupsStore(pgPool)
+		guestClaims := postgres.NewGuestClaimsStore(pgPool)
+		guests := postgres.NewGuestsStore(pgPool)
+		decks := postgres.NewDecksStore(pgPool)
+		cards := postgres.NewCardsStore(pgPool)
+		matchEvents := postgres.NewMatchEventsStore(pgPool)
 
 		if err := bootstrapAdminUser(context.Background(), logger, users, cfg.AdminBootstrapEmail, cfg.AdminBootstrapUsername, cfg.AdminBootstrapPassword); err != nil {
 			logger.Error("bootstrap admin failed", "err", err)
@@ -90,9 +111,11 @@ func main() {
 			Users:       users,
 			Friendships: friendships,
 		}
+		formatSvc = &service.FormatService{Store: postgres.NewFormatsStore(pgPool)}
 		matchSvc = &service.MatchService{
 			Matches: matches,
 			Friends: friendsSvc,
+			Formats: formatSvc,
 		}
 		usersSvc = &service.UsersService{Store: userSearch}
 		adminSvc = &service.AdminService{Users: adminUsers}
@@ -102,6 +125,25 @@ func main() {
 		}
 		emailSvc = &service.EmailService{Settings: adminSettings}
 		profileSvc = &service.ProfileService{Store: users}
+		syncSvc = &service.SyncService{Store: syncStore}
+		ratingSvc = &service.RatingService{Store: ratings, Formats: formatSvc, Logger: logger}
+		groupSvc = &service.GroupService{Store: groups, Users: users, Formats: formatSvc}
+		matchSvc.Groups = groupSvc
+		seasonSvc = &service.SeasonService{Store: seasons, Groups: groupSvc, Formats: formatSvc}
+		claimSvc = &service.GuestClaimService{Store: guestClaims, Friends: friendsSvc, Guests: guests}
+		guestSvc = &service.GuestService{Store: guests}
+		matchSvc.Guests = guests
+		deckSvc = &service.DeckService{Store: decks, Cards: cards, Formats: formatSvc}
+		matchSvc.Decks = decks
+		cardSvc = &service.CardService{Store: cards, Formats: formatSvc}
+		matchSvc.Cards = cards
+		eventSvc = &service.MatchEventService{Store: matchEvents, Matches: matches, Formats: formatSvc}
+		liveSvc = &service.LiveMatchService{
+			Store:   postgres.NewLiveMatchesStore(pgPool),
+			Matches: matchSvc,
+			Events:  matchEvents,
+			Hub:     service.NewLiveHub(),
+		}
 		notifySvc = &service.NotificationService{
 			Tokens: notificationTokens,
 			Users:  users,
@@ -118,6 +160,9 @@ func main() {
 		if friendsSvc != nil && notifySvc != nil {
 			friendsSvc.Notifier = notifySvc
 		}
+		if matchSvc != nil && notifySvc != nil {
+			matchSvc.Notifier = notifySvc
+		}
 		dbPing = pgPool.Ping
 	}
 
@@ -133,6 +178,17 @@ func main() {
 		Reset:         resetSvc,
 		Email:         emailSvc,
 		Notifications: notifySvc,
+		Sync:          syncSvc,
+		Ratings:       ratingSvc,
+		Seasons:       seasonSvc,
+		Groups:        groupSvc,
+		GuestClaims:   claimSvc,
+		Guests:        guestSvc,
+		Decks:         deckSvc,
+		Cards:         cardSvc,
+		Formats:       formatSvc,
+		MatchEvents:   eventSvc,
+		LiveMatches:   liveSvc,
 		CookieCodec:   auth.NewCookieCodec([]byte(cfg.CookieSecret)),
 		CookieSecure:  cfg.CookieSecure(),
 		SessionTTL:    cfg.SessionTTL,
@@ -203,6 +259,7 @@ func main() {
 			Admin:        adminSvc,
 			Reset:        resetSvc,
 			Email:        emailSvc,
+			Formats:      formatSvc,
 			CookieCodec:  auth.NewCookieCodec([]byte(cfg.CookieSecret)),
 			CookieSecure: cfg.CookieSecure(),
 			SessionTTL:   cfg.SessionTTL,
@@ -232,6 +289,11 @@ func main() {
 		Matches:      matchSvc,
 		Reset:        resetSvc,
 		Profile:      profileSvc,
+		Groups:       groupSvc,
+		GuestClaims:  claimSvc,
+		Decks:        deckSvc,
+		MatchEvents:  eventSvc,
+		Formats:      formatSvc,
 		AvatarDir:    cfg.AvatarDir,
 		CookieCodec:  auth.NewCookieCodec([]byte(cfg.CookieSecret)),
 		CookieSecure: cfg.CookieSecure(),
@@ -240,10 +302,26 @@ func main() {
 	root.Handle("/app", userRouter)
 	root.Handle("/app/", userRouter)
 
+	// Cancelled on shutdown so long-lived event streams end instead of holding it up.
+	baseCtx, cancelBase := context.WithCancel(context.Background())
 	srv := &http.Server{
 		Addr:              cfg.Addr,
 		Handler:           root,
 		ReadHeaderTimeout: 5 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return baseCtx },
+	}
+	srv.RegisterOnShutdown(cancelBase)
+
+	// Background workers stop with baseCtx and are waited for before the pool closes.
+	var workers sync.WaitGroup
+	if ratingSvc != nil {
+		ticker := time.NewTicker(service.RatingRefreshInterval)
+		defer ticker.Stop()
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			ratingSvc.RunRefresher(baseCtx, ticker.C)
+		}()
 	}
 
 	errCh := make(chan error, 1)
@@ -266,6 +344,8 @@ func main() {
 			os.Exit(1)
 		}
 	}
+	cancelBase()
+	workers.Wait()
 }
 
 type iconService struct {
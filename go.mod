@@ -6,6 +6,7 @@ require (
 	github.com/HendrickPhan/go-verify-apple-id-token v0.0.0-20241117103316-080e53423296
 	github.com/jackc/pgx/v5 v5.7.5
 	golang.org/x/crypto v0.40.0
+	golang.org/x/oauth2 v0.24.0
 	google.golang.org/api v0.213.0
 )
 
@@ -30,7 +31,6 @@ require (
 	go.opentelemetry.io/otel/metric v1.29.0 // indirect
 	go.opentelemetry.io/otel/trace v1.29.0 // indirect
 	golang.org/x/net v0.41.0 // indirect
-	golang.org/x/oauth2 v0.24.0 // indirect
 	golang.org/x/sync v0.16.0 // indirect
 	golang.org/x/sys v0.36.0 // indirect
 	golang.org/x/text v0.27.0 // indirect
@@ -0,0 +1,171 @@
+package adminui
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"MtgLeaderwebserver/internal/domain"
+	"MtgLeaderwebserver/internal/service"
+)
+
+func (a *app) handleFormatsGet(w http.ResponseWriter, r *http.Request) {
+	if a.formatSvc == nil {
+		a.templates.renderError(w, http.StatusServiceUnavailable, "Formats", "Formats are unavailable.")
+		return
+	}
+	data := formatsViewData{
+		Title:  "Formats",
+		Notice: mapFormatsNotice(strings.TrimSpace(r.URL.Query().Get("notice"))),
+		Form:   formatForm{Multiplayer: true, MinPlayers: 2, MaxPlayers: 8, DefaultPlayers: 4, StartingLife: 20, Enabled: true},
+	}
+	if slug := strings.TrimSpace(r.URL.Query().Get("edit")); slug != "" {
+		f, err := a.formatSvc.Get(r.Context(), slug)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			data.Error = "Format not found."
+		case err != nil:
+			a.logger.Error("adminui: get format", "err", err)
+			a.templates.renderError(w, http.StatusInternalServerError, "Formats", "Failed to load formats")
+			return
+		default:
+			data.Editing = string(f.Slug)
+			data.Form = formatFormFrom(f)
+		}
+	}
+	a.renderFormatsPage(w, r, http.StatusOK, data)
+}
+
+func (a *app) handleFormatsPost(w http.ResponseWriter, r *http.Request) {
+	if a.formatSvc == nil {
+		a.templates.renderError(w, http.StatusServiceUnavailable, "Formats", "Formats are unavailable.")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		a.renderFormatsPage(w, r, http.StatusBadRequest, formatsViewData{Title: "Formats", Error: "Invalid form"})
+		return
+	}
+
+	form := formatForm{
+		Slug:        strings.TrimSpace(r.FormValue("slug")),
+		Name:        strings.TrimSpace(r.FormValue("name")),
+		Multiplayer: r.FormValue("multiplayer") != "",
+		Commander:   r.FormValue("commander") != "",
+		Teams:       r.FormValue("teams") != "",
+		Legality:    strings.TrimSpace(r.FormValue("legality")),
+		Aliases:     strings.TrimSpace(r.FormValue("aliases")),
+		Enabled:     r.FormValue("enabled") != "",
+	}
+	data := formatsViewData{Title: "Formats", Form: form, Editing: strings.TrimSpace(r.FormValue("editing"))}
+
+	numbers := []struct {
+		field string
+		label string
+		dst   *int
+	}{
+		{"min_players", "Min players", &data.Form.MinPlayers},
+		{"max_players", "Max players", &data.Form.MaxPlayers},
+		{"default_players", "Default players", &data.Form.DefaultPlayers},
+		{"starting_life", "Starting life", &data.Form.StartingLife},
+		{"sort_order", "Sort order", &data.Form.SortOrder},
+	}
+	for _, n := range numbers {
+		v, err := strconv.Atoi(strings.TrimSpace(r.FormValue(n.field)))
+		if err != nil {
+			data.Error = n.label + " must be a number"
+			a.renderFormatsPage(w, r, http.StatusBadRequest, data)
+			return
+		}
+		*n.dst = v
+	}
+
+	p := service.FormatParams{
+		Slug:           data.Form.Slug,
+		Name:           data.Form.Name,
+		Multiplayer:    data.Form.Multiplayer,
+		MinPlayers:     data.Form.MinPlayers,
+		MaxPlayers:     data.Form.MaxPlayers,
+		DefaultPlayers: data.Form.DefaultPlayers,
+		StartingLife:   data.Form.StartingLife,
+		Commander:      data.Form.Commander,
+		Teams:          data.Form.Teams,
+		Legality:       data.Form.Legality,
+		Aliases:        strings.FieldsFunc(data.Form.Aliases, func(r rune) bool { return r == ',' || r == ' ' }),
+		Enabled:        data.Form.Enabled,
+		SortOrder:      data.Form.SortOrder,
+	}
+	var (
+		err    error
+		notice string
+	)
+	if data.Editing != "" {
+		_, err = a.formatSvc.Update(r.Context(), data.Editing, p)
+		notice = "format_updated"
+	} else {
+		_, err = a.formatSvc.Create(r.Context(), p)
+		notice = "format_created"
+	}
+	if err != nil {
+		var verr *domain.ValidationError
+		switch {
+		case errors.As(err, &verr):
+			data.Error = strings.TrimPrefix(verr.Error(), "validation failed: ")
+			a.renderFormatsPage(w, r, http.StatusBadRequest, data)
+		case errors.Is(err, domain.ErrFormatExists):
+			data.Error = "That slug or alias is already used by another format."
+			a.renderFormatsPage(w, r, http.StatusConflict, data)
+		case errors.Is(err, domain.ErrNotFound):
+			data.Error = "Format not found."
+			a.renderFormatsPage(w, r, http.StatusNotFound, data)
+		default:
+			a.logger.Error("adminui: save format", "err", err)
+			data.Error = "Failed to save format"
+			a.renderFormatsPage(w, r, http.StatusInternalServerError, data)
+		}
+		return
+	}
+	http.Redirect(w, r, "/admin/formats?"+url.Values{"notice": {notice}}.Encode(), http.StatusFound)
+}
+
+// renderFormatsPage fills in the format list and renders the page with data's form.
+func (a *app) renderFormatsPage(w http.ResponseWriter, r *http.Request, status int, data formatsViewData) {
+	formats, err := a.formatSvc.List(r.Context(), true)
+	if err != nil {
+		a.logger.Error("adminui: list formats", "err", err)
+		a.templates.renderError(w, http.StatusInternalServerError, "Formats", "Failed to load formats")
+		return
+	}
+	data.Formats = formats
+	a.templates.renderFormats(w, status, data)
+}
+
+func formatFormFrom(f domain.Format) formatForm {
+	return formatForm{
+		Slug:           string(f.Slug),
+		Name:           f.Name,
+		Multiplayer:    f.Multiplayer,
+		MinPlayers:     f.MinPlayers,
+		MaxPlayers:     f.MaxPlayers,
+		DefaultPlayers: f.DefaultPlayers,
+		StartingLife:   f.StartingLife,
+		Commander:      f.Commander,
+		Teams:          f.Teams,
+		Legality:       f.Legality,
+		Aliases:        strings.Join(f.Aliases, ", "),
+		Enabled:        f.Enabled,
+		SortOrder:      f.SortOrder,
+	}
+}
+
+func mapFormatsNotice(code string) string {
+	switch code {
+	case "format_created":
+		return "Format added."
+	case "format_updated":
+		return "Format saved."
+	default:
+		return ""
+	}
+}
@@ -20,6 +20,7 @@ type Opts struct {
 	Admin        *service.AdminService
 	Reset        *service.PasswordResetService
 	Email        *service.EmailService
+	Formats      *service.FormatService
 	CookieCodec  auth.CookieCodec
 	CookieSecure bool
 	SessionTTL   time.Duration
@@ -52,6 +53,7 @@ func New(opts Opts) http.Handler {
 		adminSvc:     opts.Admin,
 		resetSvc:     opts.Reset,
 		emailSvc:     opts.Email,
+		formatSvc:    opts.Formats,
 		cookieCodec:  opts.CookieCodec,
 		cookieSecure: opts.CookieSecure,
 		sessionTTL:   opts.SessionTTL,
@@ -82,6 +84,8 @@ func New(opts Opts) http.Handler {
 	mux.HandleFunc("POST /admin/password", app.requireAdmin(app.handlePasswordPost))
 	mux.HandleFunc("GET /admin/email", app.requireAdmin(app.handleEmailGet))
 	mux.HandleFunc("POST /admin/email", app.requireAdmin(app.handleEmailPost))
+	mux.HandleFunc("GET /admin/formats", app.requireAdmin(app.handleFormatsGet))
+	mux.HandleFunc("POST /admin/formats", app.requireAdmin(app.handleFormatsPost))
 	staticFS, err := fs.Sub(assets, "static")
 	if err != nil {
 		logger.Error("adminui: static fs setup failed", "err", err)
@@ -99,10 +103,11 @@ func New(opts Opts) http.Handler {
 type app struct {
 	logger *slog.Logger
 
-	authSvc  *service.AuthService
-	adminSvc *service.AdminService
-	resetSvc *service.PasswordResetService
-	emailSvc *service.EmailService
+	authSvc   *service.AuthService
+	adminSvc  *service.AdminService
+	resetSvc  *service.PasswordResetService
+	emailSvc  *service.EmailService
+	formatSvc *service.FormatService
 
 	cookieCodec  auth.CookieCodec
 	cookieSecure bool
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+
+	"MtgLeaderwebserver/internal/domain"
 )
 
 type templates struct {
@@ -12,6 +14,7 @@ type templates struct {
 	users     *template.Template
 	password  *template.Template
 	email     *template.Template
+	formats   *template.Template
 	errorT    *template.Template
 }
 
@@ -53,6 +56,32 @@ type smtpViewData struct {
 	TestEmail   string
 }
 
+type formatsViewData struct {
+	Title   string
+	Error   string
+	Notice  string
+	Formats []domain.Format
+	Form    formatForm
+	// Editing is the slug of the format in the form; empty when adding one.
+	Editing string
+}
+
+type formatForm struct {
+	Slug           string
+	Name           string
+	Multiplayer    bool
+	MinPlayers     int
+	MaxPlayers     int
+	DefaultPlayers int
+	StartingLife   int
+	Commander      bool
+	Teams          bool
+	Legality       string
+	Aliases        string
+	Enabled        bool
+	SortOrder      int
+}
+
 type userRow struct {
 	ID        string
 	Email     string
@@ -92,12 +121,16 @@ func parseTemplates() (*templates, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parse email: %w", err)
 	}
+	formats, err := parse("templates/layout.html", "templates/formats.html")
+	if err != nil {
+		return nil, fmt.Errorf("parse formats: %w", err)
+	}
 	errorT, err := parse("templates/error.html")
 	if err != nil {
 		return nil, fmt.Errorf("parse error: %w", err)
 	}
 
-	return &templates{login: login, dashboard: dashboard, users: users, password: password, email: emailT, errorT: errorT}, nil
+	return &templates{login: login, dashboard: dashboard, users: users, password: password, email: emailT, formats: formats, errorT: errorT}, nil
 }
 
 func (t *templates) renderLogin(w http.ResponseWriter, status int, data any) {
@@ -130,6 +163,12 @@ func (t *templates) renderEmail(w http.ResponseWriter, status int, data any) {
 	_ = t.email.ExecuteTemplate(w, "email.html", data)
 }
 
+func (t *templates) renderFormats(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_ = t.formats.ExecuteTemplate(w, "formats.html", data)
+}
+
 func (t *templates) renderErrorPage(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.WriteHeader(status)
@@ -39,6 +39,13 @@ func Load() (Config, error) {
 	return LoadFromEnv(os.Getenv)
 }
 
+// DBDSN returns APP_DB_DSN from the environment or .env without reading or checking any other
+// setting, for tools that only need the database.
+func DBDSN() string {
+	_ = loadDotEnvFile(".env", os.Setenv, os.Getenv)
+	return os.Getenv("APP_DB_DSN")
+}
+
 func LoadFromEnv(getenv func(string) string) (Config, error) {
 	cfg := Config{
 		Env:                getenv("APP_ENV"),
@@ -0,0 +1,28 @@
+package domain
+
+// Card is an Oracle card from the imported card database. ID is the Scryfall oracle_id, shared
+// by every printing. Legalities maps format names to "legal", "not_legal", "restricted" or "banned".
+type Card struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	TypeLine       string            `json:"type_line"`
+	ColorIdentity  string            `json:"color_identity"`
+	Legalities     map[string]string `json:"legalities"`
+	CanBeCommander bool              `json:"can_be_commander"`
+}
+
+// LegalIn reports whether the card may be played in format.
+func (c Card) LegalIn(format GameFormat) bool {
+	switch c.Legalities[string(format)] {
+	case "legal", "restricted":
+		return true
+	default:
+		return false
+	}
+}
+
+// CardSummary is the card shown on a match seat.
+type CardSummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
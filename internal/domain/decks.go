@@ -0,0 +1,101 @@
+package domain
+
+import "time"
+
+// Deck is a deck on a user's deck list. ColorIdentity is a subset of "WUBRG" in that order;
+// empty means colorless. Archived decks stay in stats but are hidden from the default list.
+type Deck struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Format        GameFormat `json:"format"`
+	Commanders    []string   `json:"commanders"`
+	ColorIdentity string     `json:"color_identity"`
+	Archived      bool       `json:"archived"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// DeckInput is a validated deck for the store.
+type DeckInput struct {
+	Name          string
+	Format        GameFormat
+	Commanders    []string
+	ColorIdentity string
+	Archived      bool
+}
+
+// DeckStats covers completed matches the owner played with the deck.
+type DeckStats struct {
+	Deck          Deck                 `json:"deck"`
+	MatchesPlayed int                  `json:"matches_played"`
+	Wins          int                  `json:"wins"`
+	Losses        int                  `json:"losses"`
+	Draws         int                  `json:"draws"`
+	WinPct        float64              `json:"win_pct"`
+	AvgPlace      float64              `json:"avg_place"`
+	HeadToHead    []DeckHeadToHeadStat `json:"head_to_head,omitempty"`
+}
+
+// DeckHeadToHeadStat is a deck's record against one opposing deck. Wins are matches the deck won;
+// losses are matches the opposing deck won; draws are drawn matches either deck shared first in.
+type DeckHeadToHeadStat struct {
+	DeckID        string      `json:"deck_id"`
+	DeckName      string      `json:"deck_name"`
+	Owner         UserSummary `json:"owner"`
+	MatchesPlayed int         `json:"matches_played"`
+	Wins          int         `json:"wins"`
+	Losses        int         `json:"losses"`
+	Draws         int         `json:"draws"`
+}
+
+// DeckSection is the part of a decklist a card belongs to.
+type DeckSection string
+
+const (
+	DeckSectionCommander DeckSection = "commander"
+	DeckSectionCompanion DeckSection = "companion"
+	DeckSectionMain      DeckSection = "main"
+	DeckSectionSideboard DeckSection = "sideboard"
+)
+
+// DeckCard is one line of a decklist. CardID is set when the name matched the card database.
+type DeckCard struct {
+	Section         DeckSection `json:"section"`
+	Quantity        int         `json:"quantity"`
+	Name            string      `json:"name"`
+	SetCode         string      `json:"set_code,omitempty"`
+	CollectorNumber string      `json:"collector_number,omitempty"`
+	CardID          string      `json:"card_id,omitempty"`
+}
+
+// DeckCardCounts totals card quantities per section.
+type DeckCardCounts struct {
+	Commander int `json:"commander"`
+	Companion int `json:"companion"`
+	Main      int `json:"main"`
+	Sideboard int `json:"sideboard"`
+	Total     int `json:"total"`
+}
+
+// DeckList is a deck's stored card list.
+type DeckList struct {
+	DeckID string         `json:"deck_id"`
+	Counts DeckCardCounts `json:"counts"`
+	Cards  []DeckCard     `json:"cards"`
+}
+
+// DecklistLine is an input line the decklist parser could not read.
+type DecklistLine struct {
+	Line   int    `json:"line"`
+	Text   string `json:"text"`
+	Reason string `json:"reason"`
+}
+
+// DeckImportResult is a deck created from decklist text. UnknownCards are names that did not
+// match the card database; they are stored without a card_id.
+type DeckImportResult struct {
+	Deck          Deck           `json:"deck"`
+	Counts        DeckCardCounts `json:"counts"`
+	UnknownCards  []string       `json:"unknown_cards"`
+	UnparsedLines []DecklistLine `json:"unparsed_lines"`
+}
@@ -16,6 +16,11 @@ var (
 	ErrInvalidCredentials    = errors.New("invalid_credentials")
 	ErrUserDisabled          = errors.New("user_disabled")
 	ErrFriendshipExists      = errors.New("friendship_exists")
+	ErrAlreadyGroupMember    = errors.New("already_group_member")
+	ErrGuestAliasTaken       = errors.New("guest_alias_taken")
+	ErrGuestsShareMatch      = errors.New("guests_share_match")
+	ErrLiveMatchClosed       = errors.New("live_match_closed")
+	ErrFormatExists          = errors.New("format_exists")
 	ErrExternalAccountExists = errors.New("external_account_exists")
 	ErrResetTokenInvalid     = errors.New("reset_token_invalid")
 	ErrResetTokenExpired     = errors.New("reset_token_expired")
@@ -0,0 +1,20 @@
+package domain
+
+// Format is a game format from the formats registry. Matches, decks, ratings and stats refer to it
+// by Slug. Legality is the card database legality key checked for the format's commanders.
+// Matches in a Teams format must put every player on a team.
+type Format struct {
+	Slug           GameFormat `json:"slug"`
+	Name           string     `json:"name"`
+	Multiplayer    bool       `json:"multiplayer"`
+	MinPlayers     int        `json:"min_players"`
+	MaxPlayers     int        `json:"max_players"`
+	DefaultPlayers int        `json:"default_players"`
+	StartingLife   int        `json:"starting_life"`
+	Commander      bool       `json:"commander"`
+	Teams          bool       `json:"teams"`
+	Legality       string     `json:"legality"`
+	Aliases        []string   `json:"aliases"`
+	Enabled        bool       `json:"enabled"`
+	SortOrder      int        `json:"sort_order"`
+}
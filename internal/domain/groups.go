@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+type GroupRole string
+
+const (
+	GroupRoleOwner  GroupRole = "owner"
+	GroupRoleAdmin  GroupRole = "admin"
+	GroupRoleMember GroupRole = "member"
+)
+
+// CanManage reports whether the role may invite, remove members and edit the group.
+func (r GroupRole) CanManage() bool {
+	return r == GroupRoleOwner || r == GroupRoleAdmin
+}
+
+// Group is returned from the caller's point of view: Role is the caller's role and
+// JoinCode is only filled in for owners and admins.
+type Group struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedBy   string    `json:"created_by"`
+	Role        GroupRole `json:"role"`
+	MemberCount int       `json:"member_count"`
+	JoinCode    string    `json:"join_code,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type GroupSummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type GroupMember struct {
+	User     UserSummary `json:"user"`
+	Role     GroupRole   `json:"role"`
+	JoinedAt time.Time   `json:"joined_at"`
+}
+
+type GroupInvite struct {
+	ID        string       `json:"id"`
+	Group     GroupSummary `json:"group"`
+	User      UserSummary  `json:"user"`
+	InvitedBy UserSummary  `json:"invited_by"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type GroupPlayerStats struct {
+	User          *UserSummary `json:"user,omitempty"`
+	GuestID       string       `json:"guest_id,omitempty"`
+	GuestName     string       `json:"guest_name,omitempty"`
+	MatchesPlayed int          `json:"matches_played"`
+	Wins          int          `json:"wins"`
+	WinPct        float64      `json:"win_pct"`
+	AvgPlace      float64      `json:"avg_place"`
+}
+
+// GroupStats covers completed matches recorded in the group.
+type GroupStats struct {
+	MatchesPlayed int                `json:"matches_played"`
+	ByFormat      map[string]int     `json:"by_format"`
+	Players       []GroupPlayerStats `json:"players"`
+}
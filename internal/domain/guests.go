@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// GuestClaim offers the guest seats a match creator recorded under GuestName to a registered user.
+// GuestID is set when the claim covers a saved guest, whose linked and alias seats are included.
+type GuestClaim struct {
+	ID         string      `json:"id"`
+	GuestName  string      `json:"guest_name"`
+	GuestID    string      `json:"guest_id,omitempty"`
+	InvitedBy  UserSummary `json:"invited_by"`
+	User       UserSummary `json:"user"`
+	MatchCount int         `json:"match_count"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type GuestClaimsOverview struct {
+	Incoming []GuestClaim `json:"incoming"`
+	Outgoing []GuestClaim `json:"outgoing"`
+}
+
+// Guest is a saved guest player on a user's roster. Aliases are the normalized guest names that
+// resolve to this guest in matches the owner records; the display name is always one of them.
+type Guest struct {
+	ID          string    `json:"id"`
+	DisplayName string    `json:"display_name"`
+	Aliases     []string  `json:"aliases"`
+	MatchCount  int       `json:"match_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
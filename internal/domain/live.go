@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// LiveMatchStatus is where a live match is in its lifecycle. A live match is finishing while its
+// result is being recorded; its log no longer changes.
+type LiveMatchStatus string
+
+const (
+	LiveMatchActive    LiveMatchStatus = "active"
+	LiveMatchFinishing LiveMatchStatus = "finishing"
+	LiveMatchFinished  LiveMatchStatus = "finished"
+	LiveMatchAbandoned LiveMatchStatus = "abandoned"
+)
+
+// LiveMatch is a game in progress. Seq is the last update applied to the seats, Turn the current
+// turn and ActiveSeatIndex the seat whose turn it is, once the first turn has been passed.
+type LiveMatch struct {
+	ID                string          `json:"id"`
+	CreatedBy         string          `json:"created_by"`
+	Format            GameFormat      `json:"format"`
+	GroupID           string          `json:"group_id,omitempty"`
+	StartingSeatIndex *int            `json:"starting_seat_index,omitempty"`
+	Status            LiveMatchStatus `json:"status"`
+	MatchID           string          `json:"match_id,omitempty"`
+	StartedAt         time.Time       `json:"started_at"`
+	FinishedAt        *time.Time      `json:"finished_at,omitempty"`
+	Seq               int             `json:"seq"`
+	Turn              int             `json:"turn"`
+	ActiveSeatIndex   *int            `json:"active_seat_index,omitempty"`
+	Seats             []LiveMatchSeat `json:"seats"`
+}
+
+// JoinedSeats counts the seats whose player has joined.
+func (lm LiveMatch) JoinedSeats() int {
+	n := 0
+	for _, seat := range lm.Seats {
+		if seat.JoinedAt != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// LiveMatchSeat is a seat's player and current totals.
+type LiveMatchSeat struct {
+	MatchReplaySeat
+	User           *UserSummary `json:"user,omitempty"`
+	GuestName      string       `json:"guest_name,omitempty"`
+	GuestID        string       `json:"guest_id,omitempty"`
+	DisplayName    string       `json:"display_name,omitempty"`
+	DeckID         string       `json:"deck_id,omitempty"`
+	CommanderIDs   []string     `json:"commander_ids,omitempty"`
+	JoinedAt       *time.Time   `json:"joined_at,omitempty"`
+	EliminatedTurn *int         `json:"eliminated_turn_number,omitempty"`
+}
+
+// LiveMatchInput is a validated live match ready to be written by the store.
+type LiveMatchInput struct {
+	CreatedBy         string
+	Format            GameFormat
+	GroupID           string
+	StartingSeatIndex *int
+	Seats             []MatchParticipantInput
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// MatchEventKind is the kind of an entry in a match's game log.
+type MatchEventKind string
+
+const (
+	// MatchEventLife changes a seat's life total.
+	MatchEventLife MatchEventKind = "life"
+	// MatchEventPoison changes a seat's poison counters.
+	MatchEventPoison MatchEventKind = "poison"
+	// MatchEventCommanderDamage records damage dealt to a seat by the commander of SourceSeatIndex.
+	// It does not change life; the client logs the life loss as its own event.
+	MatchEventCommanderDamage MatchEventKind = "commander_damage"
+	// MatchEventEliminated marks a seat as out of the game.
+	MatchEventEliminated MatchEventKind = "eliminated"
+	// MatchEventTurnPass starts Turn for SeatIndex.
+	MatchEventTurnPass MatchEventKind = "turn_pass"
+)
+
+// MatchEvent is one entry in a match's append-only game log. Amount is the change, negative for
+// loss. Total is the value after the change when the client knows it and wins over Amount.
+type MatchEvent struct {
+	Seq             int            `json:"seq"`
+	Kind            MatchEventKind `json:"kind"`
+	Turn            int            `json:"turn"`
+	SeatIndex       int            `json:"seat_index"`
+	SourceSeatIndex *int           `json:"source_seat_index,omitempty"`
+	Amount          int            `json:"amount,omitempty"`
+	Total           *int           `json:"total,omitempty"`
+	OccurredAt      *time.Time     `json:"occurred_at,omitempty"`
+}
+
+// MatchReplayTurn is every seat's state at the end of a turn of the game log.
+type MatchReplayTurn struct {
+	Turn   int               `json:"turn"`
+	Seats  []MatchReplaySeat `json:"seats"`
+	Events []MatchEvent      `json:"events"`
+}
+
+// MatchReplaySeat is one seat's state during a replay. CommanderDamage is keyed by source seat.
+type MatchReplaySeat struct {
+	SeatIndex       int         `json:"seat_index"`
+	Life            int         `json:"life"`
+	Poison          int         `json:"poison"`
+	CommanderDamage map[int]int `json:"commander_damage,omitempty"`
+	Eliminated      bool        `json:"eliminated"`
+}
@@ -12,21 +12,38 @@ const (
 )
 
 type MatchPlayer struct {
-	User             UserSummary `json:"user"`
-	IsWinner         bool        `json:"is_winner"`
-	Rank             *int        `json:"rank,omitempty"`
-	EliminationTurn  *int        `json:"elimination_turn,omitempty"`
-	EliminationBatch *int        `json:"elimination_batch,omitempty"`
-	SeatIndex        *int        `json:"seat_index,omitempty"`
-	GuestName        string      `json:"guest_name,omitempty"`
-	DisplayName      string      `json:"display_name,omitempty"`
-	Place            *int        `json:"place,omitempty"`
-	EliminatedTurn   *int        `json:"eliminated_turn_number,omitempty"`
-	EliminatedDuring *int        `json:"eliminated_during_seat_index,omitempty"`
-	TotalTurnTimeMs  *int64      `json:"total_turn_time_ms,omitempty"`
-	TurnsTaken       *int        `json:"turns_taken,omitempty"`
+	User             UserSummary   `json:"user"`
+	IsWinner         bool          `json:"is_winner"`
+	Rank             *int          `json:"rank,omitempty"`
+	EliminationTurn  *int          `json:"elimination_turn,omitempty"`
+	EliminationBatch *int          `json:"elimination_batch,omitempty"`
+	SeatIndex        *int          `json:"seat_index,omitempty"`
+	GuestName        string        `json:"guest_name,omitempty"`
+	GuestID          string        `json:"guest_id,omitempty"`
+	DeckID           string        `json:"deck_id,omitempty"`
+	DeckName         string        `json:"deck_name,omitempty"`
+	Commanders       []CardSummary `json:"commanders,omitempty"`
+	DisplayName      string        `json:"display_name,omitempty"`
+	Place            *int          `json:"place,omitempty"`
+	Team             *int          `json:"team,omitempty"`
+	EliminatedTurn   *int          `json:"eliminated_turn_number,omitempty"`
+	EliminatedDuring *int          `json:"eliminated_during_seat_index,omitempty"`
+	TotalTurnTimeMs  *int64        `json:"total_turn_time_ms,omitempty"`
+	TurnsTaken       *int          `json:"turns_taken,omitempty"`
+	// Confirmation is empty for guests and legacy seats.
+	Confirmation  MatchConfirmation `json:"confirmation,omitempty"`
+	DisputeReason string            `json:"dispute_reason,omitempty"`
 }
 
+// MatchConfirmation is a registered participant's review of a match someone else recorded.
+type MatchConfirmation string
+
+const (
+	MatchConfirmationPending   MatchConfirmation = "pending"
+	MatchConfirmationConfirmed MatchConfirmation = "confirmed"
+	MatchConfirmationDisputed  MatchConfirmation = "disputed"
+)
+
 type MatchResultInput struct {
 	ID               string `json:"id"`
 	Rank             int    `json:"rank"`
@@ -34,18 +51,82 @@ type MatchResultInput struct {
 	EliminationBatch *int   `json:"elimination_batch,omitempty"`
 }
 
+// MatchOutcome is how a match ended. Drawn matches have several players (or teams) sharing
+// place 1; abandoned matches have no places and no winner.
+type MatchOutcome string
+
+const (
+	MatchOutcomeWin       MatchOutcome = "win"
+	MatchOutcomeDraw      MatchOutcome = "draw"
+	MatchOutcomeAbandoned MatchOutcome = "abandoned"
+)
+
+// WinCondition is how a match ended. WinConditionCustom carries a free-form tag.
+type WinCondition string
+
+const (
+	WinConditionCombat          WinCondition = "combat"
+	WinConditionCommanderDamage WinCondition = "commander_damage"
+	WinConditionCombo           WinCondition = "combo"
+	WinConditionMill            WinCondition = "mill"
+	WinConditionPoison          WinCondition = "poison"
+	WinConditionConcession      WinCondition = "concession"
+	WinConditionAltWin          WinCondition = "alt_win"
+	WinConditionCustom          WinCondition = "custom"
+)
+
+// MatchParticipantInput is one seat of a match. Team is 0 in free-for-all matches; in team
+// matches every seat has a team and Place is the team's place. Place is 0 in abandoned matches.
 type MatchParticipantInput struct {
 	SeatIndex        int
 	UserID           string
 	GuestName        string
+	GuestID          string
+	DeckID           string
+	CommanderIDs     []string
 	DisplayName      string
 	Place            int
+	Team             int
 	EliminatedTurn   *int
 	EliminatedDuring *int
 	TotalTurnTimeMs  *int64
 	TurnsTaken       *int
 }
 
+// MatchGame is one game of a best-of-N 1v1 match. OnPlaySeatIndex is the seat that took the
+// first turn; Mulligans is indexed by seat_index.
+type MatchGame struct {
+	Number          int   `json:"game_number"`
+	WinnerSeatIndex int   `json:"winner_seat_index"`
+	OnPlaySeatIndex *int  `json:"on_play_seat_index,omitempty"`
+	Mulligans       []int `json:"mulligans,omitempty"`
+	DurationSeconds int   `json:"duration_seconds"`
+}
+
+// MatchInput is a validated match ready to be written by the store.
+type MatchInput struct {
+	CreatedBy            string
+	StartedAt            *time.Time
+	EndedAt              *time.Time
+	PlayedAt             *time.Time
+	WinnerID             string
+	Outcome              MatchOutcome
+	WinCondition         WinCondition
+	WinConditionTag      string
+	WinningCard          string
+	Participants         []MatchParticipantInput
+	Format               GameFormat
+	TotalDurationSeconds int
+	TurnCount            int
+	StartingSeatIndex    *int
+	GroupID              string
+	ClientRef            string
+	UpdatedAt            time.Time
+	// BestOf is 0 for single-game matches.
+	BestOf int
+	Games  []MatchGame
+}
+
 type Match struct {
 	ID                   string        `json:"id"`
 	CreatedBy            string        `json:"created_by"`
@@ -56,22 +137,75 @@ type Match struct {
 	EndedAt              *time.Time    `json:"ended_at,omitempty"`
 	PlayedAt             *time.Time    `json:"played_at,omitempty"`
 	WinnerID             string        `json:"winner_id,omitempty"`
+	Outcome              MatchOutcome  `json:"outcome"`
+	WinCondition         WinCondition  `json:"win_condition,omitempty"`
+	WinConditionTag      string        `json:"win_condition_tag,omitempty"`
+	WinningCard          string        `json:"winning_card,omitempty"`
 	Format               GameFormat    `json:"format"`
 	TotalDurationSeconds int           `json:"total_duration_seconds"`
 	TurnCount            int           `json:"turn_count"`
+	StartingSeatIndex    *int          `json:"starting_seat_index,omitempty"`
+	GroupID              string        `json:"group_id,omitempty"`
 	Players              []MatchPlayer `json:"players"`
+	BestOf               *int          `json:"best_of,omitempty"`
+	Games                []MatchGame   `json:"games,omitempty"`
+	DeletedAt            *time.Time    `json:"deleted_at,omitempty"`
+}
+
+type MatchResultFilter string
+
+const (
+	MatchResultWon  MatchResultFilter = "won"
+	MatchResultLost MatchResultFilter = "lost"
+)
+
+type MatchListFilter struct {
+	Limit      int
+	Format     GameFormat
+	From       *time.Time
+	To         *time.Time
+	OpponentID string
+	GuestName  string
+	Result     MatchResultFilter
+	GroupID    string
+	BeforeTime *time.Time
+	BeforeID   string
 }
 
+// StatsSettings are a user's preferences for which matches feed their own stats.
+type StatsSettings struct {
+	// ConfirmedOnly skips matches until every registered participant has confirmed them.
+	ConfirmedOnly bool `json:"confirmed_only"`
+}
+
+// StatsSummary counts completed matches. Draws are drawn matches where the user shared first
+// place; abandoned matches are left out.
 type StatsSummary struct {
 	MatchesPlayed     int                     `json:"matches_played"`
 	Wins              int                     `json:"wins"`
 	Losses            int                     `json:"losses"`
+	Draws             int                     `json:"draws"`
 	WinPct            float64                 `json:"win_pct,omitempty"`
 	AvgTurnSeconds    int                     `json:"avg_turn_seconds"`
 	ByFormat          map[string]StatsSummary `json:"by_format,omitempty"`
 	MostOftenBeat     *OpponentStat           `json:"most_often_beat,omitempty"`
 	MostOftenBeatsYou *OpponentStat           `json:"most_often_beats_you,omitempty"`
 	GuestHeadToHead   []GuestHeadToHeadStat   `json:"guest_head_to_head,omitempty"`
+	WinConditions     *WinConditionStats      `json:"win_conditions,omitempty"`
+}
+
+// WinConditionStats counts how the user's won and lost matches ended, most common first. Only
+// matches tagged with a win condition count; draws are left out.
+type WinConditionStats struct {
+	Wins   []WinConditionCount `json:"wins"`
+	Losses []WinConditionCount `json:"losses"`
+}
+
+// WinConditionCount is one win condition, or one custom tag, and how many matches ended that way.
+type WinConditionCount struct {
+	Condition WinCondition `json:"condition"`
+	Tag       string       `json:"tag,omitempty"`
+	Count     int          `json:"count"`
 }
 
 type OpponentStat struct {
@@ -79,25 +213,144 @@ type OpponentStat struct {
 	Count    int         `json:"count"`
 }
 
+// GuestHeadToHeadStat groups guest seats by roster entry when linked (GuestID set) and by name otherwise.
 type GuestHeadToHeadStat struct {
+	GuestID   string `json:"guest_id,omitempty"`
 	GuestName string `json:"guest_name"`
 	Wins      int    `json:"wins"`
 	Losses    int    `json:"losses"`
+	Draws     int    `json:"draws"`
 }
 
+// HeadToHeadStats counts Total, Wins and Losses over matches the two users played on opposing
+// sides. Draws are drawn matches where either of them shared first place. Matches on the same
+// team are counted in AsTeammates and WinsTogether instead.
 type HeadToHeadStats struct {
-	Opponent UserSummary                `json:"opponent"`
-	Total    int                        `json:"total"`
-	Wins     int                        `json:"wins"`
-	Losses   int                        `json:"losses"`
-	CoLosses int                        `json:"co_losses"`
-	ByFormat map[string]HeadToHeadStats `json:"by_format,omitempty"`
+	Opponent     UserSummary                `json:"opponent"`
+	Total        int                        `json:"total"`
+	Wins         int                        `json:"wins"`
+	Losses       int                        `json:"losses"`
+	Draws        int                        `json:"draws"`
+	CoLosses     int                        `json:"co_losses"`
+	AsTeammates  int                        `json:"as_teammates"`
+	WinsTogether int                        `json:"wins_together"`
+	TurnTime     *TurnTimeHeadToHead        `json:"turn_time,omitempty"`
+	ByFormat     map[string]HeadToHeadStats `json:"by_format,omitempty"`
+}
+
+// TurnTimeStats uses the per-player total_turn_time_ms and turns_taken uploaded by clients.
+// Pace is the user's average turn divided by the average turn of everyone else in the same matches.
+type TurnTimeStats struct {
+	MatchesTimed int                      `json:"matches_timed"`
+	AvgTurnMs    int64                    `json:"avg_turn_ms"`
+	PodAvgTurnMs int64                    `json:"pod_avg_turn_ms"`
+	Pace         float64                  `json:"pace"`
+	SlowestInPod int                      `json:"slowest_in_pod"`
+	FastestInPod int                      `json:"fastest_in_pod"`
+	Trend        []TurnTimeTrendPoint     `json:"trend"`
+	ByFormat     map[string]TurnTimeStats `json:"by_format,omitempty"`
+}
+
+type TurnTimeTrendPoint struct {
+	Month        string  `json:"month"`
+	MatchesTimed int     `json:"matches_timed"`
+	AvgTurnMs    int64   `json:"avg_turn_ms"`
+	Pace         float64 `json:"pace"`
+}
+
+type TurnTimeHeadToHead struct {
+	MatchesTimed      int   `json:"matches_timed"`
+	AvgTurnMs         int64 `json:"avg_turn_ms"`
+	OpponentAvgTurnMs int64 `json:"opponent_avg_turn_ms"`
+	YouSlower         int   `json:"you_slower"`
+	OpponentSlower    int   `json:"opponent_slower"`
+}
+
+type PositionStat struct {
+	Position      int     `json:"position"`
+	MatchesPlayed int     `json:"matches_played"`
+	Wins          int     `json:"wins"`
+	WinPct        float64 `json:"win_pct"`
+}
+
+type SeatRecord struct {
+	MatchesPlayed int     `json:"matches_played"`
+	Wins          int     `json:"wins"`
+	WinPct        float64 `json:"win_pct"`
+}
+
+// SeatStats only counts matches that recorded a starting seat.
+// BySeat is keyed by seat_index; ByTurnOrder by position after the starting seat (0 went first).
+type SeatStats struct {
+	MatchesPlayed int                  `json:"matches_played"`
+	BySeat        []PositionStat       `json:"by_seat"`
+	ByTurnOrder   []PositionStat       `json:"by_turn_order"`
+	WentFirst     SeatRecord           `json:"went_first"`
+	NotFirst      SeatRecord           `json:"not_first"`
+	ByFormat      map[string]SeatStats `json:"by_format,omitempty"`
+}
+
+// GameStats covers 1v1 matches recorded game by game. Match counts use each match's result; game
+// counts use every game, split by whether the user was on the play or on the draw.
+type GameStats struct {
+	MatchesPlayed int                  `json:"matches_played"`
+	MatchWins     int                  `json:"match_wins"`
+	MatchWinPct   float64              `json:"match_win_pct"`
+	GamesPlayed   int                  `json:"games_played"`
+	GameWins      int                  `json:"game_wins"`
+	GameWinPct    float64              `json:"game_win_pct"`
+	OnThePlay     GameRecord           `json:"on_the_play"`
+	OnTheDraw     GameRecord           `json:"on_the_draw"`
+	ByFormat      map[string]GameStats `json:"by_format,omitempty"`
+}
+
+type GameRecord struct {
+	GamesPlayed int     `json:"games_played"`
+	Wins        int     `json:"wins"`
+	WinPct      float64 `json:"win_pct"`
+}
+
+// EliminationOpponent is either a registered user or a guest. Roster guests set GuestID.
+type EliminationOpponent struct {
+	Opponent  *UserSummary `json:"opponent,omitempty"`
+	GuestID   string       `json:"guest_id,omitempty"`
+	GuestName string       `json:"guest_name,omitempty"`
+	Count     int          `json:"count"`
+}
+
+// EliminationStats is built from eliminated_during_seat_index: the player whose turn it was
+// is credited with the elimination. Eliminations on your own turn are not credited to anyone.
+type EliminationStats struct {
+	Eliminations       int                         `json:"eliminations"`
+	TimesEliminated    int                         `json:"times_eliminated"`
+	AvgEliminationTurn float64                     `json:"avg_elimination_turn"`
+	MostEliminated     *EliminationOpponent        `json:"most_eliminated,omitempty"`
+	MostEliminatedBy   *EliminationOpponent        `json:"most_eliminated_by,omitempty"`
+	Eliminated         []EliminationOpponent       `json:"eliminated"`
+	EliminatedBy       []EliminationOpponent       `json:"eliminated_by"`
+	ByFormat           map[string]EliminationStats `json:"by_format,omitempty"`
 }
 
 type FriendStatsListItem struct {
-	Friend   UserSummary `json:"friend"`
-	Total    int         `json:"total"`
-	Wins     int         `json:"wins"`
-	Losses   int         `json:"losses"`
-	CoLosses int         `json:"co_losses"`
+	Friend       UserSummary         `json:"friend"`
+	Total        int                 `json:"total"`
+	Wins         int                 `json:"wins"`
+	Losses       int                 `json:"losses"`
+	Draws        int                 `json:"draws"`
+	CoLosses     int                 `json:"co_losses"`
+	AsTeammates  int                 `json:"as_teammates"`
+	WinsTogether int                 `json:"wins_together"`
+	TurnTime     *TurnTimeHeadToHead `json:"turn_time,omitempty"`
+}
+
+// MatchPlacement is one participant row of a completed match, used to build standings.
+type MatchPlacement struct {
+	MatchID   string
+	Format    GameFormat
+	User      *UserSummary
+	GuestID   string
+	GuestName string
+	Place     int
+	// Draw is set when the match was drawn; places are shared at the top.
+	Draw bool
 }
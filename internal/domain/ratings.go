@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+type Rating struct {
+	UserID       string     `json:"user_id"`
+	Format       GameFormat `json:"format"`
+	Rating       float64    `json:"rating"`
+	PeakRating   float64    `json:"peak_rating"`
+	MatchesRated int        `json:"matches_rated"`
+	LastPlayedAt *time.Time `json:"last_played_at,omitempty"`
+}
+
+type RatingHistoryEntry struct {
+	UserID       string     `json:"-"`
+	Format       GameFormat `json:"format"`
+	MatchID      string     `json:"match_id"`
+	PlayedAt     time.Time  `json:"played_at"`
+	Place        int        `json:"place"`
+	PodSize      int        `json:"pod_size"`
+	RatingBefore float64    `json:"rating_before"`
+	RatingAfter  float64    `json:"rating_after"`
+}
+
+type RatingLeaderboardEntry struct {
+	Rank         int         `json:"rank"`
+	User         UserSummary `json:"user"`
+	Rating       float64     `json:"rating"`
+	MatchesRated int         `json:"matches_rated"`
+}
+
+// RatingRun records which match sequence and engine version the stored ratings were built from.
+type RatingRun struct {
+	SourceSeq     int64
+	EngineVersion int
+	ComputedAt    time.Time
+}
+
+// RatingRebuild replays one format's matches played at or after From. Start holds each player's
+// rating from the matches before From; a zero From replays the whole format. Ratings and History
+// are the result: every rating in the format, and the history from From onward.
+type RatingRebuild struct {
+	Format  GameFormat
+	From    time.Time
+	Start   []Rating
+	Matches []RatedMatch
+
+	Ratings []Rating
+	History []RatingHistoryEntry
+}
+
+// RatedMatch is a completed match in the order the rating engine replays it.
+type RatedMatch struct {
+	ID       string
+	Format   GameFormat
+	PlayedAt time.Time
+	Players  []RatedPlayer
+}
+
+// RatedPlayer has an empty UserID for guests; guests always play at the base rating.
+// Team is 0 in free-for-all matches.
+type RatedPlayer struct {
+	UserID string
+	Place  int
+	Team   int
+}
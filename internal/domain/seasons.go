@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// DefaultSeasonPoints is used when a season is created without a points table.
+var DefaultSeasonPoints = []int{3, 1}
+
+// Season is a named date range [StartsAt, EndsAt) whose matches are scored by place.
+// Points[i] is awarded for place i+1; places past the end of the table score 0.
+type Season struct {
+	ID         string     `json:"id"`
+	OwnerID    string     `json:"owner_id"`
+	GroupID    string     `json:"group_id,omitempty"`
+	Name       string     `json:"name"`
+	Format     GameFormat `json:"format,omitempty"`
+	StartsAt   time.Time  `json:"starts_at"`
+	EndsAt     time.Time  `json:"ends_at"`
+	Points     []int      `json:"points"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// SeasonInput is a validated season for the store.
+type SeasonInput struct {
+	GroupID  string
+	Name     string
+	Format   GameFormat
+	StartsAt time.Time
+	EndsAt   time.Time
+	Points   []int
+}
+
+type SeasonStanding struct {
+	Rank          int          `json:"rank"`
+	User          *UserSummary `json:"user,omitempty"`
+	GuestID       string       `json:"guest_id,omitempty"`
+	GuestName     string       `json:"guest_name,omitempty"`
+	Points        int          `json:"points"`
+	MatchesPlayed int          `json:"matches_played"`
+	Wins          int          `json:"wins"`
+	WinPct        float64      `json:"win_pct"`
+	AvgPlace      float64      `json:"avg_place"`
+}
+
+// SeasonStandings is live while the season is open and a frozen snapshot once it is archived.
+type SeasonStandings struct {
+	Season        Season           `json:"season"`
+	Final         bool             `json:"final"`
+	MatchesPlayed int              `json:"matches_played"`
+	Standings     []SeasonStanding `json:"standings"`
+}
@@ -0,0 +1,12 @@
+package domain
+
+const FriendStatusRemoved FriendStatus = "removed"
+
+type SyncChanges struct {
+	Profile         *User
+	Friends         []FriendConnection
+	Matches         []Match
+	RemovedMatchIDs []string
+	MaxSeq          int64
+	HasMore         bool
+}
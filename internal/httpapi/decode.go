@@ -61,3 +61,28 @@ func decodeJSONAllowEmpty(w http.ResponseWriter, r *http.Request, dst any) (bool
 	}
 	return false, nil
 }
+
+// optionalString tells a field that was left out apart from one sent as null or a string;
+// null and "" both decode to an empty Value with Set true.
+type optionalString struct {
+	Set   bool
+	Value string
+}
+
+func (o *optionalString) UnmarshalJSON(b []byte) error {
+	o.Set = true
+	if string(b) == "null" {
+		o.Value = ""
+		return nil
+	}
+	return json.Unmarshal(b, &o.Value)
+}
+
+// ptr returns nil when the field was left out.
+func (o optionalString) ptr() *string {
+	if !o.Set {
+		return nil
+	}
+	v := o.Value
+	return &v
+}
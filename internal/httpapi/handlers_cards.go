@@ -0,0 +1,42 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"MtgLeaderwebserver/internal/domain"
+	"MtgLeaderwebserver/internal/service"
+)
+
+func (a *api) handleCardsSearch(w http.ResponseWriter, r *http.Request) {
+	if _, ok := CurrentUser(r.Context()); !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	p := service.CardSearchParams{
+		Query:  query.Get("q"),
+		Format: domain.GameFormat(strings.TrimSpace(query.Get("format"))),
+	}
+	switch strings.TrimSpace(query.Get("commander")) {
+	case "1", "true":
+		p.CommanderOnly = true
+	}
+	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			WriteDomainError(w, domain.NewValidationError(map[string]string{"limit": "must be a number"}))
+			return
+		}
+		p.Limit = n
+	}
+
+	cards, err := a.cardSvc.Search(r.Context(), p)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, cards)
+}
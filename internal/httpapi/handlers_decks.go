@@ -0,0 +1,223 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+
+	"MtgLeaderwebserver/internal/domain"
+	"MtgLeaderwebserver/internal/service"
+)
+
+type createDeckRequest struct {
+	Name          string   `json:"name"`
+	Format        string   `json:"format"`
+	Commanders    []string `json:"commanders,omitempty"`
+	ColorIdentity string   `json:"color_identity"`
+	Archived      bool     `json:"archived"`
+}
+
+type updateDeckRequest struct {
+	Name          *string   `json:"name,omitempty"`
+	Format        *string   `json:"format,omitempty"`
+	Commanders    *[]string `json:"commanders,omitempty"`
+	ColorIdentity *string   `json:"color_identity,omitempty"`
+	Archived      *bool     `json:"archived,omitempty"`
+}
+
+type importDeckRequest struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	Text   string `json:"text"`
+}
+
+func (a *api) handleDecksList(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	include := r.URL.Query().Get("include_archived")
+	decks, err := a.deckSvc.List(r.Context(), u.ID, include == "1" || include == "true")
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, decks)
+}
+
+func (a *api) handleDecksCreate(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req createDeckRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	deck, err := a.deckSvc.Create(r.Context(), u.ID, service.DeckParams{
+		Name:          req.Name,
+		Format:        domain.GameFormat(strings.TrimSpace(req.Format)),
+		Commanders:    req.Commanders,
+		ColorIdentity: req.ColorIdentity,
+		Archived:      req.Archived,
+	})
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, deck)
+}
+
+func (a *api) handleDecksImport(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req importDeckRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	res, err := a.deckSvc.Import(r.Context(), u.ID, service.DeckImportParams{
+		Name:   req.Name,
+		Format: domain.GameFormat(strings.TrimSpace(req.Format)),
+		Text:   req.Text,
+	})
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, res)
+}
+
+func (a *api) handleDecksCards(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	list, err := a.deckSvc.CardList(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, list)
+}
+
+func (a *api) handleDecksExport(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	format := service.DecklistFormat(strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format"))))
+	text, err := a.deckSvc.Export(r.Context(), u.ID, r.PathValue("id"), format)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(text))
+}
+
+func (a *api) handleDecksGet(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	deck, err := a.deckSvc.Get(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, deck)
+}
+
+func (a *api) handleDecksUpdate(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req updateDeckRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	patch := service.DeckPatch{
+		Name:          req.Name,
+		Commanders:    req.Commanders,
+		ColorIdentity: req.ColorIdentity,
+		Archived:      req.Archived,
+	}
+	if req.Format != nil {
+		format := domain.GameFormat(strings.TrimSpace(*req.Format))
+		patch.Format = &format
+	}
+
+	deck, err := a.deckSvc.Update(r.Context(), u.ID, r.PathValue("id"), patch)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, deck)
+}
+
+func (a *api) handleDecksDelete(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := a.deckSvc.Delete(r.Context(), u.ID, r.PathValue("id")); err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) handleDecksStats(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	stats, err := a.deckSvc.Stats(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, stats)
+}
+
+func (a *api) handleStatsDecks(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	stats, err := a.deckSvc.ListStats(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,21 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+func (a *api) handleFormatsList(w http.ResponseWriter, r *http.Request) {
+	if _, ok := CurrentUser(r.Context()); !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	formats, err := a.formatSvc.List(r.Context(), false)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, formats)
+}
@@ -0,0 +1,336 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+
+	"MtgLeaderwebserver/internal/domain"
+	"MtgLeaderwebserver/internal/service"
+)
+
+type createGroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type updateGroupRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+type joinGroupRequest struct {
+	Code string `json:"code"`
+}
+
+type setGroupRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type groupInviteRequest struct {
+	Username string `json:"username"`
+}
+
+func (a *api) handleGroupsCreate(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req createGroupRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	group, err := a.groupSvc.Create(r.Context(), u.ID, req.Name, req.Description)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, group)
+}
+
+func (a *api) handleGroupsList(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	groups, err := a.groupSvc.List(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, groups)
+}
+
+func (a *api) handleGroupsGet(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	group, err := a.groupSvc.Get(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, group)
+}
+
+func (a *api) handleGroupsUpdate(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req updateGroupRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	group, err := a.groupSvc.Update(r.Context(), u.ID, r.PathValue("id"), service.GroupPatch{Name: req.Name, Description: req.Description})
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, group)
+}
+
+func (a *api) handleGroupsDelete(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := a.groupSvc.Delete(r.Context(), u.ID, r.PathValue("id")); err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) handleGroupsJoin(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req joinGroupRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	group, err := a.groupSvc.Join(r.Context(), u.ID, req.Code)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, group)
+}
+
+func (a *api) handleGroupsRotateJoinCode(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	group, err := a.groupSvc.RotateJoinCode(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, group)
+}
+
+func (a *api) handleGroupsMembers(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	members, err := a.groupSvc.Members(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, members)
+}
+
+func (a *api) handleGroupsSetRole(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req setGroupRoleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	role := domain.GroupRole(strings.ToLower(strings.TrimSpace(req.Role)))
+	if err := a.groupSvc.SetRole(r.Context(), u.ID, r.PathValue("id"), r.PathValue("userId"), role); err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	members, err := a.groupSvc.Members(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, members)
+}
+
+func (a *api) handleGroupsRemoveMember(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := a.groupSvc.RemoveMember(r.Context(), u.ID, r.PathValue("id"), r.PathValue("userId")); err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) handleGroupsInvite(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req groupInviteRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	invite, err := a.groupSvc.Invite(r.Context(), u.ID, r.PathValue("id"), req.Username)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, invite)
+}
+
+func (a *api) handleGroupsGroupInvites(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	invites, err := a.groupSvc.GroupInvites(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, invites)
+}
+
+func (a *api) handleGroupsInvites(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	invites, err := a.groupSvc.Invites(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, invites)
+}
+
+func (a *api) handleGroupsAcceptInvite(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	group, err := a.groupSvc.AcceptInvite(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, group)
+}
+
+func (a *api) handleGroupsDeclineInvite(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := a.groupSvc.DeclineInvite(r.Context(), u.ID, r.PathValue("id")); err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) handleGroupsMatches(w http.ResponseWriter, r *http.Request) {
+	a.writeMatchesList(w, r, strings.TrimSpace(r.PathValue("id")))
+}
+
+func (a *api) handleGroupsStats(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	stats, err := a.groupSvc.Stats(r.Context(), u.ID, r.PathValue("id"), domain.GameFormat(strings.TrimSpace(r.URL.Query().Get("format"))))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, stats)
+}
+
+// handleGroupsLeaderboard ranks the group's members by their rating in a format.
+func (a *api) handleGroupsLeaderboard(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	members, err := a.groupSvc.Members(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	others := make([]domain.UserSummary, 0, len(members))
+	for _, m := range members {
+		if m.User.ID != u.ID {
+			others = append(others, m.User)
+		}
+	}
+
+	self := domain.UserSummary{ID: u.ID, Username: u.Username, DisplayName: u.DisplayName, AvatarPath: u.AvatarPath, AvatarUpdatedAt: u.AvatarUpdatedAt}
+	board, err := a.ratingSvc.Leaderboard(r.Context(), self, domain.GameFormat(strings.TrimSpace(r.URL.Query().Get("format"))), others)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, board)
+}
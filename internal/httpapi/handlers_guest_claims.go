@@ -0,0 +1,82 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type createGuestClaimRequest struct {
+	GuestName string `json:"guest_name"`
+	GuestID   string `json:"guest_id"`
+	UserID    string `json:"user_id"`
+}
+
+type acceptGuestClaimResponse struct {
+	MatchesClaimed int `json:"matches_claimed"`
+}
+
+func (a *api) handleGuestClaimsCreate(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req createGuestClaimRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	claim, err := a.guestClaimSvc.Create(r.Context(), u.ID, req.GuestName, req.GuestID, req.UserID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, claim)
+}
+
+func (a *api) handleGuestClaimsList(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	overview, err := a.guestClaimSvc.List(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, overview)
+}
+
+func (a *api) handleGuestClaimsAccept(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	n, err := a.guestClaimSvc.Accept(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, acceptGuestClaimResponse{MatchesClaimed: n})
+}
+
+func (a *api) handleGuestClaimsDecline(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := a.guestClaimSvc.Decline(r.Context(), u.ID, r.PathValue("id")); err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
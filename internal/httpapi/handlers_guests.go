@@ -0,0 +1,132 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"MtgLeaderwebserver/internal/domain"
+	"MtgLeaderwebserver/internal/service"
+)
+
+type createGuestRequest struct {
+	DisplayName string   `json:"display_name"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+type updateGuestRequest struct {
+	DisplayName *string   `json:"display_name,omitempty"`
+	Aliases     *[]string `json:"aliases,omitempty"`
+}
+
+type mergeGuestsRequest struct {
+	SourceIDs []string `json:"source_ids"`
+}
+
+func (a *api) handleGuestsList(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	guests, err := a.guestSvc.List(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, guests)
+}
+
+func (a *api) handleGuestsCreate(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req createGuestRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	guest, err := a.guestSvc.Create(r.Context(), u.ID, req.DisplayName, req.Aliases)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, guest)
+}
+
+func (a *api) handleGuestsGet(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	guest, err := a.guestSvc.Get(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, guest)
+}
+
+func (a *api) handleGuestsUpdate(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req updateGuestRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	guest, err := a.guestSvc.Update(r.Context(), u.ID, r.PathValue("id"), service.GuestPatch{
+		DisplayName: req.DisplayName,
+		Aliases:     req.Aliases,
+	})
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, guest)
+}
+
+func (a *api) handleGuestsDelete(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := a.guestSvc.Delete(r.Context(), u.ID, r.PathValue("id")); err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) handleGuestsMerge(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req mergeGuestsRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	guest, err := a.guestSvc.Merge(r.Context(), u.ID, r.PathValue("id"), req.SourceIDs)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, guest)
+}
@@ -0,0 +1,334 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+	"MtgLeaderwebserver/internal/service"
+)
+
+// liveStreamPing keeps idle streams open through proxies that drop silent connections.
+const liveStreamPing = 15 * time.Second
+
+type createLiveMatchRequest struct {
+	Format            string                   `json:"format"`
+	GroupID           string                   `json:"group_id,omitempty"`
+	StartingSeatIndex *int                     `json:"starting_seat_index,omitempty"`
+	Players           []liveMatchPlayerRequest `json:"players"`
+}
+
+type liveMatchPlayerRequest struct {
+	SeatIndex    int      `json:"seat_index"`
+	UserID       string   `json:"user_id,omitempty"`
+	GuestName    string   `json:"guest_name,omitempty"`
+	GuestID      string   `json:"guest_id,omitempty"`
+	DisplayName  string   `json:"display_name,omitempty"`
+	DeckID       string   `json:"deck_id,omitempty"`
+	CommanderIDs []string `json:"commander_ids,omitempty"`
+}
+
+type liveMatchEventsRequest struct {
+	Events []liveMatchEventRequest `json:"events"`
+}
+
+type liveMatchEventRequest struct {
+	Kind            domain.MatchEventKind `json:"kind"`
+	SeatIndex       int                   `json:"seat_index"`
+	SourceSeatIndex *int                  `json:"source_seat_index,omitempty"`
+	Amount          int                   `json:"amount,omitempty"`
+	Total           *int                  `json:"total,omitempty"`
+}
+
+type finishLiveMatchRequest struct {
+	WinnerSeatIndex *int `json:"winner_seat_index,omitempty"`
+}
+
+type liveMatchClosedEvent struct {
+	Status  domain.LiveMatchStatus `json:"status"`
+	MatchID string                 `json:"match_id,omitempty"`
+}
+
+func (a *api) handleLiveMatchesCreate(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req createLiveMatchRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	players := make([]domain.MatchParticipantInput, 0, len(req.Players))
+	for _, p := range req.Players {
+		players = append(players, domain.MatchParticipantInput{
+			SeatIndex:    p.SeatIndex,
+			UserID:       p.UserID,
+			GuestName:    p.GuestName,
+			GuestID:      p.GuestID,
+			DisplayName:  p.DisplayName,
+			DeckID:       p.DeckID,
+			CommanderIDs: p.CommanderIDs,
+		})
+	}
+	lm, err := a.liveSvc.Create(r.Context(), u.ID, service.LiveMatchParams{
+		Format:            domain.GameFormat(req.Format),
+		GroupID:           req.GroupID,
+		StartingSeatIndex: req.StartingSeatIndex,
+		Players:           players,
+	})
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, lm)
+}
+
+func (a *api) handleLiveMatchesList(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	matches, err := a.liveSvc.List(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, matches)
+}
+
+func (a *api) handleLiveMatchesGet(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	lm, err := a.liveSvc.Get(r.Context(), u.ID, strings.TrimSpace(r.PathValue("id")))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, lm)
+}
+
+func (a *api) handleLiveMatchesJoin(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	lm, err := a.liveSvc.Join(r.Context(), u.ID, strings.TrimSpace(r.PathValue("id")))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, lm)
+}
+
+func (a *api) handleLiveMatchesEvents(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req liveMatchEventsRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+	events := make([]domain.MatchEvent, 0, len(req.Events))
+	for _, e := range req.Events {
+		events = append(events, domain.MatchEvent{
+			Kind:            e.Kind,
+			SeatIndex:       e.SeatIndex,
+			SourceSeatIndex: e.SourceSeatIndex,
+			Amount:          e.Amount,
+			Total:           e.Total,
+		})
+	}
+
+	stored, err := a.liveSvc.Update(r.Context(), u.ID, strings.TrimSpace(r.PathValue("id")), events)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, stored)
+}
+
+func (a *api) handleLiveMatchesFinish(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req finishLiveMatchRequest
+	if _, err := decodeJSONAllowEmpty(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	match, err := a.liveSvc.Finish(r.Context(), u.ID, strings.TrimSpace(r.PathValue("id")), req.WinnerSeatIndex)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, match)
+}
+
+func (a *api) handleLiveMatchesAbandon(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := a.liveSvc.Abandon(r.Context(), u.ID, strings.TrimSpace(r.PathValue("id"))); err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleLiveMatchesStream sends the live match as Server-Sent Events: a "state" snapshot, then an
+// "update" per logged event with its seq as the event id, and a final "closed" when the host
+// finishes or abandons it. Reconnecting with Last-Event-ID (or ?last_event_id=) resumes after that
+// seq instead of sending a new snapshot.
+func (a *api) handleLiveMatchesStream(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+	liveID := strings.TrimSpace(r.PathValue("id"))
+	lm, err := a.liveSvc.Get(ctx, u.ID, liveID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+
+	lastID := -1
+	raw := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if raw == "" {
+		raw = strings.TrimSpace(r.URL.Query().Get("last_event_id"))
+	}
+	if raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			WriteDomainError(w, domain.NewValidationError(map[string]string{"last_event_id": "must be a non-negative integer"}))
+			return
+		}
+		lastID = n
+	}
+
+	// Subscribe before reading so nothing written in between is missed.
+	changes, stop := a.liveSvc.Subscribe(liveID)
+	defer stop()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, "retry: 3000\n\n")
+
+	cursor := lastID
+	joined := lm.JoinedSeats()
+	if lastID < 0 || lastID > lm.Seq {
+		if err := writeSSE(w, strconv.Itoa(lm.Seq), "state", lm); err != nil {
+			return
+		}
+		cursor = lm.Seq
+	}
+
+	// send writes everything after cursor and reports whether the stream should stay open. Joins
+	// are not in the log, so a join sends a fresh snapshot instead.
+	send := func() bool {
+		c, err := a.liveSvc.Changes(ctx, liveID, cursor)
+		if err != nil {
+			if ctx.Err() == nil {
+				a.logger.Error("live stream: load changes failed", "live_match_id", liveID, "err", err)
+			}
+			return false
+		}
+		if c.Joined != joined {
+			lm, err := a.liveSvc.Get(ctx, u.ID, liveID)
+			if err != nil {
+				if ctx.Err() == nil {
+					a.logger.Error("live stream: load state failed", "live_match_id", liveID, "err", err)
+				}
+				return false
+			}
+			if err := writeSSE(w, strconv.Itoa(lm.Seq), "state", lm); err != nil {
+				return false
+			}
+			cursor = max(cursor, lm.Seq)
+			joined = lm.JoinedSeats()
+		}
+		for _, e := range c.Events {
+			if e.Seq <= cursor {
+				continue
+			}
+			if err := writeSSE(w, strconv.Itoa(e.Seq), "update", e); err != nil {
+				return false
+			}
+			cursor = e.Seq
+		}
+		if c.Status == domain.LiveMatchFinished || c.Status == domain.LiveMatchAbandoned {
+			_ = writeSSE(w, "", "closed", liveMatchClosedEvent{Status: c.Status, MatchID: c.MatchID})
+			_ = rc.Flush()
+			return false
+		}
+		return rc.Flush() == nil
+	}
+	if !send() {
+		return
+	}
+
+	ping := time.NewTicker(liveStreamPing)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ping.C:
+			// Updates sent to another server process only reach this stream through the store.
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil || !send() {
+				return
+			}
+		case <-changes:
+			if !send() {
+				return
+			}
+		}
+	}
+}
+
+func writeSSE(w io.Writer, id, event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
@@ -0,0 +1,118 @@
+package httpapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+	"MtgLeaderwebserver/internal/service"
+)
+
+type stubLiveMatchesStore struct {
+	match  domain.LiveMatch
+	events []domain.MatchEvent
+}
+
+func (s *stubLiveMatchesStore) CreateLiveMatch(ctx context.Context, in domain.LiveMatchInput, at time.Time) (string, error) {
+	return "", context.Canceled
+}
+
+func (s *stubLiveMatchesStore) GetLiveMatch(ctx context.Context, liveID string) (domain.LiveMatch, error) {
+	if liveID != s.match.ID {
+		return domain.LiveMatch{}, domain.ErrNotFound
+	}
+	return s.match, nil
+}
+
+func (s *stubLiveMatchesStore) ListLiveMatchesForUser(ctx context.Context, userID string) ([]domain.LiveMatch, error) {
+	return nil, nil
+}
+
+func (s *stubLiveMatchesStore) JoinLiveMatch(ctx context.Context, liveID, userID string, at time.Time) error {
+	return context.Canceled
+}
+
+func (s *stubLiveMatchesStore) AppendLiveMatchEvents(ctx context.Context, liveID, userID string, events []domain.MatchEvent, at time.Time) ([]domain.MatchEvent, error) {
+	return nil, context.Canceled
+}
+
+func (s *stubLiveMatchesStore) ListLiveMatchEvents(ctx context.Context, liveID string, afterSeq int) ([]domain.MatchEvent, error) {
+	var out []domain.MatchEvent
+	for _, e := range s.events {
+		if e.Seq > afterSeq {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubLiveMatchesStore) FreezeLiveMatch(ctx context.Context, liveID string) error {
+	return context.Canceled
+}
+
+func (s *stubLiveMatchesStore) ReopenLiveMatch(ctx context.Context, liveID string) error {
+	return context.Canceled
+}
+
+func (s *stubLiveMatchesStore) CloseLiveMatch(ctx context.Context, liveID, matchID string, at time.Time) error {
+	return context.Canceled
+}
+
+func TestLiveMatchStreamResumesFromLastEventID(t *testing.T) {
+	store := &stubLiveMatchesStore{
+		match: domain.LiveMatch{
+			ID:        "live-1",
+			CreatedBy: "u1",
+			Format:    domain.FormatCommander,
+			Status:    domain.LiveMatchFinished,
+			MatchID:   "match-1",
+			Seats:     []domain.LiveMatchSeat{{User: &domain.UserSummary{ID: "u1"}}},
+		},
+		events: []domain.MatchEvent{
+			{Seq: 1, Kind: domain.MatchEventTurnPass, Turn: 1},
+			{Seq: 2, Kind: domain.MatchEventLife, Turn: 1, Amount: -3},
+		},
+	}
+	a := &api{logger: slog.Default(), liveSvc: &service.LiveMatchService{Store: store, Hub: service.NewLiveHub()}}
+
+	stream := func(lastEventID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/live-matches/live-1/stream", nil)
+		req.SetPathValue("id", "live-1")
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		req = req.WithContext(context.WithValue(req.Context(), authUserKey, domain.User{ID: "u1"}))
+		rec := httptest.NewRecorder()
+		// Streams go through the API's buffering writer, which must pass them straight on.
+		brw := newBufferedResponseWriter(rec)
+		a.handleLiveMatchesStream(brw, req)
+		brw.flushTo(rec)
+		return rec
+	}
+
+	rec := stream("")
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/event-stream" || !rec.Flushed {
+		t.Fatalf("unexpected response: %d %q flushed=%v", rec.Code, rec.Header().Get("Content-Type"), rec.Flushed)
+	}
+	if !strings.Contains(body, "id: 2\nevent: state\n") || strings.Contains(body, "event: update") {
+		t.Fatalf("expected only a snapshot at seq 2:\n%s", body)
+	}
+	if !strings.Contains(body, "event: closed\ndata: {\"status\":\"finished\",\"match_id\":\"match-1\"}\n\n") {
+		t.Fatalf("expected a closed event:\n%s", body)
+	}
+
+	body = stream("1").Body.String()
+	if strings.Contains(body, "event: state") || !strings.Contains(body, "id: 2\nevent: update\n") || strings.Contains(body, "id: 1\n") {
+		t.Fatalf("expected to resume after seq 1:\n%s", body)
+	}
+
+	if rec := stream("x"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a bad Last-Event-ID, got %d", rec.Code)
+	}
+}
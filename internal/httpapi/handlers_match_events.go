@@ -0,0 +1,63 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type appendMatchEventsRequest struct {
+	Events []domain.MatchEvent `json:"events"`
+}
+
+func (a *api) handleMatchEventsList(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	events, err := a.matchEventSvc.List(r.Context(), u.ID, strings.TrimSpace(r.PathValue("id")))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, events)
+}
+
+func (a *api) handleMatchEventsAppend(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req appendMatchEventsRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	events, err := a.matchEventSvc.Append(r.Context(), u.ID, strings.TrimSpace(r.PathValue("id")), req.Events)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, events)
+}
+
+func (a *api) handleMatchReplay(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	turns, err := a.matchEventSvc.Replay(r.Context(), u.ID, strings.TrimSpace(r.PathValue("id")))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, turns)
+}
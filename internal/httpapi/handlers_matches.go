@@ -3,6 +3,7 @@ package httpapi
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
@@ -21,6 +22,7 @@ type createMatchRequest struct {
 	EndedAt              string                    `json:"ended_at"`
 	StartingSeatIndex    *int                      `json:"starting_seat_index,omitempty"`
 	Format               string                    `json:"format"`
+	GroupID              string                    `json:"group_id"`
 	TotalDurationSeconds int                       `json:"total_duration_seconds"`
 	TurnCount            int                       `json:"turn_count"`
 	ClientRef            string                    `json:"client_ref"`
@@ -28,6 +30,12 @@ type createMatchRequest struct {
 	UpdatedAt            string                    `json:"updated_at,omitempty"`
 	Results              []domain.MatchResultInput `json:"results"`
 	Players              []matchPlayerRequest      `json:"players"`
+	Outcome              domain.MatchOutcome       `json:"outcome,omitempty"`
+	BestOf               int                       `json:"best_of,omitempty"`
+	Games                []domain.MatchGame        `json:"games,omitempty"`
+	WinCondition         domain.WinCondition       `json:"win_condition,omitempty"`
+	WinConditionTag      string                    `json:"win_condition_tag,omitempty"`
+	WinningCard          string                    `json:"winning_card,omitempty"`
 }
 
 type createMatchResponse struct {
@@ -36,20 +44,39 @@ type createMatchResponse struct {
 	StatsSummary *domain.StatsSummary `json:"stats_summary,omitempty"`
 }
 
+type updateMatchRequest struct {
+	Format               string               `json:"format"`
+	TotalDurationSeconds int                  `json:"total_duration_seconds"`
+	TurnCount            int                  `json:"turn_count"`
+	StartingSeatIndex    *int                 `json:"starting_seat_index,omitempty"`
+	UpdatedAt            string               `json:"updated_at"`
+	Players              []matchPlayerRequest `json:"players"`
+	Outcome              domain.MatchOutcome  `json:"outcome,omitempty"`
+	BestOf               int                  `json:"best_of,omitempty"`
+	Games                []domain.MatchGame   `json:"games"`
+	WinCondition         optionalString       `json:"win_condition"`
+	WinConditionTag      optionalString       `json:"win_condition_tag"`
+	WinningCard          optionalString       `json:"winning_card"`
+}
+
 type matchPlayerRequest struct {
-	SeatIndex            int     `json:"seat_index"`
-	Seat                 *int    `json:"seat,omitempty"` // client-only
-	UserID               *string `json:"user_id,omitempty"`
-	GuestName            *string `json:"guest_name,omitempty"`
-	DisplayName          *string `json:"display_name,omitempty"`
-	ProfileName          *string `json:"profile_name,omitempty"` // client-only
-	Life                 *int    `json:"life,omitempty"`         // client-only
-	Counters             any     `json:"counters,omitempty"`     // client-only
-	Place                int     `json:"place"`
-	EliminatedTurnNumber *int    `json:"eliminated_turn_number,omitempty"`
-	EliminatedDuringSeat *int    `json:"eliminated_during_seat_index,omitempty"`
-	TotalTurnTimeMs      *int64  `json:"total_turn_time_ms,omitempty"`
-	TurnsTaken           *int    `json:"turns_taken,omitempty"`
+	SeatIndex            int      `json:"seat_index"`
+	Seat                 *int     `json:"seat,omitempty"` // client-only
+	UserID               *string  `json:"user_id,omitempty"`
+	GuestName            *string  `json:"guest_name,omitempty"`
+	GuestID              *string  `json:"guest_id,omitempty"`
+	DeckID               *string  `json:"deck_id,omitempty"`
+	CommanderIDs         []string `json:"commander_ids,omitempty"`
+	DisplayName          *string  `json:"display_name,omitempty"`
+	ProfileName          *string  `json:"profile_name,omitempty"` // client-only
+	Life                 *int     `json:"life,omitempty"`         // client-only
+	Counters             any      `json:"counters,omitempty"`     // client-only
+	Place                int      `json:"place"`
+	Team                 int      `json:"team,omitempty"`
+	EliminatedTurnNumber *int     `json:"eliminated_turn_number,omitempty"`
+	EliminatedDuringSeat *int     `json:"eliminated_during_seat_index,omitempty"`
+	TotalTurnTimeMs      *int64   `json:"total_turn_time_ms,omitempty"`
+	TurnsTaken           *int     `json:"turns_taken,omitempty"`
 }
 
 func (a *api) handleMatchesCreate(w http.ResponseWriter, r *http.Request) {
@@ -68,12 +95,48 @@ func (a *api) handleMatchesCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	params, err := createMatchParamsFromRequest(req)
+	if err != nil {
+		writeCreateMatchError(w, err)
+		return
+	}
+
+	match, result, err := a.matchSvc.CreateMatch(r.Context(), u.ID, params)
+	if err != nil {
+		var ve *domain.ValidationError
+		if errors.As(err, &ve) {
+			a.logger.Warn("matches: create validation failed", "fields", ve.Fields, "err", err)
+		} else {
+			a.logger.Error("matches: create failed", "err", err)
+		}
+		WriteDomainError(w, err)
+		return
+	}
+
+	if result == service.MatchCreateConflict {
+		WriteJSON(w, http.StatusOK, createMatchResponse{MatchID: match.ID, Match: &match})
+		return
+	}
+
+	summary, err := a.matchSvc.Summary(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, createMatchResponse{
+		MatchID:      match.ID,
+		Match:        &match,
+		StatsSummary: &summary,
+	})
+}
+
+func createMatchParamsFromRequest(req createMatchRequest) (service.CreateMatchParams, error) {
 	var updatedAt time.Time
 	if strings.TrimSpace(req.UpdatedAt) != "" {
 		parsed, err := parseUpdatedAt(req.UpdatedAt)
 		if err != nil {
-			WriteError(w, http.StatusBadRequest, "invalid_updated_at", "updated_at must be RFC3339 UTC with milliseconds")
-			return
+			return service.CreateMatchParams{}, errInvalidUpdatedAt
 		}
 		updatedAt = parsed
 	}
@@ -83,16 +146,14 @@ func (a *api) handleMatchesCreate(w http.ResponseWriter, r *http.Request) {
 	if clientMatchID == "" {
 		clientMatchID = clientRef
 	} else if clientRef != "" && clientRef != clientMatchID {
-		WriteDomainError(w, domain.NewValidationError(map[string]string{"client_match_id": "client_match_id must match client_ref"}))
-		return
+		return service.CreateMatchParams{}, domain.NewValidationError(map[string]string{"client_match_id": "client_match_id must match client_ref"})
 	}
 
 	var playedAt *time.Time
 	if strings.TrimSpace(req.PlayedAt) != "" {
 		t, err := time.Parse(time.RFC3339Nano, req.PlayedAt)
 		if err != nil {
-			WriteDomainError(w, domain.NewValidationError(map[string]string{"played_at": "must be RFC3339 timestamp"}))
-			return
+			return service.CreateMatchParams{}, domain.NewValidationError(map[string]string{"played_at": "must be RFC3339 timestamp"})
 		}
 		playedAt = &t
 	}
@@ -101,8 +162,7 @@ func (a *api) handleMatchesCreate(w http.ResponseWriter, r *http.Request) {
 	if strings.TrimSpace(req.StartedAt) != "" {
 		t, err := time.Parse(time.RFC3339Nano, req.StartedAt)
 		if err != nil {
-			WriteDomainError(w, domain.NewValidationError(map[string]string{"started_at": "must be RFC3339 timestamp"}))
-			return
+			return service.CreateMatchParams{}, domain.NewValidationError(map[string]string{"started_at": "must be RFC3339 timestamp"})
 		}
 		startedAt = &t
 	}
@@ -111,8 +171,7 @@ func (a *api) handleMatchesCreate(w http.ResponseWriter, r *http.Request) {
 	if strings.TrimSpace(req.EndedAt) != "" {
 		t, err := time.Parse(time.RFC3339Nano, req.EndedAt)
 		if err != nil {
-			WriteDomainError(w, domain.NewValidationError(map[string]string{"ended_at": "must be RFC3339 timestamp"}))
-			return
+			return service.CreateMatchParams{}, domain.NewValidationError(map[string]string{"ended_at": "must be RFC3339 timestamp"})
 		}
 		endedAt = &t
 	}
@@ -133,88 +192,317 @@ func (a *api) handleMatchesCreate(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	participants := make([]domain.MatchParticipantInput, 0, len(req.Players))
-	for _, p := range req.Players {
-		userID := normalizeOptionalString(derefString(p.UserID))
-		guestName := normalizeOptionalString(derefString(p.GuestName))
-		displayName := strings.TrimSpace(derefString(p.DisplayName))
-
-		participants = append(participants, domain.MatchParticipantInput{
-			SeatIndex:        p.SeatIndex,
-			UserID:           userID,
-			GuestName:        guestName,
-			DisplayName:      displayName,
-			Place:            p.Place,
-			EliminatedTurn:   p.EliminatedTurnNumber,
-			EliminatedDuring: p.EliminatedDuringSeat,
-			TotalTurnTimeMs:  p.TotalTurnTimeMs,
-			TurnsTaken:       p.TurnsTaken,
-		})
-	}
-
-	match, result, err := a.matchSvc.CreateMatch(r.Context(), u.ID, service.CreateMatchParams{
+	return service.CreateMatchParams{
 		StartedAt:            startedAt,
 		EndedAt:              endedAt,
 		PlayedAt:             playedAt,
 		WinnerID:             strings.TrimSpace(req.WinnerID),
 		PlayerIDs:            req.PlayerIDs,
 		Format:               domain.GameFormat(strings.TrimSpace(req.Format)),
+		GroupID:              strings.TrimSpace(req.GroupID),
 		TotalDurationSeconds: totalDurationSeconds,
 		TurnCount:            req.TurnCount,
+		StartingSeatIndex:    req.StartingSeatIndex,
 		ClientMatchID:        clientMatchID,
 		UpdatedAt:            updatedAt,
-		Players:              participants,
+		Players:              participantsFromRequest(req.Players),
 		Results:              req.Results,
+		Outcome:              req.Outcome,
+		BestOf:               req.BestOf,
+		Games:                req.Games,
+		WinCondition:         req.WinCondition,
+		WinConditionTag:      req.WinConditionTag,
+		WinningCard:          req.WinningCard,
+	}, nil
+}
+
+var errInvalidUpdatedAt = errors.New("invalid updated_at")
+
+func createMatchErrorResponse(err error) (int, apiError) {
+	if errors.Is(err, errInvalidUpdatedAt) {
+		return http.StatusBadRequest, apiError{Code: "invalid_updated_at", Message: "updated_at must be RFC3339 UTC with milliseconds"}
+	}
+	return domainErrorResponse(err)
+}
+
+func writeCreateMatchError(w http.ResponseWriter, err error) {
+	status, apiErr := createMatchErrorResponse(err)
+	WriteJSON(w, status, errorEnvelope{Error: apiErr})
+}
+
+const maxMatchBatchSize = 50
+
+type matchBatchItemResult struct {
+	Index         int           `json:"index"`
+	ClientMatchID string        `json:"client_match_id,omitempty"`
+	Status        string        `json:"status"`
+	MatchID       string        `json:"match_id,omitempty"`
+	Match         *domain.Match `json:"match,omitempty"`
+	Error         *apiError     `json:"error,omitempty"`
+}
+
+type matchBatchResponse struct {
+	Results      []matchBatchItemResult `json:"results"`
+	StatsSummary *domain.StatsSummary   `json:"stats_summary,omitempty"`
+}
+
+func (a *api) handleMatchesBatch(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var reqs []createMatchRequest
+	if err := decodeJSONAllowUnknownFields(w, r, &reqs); err != nil {
+		if a.logger != nil {
+			a.logger.Warn("matches: decode batch json failed", "err", err)
+		}
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+	if len(reqs) == 0 {
+		WriteDomainError(w, domain.NewValidationError(map[string]string{"matches": "required"}))
+		return
+	}
+	if len(reqs) > maxMatchBatchSize {
+		WriteDomainError(w, domain.NewValidationError(map[string]string{"matches": fmt.Sprintf("must have at most %d matches", maxMatchBatchSize)}))
+		return
+	}
+
+	results := make([]matchBatchItemResult, 0, len(reqs))
+	applied := 0
+	for i, req := range reqs {
+		item := matchBatchItemResult{
+			Index:         i,
+			ClientMatchID: strings.TrimSpace(req.ClientMatchID),
+		}
+		if item.ClientMatchID == "" {
+			item.ClientMatchID = strings.TrimSpace(req.ClientRef)
+		}
+
+		params, err := createMatchParamsFromRequest(req)
+		if err == nil {
+			var match domain.Match
+			var result service.MatchCreateResult
+			match, result, err = a.matchSvc.CreateMatch(r.Context(), u.ID, params)
+			if err == nil {
+				item.MatchID = match.ID
+				item.Match = &match
+				if result == service.MatchCreateApplied {
+					item.Status = "applied"
+					applied++
+				} else {
+					item.Status = "conflict"
+				}
+			}
+		}
+		if err != nil {
+			status, apiErr := createMatchErrorResponse(err)
+			if status >= http.StatusInternalServerError {
+				a.logger.Error("matches: batch create failed", "index", i, "err", err)
+			}
+			item.Status = "error"
+			item.Error = &apiErr
+		}
+		results = append(results, item)
+	}
+
+	resp := matchBatchResponse{Results: results}
+	if applied > 0 {
+		summary, err := a.matchSvc.Summary(r.Context(), u.ID)
+		if err != nil {
+			WriteDomainError(w, err)
+			return
+		}
+		resp.StatsSummary = &summary
+	}
+	WriteJSON(w, http.StatusOK, resp)
+}
+
+func (a *api) handleMatchesUpdate(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	matchID := strings.TrimSpace(r.PathValue("id"))
+	if matchID == "" {
+		WriteDomainError(w, domain.NewValidationError(map[string]string{"id": "required"}))
+		return
+	}
+
+	var req updateMatchRequest
+	if err := decodeJSONAllowUnknownFields(w, r, &req); err != nil {
+		if a.logger != nil {
+			a.logger.Warn("matches: decode json failed", "err", err)
+		}
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	updatedAt, err := parseUpdatedAt(req.UpdatedAt)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "invalid_updated_at", "updated_at must be RFC3339 UTC with milliseconds")
+		return
+	}
+
+	match, result, err := a.matchSvc.UpdateMatch(r.Context(), u.ID, matchID, service.UpdateMatchParams{
+		Format:               domain.GameFormat(strings.TrimSpace(req.Format)),
+		TotalDurationSeconds: req.TotalDurationSeconds,
+		TurnCount:            req.TurnCount,
+		StartingSeatIndex:    req.StartingSeatIndex,
+		UpdatedAt:            updatedAt,
+		Players:              participantsFromRequest(req.Players),
+		Outcome:              req.Outcome,
+		BestOf:               req.BestOf,
+		Games:                req.Games,
+		WinCondition:         req.WinCondition.ptr(),
+		WinConditionTag:      req.WinConditionTag.ptr(),
+		WinningCard:          req.WinningCard.ptr(),
 	})
 	if err != nil {
-		var ve *domain.ValidationError
-		if errors.As(err, &ve) {
-			a.logger.Warn("matches: create validation failed", "fields", ve.Fields, "err", err)
-		} else {
-			a.logger.Error("matches: create failed", "err", err)
-		}
 		WriteDomainError(w, err)
 		return
 	}
 
-	if result == service.MatchCreateConflict {
-		WriteJSON(w, http.StatusOK, createMatchResponse{MatchID: match.ID, Match: &match})
+	if result == service.MatchUpdateConflict {
+		WriteJSON(w, http.StatusConflict, match)
 		return
 	}
 
-	summary, err := a.matchSvc.Summary(r.Context(), u.ID)
+	WriteJSON(w, http.StatusOK, match)
+}
+
+func (a *api) handleMatchesDelete(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	matchID := strings.TrimSpace(r.PathValue("id"))
+	if matchID == "" {
+		WriteDomainError(w, domain.NewValidationError(map[string]string{"id": "required"}))
+		return
+	}
+
+	if err := a.matchSvc.DeleteMatch(r.Context(), u.ID, matchID); err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type disputeMatchRequest struct {
+	Reason string `json:"reason"`
+}
+
+type setMatchDeckRequest struct {
+	DeckID *string `json:"deck_id"`
+}
+
+func (a *api) handleMatchesConfirm(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	matchID := strings.TrimSpace(r.PathValue("id"))
+	if matchID == "" {
+		WriteDomainError(w, domain.NewValidationError(map[string]string{"id": "required"}))
+		return
+	}
+
+	match, err := a.matchSvc.ConfirmMatch(r.Context(), u.ID, matchID)
 	if err != nil {
 		WriteDomainError(w, err)
 		return
 	}
+	WriteJSON(w, http.StatusOK, match)
+}
 
-	WriteJSON(w, http.StatusCreated, createMatchResponse{
-		MatchID:      match.ID,
-		Match:        &match,
-		StatsSummary: &summary,
-	})
+func (a *api) handleMatchesDispute(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	matchID := strings.TrimSpace(r.PathValue("id"))
+	if matchID == "" {
+		WriteDomainError(w, domain.NewValidationError(map[string]string{"id": "required"}))
+		return
+	}
+
+	var req disputeMatchRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	match, err := a.matchSvc.DisputeMatch(r.Context(), u.ID, matchID, req.Reason)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, match)
 }
 
 func (a *api) handleMatchesList(w http.ResponseWriter, r *http.Request) {
+	a.writeMatchesList(w, r, "")
+}
+
+// writeMatchesList serves the caller's matches, or a group's matches when groupID is set.
+func (a *api) writeMatchesList(w http.ResponseWriter, r *http.Request, groupID string) {
 	u, ok := CurrentUser(r.Context())
 	if !ok {
 		WriteDomainError(w, domain.ErrUnauthorized)
 		return
 	}
 
-	limit := 25
-	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+	query := r.URL.Query()
+	params := service.ListMatchesParams{
+		GroupID:    groupID,
+		Cursor:     strings.TrimSpace(query.Get("cursor")),
+		Format:     domain.GameFormat(strings.TrimSpace(query.Get("format"))),
+		OpponentID: strings.TrimSpace(query.Get("opponent_id")),
+		GuestName:  strings.TrimSpace(query.Get("guest_name")),
+		Result:     domain.MatchResultFilter(strings.ToLower(strings.TrimSpace(query.Get("result")))),
+	}
+	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
 		if n, err := strconv.Atoi(raw); err == nil {
-			limit = n
+			params.Limit = n
+		}
+	}
+	if raw := strings.TrimSpace(query.Get("from")); raw != "" {
+		t, err := parseDateParam(raw, false)
+		if err != nil {
+			WriteDomainError(w, domain.NewValidationError(map[string]string{"from": "must be RFC3339 timestamp or YYYY-MM-DD"}))
+			return
+		}
+		params.From = &t
+	}
+	if raw := strings.TrimSpace(query.Get("to")); raw != "" {
+		t, err := parseDateParam(raw, true)
+		if err != nil {
+			WriteDomainError(w, domain.NewValidationError(map[string]string{"to": "must be RFC3339 timestamp or YYYY-MM-DD"}))
+			return
 		}
+		params.To = &t
 	}
 
-	matches, err := a.matchSvc.ListMatches(r.Context(), u.ID, limit)
+	matches, nextCursor, err := a.matchSvc.ListMatches(r.Context(), u.ID, params)
 	if err != nil {
 		WriteDomainError(w, err)
 		return
 	}
 
+	if nextCursor != "" {
+		w.Header().Set("X-Next-Cursor", nextCursor)
+	}
 	WriteJSON(w, http.StatusOK, matches)
 }
 
@@ -240,6 +528,50 @@ func (a *api) handleMatchesGet(w http.ResponseWriter, r *http.Request) {
 	WriteJSON(w, http.StatusOK, match)
 }
 
+// parseDateParam accepts an RFC3339 timestamp or a YYYY-MM-DD date. A bare date used as
+// an upper bound is treated as inclusive, so it resolves to the start of the following day.
+func parseDateParam(raw string, upperBound bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if upperBound {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func participantsFromRequest(players []matchPlayerRequest) []domain.MatchParticipantInput {
+	participants := make([]domain.MatchParticipantInput, 0, len(players))
+	for _, p := range players {
+		userID := normalizeOptionalString(derefString(p.UserID))
+		guestName := normalizeOptionalString(derefString(p.GuestName))
+		guestID := normalizeOptionalString(derefString(p.GuestID))
+		deckID := normalizeOptionalString(derefString(p.DeckID))
+		displayName := strings.TrimSpace(derefString(p.DisplayName))
+
+		participants = append(participants, domain.MatchParticipantInput{
+			SeatIndex:        p.SeatIndex,
+			UserID:           userID,
+			GuestName:        guestName,
+			GuestID:          guestID,
+			DeckID:           deckID,
+			CommanderIDs:     p.CommanderIDs,
+			DisplayName:      displayName,
+			Place:            p.Place,
+			Team:             p.Team,
+			EliminatedTurn:   p.EliminatedTurnNumber,
+			EliminatedDuring: p.EliminatedDuringSeat,
+			TotalTurnTimeMs:  p.TotalTurnTimeMs,
+			TurnsTaken:       p.TurnsTaken,
+		})
+	}
+	return participants
+}
+
 func jsonDecodeErrorMessage(err error) string {
 	if err == nil {
 		return "invalid json"
@@ -294,3 +626,30 @@ func normalizeOptionalString(v string) string {
 		return v
 	}
 }
+
+func (a *api) handleMatchesSetDeck(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	matchID := strings.TrimSpace(r.PathValue("id"))
+	if matchID == "" {
+		WriteDomainError(w, domain.NewValidationError(map[string]string{"id": "required"}))
+		return
+	}
+
+	var req setMatchDeckRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	match, err := a.matchSvc.SetDeck(r.Context(), u.ID, matchID, derefString(req.DeckID))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, match)
+}
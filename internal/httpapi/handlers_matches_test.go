@@ -17,13 +17,28 @@ type stubMatchesStore struct {
 	t *testing.T
 
 	getMatchByClientRefFunc func(context.Context, string, string) (domain.Match, error)
+	getMatchForUserFunc     func(context.Context, string, string) (domain.Match, error)
+	updateMatchFunc         func(context.Context, string, time.Time) (bool, error)
 }
 
-func (s *stubMatchesStore) CreateMatch(ctx context.Context, createdBy string, startedAt, endedAt, playedAt *time.Time, winnerID string, participants []domain.MatchParticipantInput, format domain.GameFormat, totalDurationSeconds, turnCount int, clientRef string, updatedAt time.Time) (string, bool, error) {
+func (s *stubMatchesStore) CreateMatch(ctx context.Context, in domain.MatchInput) (string, bool, error) {
 	s.t.Fatalf("CreateMatch called unexpectedly")
 	return "", false, context.Canceled
 }
 
+func (s *stubMatchesStore) UpdateMatch(ctx context.Context, matchID string, in domain.MatchInput) (bool, error) {
+	if s.updateMatchFunc != nil {
+		return s.updateMatchFunc(ctx, matchID, in.UpdatedAt)
+	}
+	s.t.Fatalf("UpdateMatch called unexpectedly")
+	return false, context.Canceled
+}
+
+func (s *stubMatchesStore) DeleteMatch(ctx context.Context, matchID, createdBy string, deletedAt time.Time) error {
+	s.t.Fatalf("DeleteMatch called unexpectedly")
+	return context.Canceled
+}
+
 func (s *stubMatchesStore) GetMatchByClientRef(ctx context.Context, createdBy, clientRef string) (domain.Match, error) {
 	if s.getMatchByClientRefFunc != nil {
 		return s.getMatchByClientRefFunc(ctx, createdBy, clientRef)
@@ -32,11 +47,14 @@ func (s *stubMatchesStore) GetMatchByClientRef(ctx context.Context, createdBy, c
 	return domain.Match{}, context.Canceled
 }
 
-func (s *stubMatchesStore) ListMatchesForUser(ctx context.Context, userID string, limit int) ([]domain.Match, error) {
+func (s *stubMatchesStore) ListMatchesForUser(ctx context.Context, userID string, filter domain.MatchListFilter) ([]domain.Match, error) {
 	return nil, nil
 }
 
 func (s *stubMatchesStore) GetMatchForUser(ctx context.Context, userID, matchID string) (domain.Match, error) {
+	if s.getMatchForUserFunc != nil {
+		return s.getMatchForUserFunc(ctx, userID, matchID)
+	}
 	s.t.Fatalf("GetMatchForUser called unexpectedly")
 	return domain.Match{}, context.Canceled
 }
@@ -49,6 +67,32 @@ func (s *stubMatchesStore) HeadToHead(ctx context.Context, userID, opponentID st
 	return domain.HeadToHeadStats{}, nil
 }
 
+func (s *stubMatchesStore) SeatStats(ctx context.Context, userID string) (domain.SeatStats, error) {
+	return domain.SeatStats{}, nil
+}
+
+func (s *stubMatchesStore) GameStats(ctx context.Context, userID string) (domain.GameStats, error) {
+	return domain.GameStats{}, nil
+}
+
+func (s *stubMatchesStore) EliminationStats(ctx context.Context, userID string) (domain.EliminationStats, error) {
+	return domain.EliminationStats{}, nil
+}
+
+func (s *stubMatchesStore) TurnTimeStats(ctx context.Context, userID string) (domain.TurnTimeStats, error) {
+	return domain.TurnTimeStats{}, nil
+}
+
+func (s *stubMatchesStore) SetParticipantConfirmation(ctx context.Context, matchID, userID string, state domain.MatchConfirmation, reason string, at time.Time) error {
+	s.t.Fatalf("SetParticipantConfirmation called unexpectedly")
+	return context.Canceled
+}
+
+func (s *stubMatchesStore) SetParticipantDeck(ctx context.Context, matchID, userID, deckID string) error {
+	s.t.Fatalf("SetParticipantDeck called unexpectedly")
+	return context.Canceled
+}
+
 func TestMatchesCreateInvalidUpdatedAt(t *testing.T) {
 	store := &stubMatchesStore{t: t}
 	api := &api{
@@ -124,3 +168,174 @@ func TestMatchesCreateConflictReturnsMatch(t *testing.T) {
 		t.Fatalf("expected match to be returned, got %#v", resp.Match)
 	}
 }
+
+func TestMatchesCreateReturnsDeletedMatch(t *testing.T) {
+	deletedAt := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	store := &stubMatchesStore{
+		t: t,
+		getMatchByClientRefFunc: func(_ context.Context, createdBy, clientRef string) (domain.Match, error) {
+			return domain.Match{ID: "match-1", CreatedBy: "u1", ClientMatchID: clientRef, DeletedAt: &deletedAt}, nil
+		},
+	}
+	api := &api{
+		matchSvc: &service.MatchService{Matches: store},
+	}
+
+	body := `{"updated_at":"2025-01-01T12:00:00.000Z","client_match_id":"client-1"}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/matches", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), authUserKey, domain.User{ID: "u1"}))
+
+	rr := httptest.NewRecorder()
+	api.handleMatchesCreate(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", rr.Code)
+	}
+
+	var resp createMatchResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Match == nil || resp.Match.DeletedAt == nil || !resp.Match.DeletedAt.Equal(deletedAt) {
+		t.Fatalf("expected tombstone to be returned, got %#v", resp.Match)
+	}
+}
+
+func TestMatchesUpdateRequiresUpdatedAt(t *testing.T) {
+	store := &stubMatchesStore{t: t}
+	api := &api{
+		matchSvc: &service.MatchService{Matches: store},
+	}
+
+	body := `{"players":[{"seat_index":0,"user_id":"u1","place":1},{"seat_index":1,"guest_name":"Guest","place":2}]}`
+	req := httptest.NewRequest(http.MethodPatch, "/v1/matches/match-1", strings.NewReader(body))
+	req.SetPathValue("id", "match-1")
+	req = req.WithContext(context.WithValue(req.Context(), authUserKey, domain.User{ID: "u1"}))
+
+	rr := httptest.NewRecorder()
+	api.handleMatchesUpdate(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: %d", rr.Code)
+	}
+
+	var resp errorEnvelope
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Error.Code != "invalid_updated_at" {
+		t.Fatalf("unexpected error code: %s", resp.Error.Code)
+	}
+}
+
+func TestMatchesUpdateConflictReturnsCurrentMatch(t *testing.T) {
+	current := domain.Match{
+		ID:        "match-1",
+		CreatedBy: "u1",
+		UpdatedAt: time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC),
+		Format:    domain.FormatCommander,
+	}
+
+	store := &stubMatchesStore{
+		t: t,
+		getMatchForUserFunc: func(_ context.Context, userID, matchID string) (domain.Match, error) {
+			if userID != "u1" || matchID != "match-1" {
+				t.Fatalf("unexpected match lookup: %s %s", userID, matchID)
+			}
+			return current, nil
+		},
+		updateMatchFunc: func(_ context.Context, matchID string, updatedAt time.Time) (bool, error) {
+			return false, nil
+		},
+	}
+	api := &api{
+		matchSvc: &service.MatchService{Matches: store},
+	}
+
+	body := `{"updated_at":"2025-01-01T12:00:00.000Z","players":[{"seat_index":0,"user_id":"u1","place":1},{"seat_index":1,"guest_name":"Guest","place":2}]}`
+	req := httptest.NewRequest(http.MethodPatch, "/v1/matches/match-1", strings.NewReader(body))
+	req.SetPathValue("id", "match-1")
+	req = req.WithContext(context.WithValue(req.Context(), authUserKey, domain.User{ID: "u1"}))
+
+	rr := httptest.NewRecorder()
+	api.handleMatchesUpdate(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("unexpected status: %d", rr.Code)
+	}
+
+	var resp domain.Match
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.ID != "match-1" || !resp.UpdatedAt.Equal(current.UpdatedAt) {
+		t.Fatalf("expected current match to be returned, got %#v", resp)
+	}
+}
+
+func TestMatchesUpdateRequestWinConditionFields(t *testing.T) {
+	var req updateMatchRequest
+	if err := json.Unmarshal([]byte(`{"win_condition":"combo","win_condition_tag":null}`), &req); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if v := req.WinCondition.ptr(); v == nil || *v != "combo" {
+		t.Fatalf("expected win_condition combo, got %v", v)
+	}
+	if v := req.WinConditionTag.ptr(); v == nil || *v != "" {
+		t.Fatalf("expected null win_condition_tag to clear, got %v", v)
+	}
+	if v := req.WinningCard.ptr(); v != nil {
+		t.Fatalf("expected omitted winning_card to be kept, got %q", *v)
+	}
+}
+
+func TestMatchesBatchReportsPerItemResults(t *testing.T) {
+	existing := domain.Match{ID: "match-1", CreatedBy: "u1", ClientMatchID: "client-1"}
+	store := &stubMatchesStore{
+		t: t,
+		getMatchByClientRefFunc: func(_ context.Context, createdBy, clientRef string) (domain.Match, error) {
+			if clientRef == "client-1" {
+				return existing, nil
+			}
+			return domain.Match{}, domain.ErrNotFound
+		},
+	}
+	api := &api{
+		matchSvc: &service.MatchService{Matches: store},
+	}
+
+	body := `[
+		{"client_match_id":"client-1","updated_at":"2025-01-01T12:00:00.000Z"},
+		{"client_match_id":"client-2","updated_at":"bad"},
+		{"client_match_id":"client-3","players":[{"seat_index":0,"user_id":"u1","place":1}]}
+	]`
+	req := httptest.NewRequest(http.MethodPost, "/v1/matches/batch", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), authUserKey, domain.User{ID: "u1"}))
+
+	rr := httptest.NewRecorder()
+	api.handleMatchesBatch(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", rr.Code)
+	}
+
+	var resp matchBatchResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(resp.Results))
+	}
+	if resp.Results[0].Status != "conflict" || resp.Results[0].MatchID != "match-1" {
+		t.Fatalf("unexpected first result: %#v", resp.Results[0])
+	}
+	if resp.Results[1].Status != "error" || resp.Results[1].Error == nil || resp.Results[1].Error.Code != "invalid_updated_at" {
+		t.Fatalf("unexpected second result: %#v", resp.Results[1])
+	}
+	if resp.Results[2].Status != "error" || resp.Results[2].Error == nil || resp.Results[2].Error.Fields["players"] == "" {
+		t.Fatalf("unexpected third result: %#v", resp.Results[2])
+	}
+	if resp.StatsSummary != nil {
+		t.Fatal("expected no stats summary when nothing was applied")
+	}
+}
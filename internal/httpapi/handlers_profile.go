@@ -71,6 +71,58 @@ func (a *api) handleUsersMeUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+type statsSettingsRequest struct {
+	ConfirmedOnly *bool `json:"confirmed_only"`
+}
+
+func (a *api) handleUsersMeStatsSettings(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+	if a.profileSvc == nil {
+		WriteError(w, http.StatusServiceUnavailable, "profile_unavailable", "profile unavailable")
+		return
+	}
+
+	settings, err := a.profileSvc.StatsSettings(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, settings)
+}
+
+func (a *api) handleUsersMeStatsSettingsUpdate(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+	if a.profileSvc == nil {
+		WriteError(w, http.StatusServiceUnavailable, "profile_unavailable", "profile unavailable")
+		return
+	}
+
+	var req statsSettingsRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", "invalid json")
+		return
+	}
+	if req.ConfirmedOnly == nil {
+		WriteDomainError(w, domain.NewValidationError(map[string]string{"confirmed_only": "required"}))
+		return
+	}
+
+	settings, err := a.profileSvc.UpdateStatsSettings(r.Context(), u.ID, domain.StatsSettings{ConfirmedOnly: *req.ConfirmedOnly})
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, settings)
+}
+
 func (a *api) handleUsersMeAvatar(w http.ResponseWriter, r *http.Request) {
 	u, ok := CurrentUser(r.Context())
 	if !ok {
@@ -0,0 +1,73 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+func (a *api) handleRatingsList(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	ratings, err := a.ratingSvc.Ratings(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, ratings)
+}
+
+func (a *api) handleRatingsHistory(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := 0
+	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil {
+			limit = n
+		}
+	}
+
+	history, err := a.ratingSvc.History(r.Context(), u.ID, domain.GameFormat(strings.TrimSpace(query.Get("format"))), limit)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, history)
+}
+
+func (a *api) handleRatingsLeaderboard(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var friends []domain.UserSummary
+	if a.friendsSvc != nil {
+		overview, err := a.friendsSvc.ListOverview(r.Context(), u.ID)
+		if err != nil {
+			WriteDomainError(w, err)
+			return
+		}
+		friends = overview.Friends
+	}
+
+	self := domain.UserSummary{ID: u.ID, Username: u.Username, DisplayName: u.DisplayName, AvatarPath: u.AvatarPath, AvatarUpdatedAt: u.AvatarUpdatedAt}
+	board, err := a.ratingSvc.Leaderboard(r.Context(), self, domain.GameFormat(strings.TrimSpace(r.URL.Query().Get("format"))), friends)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, board)
+}
@@ -0,0 +1,199 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+	"MtgLeaderwebserver/internal/service"
+)
+
+type createSeasonRequest struct {
+	GroupID  string `json:"group_id"`
+	Name     string `json:"name"`
+	Format   string `json:"format"`
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at"`
+	Points   []int  `json:"points"`
+}
+
+type updateSeasonRequest struct {
+	Name     *string `json:"name,omitempty"`
+	Format   *string `json:"format,omitempty"`
+	StartsAt *string `json:"starts_at,omitempty"`
+	EndsAt   *string `json:"ends_at,omitempty"`
+	Points   []int   `json:"points,omitempty"`
+}
+
+func (a *api) handleSeasonsCreate(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req createSeasonRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	fields := make(map[string]string)
+	startsAt := parseSeasonDate(req.StartsAt, false, "starts_at", fields)
+	endsAt := parseSeasonDate(req.EndsAt, true, "ends_at", fields)
+	if len(fields) > 0 {
+		WriteDomainError(w, domain.NewValidationError(fields))
+		return
+	}
+
+	season, err := a.seasonSvc.Create(r.Context(), u.ID, service.SeasonParams{
+		GroupID:  req.GroupID,
+		Name:     req.Name,
+		Format:   domain.GameFormat(strings.TrimSpace(req.Format)),
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+		Points:   req.Points,
+	})
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, season)
+}
+
+func (a *api) handleSeasonsList(w http.ResponseWriter, r *http.Request) {
+	a.writeSeasons(w, r, false)
+}
+
+func (a *api) handleSeasonsArchive(w http.ResponseWriter, r *http.Request) {
+	a.writeSeasons(w, r, true)
+}
+
+func (a *api) writeSeasons(w http.ResponseWriter, r *http.Request, archived bool) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	seasons, err := a.seasonSvc.List(r.Context(), u.ID, archived)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, seasons)
+}
+
+func (a *api) handleSeasonsGet(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	season, err := a.seasonSvc.Get(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, season)
+}
+
+func (a *api) handleSeasonsUpdate(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	var req updateSeasonRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, "bad_json", jsonDecodeErrorMessage(err))
+		return
+	}
+
+	patch := service.SeasonPatch{Name: req.Name, Points: req.Points}
+	if req.Format != nil {
+		format := domain.GameFormat(strings.TrimSpace(*req.Format))
+		patch.Format = &format
+	}
+	fields := make(map[string]string)
+	if req.StartsAt != nil {
+		t := parseSeasonDate(*req.StartsAt, false, "starts_at", fields)
+		patch.StartsAt = &t
+	}
+	if req.EndsAt != nil {
+		t := parseSeasonDate(*req.EndsAt, true, "ends_at", fields)
+		patch.EndsAt = &t
+	}
+	if len(fields) > 0 {
+		WriteDomainError(w, domain.NewValidationError(fields))
+		return
+	}
+
+	season, err := a.seasonSvc.Update(r.Context(), u.ID, r.PathValue("id"), patch)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, season)
+}
+
+func (a *api) handleSeasonsDelete(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := a.seasonSvc.Delete(r.Context(), u.ID, r.PathValue("id")); err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) handleSeasonsStandings(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	standings, err := a.seasonSvc.Standings(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, standings)
+}
+
+func (a *api) handleSeasonsClose(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	standings, err := a.seasonSvc.Archive(r.Context(), u.ID, r.PathValue("id"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, standings)
+}
+
+// parseSeasonDate accepts RFC3339 or YYYY-MM-DD. A date-only ends_at covers that whole day.
+func parseSeasonDate(raw string, upperBound bool, field string, fields map[string]string) time.Time {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}
+	}
+	t, err := parseDateParam(raw, upperBound)
+	if err != nil {
+		fields[field] = "must be RFC3339 timestamp or YYYY-MM-DD"
+		return time.Time{}
+	}
+	return t
+}
@@ -44,6 +44,66 @@ func (a *api) handleStatsHeadToHead(w http.ResponseWriter, r *http.Request) {
 	WriteJSON(w, http.StatusOK, stats)
 }
 
+func (a *api) handleStatsSeats(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	stats, err := a.matchSvc.Seats(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, stats)
+}
+
+func (a *api) handleStatsGames(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	stats, err := a.matchSvc.Games(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, stats)
+}
+
+func (a *api) handleStatsEliminations(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	stats, err := a.matchSvc.Eliminations(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, stats)
+}
+
+func (a *api) handleStatsTurnTimes(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	stats, err := a.matchSvc.TurnTimes(r.Context(), u.ID)
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, stats)
+}
+
 func (a *api) handleStatsFriends(w http.ResponseWriter, r *http.Request) {
 	u, ok := CurrentUser(r.Context())
 	if !ok {
@@ -69,11 +129,15 @@ func (a *api) handleStatsFriends(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		items = append(items, domain.FriendStatsListItem{
-			Friend:   friend,
-			Total:    stats.Total,
-			Wins:     stats.Wins,
-			Losses:   stats.Losses,
-			CoLosses: stats.CoLosses,
+			Friend:       friend,
+			Total:        stats.Total,
+			Wins:         stats.Wins,
+			Losses:       stats.Losses,
+			Draws:        stats.Draws,
+			CoLosses:     stats.CoLosses,
+			AsTeammates:  stats.AsTeammates,
+			WinsTogether: stats.WinsTogether,
+			TurnTime:     stats.TurnTime,
 		})
 	}
 
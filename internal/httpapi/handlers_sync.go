@@ -0,0 +1,54 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type syncResponse struct {
+	Cursor          string                    `json:"cursor"`
+	HasMore         bool                      `json:"has_more"`
+	Profile         *userResponse             `json:"profile,omitempty"`
+	Friends         []domain.FriendConnection `json:"friends"`
+	Matches         []domain.Match            `json:"matches"`
+	RemovedMatchIDs []string                  `json:"removed_match_ids"`
+}
+
+func (a *api) handleSync(w http.ResponseWriter, r *http.Request) {
+	u, ok := CurrentUser(r.Context())
+	if !ok {
+		WriteDomainError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	changes, cursor, err := a.syncSvc.Changes(r.Context(), u.ID, r.URL.Query().Get("cursor"))
+	if err != nil {
+		WriteDomainError(w, err)
+		return
+	}
+
+	resp := syncResponse{
+		Cursor:          cursor,
+		HasMore:         changes.HasMore,
+		Friends:         changes.Friends,
+		Matches:         changes.Matches,
+		RemovedMatchIDs: changes.RemovedMatchIDs,
+	}
+	if changes.Profile != nil {
+		profile := newUserResponse(*changes.Profile, nil)
+		resp.Profile = &profile
+	}
+	if resp.Friends == nil {
+		resp.Friends = []domain.FriendConnection{}
+	}
+	if resp.Matches == nil {
+		resp.Matches = []domain.Match{}
+	}
+	if resp.RemovedMatchIDs == nil {
+		resp.RemovedMatchIDs = []string{}
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	WriteJSON(w, http.StatusOK, resp)
+}
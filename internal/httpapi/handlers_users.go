@@ -29,7 +29,11 @@ type userResponse struct {
 
 func writeUser(w http.ResponseWriter, status int, u domain.User, stats *domain.StatsSummary) {
 	w.Header().Set("ETag", userETag(u))
-	resp := userResponse{
+	WriteJSON(w, status, newUserResponse(u, stats))
+}
+
+func newUserResponse(u domain.User, stats *domain.StatsSummary) userResponse {
+	return userResponse{
 		ID:              u.ID,
 		Email:           u.Email,
 		Username:        u.Username,
@@ -42,7 +46,6 @@ func writeUser(w http.ResponseWriter, status int, u domain.User, stats *domain.S
 		UpdatedAt:       formatMillis(u.UpdatedAt),
 		StatsSummary:    stats,
 	}
-	WriteJSON(w, status, resp)
 }
 
 func (a *api) handleUsersMe(w http.ResponseWriter, r *http.Request) {
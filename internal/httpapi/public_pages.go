@@ -264,7 +264,7 @@ var publicWikiDeleteAccountBody = template.HTML(`
 <section class="rounded-3xl border border-white/10 bg-white/5 p-6 shadow-sm">
   <span class="inline-flex items-center rounded-full border border-rose-500/30 bg-rose-500/10 px-3 py-1 text-xs font-semibold uppercase tracking-[0.25em] text-rose-200">Wiki</span>
   <h1 class="mt-4 font-['Space_Grotesk'] text-3xl font-bold tracking-tight text-slate-50">Delete your account</h1>
-  <p class="mt-4 text-sm leading-6 text-slate-300">You can permanently delete your MTG Leader account from the web profile page. This removes your profile, matches, and friends.</p>
+  <p class="mt-4 text-sm leading-6 text-slate-300">You can permanently delete your MTG Leader account from the web profile page. This removes your profile, friends, and your own stats. Matches you played with other people stay in their history, with your seat shown as "Deleted player".</p>
 
   <h2 class="mt-8 font-['Space_Grotesk'] text-xl font-bold text-slate-50">Steps</h2>
   <ul class="mt-3 list-disc space-y-2 pl-6 text-sm leading-6 text-slate-300">
@@ -29,6 +29,11 @@ func WriteJSON(w http.ResponseWriter, status int, v any) {
 }
 
 func WriteDomainError(w http.ResponseWriter, err error) {
+	status, apiErr := domainErrorResponse(err)
+	WriteJSON(w, status, errorEnvelope{Error: apiErr})
+}
+
+func domainErrorResponse(err error) (int, apiError) {
 	switch {
 	case errors.Is(err, domain.ErrValidation):
 		apiErr := apiError{Code: "validation_error", Message: "invalid request"}
@@ -36,30 +41,40 @@ func WriteDomainError(w http.ResponseWriter, err error) {
 		if errors.As(err, &ve) && len(ve.Fields) > 0 {
 			apiErr.Fields = ve.Fields
 		}
-		WriteJSON(w, http.StatusBadRequest, errorEnvelope{Error: apiErr})
+		return http.StatusBadRequest, apiErr
 	case errors.Is(err, domain.ErrUsernameTaken):
-		WriteError(w, http.StatusConflict, "username_taken", "username already taken")
+		return http.StatusConflict, apiError{Code: "username_taken", Message: "username already taken"}
 	case errors.Is(err, domain.ErrEmailTaken):
-		WriteError(w, http.StatusConflict, "email_taken", "email already taken")
+		return http.StatusConflict, apiError{Code: "email_taken", Message: "email already taken"}
 	case errors.Is(err, domain.ErrInvalidCredentials):
-		WriteError(w, http.StatusUnauthorized, "invalid_credentials", "invalid login or password")
+		return http.StatusUnauthorized, apiError{Code: "invalid_credentials", Message: "invalid login or password"}
 	case errors.Is(err, domain.ErrUnauthorized):
-		WriteError(w, http.StatusUnauthorized, "unauthorized", "unauthorized")
+		return http.StatusUnauthorized, apiError{Code: "unauthorized", Message: "unauthorized"}
 	case errors.Is(err, domain.ErrForbidden):
-		WriteError(w, http.StatusForbidden, "forbidden", "forbidden")
+		return http.StatusForbidden, apiError{Code: "forbidden", Message: "forbidden"}
 	case errors.Is(err, domain.ErrUserDisabled):
-		WriteError(w, http.StatusForbidden, "user_disabled", "user is disabled")
+		return http.StatusForbidden, apiError{Code: "user_disabled", Message: "user is disabled"}
 	case errors.Is(err, domain.ErrFriendshipExists):
-		WriteError(w, http.StatusConflict, "friendship_exists", "friend request already exists")
+		return http.StatusConflict, apiError{Code: "friendship_exists", Message: "friend request already exists"}
+	case errors.Is(err, domain.ErrAlreadyGroupMember):
+		return http.StatusConflict, apiError{Code: "already_group_member", Message: "user is already a member of the group"}
+	case errors.Is(err, domain.ErrGuestAliasTaken):
+		return http.StatusConflict, apiError{Code: "guest_alias_taken", Message: "alias already belongs to another guest"}
+	case errors.Is(err, domain.ErrGuestsShareMatch):
+		return http.StatusConflict, apiError{Code: "guests_share_match", Message: "guests played in the same match"}
+	case errors.Is(err, domain.ErrLiveMatchClosed):
+		return http.StatusConflict, apiError{Code: "live_match_closed", Message: "live match has already ended"}
+	case errors.Is(err, domain.ErrFormatExists):
+		return http.StatusConflict, apiError{Code: "format_exists", Message: "format slug or alias already in use"}
 	case errors.Is(err, domain.ErrExternalAccountExists):
-		WriteError(w, http.StatusConflict, "external_account_exists", "external account already linked")
+		return http.StatusConflict, apiError{Code: "external_account_exists", Message: "external account already linked"}
 	case errors.Is(err, domain.ErrResetTokenInvalid):
-		WriteError(w, http.StatusBadRequest, "reset_token_invalid", "reset token is invalid or already used")
+		return http.StatusBadRequest, apiError{Code: "reset_token_invalid", Message: "reset token is invalid or already used"}
 	case errors.Is(err, domain.ErrResetTokenExpired):
-		WriteError(w, http.StatusBadRequest, "reset_token_expired", "reset token has expired")
+		return http.StatusBadRequest, apiError{Code: "reset_token_expired", Message: "reset token has expired"}
 	case errors.Is(err, domain.ErrNotFound):
-		WriteError(w, http.StatusNotFound, "not_found", "not found")
+		return http.StatusNotFound, apiError{Code: "not_found", Message: "not found"}
 	default:
-		WriteError(w, http.StatusInternalServerError, "internal_error", "internal server error")
+		return http.StatusInternalServerError, apiError{Code: "internal_error", Message: "internal server error"}
 	}
 }
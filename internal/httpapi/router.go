@@ -27,6 +27,17 @@ type RouterOpts struct {
 	Reset         *service.PasswordResetService
 	Email         *service.EmailService
 	Notifications *service.NotificationService
+	Sync          *service.SyncService
+	Ratings       *service.RatingService
+	Seasons       *service.SeasonService
+	Groups        *service.GroupService
+	GuestClaims   *service.GuestClaimService
+	Guests        *service.GuestService
+	Decks         *service.DeckService
+	Cards         *service.CardService
+	Formats       *service.FormatService
+	MatchEvents   *service.MatchEventService
+	LiveMatches   *service.LiveMatchService
 	CookieCodec   auth.CookieCodec
 	CookieSecure  bool
 	SessionTTL    time.Duration
@@ -56,6 +67,17 @@ func NewRouter(opts RouterOpts) http.Handler {
 		resetSvc:         opts.Reset,
 		emailSvc:         opts.Email,
 		notificationsSvc: opts.Notifications,
+		syncSvc:          opts.Sync,
+		ratingSvc:        opts.Ratings,
+		seasonSvc:        opts.Seasons,
+		groupSvc:         opts.Groups,
+		guestClaimSvc:    opts.GuestClaims,
+		guestSvc:         opts.Guests,
+		deckSvc:          opts.Decks,
+		cardSvc:          opts.Cards,
+		formatSvc:        opts.Formats,
+		matchEventSvc:    opts.MatchEvents,
+		liveSvc:          opts.LiveMatches,
 		avatarDir:        opts.AvatarDir,
 		publicURL:        opts.PublicURL,
 		cookieCodec:      opts.CookieCodec,
@@ -114,6 +136,9 @@ func NewRouter(opts RouterOpts) http.Handler {
 		apiMux.HandleFunc("POST /v1/users/me/delete/", api.requireAuth(api.handleUsersMeDelete))
 		apiMux.HandleFunc("DELETE /v1/users/me/delete", api.requireAuth(api.handleUsersMeDelete))
 		apiMux.HandleFunc("DELETE /v1/users/me/delete/", api.requireAuth(api.handleUsersMeDelete))
+		apiMux.HandleFunc("GET /v1/users/me/stats-settings", api.requireAuth(api.handleUsersMeStatsSettings))
+		apiMux.HandleFunc("PUT /v1/users/me/stats-settings", api.requireAuth(api.handleUsersMeStatsSettingsUpdate))
+		apiMux.HandleFunc("PATCH /v1/users/me/stats-settings", api.requireAuth(api.handleUsersMeStatsSettingsUpdate))
 		apiMux.HandleFunc("POST /v1/users/me/avatar", api.requireAuth(api.handleUsersMeAvatar))
 		apiMux.HandleFunc("PATCH /v1/users/me/avatar", api.requireAuth(api.handleUsersMeAvatar))
 		apiMux.HandleFunc("PUT /v1/users/me/avatar", api.requireAuth(api.handleUsersMeAvatar))
@@ -137,14 +162,114 @@ func NewRouter(opts RouterOpts) http.Handler {
 
 		if api.matchSvc != nil {
 			apiMux.HandleFunc("POST /v1/matches", api.requireAuth(api.handleMatchesCreate))
+			apiMux.HandleFunc("POST /v1/matches/batch", api.requireAuth(api.handleMatchesBatch))
 			apiMux.HandleFunc("GET /v1/matches", api.requireAuth(api.handleMatchesList))
 			apiMux.HandleFunc("GET /v1/matches/{id}", api.requireAuth(api.handleMatchesGet))
+			apiMux.HandleFunc("PATCH /v1/matches/{id}", api.requireAuth(api.handleMatchesUpdate))
+			apiMux.HandleFunc("PUT /v1/matches/{id}", api.requireAuth(api.handleMatchesUpdate))
+			apiMux.HandleFunc("DELETE /v1/matches/{id}", api.requireAuth(api.handleMatchesDelete))
+			apiMux.HandleFunc("POST /v1/matches/{id}/confirm", api.requireAuth(api.handleMatchesConfirm))
+			apiMux.HandleFunc("POST /v1/matches/{id}/dispute", api.requireAuth(api.handleMatchesDispute))
+			apiMux.HandleFunc("PUT /v1/matches/{id}/deck", api.requireAuth(api.handleMatchesSetDeck))
 			apiMux.HandleFunc("GET /v1/stats/summary", api.requireAuth(api.handleStatsSummary))
 			apiMux.HandleFunc("GET /v1/stats/head-to-head/{id}", api.requireAuth(api.handleStatsHeadToHead))
+			apiMux.HandleFunc("GET /v1/stats/seats", api.requireAuth(api.handleStatsSeats))
+			apiMux.HandleFunc("GET /v1/stats/games", api.requireAuth(api.handleStatsGames))
+			apiMux.HandleFunc("GET /v1/stats/eliminations", api.requireAuth(api.handleStatsEliminations))
+			apiMux.HandleFunc("GET /v1/stats/turn-times", api.requireAuth(api.handleStatsTurnTimes))
 			if api.friendsSvc != nil {
 				apiMux.HandleFunc("GET /v1/stats/friends", api.requireAuth(api.handleStatsFriends))
 			}
 		}
+		if api.syncSvc != nil {
+			apiMux.HandleFunc("GET /v1/sync", api.requireAuth(api.handleSync))
+		}
+		if api.ratingSvc != nil {
+			apiMux.HandleFunc("GET /v1/stats/ratings", api.requireAuth(api.handleRatingsList))
+			apiMux.HandleFunc("GET /v1/stats/ratings/history", api.requireAuth(api.handleRatingsHistory))
+			apiMux.HandleFunc("GET /v1/stats/ratings/leaderboard", api.requireAuth(api.handleRatingsLeaderboard))
+		}
+		if api.seasonSvc != nil {
+			apiMux.HandleFunc("POST /v1/seasons", api.requireAuth(api.handleSeasonsCreate))
+			apiMux.HandleFunc("GET /v1/seasons", api.requireAuth(api.handleSeasonsList))
+			apiMux.HandleFunc("GET /v1/seasons/archive", api.requireAuth(api.handleSeasonsArchive))
+			apiMux.HandleFunc("GET /v1/seasons/{id}", api.requireAuth(api.handleSeasonsGet))
+			apiMux.HandleFunc("PATCH /v1/seasons/{id}", api.requireAuth(api.handleSeasonsUpdate))
+			apiMux.HandleFunc("DELETE /v1/seasons/{id}", api.requireAuth(api.handleSeasonsDelete))
+			apiMux.HandleFunc("GET /v1/seasons/{id}/standings", api.requireAuth(api.handleSeasonsStandings))
+			apiMux.HandleFunc("POST /v1/seasons/{id}/archive", api.requireAuth(api.handleSeasonsClose))
+		}
+		if api.groupSvc != nil {
+			apiMux.HandleFunc("POST /v1/groups", api.requireAuth(api.handleGroupsCreate))
+			apiMux.HandleFunc("GET /v1/groups", api.requireAuth(api.handleGroupsList))
+			apiMux.HandleFunc("POST /v1/groups/join", api.requireAuth(api.handleGroupsJoin))
+			apiMux.HandleFunc("GET /v1/groups/invites", api.requireAuth(api.handleGroupsInvites))
+			apiMux.HandleFunc("POST /v1/groups/invites/{id}/accept", api.requireAuth(api.handleGroupsAcceptInvite))
+			apiMux.HandleFunc("POST /v1/groups/invites/{id}/decline", api.requireAuth(api.handleGroupsDeclineInvite))
+			apiMux.HandleFunc("GET /v1/groups/{id}", api.requireAuth(api.handleGroupsGet))
+			apiMux.HandleFunc("PATCH /v1/groups/{id}", api.requireAuth(api.handleGroupsUpdate))
+			apiMux.HandleFunc("DELETE /v1/groups/{id}", api.requireAuth(api.handleGroupsDelete))
+			apiMux.HandleFunc("POST /v1/groups/{id}/join-code", api.requireAuth(api.handleGroupsRotateJoinCode))
+			apiMux.HandleFunc("GET /v1/groups/{id}/members", api.requireAuth(api.handleGroupsMembers))
+			apiMux.HandleFunc("PATCH /v1/groups/{id}/members/{userId}", api.requireAuth(api.handleGroupsSetRole))
+			apiMux.HandleFunc("DELETE /v1/groups/{id}/members/{userId}", api.requireAuth(api.handleGroupsRemoveMember))
+			apiMux.HandleFunc("POST /v1/groups/{id}/invites", api.requireAuth(api.handleGroupsInvite))
+			apiMux.HandleFunc("GET /v1/groups/{id}/invites", api.requireAuth(api.handleGroupsGroupInvites))
+			apiMux.HandleFunc("GET /v1/groups/{id}/stats", api.requireAuth(api.handleGroupsStats))
+			if api.matchSvc != nil {
+				apiMux.HandleFunc("GET /v1/groups/{id}/matches", api.requireAuth(api.handleGroupsMatches))
+			}
+			if api.ratingSvc != nil {
+				apiMux.HandleFunc("GET /v1/groups/{id}/leaderboard", api.requireAuth(api.handleGroupsLeaderboard))
+			}
+		}
+		if api.guestClaimSvc != nil {
+			apiMux.HandleFunc("POST /v1/guest-claims", api.requireAuth(api.handleGuestClaimsCreate))
+			apiMux.HandleFunc("GET /v1/guest-claims", api.requireAuth(api.handleGuestClaimsList))
+			apiMux.HandleFunc("POST /v1/guest-claims/{id}/accept", api.requireAuth(api.handleGuestClaimsAccept))
+			apiMux.HandleFunc("POST /v1/guest-claims/{id}/decline", api.requireAuth(api.handleGuestClaimsDecline))
+		}
+		if api.guestSvc != nil {
+			apiMux.HandleFunc("GET /v1/guests", api.requireAuth(api.handleGuestsList))
+			apiMux.HandleFunc("POST /v1/guests", api.requireAuth(api.handleGuestsCreate))
+			apiMux.HandleFunc("GET /v1/guests/{id}", api.requireAuth(api.handleGuestsGet))
+			apiMux.HandleFunc("PATCH /v1/guests/{id}", api.requireAuth(api.handleGuestsUpdate))
+			apiMux.HandleFunc("DELETE /v1/guests/{id}", api.requireAuth(api.handleGuestsDelete))
+			apiMux.HandleFunc("POST /v1/guests/{id}/merge", api.requireAuth(api.handleGuestsMerge))
+		}
+		if api.deckSvc != nil {
+			apiMux.HandleFunc("GET /v1/decks", api.requireAuth(api.handleDecksList))
+			apiMux.HandleFunc("POST /v1/decks", api.requireAuth(api.handleDecksCreate))
+			apiMux.HandleFunc("POST /v1/decks/import", api.requireAuth(api.handleDecksImport))
+			apiMux.HandleFunc("GET /v1/decks/{id}", api.requireAuth(api.handleDecksGet))
+			apiMux.HandleFunc("PATCH /v1/decks/{id}", api.requireAuth(api.handleDecksUpdate))
+			apiMux.HandleFunc("DELETE /v1/decks/{id}", api.requireAuth(api.handleDecksDelete))
+			apiMux.HandleFunc("GET /v1/decks/{id}/stats", api.requireAuth(api.handleDecksStats))
+			apiMux.HandleFunc("GET /v1/decks/{id}/cards", api.requireAuth(api.handleDecksCards))
+			apiMux.HandleFunc("GET /v1/decks/{id}/export", api.requireAuth(api.handleDecksExport))
+			apiMux.HandleFunc("GET /v1/stats/decks", api.requireAuth(api.handleStatsDecks))
+		}
+		if api.matchEventSvc != nil {
+			apiMux.HandleFunc("GET /v1/matches/{id}/events", api.requireAuth(api.handleMatchEventsList))
+			apiMux.HandleFunc("POST /v1/matches/{id}/events", api.requireAuth(api.handleMatchEventsAppend))
+			apiMux.HandleFunc("GET /v1/matches/{id}/replay", api.requireAuth(api.handleMatchReplay))
+		}
+		if api.liveSvc != nil {
+			apiMux.HandleFunc("POST /v1/live-matches", api.requireAuth(api.handleLiveMatchesCreate))
+			apiMux.HandleFunc("GET /v1/live-matches", api.requireAuth(api.handleLiveMatchesList))
+			apiMux.HandleFunc("GET /v1/live-matches/{id}", api.requireAuth(api.handleLiveMatchesGet))
+			apiMux.HandleFunc("DELETE /v1/live-matches/{id}", api.requireAuth(api.handleLiveMatchesAbandon))
+			apiMux.HandleFunc("POST /v1/live-matches/{id}/join", api.requireAuth(api.handleLiveMatchesJoin))
+			apiMux.HandleFunc("POST /v1/live-matches/{id}/events", api.requireAuth(api.handleLiveMatchesEvents))
+			apiMux.HandleFunc("POST /v1/live-matches/{id}/finish", api.requireAuth(api.handleLiveMatchesFinish))
+			apiMux.HandleFunc("GET /v1/live-matches/{id}/stream", api.requireAuth(api.handleLiveMatchesStream))
+		}
+		if api.cardSvc != nil {
+			apiMux.HandleFunc("GET /v1/cards/search", api.requireAuth(api.handleCardsSearch))
+		}
+		if api.formatSvc != nil {
+			apiMux.HandleFunc("GET /v1/formats", api.requireAuth(api.handleFormatsList))
+		}
 		if api.notificationsSvc != nil {
 			apiMux.HandleFunc("POST /v1/notifications/token", api.requireAuth(api.handleNotificationsTokenUpsert))
 			apiMux.HandleFunc("DELETE /v1/notifications/token", api.requireAuth(api.handleNotificationsTokenDelete))
@@ -154,7 +279,7 @@ func NewRouter(opts RouterOpts) http.Handler {
 	apiHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// We need ServeMux.ServeHTTP to run so it populates r.PathValue(...) for patterns
 		// like "/v1/friends/requests/{id}/accept". We also want JSON 404s for API routes.
-		brw := newBufferedResponseWriter()
+		brw := newBufferedResponseWriter(w)
 		apiMux.ServeHTTP(brw, r)
 		if brw.status == http.StatusNotFound {
 			handleV1NotFound(w, r)
@@ -186,37 +311,72 @@ func handleV1NotFound(w http.ResponseWriter, _ *http.Request) {
 	WriteError(w, http.StatusNotFound, "not_found", "not found")
 }
 
+// bufferedResponseWriter holds the response until the handler returns, except for event streams,
+// which are written through to dst as soon as their header is written.
 type bufferedResponseWriter struct {
-	header http.Header
-	status int
-	body   bytes.Buffer
+	dst       http.ResponseWriter
+	header    http.Header
+	status    int
+	body      bytes.Buffer
+	streaming bool
 }
 
-func newBufferedResponseWriter() *bufferedResponseWriter {
+func newBufferedResponseWriter(dst http.ResponseWriter) *bufferedResponseWriter {
 	return &bufferedResponseWriter{
+		dst:    dst,
 		header: make(http.Header),
 		status: http.StatusOK,
 	}
 }
 
 func (w *bufferedResponseWriter) Header() http.Header {
+	if w.streaming {
+		return w.dst.Header()
+	}
 	return w.header
 }
 
 func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if w.streaming {
+		return
+	}
 	w.status = status
+	if status == http.StatusOK && strings.HasPrefix(w.header.Get("Content-Type"), "text/event-stream") {
+		w.streaming = true
+		w.copyHeader(w.dst)
+		w.dst.WriteHeader(status)
+	}
 }
 
 func (w *bufferedResponseWriter) Write(p []byte) (int, error) {
+	if w.streaming {
+		return w.dst.Write(p)
+	}
 	return w.body.Write(p)
 }
 
-func (w *bufferedResponseWriter) flushTo(dst http.ResponseWriter) {
+// FlushError lets http.ResponseController flush event streams. Buffered responses are written
+// when the handler returns.
+func (w *bufferedResponseWriter) FlushError() error {
+	if !w.streaming {
+		return nil
+	}
+	return http.NewResponseController(w.dst).Flush()
+}
+
+func (w *bufferedResponseWriter) copyHeader(dst http.ResponseWriter) {
 	for k, vv := range w.header {
 		for _, v := range vv {
 			dst.Header().Add(k, v)
 		}
 	}
+}
+
+func (w *bufferedResponseWriter) flushTo(dst http.ResponseWriter) {
+	if w.streaming {
+		return
+	}
+	w.copyHeader(dst)
 	dst.WriteHeader(w.status)
 	_, _ = dst.Write(w.body.Bytes())
 }
@@ -235,6 +395,17 @@ type api struct {
 	resetSvc         *service.PasswordResetService
 	emailSvc         *service.EmailService
 	notificationsSvc *service.NotificationService
+	syncSvc          *service.SyncService
+	ratingSvc        *service.RatingService
+	seasonSvc        *service.SeasonService
+	groupSvc         *service.GroupService
+	guestClaimSvc    *service.GuestClaimService
+	guestSvc         *service.GuestService
+	deckSvc          *service.DeckService
+	cardSvc          *service.CardService
+	formatSvc        *service.FormatService
+	matchEventSvc    *service.MatchEventService
+	liveSvc          *service.LiveMatchService
 	avatarDir        string
 	publicURL        *url.URL
 	cookieCodec      auth.CookieCodec
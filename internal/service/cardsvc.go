@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type CardsStore interface {
+	UpsertCards(ctx context.Context, cards []domain.Card) error
+	SearchCards(ctx context.Context, query string, format domain.GameFormat, commanderOnly bool, limit int) ([]domain.Card, error)
+	GetCards(ctx context.Context, ids []string) ([]domain.Card, error)
+}
+
+// CardService reads the offline card database and loads it from Scryfall bulk data files.
+type CardService struct {
+	Store   CardsStore
+	Formats FormatResolver
+}
+
+const (
+	minCardQueryLength  = 2
+	maxCardQueryLength  = 100
+	defaultCardLimit    = 20
+	maxCardLimit        = 50
+	cardImportBatchSize = 500
+)
+
+type CardSearchParams struct {
+	Query string
+	// Format limits results to cards legal in that format; empty means any format.
+	Format domain.GameFormat
+	// CommanderOnly limits results to cards that can be a commander.
+	CommanderOnly bool
+	Limit         int
+}
+
+// Search matches cards whose name, or any word of it, starts with the query. Exact and
+// prefix matches come first.
+func (s *CardService) Search(ctx context.Context, p CardSearchParams) ([]domain.Card, error) {
+	fields := make(map[string]string)
+
+	query := strings.Join(strings.Fields(p.Query), " ")
+	switch {
+	case utf8.RuneCountInString(query) < minCardQueryLength:
+		fields["q"] = fmt.Sprintf("must be at least %d characters", minCardQueryLength)
+	case utf8.RuneCountInString(query) > maxCardQueryLength:
+		fields["q"] = fmt.Sprintf("must be at most %d characters", maxCardQueryLength)
+	}
+
+	var format domain.GameFormat
+	if strings.TrimSpace(string(p.Format)) != "" {
+		f, msg, err := resolveFormat(ctx, s.Formats, p.Format)
+		if err != nil {
+			return nil, err
+		}
+		if msg != "" {
+			fields["format"] = msg
+		}
+		format = domain.GameFormat(legalityKey(f))
+	}
+
+	limit := p.Limit
+	switch {
+	case limit == 0:
+		limit = defaultCardLimit
+	case limit < 1 || limit > maxCardLimit:
+		fields["limit"] = fmt.Sprintf("must be between 1 and %d", maxCardLimit)
+	}
+
+	if len(fields) > 0 {
+		return nil, domain.NewValidationError(fields)
+	}
+
+	cards, err := s.Store.SearchCards(ctx, query, format, p.CommanderOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	if cards == nil {
+		cards = []domain.Card{}
+	}
+	return cards, nil
+}
+
+// scryfallCard holds the fields of a Scryfall card object the import uses.
+type scryfallCard struct {
+	OracleID      string            `json:"oracle_id"`
+	Name          string            `json:"name"`
+	Layout        string            `json:"layout"`
+	TypeLine      string            `json:"type_line"`
+	OracleText    string            `json:"oracle_text"`
+	ColorIdentity []string          `json:"color_identity"`
+	Legalities    map[string]string `json:"legalities"`
+	CardFaces     []struct {
+		OracleID   string `json:"oracle_id"`
+		TypeLine   string `json:"type_line"`
+		OracleText string `json:"oracle_text"`
+	} `json:"card_faces"`
+}
+
+// Layouts that are not cards a deck can contain.
+var skippedCardLayouts = map[string]bool{
+	"token":              true,
+	"double_faced_token": true,
+	"emblem":             true,
+	"art_series":         true,
+	"vanguard":           true,
+	"planar":             true,
+	"scheme":             true,
+}
+
+// Import reads a Scryfall bulk data file (a JSON array of card objects, such as "Oracle Cards"
+// or "Default Cards") and upserts one card per oracle_id. Later printings of a card already seen
+// in the file are skipped. It returns the number of cards written.
+func (s *CardService) Import(ctx context.Context, r io.Reader) (int, error) {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return 0, fmt.Errorf("read card file: %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return 0, errors.New("read card file: expected a JSON array of cards")
+	}
+
+	seen := make(map[string]bool)
+	batch := make([]domain.Card, 0, cardImportBatchSize)
+	written := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := s.Store.UpsertCards(ctx, batch); err != nil {
+			return err
+		}
+		written += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for n := 0; dec.More(); n++ {
+		var raw scryfallCard
+		if err := dec.Decode(&raw); err != nil {
+			return written, fmt.Errorf("read card %d: %w", n, err)
+		}
+		card, ok := cardFromScryfall(raw)
+		if !ok || seen[card.ID] {
+			continue
+		}
+		seen[card.ID] = true
+		batch = append(batch, card)
+		if len(batch) == cardImportBatchSize {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return written, fmt.Errorf("read card file: %w", err)
+	}
+	if err := flush(); err != nil {
+		return written, err
+	}
+	return written, nil
+}
+
+func cardFromScryfall(raw scryfallCard) (domain.Card, bool) {
+	if skippedCardLayouts[raw.Layout] {
+		return domain.Card{}, false
+	}
+	oracleID := raw.OracleID
+	frontType := raw.TypeLine
+	text := raw.OracleText
+	for i, face := range raw.CardFaces {
+		if oracleID == "" {
+			oracleID = face.OracleID
+		}
+		if i == 0 && face.TypeLine != "" {
+			frontType = face.TypeLine
+		}
+		text += "\n" + face.OracleText
+	}
+	name := strings.TrimSpace(raw.Name)
+	if oracleID == "" || name == "" {
+		return domain.Card{}, false
+	}
+
+	colors, ok := normalizeColorIdentity(strings.Join(raw.ColorIdentity, ""))
+	if !ok {
+		colors = ""
+	}
+	legalities := raw.Legalities
+	if legalities == nil {
+		legalities = map[string]string{}
+	}
+
+	legendary := strings.Contains(frontType, "Legendary")
+	canBeCommander := (legendary && (strings.Contains(frontType, "Creature") || strings.Contains(frontType, "Background"))) ||
+		strings.Contains(text, "can be your commander")
+
+	return domain.Card{
+		ID:             oracleID,
+		Name:           name,
+		TypeLine:       strings.TrimSpace(raw.TypeLine),
+		ColorIdentity:  colors,
+		Legalities:     legalities,
+		CanBeCommander: canBeCommander,
+	}, true
+}
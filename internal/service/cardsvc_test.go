@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type stubCardsStore struct {
+	upserts [][]domain.Card
+	search  struct {
+		called        bool
+		query         string
+		format        domain.GameFormat
+		commanderOnly bool
+		limit         int
+	}
+}
+
+func (s *stubCardsStore) UpsertCards(ctx context.Context, cards []domain.Card) error {
+	s.upserts = append(s.upserts, append([]domain.Card(nil), cards...))
+	return nil
+}
+
+func (s *stubCardsStore) SearchCards(ctx context.Context, query string, format domain.GameFormat, commanderOnly bool, limit int) ([]domain.Card, error) {
+	s.search.called = true
+	s.search.query = query
+	s.search.format = format
+	s.search.commanderOnly = commanderOnly
+	s.search.limit = limit
+	return nil, nil
+}
+
+func (s *stubCardsStore) GetCards(ctx context.Context, ids []string) ([]domain.Card, error) {
+	return nil, nil
+}
+
+const scryfallSample = `[
+  {"object": "card", "oracle_id": "o-atraxa", "name": "Atraxa, Praetors' Voice", "layout": "normal",
+   "type_line": "Legendary Creature — Phyrexian Angel Horror", "color_identity": ["G", "W", "U", "B"],
+   "legalities": {"commander": "legal", "modern": "legal"}},
+  {"object": "card", "oracle_id": "o-atraxa", "name": "Atraxa, Praetors' Voice", "layout": "normal",
+   "type_line": "Legendary Creature — Phyrexian Angel Horror", "color_identity": ["G", "W", "U", "B"],
+   "legalities": {"commander": "legal"}},
+  {"object": "card", "oracle_id": "o-teferi", "name": "Teferi, Temporal Archmage", "layout": "normal",
+   "type_line": "Legendary Planeswalker — Teferi", "oracle_text": "Teferi, Temporal Archmage can be your commander.",
+   "color_identity": ["U"], "legalities": {"commander": "legal"}},
+  {"object": "card", "name": "Esika, God of the Tree // The Prismatic Bridge", "layout": "modal_dfc",
+   "type_line": "Legendary Creature — God // Legendary Enchantment",
+   "card_faces": [{"oracle_id": "o-esika", "type_line": "Legendary Creature — God"}, {"type_line": "Legendary Enchantment"}],
+   "color_identity": ["G", "W", "U", "B", "R"], "legalities": {"commander": "legal"}},
+  {"object": "card", "oracle_id": "o-sol", "name": "Sol Ring", "layout": "normal", "type_line": "Artifact",
+   "color_identity": [], "legalities": {"commander": "legal"}},
+  {"object": "card", "oracle_id": "o-token", "name": "Soldier", "layout": "token", "type_line": "Token Creature — Soldier"}
+]`
+
+func TestCardImport(t *testing.T) {
+	store := &stubCardsStore{}
+	svc := &CardService{Store: store}
+
+	n, err := svc.Import(context.Background(), strings.NewReader(scryfallSample))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 4 || len(store.upserts) != 1 {
+		t.Fatalf("expected 4 cards in one batch, got %d in %d batches", n, len(store.upserts))
+	}
+
+	byID := make(map[string]domain.Card)
+	for _, c := range store.upserts[0] {
+		byID[c.ID] = c
+	}
+	if c := byID["o-atraxa"]; !c.CanBeCommander || c.ColorIdentity != "WUBG" || !c.LegalIn(domain.FormatModern) {
+		t.Fatalf("unexpected atraxa: %+v", c)
+	}
+	if !byID["o-teferi"].CanBeCommander {
+		t.Fatal("expected teferi to be commander eligible from its rules text")
+	}
+	if c := byID["o-esika"]; !c.CanBeCommander || c.ColorIdentity != "WUBRG" {
+		t.Fatalf("expected esika from its front face, got %+v", c)
+	}
+	if c := byID["o-sol"]; c.CanBeCommander || c.ColorIdentity != "" {
+		t.Fatalf("unexpected sol ring: %+v", c)
+	}
+	if _, ok := byID["o-token"]; ok {
+		t.Fatal("tokens should be skipped")
+	}
+}
+
+func TestCardImportRejectsNonArray(t *testing.T) {
+	svc := &CardService{Store: &stubCardsStore{}}
+	if _, err := svc.Import(context.Background(), strings.NewReader(`{"object": "list"}`)); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestCardSearchValidation(t *testing.T) {
+	store := &stubCardsStore{}
+	svc := &CardService{Store: store}
+	ctx := context.Background()
+
+	for _, p := range []CardSearchParams{
+		{Query: "a"},
+		{Query: "atraxa", Format: "vintage"},
+		{Query: "atraxa", Limit: 500},
+	} {
+		_, err := svc.Search(ctx, p)
+		expectValidation(t, err)
+	}
+	if store.search.called {
+		t.Fatal("store should not be called")
+	}
+
+	cards, err := svc.Search(ctx, CardSearchParams{Query: "  atraxa   praetors ", Format: "EDH", CommanderOnly: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cards == nil {
+		t.Fatal("expected empty slice")
+	}
+	if store.search.query != "atraxa praetors" || store.search.format != domain.FormatCommander || !store.search.commanderOnly || store.search.limit != defaultCardLimit {
+		t.Fatalf("unexpected search: %+v", store.search)
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+// DecklistFormat is a decklist text layout.
+type DecklistFormat string
+
+const (
+	// DecklistPlain is "1 Sol Ring" lines under Commander/Companion/Main/Sideboard headers.
+	DecklistPlain DecklistFormat = "plain"
+	// DecklistMTGO is the MTGO .txt layout: main deck, a blank line, then the sideboard. Commanders
+	// and companions are written at the start of the sideboard block.
+	DecklistMTGO DecklistFormat = "mtgo"
+	// DecklistArena is the MTG Arena export: section headers and "(SET) number" after each name.
+	DecklistArena DecklistFormat = "arena"
+)
+
+const (
+	maxDecklistLines    = 1000
+	maxDeckCardQuantity = 250
+)
+
+var (
+	decklistCountRe  = regexp.MustCompile(`^(\d+)\s*[xX]?\s+(.+)$`)
+	decklistSetRe    = regexp.MustCompile(`^(.+?)\s+\(([A-Za-z0-9]{2,6})\)(?:\s+(\S+))?$`)
+	decklistMarkerRe = regexp.MustCompile(`\s+\*([A-Za-z]+)\*$`)
+	decklistHeaderRe = regexp.MustCompile(`^([A-Za-z]+)\s*(?:\(\d+\))?\s*:?$`)
+)
+
+// decklistHeaders maps section headers to sections. Maybeboard cards are skipped.
+var decklistHeaders = map[string]domain.DeckSection{
+	"commander":   domain.DeckSectionCommander,
+	"commanders":  domain.DeckSectionCommander,
+	"companion":   domain.DeckSectionCompanion,
+	"deck":        domain.DeckSectionMain,
+	"main":        domain.DeckSectionMain,
+	"maindeck":    domain.DeckSectionMain,
+	"mainboard":   domain.DeckSectionMain,
+	"sideboard":   domain.DeckSectionSideboard,
+	"maybeboard":  "",
+	"considering": "",
+}
+
+type parsedDecklist struct {
+	// Name comes from an Arena "About / Name ..." block.
+	Name     string
+	Cards    []domain.DeckCard
+	Unparsed []domain.DecklistLine
+}
+
+// parseDecklist reads plain, MTGO and Arena decklists. Without section headers, a blank line after
+// the first cards starts the sideboard, as in MTGO and older Arena exports; in commander decks a
+// final block of one or two cards is the commander instead. Repeated cards in a section are merged.
+func parseDecklist(text string, commanderDeck bool) parsedDecklist {
+	var out parsedDecklist
+	index := make(map[string]int)
+	add := func(card domain.DeckCard) {
+		key := string(card.Section) + "\x00" + strings.ToLower(card.Name)
+		if i, ok := index[key]; ok {
+			out.Cards[i].Quantity += card.Quantity
+			return
+		}
+		index[key] = len(out.Cards)
+		out.Cards = append(out.Cards, card)
+	}
+
+	section := domain.DeckSectionMain
+	sawHeader := false
+	pendingBreak := false
+	implicitStart := -1
+	afterAbout := false
+
+	for i, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			if !sawHeader && implicitStart < 0 && len(out.Cards) > 0 {
+				pendingBreak = true
+			}
+			continue
+		}
+
+		comment := strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#")
+		header := strings.TrimSpace(strings.TrimLeft(line, "/#"))
+		if m := decklistHeaderRe.FindStringSubmatch(header); m != nil {
+			if s, ok := decklistHeaders[strings.ToLower(m[1])]; ok {
+				section = s
+				sawHeader = true
+				pendingBreak = false
+				continue
+			}
+		}
+		if comment {
+			continue
+		}
+		if strings.EqualFold(line, "about") {
+			afterAbout = true
+			continue
+		}
+		if afterAbout {
+			afterAbout = false
+			if len(line) > 5 && strings.EqualFold(line[:5], "name ") {
+				out.Name = strings.TrimSpace(line[5:])
+				continue
+			}
+		}
+
+		if pendingBreak {
+			pendingBreak = false
+			implicitStart = len(out.Cards)
+			section = domain.DeckSectionSideboard
+		}
+
+		card, commanderMarker, reason := parseDecklistCard(line)
+		if reason != "" {
+			out.Unparsed = append(out.Unparsed, domain.DecklistLine{Line: i + 1, Text: line, Reason: reason})
+			continue
+		}
+		card.Section = section
+		if len(line) > 3 && strings.EqualFold(line[:3], "sb:") {
+			card.Section = domain.DeckSectionSideboard
+		}
+		if commanderMarker {
+			card.Section = domain.DeckSectionCommander
+		}
+		if card.Section == "" {
+			continue
+		}
+		add(card)
+	}
+
+	if commanderDeck && !sawHeader && implicitStart >= 0 {
+		block := out.Cards[implicitStart:]
+		total := 0
+		for _, c := range block {
+			total += c.Quantity
+		}
+		if total <= maxDeckCommanders {
+			for i := range block {
+				block[i].Section = domain.DeckSectionCommander
+			}
+		}
+	}
+	return out
+}
+
+// parseDecklistCard reads "4 Name", "4x Name" and "1 Name (SET) 123", with an optional "SB:" prefix
+// and trailing markers such as *F*. It reports whether a *CMDR* marker was present.
+func parseDecklistCard(line string) (domain.DeckCard, bool, string) {
+	if len(line) > 3 && strings.EqualFold(line[:3], "sb:") {
+		line = strings.TrimSpace(line[3:])
+	}
+	commander := false
+	for {
+		m := decklistMarkerRe.FindStringSubmatchIndex(line)
+		if m == nil {
+			break
+		}
+		if strings.EqualFold(line[m[2]:m[3]], "cmdr") {
+			commander = true
+		}
+		line = strings.TrimSpace(line[:m[0]])
+	}
+
+	m := decklistCountRe.FindStringSubmatch(line)
+	if m == nil {
+		if _, err := strconv.Atoi(line); err == nil {
+			return domain.DeckCard{}, false, "missing card name"
+		}
+		return domain.DeckCard{}, false, "missing quantity"
+	}
+	quantity, err := strconv.Atoi(m[1])
+	if err != nil || quantity < 1 || quantity > maxDeckCardQuantity {
+		return domain.DeckCard{}, false, fmt.Sprintf("quantity must be between 1 and %d", maxDeckCardQuantity)
+	}
+
+	card := domain.DeckCard{Quantity: quantity, Name: strings.TrimSpace(m[2])}
+	if s := decklistSetRe.FindStringSubmatch(card.Name); s != nil {
+		card.Name = strings.TrimSpace(s[1])
+		card.SetCode = strings.ToUpper(s[2])
+		card.CollectorNumber = s[3]
+	}
+	if utf8.RuneCountInString(card.Name) > maxCommanderNameLength {
+		return domain.DeckCard{}, false, fmt.Sprintf("card name must be at most %d characters", maxCommanderNameLength)
+	}
+	return card, commander, ""
+}
+
+func countDeckCards(cards []domain.DeckCard) domain.DeckCardCounts {
+	var c domain.DeckCardCounts
+	for _, card := range cards {
+		switch card.Section {
+		case domain.DeckSectionCommander:
+			c.Commander += card.Quantity
+		case domain.DeckSectionCompanion:
+			c.Companion += card.Quantity
+		case domain.DeckSectionMain:
+			c.Main += card.Quantity
+		case domain.DeckSectionSideboard:
+			c.Sideboard += card.Quantity
+		}
+		c.Total += card.Quantity
+	}
+	return c
+}
+
+// formatDecklist writes cards in the given layout. Plain and Arena output parse back to the same list.
+func formatDecklist(cards []domain.DeckCard, format DecklistFormat) string {
+	bySection := make(map[domain.DeckSection][]domain.DeckCard)
+	for _, c := range cards {
+		bySection[c.Section] = append(bySection[c.Section], c)
+	}
+
+	var b strings.Builder
+	writeCards := func(cards []domain.DeckCard, withSet bool) {
+		for _, c := range cards {
+			fmt.Fprintf(&b, "%d %s", c.Quantity, c.Name)
+			if withSet && c.SetCode != "" {
+				fmt.Fprintf(&b, " (%s)", c.SetCode)
+				if c.CollectorNumber != "" {
+					fmt.Fprintf(&b, " %s", c.CollectorNumber)
+				}
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if format == DecklistMTGO {
+		writeCards(bySection[domain.DeckSectionMain], false)
+		var side []domain.DeckCard
+		side = append(side, bySection[domain.DeckSectionCommander]...)
+		side = append(side, bySection[domain.DeckSectionCompanion]...)
+		side = append(side, bySection[domain.DeckSectionSideboard]...)
+		if len(side) > 0 {
+			b.WriteString("\n")
+			writeCards(side, false)
+		}
+		return b.String()
+	}
+
+	mainHeader := "Main"
+	if format == DecklistArena {
+		mainHeader = "Deck"
+	}
+	sections := []struct {
+		section domain.DeckSection
+		header  string
+	}{
+		{domain.DeckSectionCommander, "Commander"},
+		{domain.DeckSectionCompanion, "Companion"},
+		{domain.DeckSectionMain, mainHeader},
+		{domain.DeckSectionSideboard, "Sideboard"},
+	}
+	for _, s := range sections {
+		cards := bySection[s.section]
+		if len(cards) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(s.header + "\n")
+		writeCards(cards, format == DecklistArena)
+	}
+	return b.String()
+}
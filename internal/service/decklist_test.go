@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+func TestParseDecklistArena(t *testing.T) {
+	text := "About\nName Atraxa Counters\n\nCommander\n1 Atraxa, Praetors' Voice (C16) 28\n\nDeck\n1 Sol Ring (C21) 263\n1x Doubling Season *F*\n2 Sol Ring\n\nSideboard\n1 Swords to Plowshares (STA) 10\n"
+	got := parseDecklist(text, true)
+
+	if got.Name != "Atraxa Counters" {
+		t.Fatalf("expected deck name, got %q", got.Name)
+	}
+	want := []domain.DeckCard{
+		{Section: domain.DeckSectionCommander, Quantity: 1, Name: "Atraxa, Praetors' Voice", SetCode: "C16", CollectorNumber: "28"},
+		{Section: domain.DeckSectionMain, Quantity: 3, Name: "Sol Ring", SetCode: "C21", CollectorNumber: "263"},
+		{Section: domain.DeckSectionMain, Quantity: 1, Name: "Doubling Season"},
+		{Section: domain.DeckSectionSideboard, Quantity: 1, Name: "Swords to Plowshares", SetCode: "STA", CollectorNumber: "10"},
+	}
+	if !reflect.DeepEqual(got.Cards, want) {
+		t.Fatalf("unexpected cards:\n got %+v\nwant %+v", got.Cards, want)
+	}
+	if len(got.Unparsed) != 0 {
+		t.Fatalf("unexpected unparsed lines: %+v", got.Unparsed)
+	}
+}
+
+func TestParseDecklistMTGO(t *testing.T) {
+	text := "4 Lightning Bolt\r\n20 Mountain\r\n\r\n2 Smash to Smithereens\r\nSB: 1 Pyroblast\r\n"
+	got := parseDecklist(text, false)
+	counts := countDeckCards(got.Cards)
+	if counts.Main != 24 || counts.Sideboard != 3 || counts.Total != 27 {
+		t.Fatalf("unexpected counts: %+v", counts)
+	}
+
+	commander := parseDecklist("1 Sol Ring\n99 Forest\n\n1 Omnath, Locus of Mana\n", true)
+	if c := countDeckCards(commander.Cards); c.Commander != 1 || c.Sideboard != 0 {
+		t.Fatalf("expected the trailing block to be the commander, got %+v", c)
+	}
+}
+
+func TestParseDecklistReportsBadLines(t *testing.T) {
+	got := parseDecklist("// Burn\n4 Lightning Bolt\nLightning Bolt\n0 Mountain\n12\nMaybeboard\n1 Fireblast\n", false)
+	if len(got.Cards) != 1 {
+		t.Fatalf("expected one card, got %+v", got.Cards)
+	}
+	var lines []int
+	for _, l := range got.Unparsed {
+		lines = append(lines, l.Line)
+	}
+	if want := []int{3, 4, 5}; !reflect.DeepEqual(lines, want) {
+		t.Fatalf("expected unparsed lines %v, got %+v", want, got.Unparsed)
+	}
+}
+
+func TestFormatDecklistRoundTrip(t *testing.T) {
+	cards := []domain.DeckCard{
+		{Section: domain.DeckSectionCommander, Quantity: 1, Name: "Atraxa, Praetors' Voice", SetCode: "C16", CollectorNumber: "28"},
+		{Section: domain.DeckSectionMain, Quantity: 1, Name: "Sol Ring", SetCode: "C21", CollectorNumber: "263"},
+		{Section: domain.DeckSectionMain, Quantity: 30, Name: "Forest"},
+		{Section: domain.DeckSectionSideboard, Quantity: 1, Name: "Swords to Plowshares"},
+	}
+	if got := parseDecklist(formatDecklist(cards, DecklistArena), true).Cards; !reflect.DeepEqual(got, cards) {
+		t.Fatalf("arena round trip:\n got %+v\nwant %+v", got, cards)
+	}
+
+	plain := formatDecklist(cards, DecklistPlain)
+	if strings.Contains(plain, "(C21)") {
+		t.Fatalf("plain export should not have set codes: %q", plain)
+	}
+	if got := countDeckCards(parseDecklist(plain, true).Cards); got != countDeckCards(cards) {
+		t.Fatalf("plain round trip counts: %+v", got)
+	}
+
+	mtgo := formatDecklist(cards[:3], DecklistMTGO)
+	if want := "1 Sol Ring\n30 Forest\n\n1 Atraxa, Praetors' Voice\n"; mtgo != want {
+		t.Fatalf("unexpected mtgo export %q", mtgo)
+	}
+}
+
+type stubDeckCardLookup []domain.Card
+
+func (c stubDeckCardLookup) FindCardsByName(ctx context.Context, names []string) ([]domain.Card, error) {
+	return c, nil
+}
+
+func TestDeckImport(t *testing.T) {
+	store := &stubDecksStore{}
+	svc := &DeckService{Store: store, Cards: stubDeckCardLookup{
+		{ID: "c-esika", Name: "Esika, God of the Tree // The Prismatic Bridge", ColorIdentity: "WUBRG"},
+		{ID: "c-sol", Name: "Sol Ring"},
+		{ID: "c-forest", Name: "Forest", ColorIdentity: "G"},
+	}}
+	ctx := context.Background()
+
+	_, err := svc.Import(ctx, "u1", DeckImportParams{Name: "Empty", Text: "Sideboard\n"})
+	expectValidation(t, err)
+
+	res, err := svc.Import(ctx, "u1", DeckImportParams{
+		Name:   "Esika",
+		Format: "commander",
+		Text:   "Commander\n1 Esika, God of the Tree\n\nDeck\n1 Sol Ring\n1 Made Up Card\n97 Forest\nnonsense\n",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.imported.ColorIdentity != "WUBRG" || !reflect.DeepEqual(store.imported.Commanders, []string{"Esika, God of the Tree"}) {
+		t.Fatalf("unexpected deck input: %+v", store.imported)
+	}
+	if store.cards[0].CardID != "c-esika" || store.cards[2].CardID != "" {
+		t.Fatalf("unexpected card ids: %+v", store.cards)
+	}
+	if res.Counts.Total != 100 || res.Counts.Commander != 1 {
+		t.Fatalf("unexpected counts: %+v", res.Counts)
+	}
+	if !reflect.DeepEqual(res.UnknownCards, []string{"Made Up Card"}) || len(res.UnparsedLines) != 1 || res.UnparsedLines[0].Line != 8 {
+		t.Fatalf("unexpected report: %+v / %+v", res.UnknownCards, res.UnparsedLines)
+	}
+
+	if _, err := svc.Export(ctx, "u1", "d1", "csv"); err == nil {
+		t.Fatal("expected validation error for unknown export format")
+	}
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type DecksStore interface {
+	ListDecks(ctx context.Context, ownerID string, includeArchived bool) ([]domain.Deck, error)
+	GetDeck(ctx context.Context, ownerID, deckID string) (domain.Deck, error)
+	CreateDeck(ctx context.Context, ownerID string, in domain.DeckInput) (domain.Deck, error)
+	UpdateDeck(ctx context.Context, ownerID, deckID string, in domain.DeckInput) (domain.Deck, error)
+	DeleteDeck(ctx context.Context, ownerID, deckID string) error
+	ListDeckStats(ctx context.Context, userID string) ([]domain.DeckStats, error)
+	DeckStats(ctx context.Context, userID, deckID string) (domain.DeckStats, error)
+	ImportDeck(ctx context.Context, ownerID string, in domain.DeckInput, cards []domain.DeckCard) (domain.Deck, error)
+	ListDeckCards(ctx context.Context, deckID string) ([]domain.DeckCard, error)
+}
+
+// DeckCardLookup matches decklist card names against the card database.
+type DeckCardLookup interface {
+	FindCardsByName(ctx context.Context, names []string) ([]domain.Card, error)
+}
+
+// DeckService manages a user's decks. Decks are private to their owner; match seats reference
+// them through deck_id.
+type DeckService struct {
+	Store   DecksStore
+	Cards   DeckCardLookup
+	Formats FormatResolver
+}
+
+const (
+	maxDeckNameLength      = 100
+	maxDeckCommanders      = 2
+	maxCommanderNameLength = 150
+)
+
+type DeckParams struct {
+	Name          string
+	Format        domain.GameFormat
+	Commanders    []string
+	ColorIdentity string
+	Archived      bool
+}
+
+// DeckPatch leaves nil fields unchanged.
+type DeckPatch struct {
+	Name          *string
+	Format        *domain.GameFormat
+	Commanders    *[]string
+	ColorIdentity *string
+	Archived      *bool
+}
+
+func (s *DeckService) List(ctx context.Context, ownerID string, includeArchived bool) ([]domain.Deck, error) {
+	decks, err := s.Store.ListDecks(ctx, ownerID, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	if decks == nil {
+		decks = []domain.Deck{}
+	}
+	return decks, nil
+}
+
+func (s *DeckService) Get(ctx context.Context, ownerID, deckID string) (domain.Deck, error) {
+	return s.Store.GetDeck(ctx, ownerID, deckID)
+}
+
+func (s *DeckService) Create(ctx context.Context, ownerID string, p DeckParams) (domain.Deck, error) {
+	in, err := validateDeck(ctx, s.Formats, p, "")
+	if err != nil {
+		return domain.Deck{}, err
+	}
+	return s.Store.CreateDeck(ctx, ownerID, in)
+}
+
+func (s *DeckService) Update(ctx context.Context, ownerID, deckID string, p DeckPatch) (domain.Deck, error) {
+	deck, err := s.Store.GetDeck(ctx, ownerID, deckID)
+	if err != nil {
+		return domain.Deck{}, err
+	}
+
+	merged := DeckParams{
+		Name:          deck.Name,
+		Format:        deck.Format,
+		Commanders:    deck.Commanders,
+		ColorIdentity: deck.ColorIdentity,
+		Archived:      deck.Archived,
+	}
+	if p.Name != nil {
+		merged.Name = *p.Name
+	}
+	if p.Format != nil {
+		merged.Format = *p.Format
+	}
+	if p.Commanders != nil {
+		merged.Commanders = *p.Commanders
+	}
+	if p.ColorIdentity != nil {
+		merged.ColorIdentity = *p.ColorIdentity
+	}
+	if p.Archived != nil {
+		merged.Archived = *p.Archived
+	}
+
+	in, err := validateDeck(ctx, s.Formats, merged, deck.Format)
+	if err != nil {
+		return domain.Deck{}, err
+	}
+	return s.Store.UpdateDeck(ctx, ownerID, deckID, in)
+}
+
+// Delete removes a deck. Matches played with it keep their results without the deck.
+func (s *DeckService) Delete(ctx context.Context, ownerID, deckID string) error {
+	return s.Store.DeleteDeck(ctx, ownerID, deckID)
+}
+
+// ListStats returns win rate and average place for each of the user's decks, most played first.
+func (s *DeckService) ListStats(ctx context.Context, userID string) ([]domain.DeckStats, error) {
+	stats, err := s.Store.ListDeckStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		stats = []domain.DeckStats{}
+	}
+	return stats, nil
+}
+
+// Stats returns one deck's record, including head-to-head against the decks it played against.
+func (s *DeckService) Stats(ctx context.Context, userID, deckID string) (domain.DeckStats, error) {
+	stats, err := s.Store.DeckStats(ctx, userID, deckID)
+	if err != nil {
+		return domain.DeckStats{}, err
+	}
+	if stats.HeadToHead == nil {
+		stats.HeadToHead = []domain.DeckHeadToHeadStat{}
+	}
+	return stats, nil
+}
+
+type DeckImportParams struct {
+	// Name falls back to the name in an Arena export.
+	Name   string
+	Format domain.GameFormat
+	Text   string
+}
+
+// Import creates a deck from decklist text. Commanders come from the commander section and the
+// color identity from the commanders, or from every main deck card when there are none. Cards the
+// card database does not know are stored by name and reported, as are lines that could not be read.
+func (s *DeckService) Import(ctx context.Context, ownerID string, p DeckImportParams) (domain.DeckImportResult, error) {
+	text := strings.TrimSpace(p.Text)
+	switch {
+	case text == "":
+		return domain.DeckImportResult{}, domain.NewValidationError(map[string]string{"text": "required"})
+	case strings.Count(text, "\n") >= maxDecklistLines:
+		return domain.DeckImportResult{}, domain.NewValidationError(map[string]string{"text": fmt.Sprintf("must be at most %d lines", maxDecklistLines)})
+	}
+
+	format, msg, err := usableFormat(ctx, s.Formats, p.Format, "")
+	if err != nil {
+		return domain.DeckImportResult{}, err
+	}
+	if msg != "" {
+		return domain.DeckImportResult{}, domain.NewValidationError(map[string]string{"format": msg})
+	}
+	parsed := parseDecklist(text, format.Commander)
+	if len(parsed.Cards) == 0 {
+		return domain.DeckImportResult{}, domain.NewValidationError(map[string]string{"text": "no cards found"})
+	}
+
+	known, err := s.matchCards(ctx, parsed.Cards)
+	if err != nil {
+		return domain.DeckImportResult{}, err
+	}
+
+	unknown := []string{}
+	seenUnknown := make(map[string]bool)
+	var commanders []string
+	var commanderColors, deckColors strings.Builder
+	for i, card := range parsed.Cards {
+		c, ok := known[strings.ToLower(card.Name)]
+		if ok {
+			parsed.Cards[i].CardID = c.ID
+		} else if !seenUnknown[strings.ToLower(card.Name)] {
+			seenUnknown[strings.ToLower(card.Name)] = true
+			unknown = append(unknown, card.Name)
+		}
+		switch card.Section {
+		case domain.DeckSectionCommander:
+			commanders = append(commanders, card.Name)
+			commanderColors.WriteString(c.ColorIdentity)
+		case domain.DeckSectionCompanion, domain.DeckSectionMain:
+			deckColors.WriteString(c.ColorIdentity)
+		}
+	}
+	colors := deckColors.String()
+	if len(commanders) > 0 {
+		colors = commanderColors.String()
+	}
+
+	name := p.Name
+	if strings.TrimSpace(name) == "" {
+		name = parsed.Name
+	}
+	in, err := validateDeck(ctx, s.Formats, DeckParams{
+		Name:          name,
+		Format:        format.Slug,
+		Commanders:    commanders,
+		ColorIdentity: colors,
+	}, "")
+	if err != nil {
+		return domain.DeckImportResult{}, err
+	}
+
+	deck, err := s.Store.ImportDeck(ctx, ownerID, in, parsed.Cards)
+	if err != nil {
+		return domain.DeckImportResult{}, err
+	}
+	unparsed := parsed.Unparsed
+	if unparsed == nil {
+		unparsed = []domain.DecklistLine{}
+	}
+	return domain.DeckImportResult{
+		Deck:          deck,
+		Counts:        countDeckCards(parsed.Cards),
+		UnknownCards:  unknown,
+		UnparsedLines: unparsed,
+	}, nil
+}
+
+// matchCards returns the known cards keyed by lower-cased name. Double-faced cards also match
+// their front face name, which is how Arena lists them.
+func (s *DeckService) matchCards(ctx context.Context, cards []domain.DeckCard) (map[string]domain.Card, error) {
+	known := make(map[string]domain.Card)
+	if s.Cards == nil {
+		return known, nil
+	}
+	names := make([]string, 0, len(cards))
+	for _, c := range cards {
+		names = append(names, strings.ToLower(c.Name))
+	}
+	found, err := s.Cards.FindCardsByName(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range found {
+		name := strings.ToLower(c.Name)
+		known[name] = c
+		if front, _, ok := strings.Cut(name, " // "); ok {
+			if _, exists := known[front]; !exists {
+				known[front] = c
+			}
+		}
+	}
+	return known, nil
+}
+
+// CardList returns the deck's stored card list.
+func (s *DeckService) CardList(ctx context.Context, ownerID, deckID string) (domain.DeckList, error) {
+	deck, err := s.Store.GetDeck(ctx, ownerID, deckID)
+	if err != nil {
+		return domain.DeckList{}, err
+	}
+	cards, err := s.Store.ListDeckCards(ctx, deck.ID)
+	if err != nil {
+		return domain.DeckList{}, err
+	}
+	if cards == nil {
+		cards = []domain.DeckCard{}
+	}
+	return domain.DeckList{DeckID: deck.ID, Counts: countDeckCards(cards), Cards: cards}, nil
+}
+
+// Export writes the deck's card list as plain, MTGO or Arena text.
+func (s *DeckService) Export(ctx context.Context, ownerID, deckID string, format DecklistFormat) (string, error) {
+	switch format {
+	case "":
+		format = DecklistPlain
+	case DecklistPlain, DecklistMTGO, DecklistArena:
+	default:
+		return "", domain.NewValidationError(map[string]string{"format": "must be plain, mtgo, or arena"})
+	}
+	list, err := s.CardList(ctx, ownerID, deckID)
+	if err != nil {
+		return "", err
+	}
+	return formatDecklist(list.Cards, format), nil
+}
+
+// validateDeck checks p; current is the deck's stored format, which stays usable once disabled.
+func validateDeck(ctx context.Context, formats FormatResolver, p DeckParams, current domain.GameFormat) (domain.DeckInput, error) {
+	fields := make(map[string]string)
+
+	name := strings.TrimSpace(p.Name)
+	switch {
+	case name == "":
+		fields["name"] = "required"
+	case utf8.RuneCountInString(name) > maxDeckNameLength:
+		fields["name"] = fmt.Sprintf("must be at most %d characters", maxDeckNameLength)
+	}
+
+	format, msg, err := usableFormat(ctx, formats, p.Format, current)
+	if err != nil {
+		return domain.DeckInput{}, err
+	}
+	if msg != "" {
+		fields["format"] = msg
+	}
+
+	commanders := make([]string, 0, len(p.Commanders))
+	for _, c := range p.Commanders {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if utf8.RuneCountInString(c) > maxCommanderNameLength {
+			fields["commanders"] = fmt.Sprintf("each commander must be at most %d characters", maxCommanderNameLength)
+		}
+		commanders = append(commanders, c)
+	}
+	switch {
+	case len(commanders) > maxDeckCommanders:
+		fields["commanders"] = fmt.Sprintf("at most %d commanders", maxDeckCommanders)
+	case len(commanders) > 0 && msg == "" && !format.Commander:
+		fields["commanders"] = "only decks in formats with commanders have commanders"
+	}
+
+	colors, ok := normalizeColorIdentity(p.ColorIdentity)
+	if !ok {
+		fields["color_identity"] = "must only contain W, U, B, R, G, or C for colorless"
+	}
+
+	if len(fields) > 0 {
+		return domain.DeckInput{}, domain.NewValidationError(fields)
+	}
+	return domain.DeckInput{
+		Name:          name,
+		Format:        format.Slug,
+		Commanders:    commanders,
+		ColorIdentity: colors,
+		Archived:      p.Archived,
+	}, nil
+}
+
+// normalizeColorIdentity returns the colors in WUBRG order without duplicates. "C" alone means colorless.
+func normalizeColorIdentity(raw string) (string, bool) {
+	raw = strings.ToUpper(strings.TrimSpace(raw))
+	if raw == "C" {
+		return "", true
+	}
+	for _, r := range raw {
+		if !strings.ContainsRune("WUBRG", r) {
+			return "", false
+		}
+	}
+	var b strings.Builder
+	for _, c := range "WUBRG" {
+		if strings.ContainsRune(raw, c) {
+			b.WriteRune(c)
+		}
+	}
+	return b.String(), true
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type stubDecksStore struct {
+	decks    map[string]domain.Deck
+	updated  domain.DeckInput
+	imported domain.DeckInput
+	cards    []domain.DeckCard
+}
+
+func (s *stubDecksStore) ListDecks(ctx context.Context, ownerID string, includeArchived bool) ([]domain.Deck, error) {
+	return nil, nil
+}
+
+func (s *stubDecksStore) GetDeck(ctx context.Context, ownerID, deckID string) (domain.Deck, error) {
+	deck, ok := s.decks[deckID]
+	if !ok {
+		return domain.Deck{}, domain.ErrNotFound
+	}
+	return deck, nil
+}
+
+func (s *stubDecksStore) CreateDeck(ctx context.Context, ownerID string, in domain.DeckInput) (domain.Deck, error) {
+	return domain.Deck{ID: "d1", Name: in.Name, Format: in.Format, Commanders: in.Commanders, ColorIdentity: in.ColorIdentity}, nil
+}
+
+func (s *stubDecksStore) UpdateDeck(ctx context.Context, ownerID, deckID string, in domain.DeckInput) (domain.Deck, error) {
+	s.updated = in
+	return domain.Deck{ID: deckID, Name: in.Name, Format: in.Format, Commanders: in.Commanders, ColorIdentity: in.ColorIdentity, Archived: in.Archived}, nil
+}
+
+func (s *stubDecksStore) DeleteDeck(ctx context.Context, ownerID, deckID string) error {
+	return nil
+}
+
+func (s *stubDecksStore) ListDeckStats(ctx context.Context, userID string) ([]domain.DeckStats, error) {
+	return nil, nil
+}
+
+func (s *stubDecksStore) DeckStats(ctx context.Context, userID, deckID string) (domain.DeckStats, error) {
+	return domain.DeckStats{}, nil
+}
+
+func (s *stubDecksStore) ImportDeck(ctx context.Context, ownerID string, in domain.DeckInput, cards []domain.DeckCard) (domain.Deck, error) {
+	s.imported = in
+	s.cards = cards
+	return domain.Deck{ID: "d1", Name: in.Name, Format: in.Format, Commanders: in.Commanders, ColorIdentity: in.ColorIdentity}, nil
+}
+
+func (s *stubDecksStore) ListDeckCards(ctx context.Context, deckID string) ([]domain.DeckCard, error) {
+	return s.cards, nil
+}
+
+func TestDeckCreateValidation(t *testing.T) {
+	svc := &DeckService{Store: &stubDecksStore{}}
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, "u1", DeckParams{Name: " "})
+	expectValidation(t, err)
+	_, err = svc.Create(ctx, "u1", DeckParams{Name: "Burn", Format: "modern", Commanders: []string{"Krenko"}})
+	expectValidation(t, err)
+	_, err = svc.Create(ctx, "u1", DeckParams{Name: "Pod", Commanders: []string{"A", "B", "C"}})
+	expectValidation(t, err)
+	_, err = svc.Create(ctx, "u1", DeckParams{Name: "Pod", ColorIdentity: "WX"})
+	expectValidation(t, err)
+
+	deck, err := svc.Create(ctx, "u1", DeckParams{Name: " Atraxa ", Format: "EDH", Commanders: []string{" Atraxa, Praetors' Voice ", ""}, ColorIdentity: "gubw"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deck.Name != "Atraxa" || deck.Format != domain.FormatCommander || deck.ColorIdentity != "WUBG" {
+		t.Fatalf("unexpected deck: %+v", deck)
+	}
+	if want := []string{"Atraxa, Praetors' Voice"}; !reflect.DeepEqual(deck.Commanders, want) {
+		t.Fatalf("expected commanders %v, got %v", want, deck.Commanders)
+	}
+}
+
+func TestDeckUpdateMergesPatch(t *testing.T) {
+	store := &stubDecksStore{decks: map[string]domain.Deck{
+		"d1": {ID: "d1", Name: "Krenko", Format: domain.FormatCommander, Commanders: []string{"Krenko, Mob Boss"}, ColorIdentity: "R"},
+	}}
+	svc := &DeckService{Store: store}
+
+	archived := true
+	colorless := "c"
+	deck, err := svc.Update(context.Background(), "u1", "d1", DeckPatch{Archived: &archived, ColorIdentity: &colorless})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !deck.Archived || deck.Name != "Krenko" || deck.ColorIdentity != "" || len(deck.Commanders) != 1 {
+		t.Fatalf("unexpected deck: %+v", deck)
+	}
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type FormatsStore interface {
+	// ListFormats returns every format, enabled or not, with its aliases.
+	ListFormats(ctx context.Context) ([]domain.Format, error)
+	// CreateFormat returns domain.ErrFormatExists when the slug or an alias is taken.
+	CreateFormat(ctx context.Context, f domain.Format) error
+	// UpdateFormat replaces everything but the slug, aliases included.
+	UpdateFormat(ctx context.Context, f domain.Format) error
+}
+
+// FormatResolver finds a format by slug or alias. Disabled formats are returned too.
+type FormatResolver interface {
+	ResolveFormat(ctx context.Context, name domain.GameFormat) (domain.Format, bool, error)
+}
+
+// FormatService is the registry of game formats, managed by admins. Reads are served from a copy
+// of the registry that is reloaded every formatCacheTTL and after every change made through it.
+type FormatService struct {
+	Store FormatsStore
+	Now   func() time.Time
+
+	mu       sync.Mutex
+	cached   []domain.Format
+	loadedAt time.Time
+}
+
+const (
+	formatCacheTTL          = time.Minute
+	maxFormatNameLength     = 64
+	maxFormatPlayers        = 16
+	maxFormatStartingLife   = 1000
+	maxFormatAliases        = 10
+	formatUnknownMessage    = "must be a format from GET /v1/formats"
+	formatNotEnabledMessage = "format is not enabled"
+)
+
+var formatSlugRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// builtinFormats stand in for the registry when a service has no FormatResolver. They match the
+// rows seeded by the formats migration.
+var builtinFormats = []domain.Format{
+	{Slug: domain.FormatCommander, Name: "Commander", Multiplayer: true, MinPlayers: 2, MaxPlayers: 8, DefaultPlayers: 4, StartingLife: 40, Commander: true, Legality: "commander", Aliases: []string{"edh"}, Enabled: true, SortOrder: 10},
+	{Slug: domain.FormatBrawl, Name: "Brawl", Multiplayer: true, MinPlayers: 2, MaxPlayers: 6, DefaultPlayers: 2, StartingLife: 25, Commander: true, Legality: "brawl", Aliases: []string{}, Enabled: true, SortOrder: 20},
+	{Slug: domain.FormatStandard, Name: "Standard", MinPlayers: 2, MaxPlayers: 2, DefaultPlayers: 2, StartingLife: 20, Legality: "standard", Aliases: []string{}, Enabled: true, SortOrder: 30},
+	{Slug: domain.FormatModern, Name: "Modern", MinPlayers: 2, MaxPlayers: 2, DefaultPlayers: 2, StartingLife: 20, Legality: "modern", Aliases: []string{}, Enabled: true, SortOrder: 40},
+}
+
+// FormatParams describes a format to create or replace. An empty Legality means the slug.
+type FormatParams struct {
+	Slug           string
+	Name           string
+	Multiplayer    bool
+	MinPlayers     int
+	MaxPlayers     int
+	DefaultPlayers int
+	StartingLife   int
+	Commander      bool
+	Teams          bool
+	Legality       string
+	Aliases        []string
+	Enabled        bool
+	SortOrder      int
+}
+
+// List returns the formats in picker order. Disabled formats are only included for admins.
+func (s *FormatService) List(ctx context.Context, includeDisabled bool) ([]domain.Format, error) {
+	all, err := s.formats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.Format, 0, len(all))
+	for _, f := range all {
+		if f.Enabled || includeDisabled {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// Get returns a format by slug, enabled or not.
+func (s *FormatService) Get(ctx context.Context, slug string) (domain.Format, error) {
+	all, err := s.formats(ctx)
+	if err != nil {
+		return domain.Format{}, err
+	}
+	for _, f := range all {
+		if string(f.Slug) == slug {
+			return f, nil
+		}
+	}
+	return domain.Format{}, domain.ErrNotFound
+}
+
+// ResolveFormat implements FormatResolver. name must already be trimmed and lower-cased.
+func (s *FormatService) ResolveFormat(ctx context.Context, name domain.GameFormat) (domain.Format, bool, error) {
+	all, err := s.formats(ctx)
+	if err != nil {
+		return domain.Format{}, false, err
+	}
+	f, ok := findFormat(all, name)
+	return f, ok, nil
+}
+
+func (s *FormatService) Create(ctx context.Context, p FormatParams) (domain.Format, error) {
+	all, err := s.formats(ctx)
+	if err != nil {
+		return domain.Format{}, err
+	}
+	f, err := validateFormat(p, all, "")
+	if err != nil {
+		return domain.Format{}, err
+	}
+	if err := s.Store.CreateFormat(ctx, f); err != nil {
+		return domain.Format{}, err
+	}
+	s.invalidate()
+	return s.Get(ctx, string(f.Slug))
+}
+
+// Update replaces a format's settings and aliases. The slug can't change: matches refer to it.
+func (s *FormatService) Update(ctx context.Context, slug string, p FormatParams) (domain.Format, error) {
+	current, err := s.Get(ctx, slug)
+	if err != nil {
+		return domain.Format{}, err
+	}
+	all, err := s.formats(ctx)
+	if err != nil {
+		return domain.Format{}, err
+	}
+	p.Slug = string(current.Slug)
+	f, err := validateFormat(p, all, current.Slug)
+	if err != nil {
+		return domain.Format{}, err
+	}
+	if err := s.Store.UpdateFormat(ctx, f); err != nil {
+		return domain.Format{}, err
+	}
+	s.invalidate()
+	return s.Get(ctx, slug)
+}
+
+func (s *FormatService) formats(ctx context.Context) ([]domain.Format, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cached != nil && s.now().Sub(s.loadedAt) < formatCacheTTL {
+		return s.cached, nil
+	}
+	all, err := s.Store.ListFormats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sortFormats(all)
+	for i := range all {
+		if all[i].Aliases == nil {
+			all[i].Aliases = []string{}
+		}
+	}
+	s.cached = all
+	s.loadedAt = s.now()
+	return all, nil
+}
+
+func (s *FormatService) invalidate() {
+	s.mu.Lock()
+	s.cached = nil
+	s.mu.Unlock()
+}
+
+func (s *FormatService) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func sortFormats(formats []domain.Format) {
+	sort.SliceStable(formats, func(i, j int) bool {
+		if formats[i].SortOrder != formats[j].SortOrder {
+			return formats[i].SortOrder < formats[j].SortOrder
+		}
+		return formats[i].Slug < formats[j].Slug
+	})
+}
+
+func findFormat(formats []domain.Format, name domain.GameFormat) (domain.Format, bool) {
+	for _, f := range formats {
+		if f.Slug == name {
+			return f, true
+		}
+	}
+	for _, f := range formats {
+		for _, alias := range f.Aliases {
+			if alias == string(name) {
+				return f, true
+			}
+		}
+	}
+	return domain.Format{}, false
+}
+
+// resolveFormat looks raw up by slug or alias, case-insensitively; an empty format is commander.
+// A non-empty message means the format is unknown.
+func resolveFormat(ctx context.Context, formats FormatResolver, raw domain.GameFormat) (domain.Format, string, error) {
+	name := domain.GameFormat(strings.ToLower(strings.TrimSpace(string(raw))))
+	if name == "" {
+		name = domain.FormatCommander
+	}
+
+	var (
+		f   domain.Format
+		ok  bool
+		err error
+	)
+	if formats == nil {
+		f, ok = findFormat(builtinFormats, name)
+	} else {
+		f, ok, err = formats.ResolveFormat(ctx, name)
+		if err != nil {
+			return domain.Format{}, "", err
+		}
+	}
+	if !ok {
+		return domain.Format{}, formatUnknownMessage, nil
+	}
+	return f, "", nil
+}
+
+// usableFormat resolves the format a match, deck or live match is saved with. Disabled formats
+// are only accepted when the record already uses them, so old records stay editable.
+func usableFormat(ctx context.Context, formats FormatResolver, raw, current domain.GameFormat) (domain.Format, string, error) {
+	f, msg, err := resolveFormat(ctx, formats, raw)
+	if err != nil || msg != "" {
+		return domain.Format{}, msg, err
+	}
+	if !f.Enabled && f.Slug != current {
+		return domain.Format{}, formatNotEnabledMessage, nil
+	}
+	return f, "", nil
+}
+
+// filterFormat resolves an optional format filter. Empty means every format. Disabled formats are
+// accepted since older records use them.
+func filterFormat(ctx context.Context, formats FormatResolver, raw domain.GameFormat) (domain.GameFormat, error) {
+	if strings.TrimSpace(string(raw)) == "" {
+		return "", nil
+	}
+	f, msg, err := resolveFormat(ctx, formats, raw)
+	if err != nil {
+		return "", err
+	}
+	if msg != "" {
+		return "", domain.NewValidationError(map[string]string{"format": msg})
+	}
+	return f.Slug, nil
+}
+
+// startingLife is the life total each player starts a game of format with. Unknown formats
+// start at 20.
+func startingLife(ctx context.Context, formats FormatResolver, format domain.GameFormat) (int, error) {
+	f, msg, err := resolveFormat(ctx, formats, format)
+	if err != nil {
+		return 0, err
+	}
+	if msg != "" {
+		return 20, nil
+	}
+	return f.StartingLife, nil
+}
+
+// legalityKey is the card database legality checked for the format's cards.
+func legalityKey(f domain.Format) string {
+	if f.Legality != "" {
+		return f.Legality
+	}
+	return string(f.Slug)
+}
+
+// validateFormat checks p against the other formats in all. current is the slug being updated.
+func validateFormat(p FormatParams, all []domain.Format, current domain.GameFormat) (domain.Format, error) {
+	fields := make(map[string]string)
+
+	slug := strings.ToLower(strings.TrimSpace(p.Slug))
+	if !formatSlugRe.MatchString(slug) {
+		fields["slug"] = "must be 1-32 lowercase letters, digits, '-' or '_'"
+	} else if current == "" {
+		if _, ok := findFormat(all, domain.GameFormat(slug)); ok {
+			fields["slug"] = "already used by another format"
+		}
+	}
+
+	name := strings.TrimSpace(p.Name)
+	switch {
+	case name == "":
+		fields["name"] = "required"
+	case utf8.RuneCountInString(name) > maxFormatNameLength:
+		fields["name"] = fmt.Sprintf("must be at most %d characters", maxFormatNameLength)
+	}
+
+	switch {
+	case p.MinPlayers < 1 || p.MaxPlayers > maxFormatPlayers || p.MinPlayers > p.MaxPlayers:
+		fields["max_players"] = fmt.Sprintf("players must be between 1 and %d, min <= max", maxFormatPlayers)
+	case p.DefaultPlayers < p.MinPlayers || p.DefaultPlayers > p.MaxPlayers:
+		fields["default_players"] = "must be between min_players and max_players"
+	case !p.Multiplayer && (p.MinPlayers != 2 || p.MaxPlayers != 2):
+		fields["max_players"] = "1v1 formats have exactly 2 players"
+	case p.Teams && !p.Multiplayer:
+		fields["teams"] = "only multiplayer formats can be played in teams"
+	}
+
+	if p.StartingLife < 1 || p.StartingLife > maxFormatStartingLife {
+		fields["starting_life"] = fmt.Sprintf("must be between 1 and %d", maxFormatStartingLife)
+	}
+
+	legality := strings.ToLower(strings.TrimSpace(p.Legality))
+	if legality == "" {
+		legality = slug
+	} else if !formatSlugRe.MatchString(legality) {
+		fields["legality"] = "must be a card database legality key such as commander or pauper"
+	}
+
+	aliases := make([]string, 0, len(p.Aliases))
+	seen := map[string]bool{slug: true}
+	for _, a := range p.Aliases {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == "" || seen[a] {
+			continue
+		}
+		seen[a] = true
+		if !formatSlugRe.MatchString(a) {
+			fields["aliases"] = fmt.Sprintf("%q must be 1-32 lowercase letters, digits, '-' or '_'", a)
+			continue
+		}
+		if other, ok := findFormat(all, domain.GameFormat(a)); ok && other.Slug != current {
+			fields["aliases"] = fmt.Sprintf("%q is already used by %s", a, other.Slug)
+			continue
+		}
+		aliases = append(aliases, a)
+	}
+	if len(aliases) > maxFormatAliases {
+		fields["aliases"] = fmt.Sprintf("at most %d aliases", maxFormatAliases)
+	}
+
+	if len(fields) > 0 {
+		return domain.Format{}, domain.NewValidationError(fields)
+	}
+	return domain.Format{
+		Slug:           domain.GameFormat(slug),
+		Name:           name,
+		Multiplayer:    p.Multiplayer,
+		MinPlayers:     p.MinPlayers,
+		MaxPlayers:     p.MaxPlayers,
+		DefaultPlayers: p.DefaultPlayers,
+		StartingLife:   p.StartingLife,
+		Commander:      p.Commander,
+		Teams:          p.Teams,
+		Legality:       legality,
+		Aliases:        aliases,
+		Enabled:        p.Enabled,
+		SortOrder:      p.SortOrder,
+	}, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type stubFormatsStore struct {
+	formats []domain.Format
+	lists   int
+}
+
+func (s *stubFormatsStore) ListFormats(ctx context.Context) ([]domain.Format, error) {
+	s.lists++
+	out := make([]domain.Format, len(s.formats))
+	copy(out, s.formats)
+	return out, nil
+}
+
+func (s *stubFormatsStore) CreateFormat(ctx context.Context, f domain.Format) error {
+	s.formats = append(s.formats, f)
+	return nil
+}
+
+func (s *stubFormatsStore) UpdateFormat(ctx context.Context, f domain.Format) error {
+	for i := range s.formats {
+		if s.formats[i].Slug == f.Slug {
+			s.formats[i] = f
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func newFormatTestService() (*FormatService, *stubFormatsStore) {
+	store := &stubFormatsStore{formats: append([]domain.Format(nil), builtinFormats...)}
+	store.formats = append(store.formats, domain.Format{
+		Slug: "pauper", Name: "Pauper", MinPlayers: 2, MaxPlayers: 2, DefaultPlayers: 2,
+		StartingLife: 20, Legality: "pauper", Aliases: []string{"pau"}, SortOrder: 50,
+	})
+	return &FormatService{Store: store}, store
+}
+
+func TestFormatServiceResolvesAliasesAndCaches(t *testing.T) {
+	svc, store := newFormatTestService()
+	ctx := context.Background()
+
+	f, msg, err := resolveFormat(ctx, svc, " EDH ")
+	if err != nil || msg != "" || f.Slug != domain.FormatCommander || f.StartingLife != 40 {
+		t.Fatalf("expected edh to resolve to commander, got %+v %q %v", f, msg, err)
+	}
+	if _, msg, _ := resolveFormat(ctx, svc, "vintage"); msg != formatUnknownMessage {
+		t.Fatalf("expected vintage to be unknown, got %q", msg)
+	}
+
+	enabled, err := svc.List(ctx, false)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(enabled) != 4 || enabled[0].Slug != domain.FormatCommander {
+		t.Fatalf("expected the 4 enabled formats in sort order, got %+v", enabled)
+	}
+	if store.lists != 1 {
+		t.Fatalf("expected one store read, got %d", store.lists)
+	}
+
+	svc.Now = func() time.Time { return time.Now().Add(2 * formatCacheTTL) }
+	if _, err := svc.List(ctx, true); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if store.lists != 2 {
+		t.Fatalf("expected the cache to expire, got %d store reads", store.lists)
+	}
+}
+
+func TestUsableFormatRejectsDisabledFormats(t *testing.T) {
+	svc, _ := newFormatTestService()
+	ctx := context.Background()
+
+	if _, msg, _ := usableFormat(ctx, svc, "pau", ""); msg != formatNotEnabledMessage {
+		t.Fatalf("expected disabled pauper to be rejected, got %q", msg)
+	}
+	if f, msg, _ := usableFormat(ctx, svc, "pauper", "pauper"); msg != "" || f.Slug != "pauper" {
+		t.Fatalf("expected a record already in pauper to keep it, got %+v %q", f, msg)
+	}
+	if format, err := filterFormat(ctx, svc, "PAU"); err != nil || format != "pauper" {
+		t.Fatalf("expected filters to accept disabled formats, got %q %v", format, err)
+	}
+
+	matches := &MatchService{Matches: &stubMatchesStore{}, Formats: svc}
+	_, _, err := matches.CreateMatch(ctx, "u1", CreateMatchParams{
+		Format:    "pauper",
+		UpdatedAt: time.Now(),
+		Results: []domain.MatchResultInput{
+			{ID: "u1", Rank: 1},
+			{ID: "u2", Rank: 2},
+		},
+	})
+	expectValidation(t, err)
+}
+
+func TestFormatServiceCreateAndUpdate(t *testing.T) {
+	svc, _ := newFormatTestService()
+	ctx := context.Background()
+
+	// Loads the cache so the writes below have to invalidate it.
+	if _, err := svc.List(ctx, true); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+
+	_, err := svc.Create(ctx, FormatParams{Slug: "oathbreaker", Name: "Oathbreaker", Multiplayer: true, MinPlayers: 2, MaxPlayers: 6, DefaultPlayers: 4, StartingLife: 20, Aliases: []string{"edh"}})
+	expectValidation(t, err)
+	_, err = svc.Create(ctx, FormatParams{Slug: "duel", Name: "Duel", MinPlayers: 2, MaxPlayers: 4, DefaultPlayers: 2, StartingLife: 20})
+	expectValidation(t, err)
+
+	created, err := svc.Create(ctx, FormatParams{Slug: " Oathbreaker ", Name: "Oathbreaker", Multiplayer: true, MinPlayers: 2, MaxPlayers: 6, DefaultPlayers: 4, StartingLife: 20, Commander: true, Aliases: []string{"OB", "ob"}, Enabled: true})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.Slug != "oathbreaker" || created.Legality != "oathbreaker" || len(created.Aliases) != 1 || created.Aliases[0] != "ob" {
+		t.Fatalf("unexpected created format: %+v", created)
+	}
+	if f, msg, _ := usableFormat(ctx, svc, "ob", ""); msg != "" || f.Slug != "oathbreaker" {
+		t.Fatalf("expected the new alias to resolve, got %+v %q", f, msg)
+	}
+
+	updated, err := svc.Update(ctx, "pauper", FormatParams{Slug: "ignored", Name: "Pauper", MinPlayers: 2, MaxPlayers: 2, DefaultPlayers: 2, StartingLife: 20, Legality: "pauper", Enabled: true})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Slug != "pauper" || !updated.Enabled || len(updated.Aliases) != 0 {
+		t.Fatalf("unexpected updated format: %+v", updated)
+	}
+	if _, msg, _ := usableFormat(ctx, svc, "pauper", ""); msg != "" {
+		t.Fatalf("expected pauper to be usable once enabled, got %q", msg)
+	}
+}
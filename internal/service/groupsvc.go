@@ -0,0 +1,457 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type GroupsStore interface {
+	CreateGroup(ctx context.Context, ownerID, name, description, joinCode string) (string, error)
+	GetGroupForUser(ctx context.Context, groupID, userID string) (domain.Group, error)
+	GetGroupByJoinCode(ctx context.Context, joinCode string) (domain.GroupSummary, error)
+	ListGroupsForUser(ctx context.Context, userID string) ([]domain.Group, error)
+	UpdateGroup(ctx context.Context, groupID, name, description string) error
+	SetJoinCode(ctx context.Context, groupID, joinCode string) error
+	DeleteGroup(ctx context.Context, groupID string) error
+
+	ListMembers(ctx context.Context, groupID string) ([]domain.GroupMember, error)
+	GetMemberRole(ctx context.Context, groupID, userID string) (domain.GroupRole, error)
+	AddMember(ctx context.Context, groupID, userID string, role domain.GroupRole) error
+	SetMemberRole(ctx context.Context, groupID, userID string, role domain.GroupRole) error
+	TransferOwnership(ctx context.Context, groupID, fromUserID, toUserID string) error
+	RemoveMember(ctx context.Context, groupID, userID string) error
+	ShareGroup(ctx context.Context, userA, userB string) (bool, error)
+
+	CreateInvite(ctx context.Context, groupID, userID, invitedBy string) (string, error)
+	GetInvite(ctx context.Context, inviteID string) (domain.GroupInvite, error)
+	ListInvitesForUser(ctx context.Context, userID string) ([]domain.GroupInvite, error)
+	ListInvitesForGroup(ctx context.Context, groupID string) ([]domain.GroupInvite, error)
+	AcceptInvite(ctx context.Context, inviteID, userID string) error
+	DeleteInvite(ctx context.Context, inviteID string) error
+
+	ListGroupResults(ctx context.Context, groupID string, format domain.GameFormat) ([]domain.MatchPlacement, error)
+}
+
+type GroupUsersStore interface {
+	GetUserByLogin(ctx context.Context, login string) (domain.UserWithPassword, error)
+}
+
+// GroupService manages playgroups. Members may record matches with each other without being friends.
+type GroupService struct {
+	Store   GroupsStore
+	Users   GroupUsersStore
+	Formats FormatResolver
+}
+
+const (
+	maxGroupNameLength        = 80
+	maxGroupDescriptionLength = 500
+)
+
+type GroupPatch struct {
+	Name        *string
+	Description *string
+}
+
+func (s *GroupService) Create(ctx context.Context, userID, name, description string) (domain.Group, error) {
+	name, description, err := validateGroupFields(name, description)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	code, err := newGroupJoinCode()
+	if err != nil {
+		return domain.Group{}, err
+	}
+	groupID, err := s.Store.CreateGroup(ctx, userID, name, description, code)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	return s.Store.GetGroupForUser(ctx, groupID, userID)
+}
+
+func (s *GroupService) List(ctx context.Context, userID string) ([]domain.Group, error) {
+	groups, err := s.Store.ListGroupsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if groups == nil {
+		groups = []domain.Group{}
+	}
+	return groups, nil
+}
+
+// Get returns ErrNotFound for groups the user is not a member of.
+func (s *GroupService) Get(ctx context.Context, userID, groupID string) (domain.Group, error) {
+	return s.Store.GetGroupForUser(ctx, groupID, userID)
+}
+
+func (s *GroupService) Update(ctx context.Context, userID, groupID string, p GroupPatch) (domain.Group, error) {
+	group, err := s.requireManager(ctx, userID, groupID)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	name, description := group.Name, group.Description
+	if p.Name != nil {
+		name = *p.Name
+	}
+	if p.Description != nil {
+		description = *p.Description
+	}
+	name, description, err = validateGroupFields(name, description)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	if err := s.Store.UpdateGroup(ctx, groupID, name, description); err != nil {
+		return domain.Group{}, err
+	}
+	return s.Store.GetGroupForUser(ctx, groupID, userID)
+}
+
+func (s *GroupService) Delete(ctx context.Context, userID, groupID string) error {
+	group, err := s.Store.GetGroupForUser(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if group.Role != domain.GroupRoleOwner {
+		return domain.ErrForbidden
+	}
+	return s.Store.DeleteGroup(ctx, groupID)
+}
+
+// RotateJoinCode invalidates the current join code.
+func (s *GroupService) RotateJoinCode(ctx context.Context, userID, groupID string) (domain.Group, error) {
+	if _, err := s.requireManager(ctx, userID, groupID); err != nil {
+		return domain.Group{}, err
+	}
+	code, err := newGroupJoinCode()
+	if err != nil {
+		return domain.Group{}, err
+	}
+	if err := s.Store.SetJoinCode(ctx, groupID, code); err != nil {
+		return domain.Group{}, err
+	}
+	return s.Store.GetGroupForUser(ctx, groupID, userID)
+}
+
+func (s *GroupService) Join(ctx context.Context, userID, joinCode string) (domain.Group, error) {
+	joinCode = strings.ToUpper(strings.TrimSpace(joinCode))
+	if joinCode == "" {
+		return domain.Group{}, domain.NewValidationError(map[string]string{"code": "required"})
+	}
+	summary, err := s.Store.GetGroupByJoinCode(ctx, joinCode)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	if err := s.Store.AddMember(ctx, summary.ID, userID, domain.GroupRoleMember); err != nil {
+		return domain.Group{}, err
+	}
+	return s.Store.GetGroupForUser(ctx, summary.ID, userID)
+}
+
+func (s *GroupService) Members(ctx context.Context, userID, groupID string) ([]domain.GroupMember, error) {
+	if _, err := s.Store.GetMemberRole(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	members, err := s.Store.ListMembers(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if members == nil {
+		members = []domain.GroupMember{}
+	}
+	return members, nil
+}
+
+// SetRole is owner-only. Giving another member the owner role transfers ownership and
+// makes the previous owner an admin.
+func (s *GroupService) SetRole(ctx context.Context, userID, groupID, memberID string, role domain.GroupRole) error {
+	callerRole, err := s.Store.GetMemberRole(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if callerRole != domain.GroupRoleOwner {
+		return domain.ErrForbidden
+	}
+	if memberID == userID {
+		return domain.NewValidationError(map[string]string{"user_id": "cannot change your own role"})
+	}
+	if _, err := s.Store.GetMemberRole(ctx, groupID, memberID); err != nil {
+		return err
+	}
+
+	switch role {
+	case domain.GroupRoleOwner:
+		return s.Store.TransferOwnership(ctx, groupID, userID, memberID)
+	case domain.GroupRoleAdmin, domain.GroupRoleMember:
+		return s.Store.SetMemberRole(ctx, groupID, memberID, role)
+	default:
+		return domain.NewValidationError(map[string]string{"role": "must be owner, admin, or member"})
+	}
+}
+
+// RemoveMember lets members leave, admins remove plain members and owners remove anyone.
+// The owner has to transfer ownership (or delete the group) before leaving.
+func (s *GroupService) RemoveMember(ctx context.Context, userID, groupID, memberID string) error {
+	callerRole, err := s.Store.GetMemberRole(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	targetRole, err := s.Store.GetMemberRole(ctx, groupID, memberID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case memberID == userID:
+		if callerRole == domain.GroupRoleOwner {
+			return domain.NewValidationError(map[string]string{"user_id": "transfer ownership before leaving the group"})
+		}
+	case callerRole == domain.GroupRoleOwner:
+	case callerRole == domain.GroupRoleAdmin && targetRole == domain.GroupRoleMember:
+	default:
+		return domain.ErrForbidden
+	}
+	return s.Store.RemoveMember(ctx, groupID, memberID)
+}
+
+func (s *GroupService) Invite(ctx context.Context, userID, groupID, username string) (domain.GroupInvite, error) {
+	if _, err := s.requireManager(ctx, userID, groupID); err != nil {
+		return domain.GroupInvite{}, err
+	}
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return domain.GroupInvite{}, domain.NewValidationError(map[string]string{"username": "required"})
+	}
+	target, err := s.Users.GetUserByLogin(ctx, username)
+	if err != nil {
+		return domain.GroupInvite{}, err
+	}
+	if target.Status == domain.UserStatusDisabled {
+		return domain.GroupInvite{}, domain.ErrForbidden
+	}
+	if _, err := s.Store.GetMemberRole(ctx, groupID, target.ID); err == nil {
+		return domain.GroupInvite{}, domain.ErrAlreadyGroupMember
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return domain.GroupInvite{}, err
+	}
+
+	inviteID, err := s.Store.CreateInvite(ctx, groupID, target.ID, userID)
+	if err != nil {
+		return domain.GroupInvite{}, err
+	}
+	return s.Store.GetInvite(ctx, inviteID)
+}
+
+// Invites lists pending invites addressed to the user.
+func (s *GroupService) Invites(ctx context.Context, userID string) ([]domain.GroupInvite, error) {
+	invites, err := s.Store.ListInvitesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if invites == nil {
+		invites = []domain.GroupInvite{}
+	}
+	return invites, nil
+}
+
+// GroupInvites lists a group's pending invites for its owner and admins.
+func (s *GroupService) GroupInvites(ctx context.Context, userID, groupID string) ([]domain.GroupInvite, error) {
+	if _, err := s.requireManager(ctx, userID, groupID); err != nil {
+		return nil, err
+	}
+	invites, err := s.Store.ListInvitesForGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if invites == nil {
+		invites = []domain.GroupInvite{}
+	}
+	return invites, nil
+}
+
+func (s *GroupService) AcceptInvite(ctx context.Context, userID, inviteID string) (domain.Group, error) {
+	invite, err := s.Store.GetInvite(ctx, inviteID)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	if invite.User.ID != userID {
+		return domain.Group{}, domain.ErrNotFound
+	}
+	if err := s.Store.AcceptInvite(ctx, inviteID, userID); err != nil {
+		return domain.Group{}, err
+	}
+	return s.Store.GetGroupForUser(ctx, invite.Group.ID, userID)
+}
+
+// DeclineInvite is used by the invitee to decline and by group managers to cancel.
+func (s *GroupService) DeclineInvite(ctx context.Context, userID, inviteID string) error {
+	invite, err := s.Store.GetInvite(ctx, inviteID)
+	if err != nil {
+		return err
+	}
+	if invite.User.ID != userID {
+		role, err := s.Store.GetMemberRole(ctx, invite.Group.ID, userID)
+		if err != nil {
+			return domain.ErrNotFound
+		}
+		if !role.CanManage() {
+			return domain.ErrForbidden
+		}
+	}
+	return s.Store.DeleteInvite(ctx, inviteID)
+}
+
+func (s *GroupService) Stats(ctx context.Context, userID, groupID string, rawFormat domain.GameFormat) (domain.GroupStats, error) {
+	if _, err := s.Store.GetMemberRole(ctx, groupID, userID); err != nil {
+		return domain.GroupStats{}, err
+	}
+	format, err := filterFormat(ctx, s.Formats, rawFormat)
+	if err != nil {
+		return domain.GroupStats{}, err
+	}
+	results, err := s.Store.ListGroupResults(ctx, groupID, format)
+	if err != nil {
+		return domain.GroupStats{}, err
+	}
+	return ComputeGroupStats(results), nil
+}
+
+// MemberRole implements SeasonGroupRoles.
+func (s *GroupService) MemberRole(ctx context.Context, groupID, userID string) (domain.GroupRole, error) {
+	return s.Store.GetMemberRole(ctx, groupID, userID)
+}
+
+// IsGroupMember implements GroupMembershipChecker.
+func (s *GroupService) IsGroupMember(ctx context.Context, groupID, userID string) (bool, error) {
+	if _, err := s.Store.GetMemberRole(ctx, groupID, userID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ShareGroup implements GroupMembershipChecker.
+func (s *GroupService) ShareGroup(ctx context.Context, userA, userB string) (bool, error) {
+	return s.Store.ShareGroup(ctx, userA, userB)
+}
+
+// placementKey identifies the player behind a placement: a user, a roster guest, or a guest name.
+func placementKey(r domain.MatchPlacement) string {
+	switch {
+	case r.User != nil:
+		return "u:" + r.User.ID
+	case r.GuestID != "":
+		return "r:" + r.GuestID
+	default:
+		return "g:" + strings.ToLower(strings.TrimSpace(r.GuestName))
+	}
+}
+
+// ComputeGroupStats aggregates per-player results; players are ordered by wins, then win rate.
+func ComputeGroupStats(results []domain.MatchPlacement) domain.GroupStats {
+	type tally struct {
+		stats      domain.GroupPlayerStats
+		placeTotal int
+	}
+	byPlayer := make(map[string]*tally)
+	var order []string
+	matchFormats := make(map[string]domain.GameFormat)
+
+	for _, r := range results {
+		matchFormats[r.MatchID] = r.Format
+
+		key := placementKey(r)
+		t, ok := byPlayer[key]
+		if !ok {
+			t = &tally{stats: domain.GroupPlayerStats{User: r.User, GuestID: r.GuestID}}
+			if r.User == nil {
+				t.stats.GuestName = strings.TrimSpace(r.GuestName)
+			}
+			byPlayer[key] = t
+			order = append(order, key)
+		}
+		t.stats.MatchesPlayed++
+		t.placeTotal += r.Place
+		if r.Place == 1 && !r.Draw {
+			t.stats.Wins++
+		}
+	}
+
+	out := domain.GroupStats{
+		MatchesPlayed: len(matchFormats),
+		ByFormat:      make(map[string]int),
+		Players:       make([]domain.GroupPlayerStats, 0, len(order)),
+	}
+	for _, format := range matchFormats {
+		out.ByFormat[string(format)]++
+	}
+	for _, key := range order {
+		t := byPlayer[key]
+		st := t.stats
+		if st.MatchesPlayed > 0 {
+			st.WinPct = float64(st.Wins) / float64(st.MatchesPlayed)
+			st.AvgPlace = float64(t.placeTotal) / float64(st.MatchesPlayed)
+		}
+		out.Players = append(out.Players, st)
+	}
+	sort.SliceStable(out.Players, func(i, j int) bool {
+		a, b := out.Players[i], out.Players[j]
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if a.WinPct != b.WinPct {
+			return a.WinPct > b.WinPct
+		}
+		return a.AvgPlace < b.AvgPlace
+	})
+	return out
+}
+
+func (s *GroupService) requireManager(ctx context.Context, userID, groupID string) (domain.Group, error) {
+	group, err := s.Store.GetGroupForUser(ctx, groupID, userID)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	if !group.Role.CanManage() {
+		return domain.Group{}, domain.ErrForbidden
+	}
+	return group, nil
+}
+
+func validateGroupFields(name, description string) (string, string, error) {
+	fields := make(map[string]string)
+	name = strings.TrimSpace(name)
+	description = strings.TrimSpace(description)
+	switch {
+	case name == "":
+		fields["name"] = "required"
+	case utf8.RuneCountInString(name) > maxGroupNameLength:
+		fields["name"] = fmt.Sprintf("must be at most %d characters", maxGroupNameLength)
+	}
+	if utf8.RuneCountInString(description) > maxGroupDescriptionLength {
+		fields["description"] = fmt.Sprintf("must be at most %d characters", maxGroupDescriptionLength)
+	}
+	if len(fields) > 0 {
+		return "", "", domain.NewValidationError(fields)
+	}
+	return name, description, nil
+}
+
+// newGroupJoinCode returns 10 base32 characters; easy to read out loud at the table.
+func newGroupJoinCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("read join code: %w", err)
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)[:10], nil
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type stubGroupsStore struct {
+	roles       map[string]domain.GroupRole
+	transferred [2]string
+	setRole     domain.GroupRole
+	removed     string
+	invited     string
+	results     []domain.MatchPlacement
+}
+
+func (s *stubGroupsStore) CreateGroup(ctx context.Context, ownerID, name, description, joinCode string) (string, error) {
+	s.roles[ownerID] = domain.GroupRoleOwner
+	return "g1", nil
+}
+
+func (s *stubGroupsStore) GetGroupForUser(ctx context.Context, groupID, userID string) (domain.Group, error) {
+	role, ok := s.roles[userID]
+	if !ok {
+		return domain.Group{}, domain.ErrNotFound
+	}
+	return domain.Group{ID: groupID, Name: "Pod", Role: role}, nil
+}
+
+func (s *stubGroupsStore) GetGroupByJoinCode(ctx context.Context, joinCode string) (domain.GroupSummary, error) {
+	return domain.GroupSummary{}, domain.ErrNotFound
+}
+
+func (s *stubGroupsStore) ListGroupsForUser(ctx context.Context, userID string) ([]domain.Group, error) {
+	return nil, nil
+}
+
+func (s *stubGroupsStore) UpdateGroup(ctx context.Context, groupID, name, description string) error {
+	return nil
+}
+
+func (s *stubGroupsStore) SetJoinCode(ctx context.Context, groupID, joinCode string) error {
+	return nil
+}
+
+func (s *stubGroupsStore) DeleteGroup(ctx context.Context, groupID string) error {
+	return nil
+}
+
+func (s *stubGroupsStore) ListMembers(ctx context.Context, groupID string) ([]domain.GroupMember, error) {
+	return nil, nil
+}
+
+func (s *stubGroupsStore) GetMemberRole(ctx context.Context, groupID, userID string) (domain.GroupRole, error) {
+	role, ok := s.roles[userID]
+	if !ok {
+		return "", domain.ErrNotFound
+	}
+	return role, nil
+}
+
+func (s *stubGroupsStore) AddMember(ctx context.Context, groupID, userID string, role domain.GroupRole) error {
+	if _, ok := s.roles[userID]; ok {
+		return domain.ErrAlreadyGroupMember
+	}
+	s.roles[userID] = role
+	return nil
+}
+
+func (s *stubGroupsStore) SetMemberRole(ctx context.Context, groupID, userID string, role domain.GroupRole) error {
+	s.setRole = role
+	return nil
+}
+
+func (s *stubGroupsStore) TransferOwnership(ctx context.Context, groupID, fromUserID, toUserID string) error {
+	s.transferred = [2]string{fromUserID, toUserID}
+	return nil
+}
+
+func (s *stubGroupsStore) RemoveMember(ctx context.Context, groupID, userID string) error {
+	s.removed = userID
+	return nil
+}
+
+func (s *stubGroupsStore) ShareGroup(ctx context.Context, userA, userB string) (bool, error) {
+	_, okA := s.roles[userA]
+	_, okB := s.roles[userB]
+	return okA && okB, nil
+}
+
+func (s *stubGroupsStore) CreateInvite(ctx context.Context, groupID, userID, invitedBy string) (string, error) {
+	s.invited = userID
+	return "inv1", nil
+}
+
+func (s *stubGroupsStore) GetInvite(ctx context.Context, inviteID string) (domain.GroupInvite, error) {
+	return domain.GroupInvite{ID: inviteID, Group: domain.GroupSummary{ID: "g1"}, User: domain.UserSummary{ID: s.invited}}, nil
+}
+
+func (s *stubGroupsStore) ListInvitesForUser(ctx context.Context, userID string) ([]domain.GroupInvite, error) {
+	return nil, nil
+}
+
+func (s *stubGroupsStore) ListInvitesForGroup(ctx context.Context, groupID string) ([]domain.GroupInvite, error) {
+	return nil, nil
+}
+
+func (s *stubGroupsStore) AcceptInvite(ctx context.Context, inviteID, userID string) error {
+	return nil
+}
+
+func (s *stubGroupsStore) DeleteInvite(ctx context.Context, inviteID string) error {
+	return nil
+}
+
+func (s *stubGroupsStore) ListGroupResults(ctx context.Context, groupID string, format domain.GameFormat) ([]domain.MatchPlacement, error) {
+	return s.results, nil
+}
+
+type stubGroupUsers struct {
+	users map[string]domain.UserWithPassword
+}
+
+func (s *stubGroupUsers) GetUserByLogin(ctx context.Context, login string) (domain.UserWithPassword, error) {
+	u, ok := s.users[login]
+	if !ok {
+		return domain.UserWithPassword{}, domain.ErrNotFound
+	}
+	return u, nil
+}
+
+func newTestGroupService() (*GroupService, *stubGroupsStore) {
+	store := &stubGroupsStore{roles: map[string]domain.GroupRole{
+		"owner":  domain.GroupRoleOwner,
+		"admin":  domain.GroupRoleAdmin,
+		"member": domain.GroupRoleMember,
+	}}
+	users := &stubGroupUsers{users: map[string]domain.UserWithPassword{
+		"member":   {User: domain.User{ID: "member", Username: "member"}},
+		"newcomer": {User: domain.User{ID: "newcomer", Username: "newcomer"}},
+	}}
+	return &GroupService{Store: store, Users: users}, store
+}
+
+func TestGroupSetRoleOwnerOnly(t *testing.T) {
+	svc, store := newTestGroupService()
+	ctx := context.Background()
+
+	if err := svc.SetRole(ctx, "admin", "g1", "member", domain.GroupRoleAdmin); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden for admin, got %v", err)
+	}
+	if err := svc.SetRole(ctx, "owner", "g1", "member", domain.GroupRoleAdmin); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.setRole != domain.GroupRoleAdmin {
+		t.Fatalf("expected admin role stored, got %q", store.setRole)
+	}
+	if err := svc.SetRole(ctx, "owner", "g1", "admin", domain.GroupRoleOwner); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.transferred != [2]string{"owner", "admin"} {
+		t.Fatalf("expected ownership transfer, got %v", store.transferred)
+	}
+	expectValidation(t, svc.SetRole(ctx, "owner", "g1", "member", "boss"))
+}
+
+func TestGroupRemoveMemberRules(t *testing.T) {
+	svc, store := newTestGroupService()
+	ctx := context.Background()
+
+	expectValidation(t, svc.RemoveMember(ctx, "owner", "g1", "owner"))
+	if err := svc.RemoveMember(ctx, "admin", "g1", "owner"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden removing owner, got %v", err)
+	}
+	if err := svc.RemoveMember(ctx, "member", "g1", "admin"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden for member, got %v", err)
+	}
+	if err := svc.RemoveMember(ctx, "admin", "g1", "member"); err != nil || store.removed != "member" {
+		t.Fatalf("expected admin to remove member, got %v (%q)", err, store.removed)
+	}
+	if err := svc.RemoveMember(ctx, "admin", "g1", "admin"); err != nil || store.removed != "admin" {
+		t.Fatalf("expected admin to leave, got %v (%q)", err, store.removed)
+	}
+}
+
+func TestGroupInvite(t *testing.T) {
+	svc, store := newTestGroupService()
+	ctx := context.Background()
+
+	if _, err := svc.Invite(ctx, "member", "g1", "newcomer"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden for plain member, got %v", err)
+	}
+	if _, err := svc.Invite(ctx, "admin", "g1", "member"); !errors.Is(err, domain.ErrAlreadyGroupMember) {
+		t.Fatalf("expected already member, got %v", err)
+	}
+	invite, err := svc.Invite(ctx, "admin", "g1", "newcomer")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.invited != "newcomer" || invite.ID != "inv1" {
+		t.Fatalf("unexpected invite: %+v", invite)
+	}
+	if _, err := svc.AcceptInvite(ctx, "member", invite.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected other users to see not found, got %v", err)
+	}
+}
+
+func TestComputeGroupStats(t *testing.T) {
+	alice := &domain.UserSummary{ID: "a", Username: "alice"}
+	bob := &domain.UserSummary{ID: "b", Username: "bob"}
+	results := []domain.MatchPlacement{
+		{MatchID: "m1", Format: domain.FormatCommander, User: alice, Place: 1},
+		{MatchID: "m1", Format: domain.FormatCommander, User: bob, Place: 2},
+		{MatchID: "m1", Format: domain.FormatCommander, GuestName: "Sam", Place: 3},
+		{MatchID: "m2", Format: domain.FormatModern, User: bob, Place: 1},
+		{MatchID: "m2", Format: domain.FormatModern, GuestName: " sam ", Place: 2},
+		{MatchID: "m3", Format: domain.FormatCommander, User: bob, Place: 1},
+		{MatchID: "m3", Format: domain.FormatCommander, User: alice, Place: 2},
+	}
+
+	stats := ComputeGroupStats(results)
+	if stats.MatchesPlayed != 3 {
+		t.Fatalf("expected 3 matches, got %d", stats.MatchesPlayed)
+	}
+	if stats.ByFormat["commander"] != 2 || stats.ByFormat["modern"] != 1 {
+		t.Fatalf("unexpected formats: %v", stats.ByFormat)
+	}
+	if len(stats.Players) != 3 {
+		t.Fatalf("expected 3 players, got %d", len(stats.Players))
+	}
+	if stats.Players[0].User == nil || stats.Players[0].User.ID != "b" || stats.Players[0].Wins != 2 {
+		t.Fatalf("expected bob first, got %+v", stats.Players[0])
+	}
+	guest := stats.Players[2]
+	if guest.GuestName != "Sam" || guest.MatchesPlayed != 2 || guest.AvgPlace != 2.5 {
+		t.Fatalf("unexpected guest row: %+v", guest)
+	}
+}
+
+func TestCreateMatchAllowsGroupMatesWithoutFriendship(t *testing.T) {
+	groups, _ := newTestGroupService()
+	store := &stubMatchesStore{returnID: "match-g", createdFlag: true, matchForUser: domain.Match{ID: "match-g"}}
+	svc := &MatchService{Matches: store, Friends: noFriends{}, Groups: groups}
+
+	players := []domain.MatchParticipantInput{
+		{SeatIndex: 0, UserID: "owner", Place: 1},
+		{SeatIndex: 1, UserID: "member", Place: 2},
+	}
+	if _, _, err := svc.CreateMatch(context.Background(), "owner", CreateMatchParams{
+		ClientMatchID: "client-g1",
+		UpdatedAt:     time.Now(),
+		Players:       players,
+	}); err != nil {
+		t.Fatalf("expected group mates to be allowed, got %v", err)
+	}
+
+	players[1].UserID = "stranger"
+	if _, _, err := svc.CreateMatch(context.Background(), "owner", CreateMatchParams{
+		ClientMatchID: "client-g2",
+		UpdatedAt:     time.Now(),
+		Players:       players,
+	}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden for non-friend outside the group, got %v", err)
+	}
+
+	if _, _, err := svc.CreateMatch(context.Background(), "stranger", CreateMatchParams{
+		ClientMatchID: "client-g3",
+		GroupID:       "g1",
+		UpdatedAt:     time.Now(),
+		Players: []domain.MatchParticipantInput{
+			{SeatIndex: 0, UserID: "stranger", Place: 1},
+			{SeatIndex: 1, GuestName: "Guest", Place: 2},
+		},
+	}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected non-members to see not found, got %v", err)
+	}
+}
+
+type noFriends struct{}
+
+func (noFriends) AreFriends(ctx context.Context, userA, userB string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type GuestClaimsStore interface {
+	CountGuestMatches(ctx context.Context, createdBy, guestName, guestID string) (int, error)
+	CreateGuestClaim(ctx context.Context, createdBy, guestName, guestID, userID string) (string, error)
+	GetGuestClaim(ctx context.Context, claimID string) (domain.GuestClaim, error)
+	ListIncomingGuestClaims(ctx context.Context, userID string) ([]domain.GuestClaim, error)
+	ListOutgoingGuestClaims(ctx context.Context, userID string) ([]domain.GuestClaim, error)
+	AcceptGuestClaim(ctx context.Context, claimID, userID string, at time.Time) (int, error)
+	DeleteGuestClaim(ctx context.Context, claimID string) error
+}
+
+// GuestClaimService lets a match creator hand the history they recorded for a guest to a
+// registered friend. Accepting rewrites the guest seats, so stats and ratings follow the user.
+type GuestClaimService struct {
+	Store   GuestClaimsStore
+	Friends FriendshipChecker
+	Guests  GuestRoster
+	Now     func() time.Time
+}
+
+// Create offers the creator's guest seats named guestName to userID. With guestID, it offers a
+// saved guest instead: every seat linked to it or recorded under one of its aliases.
+func (s *GuestClaimService) Create(ctx context.Context, creatorID, guestName, guestID, userID string) (domain.GuestClaim, error) {
+	guestName = strings.TrimSpace(guestName)
+	guestID = strings.TrimSpace(guestID)
+	userID = strings.TrimSpace(userID)
+	fields := map[string]string{}
+	if guestName == "" && guestID == "" {
+		fields["guest_name"] = "required"
+	}
+	if userID == "" {
+		fields["user_id"] = "required"
+	} else if userID == creatorID {
+		fields["user_id"] = "must be another player"
+	}
+	if len(fields) > 0 {
+		return domain.GuestClaim{}, domain.NewValidationError(fields)
+	}
+
+	ok, err := s.Friends.AreFriends(ctx, creatorID, userID)
+	if err != nil {
+		return domain.GuestClaim{}, err
+	}
+	if !ok {
+		return domain.GuestClaim{}, domain.ErrForbidden
+	}
+
+	if guestID != "" {
+		if s.Guests == nil {
+			return domain.GuestClaim{}, domain.NewValidationError(map[string]string{"guest_id": "not supported"})
+		}
+		guest, err := s.Guests.GetGuest(ctx, creatorID, guestID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.GuestClaim{}, domain.NewValidationError(map[string]string{"guest_id": "must be one of your saved guests"})
+		}
+		if err != nil {
+			return domain.GuestClaim{}, err
+		}
+		guestName = guest.DisplayName
+	}
+
+	n, err := s.Store.CountGuestMatches(ctx, creatorID, guestName, guestID)
+	if err != nil {
+		return domain.GuestClaim{}, err
+	}
+	if n == 0 {
+		return domain.GuestClaim{}, domain.NewValidationError(map[string]string{"guest_name": "no matches you recorded use this guest"})
+	}
+
+	claimID, err := s.Store.CreateGuestClaim(ctx, creatorID, guestName, guestID, userID)
+	if err != nil {
+		return domain.GuestClaim{}, err
+	}
+	return s.Store.GetGuestClaim(ctx, claimID)
+}
+
+// List returns the claims offered to the user and the ones they offered.
+func (s *GuestClaimService) List(ctx context.Context, userID string) (domain.GuestClaimsOverview, error) {
+	incoming, err := s.Store.ListIncomingGuestClaims(ctx, userID)
+	if err != nil {
+		return domain.GuestClaimsOverview{}, err
+	}
+	outgoing, err := s.Store.ListOutgoingGuestClaims(ctx, userID)
+	if err != nil {
+		return domain.GuestClaimsOverview{}, err
+	}
+	if incoming == nil {
+		incoming = []domain.GuestClaim{}
+	}
+	if outgoing == nil {
+		outgoing = []domain.GuestClaim{}
+	}
+	return domain.GuestClaimsOverview{Incoming: incoming, Outgoing: outgoing}, nil
+}
+
+// Accept converts the guest seats to the user and returns how many matches changed.
+func (s *GuestClaimService) Accept(ctx context.Context, userID, claimID string) (int, error) {
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+	claim, err := s.Store.GetGuestClaim(ctx, claimID)
+	if err != nil {
+		return 0, err
+	}
+	if claim.User.ID != userID {
+		return 0, domain.ErrNotFound
+	}
+	return s.Store.AcceptGuestClaim(ctx, claimID, userID, s.Now().UTC().Truncate(time.Millisecond))
+}
+
+// Decline is used by the invited user to decline and by the creator to withdraw.
+func (s *GuestClaimService) Decline(ctx context.Context, userID, claimID string) error {
+	claim, err := s.Store.GetGuestClaim(ctx, claimID)
+	if err != nil {
+		return err
+	}
+	if claim.User.ID != userID && claim.InvitedBy.ID != userID {
+		return domain.ErrNotFound
+	}
+	return s.Store.DeleteGuestClaim(ctx, claimID)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type stubGuestClaimsStore struct {
+	guestMatches map[string]int
+	claims       map[string]domain.GuestClaim
+	accepted     string
+	acceptedAt   time.Time
+	deleted      string
+}
+
+func (s *stubGuestClaimsStore) CountGuestMatches(ctx context.Context, createdBy, guestName, guestID string) (int, error) {
+	return s.guestMatches[guestName], nil
+}
+
+func (s *stubGuestClaimsStore) CreateGuestClaim(ctx context.Context, createdBy, guestName, guestID, userID string) (string, error) {
+	s.claims["c1"] = domain.GuestClaim{
+		ID:         "c1",
+		GuestName:  guestName,
+		GuestID:    guestID,
+		InvitedBy:  domain.UserSummary{ID: createdBy},
+		User:       domain.UserSummary{ID: userID},
+		MatchCount: s.guestMatches[guestName],
+	}
+	return "c1", nil
+}
+
+func (s *stubGuestClaimsStore) GetGuestClaim(ctx context.Context, claimID string) (domain.GuestClaim, error) {
+	claim, ok := s.claims[claimID]
+	if !ok {
+		return domain.GuestClaim{}, domain.ErrNotFound
+	}
+	return claim, nil
+}
+
+func (s *stubGuestClaimsStore) ListIncomingGuestClaims(ctx context.Context, userID string) ([]domain.GuestClaim, error) {
+	return nil, nil
+}
+
+func (s *stubGuestClaimsStore) ListOutgoingGuestClaims(ctx context.Context, userID string) ([]domain.GuestClaim, error) {
+	return nil, nil
+}
+
+func (s *stubGuestClaimsStore) AcceptGuestClaim(ctx context.Context, claimID, userID string, at time.Time) (int, error) {
+	s.accepted = claimID
+	s.acceptedAt = at
+	return s.claims[claimID].MatchCount, nil
+}
+
+func (s *stubGuestClaimsStore) DeleteGuestClaim(ctx context.Context, claimID string) error {
+	s.deleted = claimID
+	return nil
+}
+
+type friendsWith map[string]bool
+
+func (f friendsWith) AreFriends(ctx context.Context, userA, userB string) (bool, error) {
+	return f[userB], nil
+}
+
+func TestGuestClaimCreateRules(t *testing.T) {
+	store := &stubGuestClaimsStore{guestMatches: map[string]int{"Sam": 3}, claims: map[string]domain.GuestClaim{}}
+	svc := &GuestClaimService{Store: store, Friends: friendsWith{"friend": true}}
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, "creator", " ", "", "friend")
+	expectValidation(t, err)
+	_, err = svc.Create(ctx, "creator", "Sam", "", "creator")
+	expectValidation(t, err)
+	_, err = svc.Create(ctx, "creator", "Nobody", "", "friend")
+	expectValidation(t, err)
+	if _, err := svc.Create(ctx, "creator", "Sam", "", "stranger"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden for non-friend, got %v", err)
+	}
+
+	claim, err := svc.Create(ctx, "creator", "  Sam ", "", "friend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claim.GuestName != "Sam" || claim.User.ID != "friend" || claim.MatchCount != 3 {
+		t.Fatalf("unexpected claim: %+v", claim)
+	}
+}
+
+func TestGuestClaimCreateForSavedGuest(t *testing.T) {
+	store := &stubGuestClaimsStore{guestMatches: map[string]int{"Sam": 4}, claims: map[string]domain.GuestClaim{}}
+	guests := &stubGuestsStore{guests: map[string]domain.Guest{"g1": {ID: "g1", DisplayName: "Sam", Aliases: []string{"sam", "sammy"}}}}
+	svc := &GuestClaimService{Store: store, Friends: friendsWith{"friend": true}, Guests: guests}
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, "creator", "", "missing", "friend")
+	expectValidation(t, err)
+
+	claim, err := svc.Create(ctx, "creator", "", "g1", "friend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claim.GuestID != "g1" || claim.GuestName != "Sam" || claim.MatchCount != 4 {
+		t.Fatalf("unexpected claim: %+v", claim)
+	}
+}
+
+func TestGuestClaimAcceptAndDecline(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 678901234, time.UTC)
+	store := &stubGuestClaimsStore{claims: map[string]domain.GuestClaim{
+		"c1": {ID: "c1", GuestName: "Sam", InvitedBy: domain.UserSummary{ID: "creator"}, User: domain.UserSummary{ID: "friend"}, MatchCount: 2},
+	}}
+	svc := &GuestClaimService{Store: store, Friends: friendsWith{}, Now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	if _, err := svc.Accept(ctx, "creator", "c1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected only the invited user to accept, got %v", err)
+	}
+	n, err := svc.Accept(ctx, "friend", "c1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 || store.accepted != "c1" || !store.acceptedAt.Equal(now.Truncate(time.Millisecond)) {
+		t.Fatalf("unexpected accept: n=%d claim=%q at=%v", n, store.accepted, store.acceptedAt)
+	}
+
+	if err := svc.Decline(ctx, "stranger", "c1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for unrelated user, got %v", err)
+	}
+	if err := svc.Decline(ctx, "creator", "c1"); err != nil || store.deleted != "c1" {
+		t.Fatalf("expected creator to withdraw, got %v (%q)", err, store.deleted)
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type GuestsStore interface {
+	ListGuests(ctx context.Context, ownerID string) ([]domain.Guest, error)
+	GetGuest(ctx context.Context, ownerID, guestID string) (domain.Guest, error)
+	CreateGuest(ctx context.Context, ownerID, displayName string, aliases []string, at time.Time) (string, error)
+	UpdateGuest(ctx context.Context, ownerID, guestID, displayName string, aliases []string, at time.Time) error
+	DeleteGuest(ctx context.Context, ownerID, guestID string) error
+	MergeGuests(ctx context.Context, ownerID, targetID string, sourceIDs []string, at time.Time) error
+}
+
+// GuestService manages a user's saved guest roster. Guest seats in matches the user records
+// link to a roster entry when their name matches one of its aliases, or when sent with guest_id.
+type GuestService struct {
+	Store GuestsStore
+	Now   func() time.Time
+}
+
+const (
+	maxGuestNameLength = 80
+	maxGuestAliases    = 20
+)
+
+type GuestPatch struct {
+	DisplayName *string
+	// Aliases replaces the alias list. The display name is always kept as an alias.
+	Aliases *[]string
+}
+
+func (s *GuestService) List(ctx context.Context, ownerID string) ([]domain.Guest, error) {
+	guests, err := s.Store.ListGuests(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if guests == nil {
+		guests = []domain.Guest{}
+	}
+	return guests, nil
+}
+
+func (s *GuestService) Get(ctx context.Context, ownerID, guestID string) (domain.Guest, error) {
+	return s.Store.GetGuest(ctx, ownerID, guestID)
+}
+
+func (s *GuestService) Create(ctx context.Context, ownerID, displayName string, aliases []string) (domain.Guest, error) {
+	displayName, aliases, err := validateGuestFields(displayName, aliases)
+	if err != nil {
+		return domain.Guest{}, err
+	}
+	guestID, err := s.Store.CreateGuest(ctx, ownerID, displayName, aliases, s.now())
+	if err != nil {
+		return domain.Guest{}, err
+	}
+	return s.Store.GetGuest(ctx, ownerID, guestID)
+}
+
+// Update renames a guest and/or replaces its aliases. Renaming keeps the old aliases, so matches
+// recorded under the previous name stay linked.
+func (s *GuestService) Update(ctx context.Context, ownerID, guestID string, p GuestPatch) (domain.Guest, error) {
+	guest, err := s.Store.GetGuest(ctx, ownerID, guestID)
+	if err != nil {
+		return domain.Guest{}, err
+	}
+	displayName, aliases := guest.DisplayName, guest.Aliases
+	if p.DisplayName != nil {
+		displayName = *p.DisplayName
+	}
+	if p.Aliases != nil {
+		aliases = *p.Aliases
+	}
+	displayName, aliases, err = validateGuestFields(displayName, aliases)
+	if err != nil {
+		return domain.Guest{}, err
+	}
+	if err := s.Store.UpdateGuest(ctx, ownerID, guestID, displayName, aliases, s.now()); err != nil {
+		return domain.Guest{}, err
+	}
+	return s.Store.GetGuest(ctx, ownerID, guestID)
+}
+
+// Delete removes a guest from the roster. Its matches keep the guest name they were recorded with.
+func (s *GuestService) Delete(ctx context.Context, ownerID, guestID string) error {
+	return s.Store.DeleteGuest(ctx, ownerID, guestID)
+}
+
+// Merge folds sourceIDs into targetID, moving their aliases and every linked match seat.
+func (s *GuestService) Merge(ctx context.Context, ownerID, targetID string, sourceIDs []string) (domain.Guest, error) {
+	targetID = strings.TrimSpace(targetID)
+	seen := map[string]bool{targetID: true}
+	sources := make([]string, 0, len(sourceIDs))
+	for _, id := range sourceIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		sources = append(sources, id)
+	}
+	if len(sources) == 0 {
+		return domain.Guest{}, domain.NewValidationError(map[string]string{"source_ids": "must include at least one other guest"})
+	}
+	if _, err := s.Store.GetGuest(ctx, ownerID, targetID); err != nil {
+		return domain.Guest{}, err
+	}
+	if err := s.Store.MergeGuests(ctx, ownerID, targetID, sources, s.now()); err != nil {
+		return domain.Guest{}, err
+	}
+	return s.Store.GetGuest(ctx, ownerID, targetID)
+}
+
+func (s *GuestService) now() time.Time {
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+	return s.Now().UTC().Truncate(time.Millisecond)
+}
+
+// validateGuestFields trims the display name and returns the normalized alias list with the
+// display name first.
+func validateGuestFields(displayName string, aliases []string) (string, []string, error) {
+	fields := make(map[string]string)
+	displayName = strings.TrimSpace(displayName)
+	switch {
+	case displayName == "":
+		fields["display_name"] = "required"
+	case utf8.RuneCountInString(displayName) > maxGuestNameLength:
+		fields["display_name"] = fmt.Sprintf("must be at most %d characters", maxGuestNameLength)
+	}
+
+	out := make([]string, 0, len(aliases)+1)
+	seen := make(map[string]bool, len(aliases)+1)
+	for _, alias := range append([]string{displayName}, aliases...) {
+		alias = normalizeGuestAlias(alias)
+		if alias == "" || seen[alias] {
+			continue
+		}
+		if utf8.RuneCountInString(alias) > maxGuestNameLength {
+			fields["aliases"] = fmt.Sprintf("each alias must be at most %d characters", maxGuestNameLength)
+			continue
+		}
+		seen[alias] = true
+		out = append(out, alias)
+	}
+	if len(out) > maxGuestAliases {
+		fields["aliases"] = fmt.Sprintf("at most %d aliases", maxGuestAliases)
+	}
+	if len(fields) > 0 {
+		return "", nil, domain.NewValidationError(fields)
+	}
+	return displayName, out, nil
+}
+
+func normalizeGuestAlias(alias string) string {
+	return strings.ToLower(strings.TrimSpace(alias))
+}
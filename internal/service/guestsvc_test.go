@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type stubGuestsStore struct {
+	guests  map[string]domain.Guest
+	merged  []string
+	mergeTo string
+}
+
+func (s *stubGuestsStore) ListGuests(ctx context.Context, ownerID string) ([]domain.Guest, error) {
+	return nil, nil
+}
+
+func (s *stubGuestsStore) GetGuest(ctx context.Context, ownerID, guestID string) (domain.Guest, error) {
+	g, ok := s.guests[guestID]
+	if !ok {
+		return domain.Guest{}, domain.ErrNotFound
+	}
+	return g, nil
+}
+
+func (s *stubGuestsStore) GuestIDForName(ctx context.Context, ownerID, guestName string) (string, error) {
+	name := normalizeGuestAlias(guestName)
+	for _, g := range s.guests {
+		for _, alias := range g.Aliases {
+			if alias == name {
+				return g.ID, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func (s *stubGuestsStore) CreateGuest(ctx context.Context, ownerID, displayName string, aliases []string, at time.Time) (string, error) {
+	id := "g" + displayName
+	s.guests[id] = domain.Guest{ID: id, DisplayName: displayName, Aliases: aliases}
+	return id, nil
+}
+
+func (s *stubGuestsStore) UpdateGuest(ctx context.Context, ownerID, guestID, displayName string, aliases []string, at time.Time) error {
+	s.guests[guestID] = domain.Guest{ID: guestID, DisplayName: displayName, Aliases: aliases}
+	return nil
+}
+
+func (s *stubGuestsStore) DeleteGuest(ctx context.Context, ownerID, guestID string) error {
+	delete(s.guests, guestID)
+	return nil
+}
+
+func (s *stubGuestsStore) MergeGuests(ctx context.Context, ownerID, targetID string, sourceIDs []string, at time.Time) error {
+	s.mergeTo = targetID
+	s.merged = sourceIDs
+	return nil
+}
+
+func TestGuestCreateNormalizesAliases(t *testing.T) {
+	store := &stubGuestsStore{guests: map[string]domain.Guest{}}
+	svc := &GuestService{Store: store}
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, "owner", "  ", nil)
+	expectValidation(t, err)
+
+	guest, err := svc.Create(ctx, "owner", " Mike ", []string{"mike ", "Mikey", "", "MIKEY"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if guest.DisplayName != "Mike" {
+		t.Fatalf("unexpected display name: %q", guest.DisplayName)
+	}
+	if want := []string{"mike", "mikey"}; !reflect.DeepEqual(guest.Aliases, want) {
+		t.Fatalf("expected aliases %v, got %v", want, guest.Aliases)
+	}
+}
+
+func TestGuestRenameKeepsOldAliases(t *testing.T) {
+	store := &stubGuestsStore{guests: map[string]domain.Guest{
+		"g1": {ID: "g1", DisplayName: "Mike", Aliases: []string{"mike"}},
+	}}
+	svc := &GuestService{Store: store}
+
+	name := "Michael"
+	guest, err := svc.Update(context.Background(), "owner", "g1", GuestPatch{DisplayName: &name})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"michael", "mike"}; !reflect.DeepEqual(guest.Aliases, want) {
+		t.Fatalf("expected aliases %v, got %v", want, guest.Aliases)
+	}
+}
+
+func TestGuestMergeRules(t *testing.T) {
+	store := &stubGuestsStore{guests: map[string]domain.Guest{
+		"g1": {ID: "g1", DisplayName: "Mike", Aliases: []string{"mike"}},
+	}}
+	svc := &GuestService{Store: store}
+	ctx := context.Background()
+
+	_, err := svc.Merge(ctx, "owner", "g1", []string{"g1", " "})
+	expectValidation(t, err)
+	if _, err := svc.Merge(ctx, "owner", "missing", []string{"g2"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for unknown target, got %v", err)
+	}
+
+	if _, err := svc.Merge(ctx, "owner", "g1", []string{"g2", "g3", "g2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.mergeTo != "g1" || !reflect.DeepEqual(store.merged, []string{"g2", "g3"}) {
+		t.Fatalf("unexpected merge: %q <- %v", store.mergeTo, store.merged)
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import "sync"
+
+// LiveHub tells the streams watching a live match that it changed. Signals carry no data and
+// coalesce, so a slow stream never holds up a writer; it reloads what it missed from the store.
+// Streams only hear about changes made through the same process.
+type LiveHub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan struct{}]struct{}
+}
+
+func NewLiveHub() *LiveHub {
+	return &LiveHub{subs: make(map[string]map[chan struct{}]struct{})}
+}
+
+// Subscribe returns a channel that receives after every change to the live match, and a func that
+// stops the subscription.
+func (h *LiveHub) Subscribe(liveID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.subs[liveID] == nil {
+		h.subs[liveID] = make(map[chan struct{}]struct{})
+	}
+	h.subs[liveID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs[liveID], ch)
+		if len(h.subs[liveID]) == 0 {
+			delete(h.subs, liveID)
+		}
+	}
+}
+
+// Publish wakes every subscriber of the live match without blocking.
+func (h *LiveHub) Publish(liveID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[liveID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,562 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type LiveMatchesStore interface {
+	CreateLiveMatch(ctx context.Context, in domain.LiveMatchInput, at time.Time) (string, error)
+	// GetLiveMatch returns the live match and its players with no updates applied.
+	GetLiveMatch(ctx context.Context, liveID string) (domain.LiveMatch, error)
+	// ListLiveMatchesForUser returns the active live matches the user hosts or has a seat in.
+	ListLiveMatchesForUser(ctx context.Context, userID string) ([]domain.LiveMatch, error)
+	JoinLiveMatch(ctx context.Context, liveID, userID string, at time.Time) error
+	// AppendLiveMatchEvents numbers the events after the last stored one and stores them. A
+	// turn_pass starts the next turn; other events are logged on the current turn. It returns
+	// domain.ErrLiveMatchClosed once the live match has ended.
+	AppendLiveMatchEvents(ctx context.Context, liveID, userID string, events []domain.MatchEvent, at time.Time) ([]domain.MatchEvent, error)
+	ListLiveMatchEvents(ctx context.Context, liveID string, afterSeq int) ([]domain.MatchEvent, error)
+	// FreezeLiveMatch moves an active live match to finishing, after which updates and abandoning
+	// are refused. A live match that is already finishing stays so. It returns
+	// domain.ErrLiveMatchClosed if the live match already ended.
+	FreezeLiveMatch(ctx context.Context, liveID string) error
+	// ReopenLiveMatch moves a finishing live match back to active.
+	ReopenLiveMatch(ctx context.Context, liveID string) error
+	// CloseLiveMatch ends the live match: a finishing one as finished with matchID, or an active one
+	// as abandoned when matchID is empty. It returns domain.ErrLiveMatchClosed otherwise.
+	CloseLiveMatch(ctx context.Context, liveID, matchID string, at time.Time) error
+}
+
+// LiveMatchService runs games in progress. The host creates the live match with its seats, seated
+// players join from their own devices, and every update is pushed to the streams watching it.
+// Finishing records the game as a normal match.
+type LiveMatchService struct {
+	Store   LiveMatchesStore
+	Matches *MatchService
+	// Events receives the live log when the match is finished. Optional.
+	Events MatchEventsStore
+	Hub    *LiveHub
+	Now    func() time.Time
+}
+
+type LiveMatchParams struct {
+	Format            domain.GameFormat
+	GroupID           string
+	StartingSeatIndex *int
+	Players           []domain.MatchParticipantInput
+}
+
+// LiveMatchChanges is what a stream has to send after seq.
+type LiveMatchChanges struct {
+	Events  []domain.MatchEvent
+	Status  domain.LiveMatchStatus
+	MatchID string
+	Joined  int
+}
+
+const maxLiveMatchEventsPerRequest = 100
+
+func (s *LiveMatchService) Create(ctx context.Context, hostID string, p LiveMatchParams) (domain.LiveMatch, error) {
+	format, err := s.Matches.validateMatchFields(ctx, p.Format, "", 0, 0)
+	if err != nil {
+		return domain.LiveMatch{}, err
+	}
+	groupID := strings.TrimSpace(p.GroupID)
+	if groupID != "" {
+		if err := s.Matches.requireGroupMember(ctx, groupID, hostID); err != nil {
+			return domain.LiveMatch{}, err
+		}
+	}
+
+	if format.Teams {
+		return domain.LiveMatch{}, domain.NewValidationError(map[string]string{"format": "team formats can't be played live yet"})
+	}
+	for _, pl := range p.Players {
+		if pl.Team != 0 {
+			return domain.LiveMatch{}, domain.NewValidationError(map[string]string{"players": "team matches can't be played live yet"})
+		}
+	}
+
+	// Seats follow the rules for a recorded match's players. Places are worked out at the finish,
+	// so stand-in places get the players through the check.
+	players := make([]domain.MatchParticipantInput, len(p.Players))
+	for i, pl := range p.Players {
+		pl.Place = 2
+		if i == 0 {
+			pl.Place = 1
+		}
+		players[i] = pl
+	}
+	seats, _, err := s.Matches.buildParticipantsFromPayload(ctx, hostID, groupID, domain.MatchOutcomeWin, players)
+	if err != nil {
+		return domain.LiveMatch{}, err
+	}
+	if err := validateStartingSeat(p.StartingSeatIndex, seats); err != nil {
+		return domain.LiveMatch{}, err
+	}
+	if err := s.Matches.checkCommanders(ctx, format, seats); err != nil {
+		return domain.LiveMatch{}, err
+	}
+	for i := range seats {
+		seats[i].Place = 0
+	}
+
+	liveID, err := s.Store.CreateLiveMatch(ctx, domain.LiveMatchInput{
+		CreatedBy:         hostID,
+		Format:            format.Slug,
+		GroupID:           groupID,
+		StartingSeatIndex: p.StartingSeatIndex,
+		Seats:             seats,
+	}, s.now())
+	if err != nil {
+		return domain.LiveMatch{}, err
+	}
+	return s.load(ctx, liveID)
+}
+
+// List returns the active live matches the user hosts or has a seat in.
+func (s *LiveMatchService) List(ctx context.Context, userID string) ([]domain.LiveMatch, error) {
+	matches, err := s.Store.ListLiveMatchesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.LiveMatch, 0, len(matches))
+	for _, lm := range matches {
+		events, err := s.Store.ListLiveMatchEvents(ctx, lm.ID, 0)
+		if err != nil {
+			return nil, err
+		}
+		life, err := startingLife(ctx, s.formats(), lm.Format)
+		if err != nil {
+			return nil, err
+		}
+		applyLiveMatchEvents(&lm, events, life)
+		out = append(out, lm)
+	}
+	return out, nil
+}
+
+// Get returns the live match with every update applied. Only the host and seated players see it.
+func (s *LiveMatchService) Get(ctx context.Context, userID, liveID string) (domain.LiveMatch, error) {
+	lm, err := s.load(ctx, liveID)
+	if err != nil {
+		return domain.LiveMatch{}, err
+	}
+	if !liveMatchVisible(lm, userID) {
+		return domain.LiveMatch{}, domain.ErrNotFound
+	}
+	return lm, nil
+}
+
+// Join marks the caller's seat as joined, which lets them send updates, and notifies every stream.
+func (s *LiveMatchService) Join(ctx context.Context, userID, liveID string) (domain.LiveMatch, error) {
+	lm, err := s.Get(ctx, userID, liveID)
+	if err != nil {
+		return domain.LiveMatch{}, err
+	}
+	if lm.Status != domain.LiveMatchActive {
+		return domain.LiveMatch{}, domain.ErrLiveMatchClosed
+	}
+	if liveMatchSeat(lm, userID) == nil {
+		return domain.LiveMatch{}, domain.ErrForbidden
+	}
+	if err := s.Store.JoinLiveMatch(ctx, liveID, userID, s.now()); err != nil {
+		return domain.LiveMatch{}, err
+	}
+	s.publish(liveID)
+	return s.load(ctx, liveID)
+}
+
+// Update appends events from the host or a joined player and pushes them to every stream. Seq and
+// turn are assigned by the server; a turn_pass starts the next turn.
+func (s *LiveMatchService) Update(ctx context.Context, userID, liveID string, events []domain.MatchEvent) ([]domain.MatchEvent, error) {
+	lm, err := s.Get(ctx, userID, liveID)
+	if err != nil {
+		return nil, err
+	}
+	if lm.Status != domain.LiveMatchActive {
+		return nil, domain.ErrLiveMatchClosed
+	}
+	if lm.CreatedBy != userID {
+		if seat := liveMatchSeat(lm, userID); seat == nil || seat.JoinedAt == nil {
+			return nil, domain.ErrForbidden
+		}
+	}
+
+	switch {
+	case len(events) == 0:
+		return nil, domain.NewValidationError(map[string]string{"events": "required"})
+	case len(events) > maxLiveMatchEventsPerRequest:
+		return nil, domain.NewValidationError(map[string]string{"events": fmt.Sprintf("at most %d events per request", maxLiveMatchEventsPerRequest)})
+	}
+	seats := make(map[int]bool, len(lm.Seats))
+	for _, seat := range lm.Seats {
+		seats[seat.SeatIndex] = true
+	}
+	for i, e := range events {
+		if msg := validateMatchEvent(e, seats); msg != "" {
+			return nil, domain.NewValidationError(map[string]string{"events": fmt.Sprintf("event %d: %s", i, msg)})
+		}
+	}
+
+	stored, err := s.Store.AppendLiveMatchEvents(ctx, liveID, userID, events, s.now())
+	if err != nil {
+		return nil, err
+	}
+	s.publish(liveID)
+	return stored, nil
+}
+
+// Subscribe returns a channel that receives after every change to the live match, and a func that
+// stops the subscription. Callers check access with Get first.
+func (s *LiveMatchService) Subscribe(liveID string) (<-chan struct{}, func()) {
+	return s.Hub.Subscribe(liveID)
+}
+
+// Changes returns the updates after seq, whether the live match is still running and how many
+// seats have joined.
+func (s *LiveMatchService) Changes(ctx context.Context, liveID string, afterSeq int) (LiveMatchChanges, error) {
+	events, err := s.Store.ListLiveMatchEvents(ctx, liveID, afterSeq)
+	if err != nil {
+		return LiveMatchChanges{}, err
+	}
+	lm, err := s.Store.GetLiveMatch(ctx, liveID)
+	if err != nil {
+		return LiveMatchChanges{}, err
+	}
+	return LiveMatchChanges{Events: events, Status: lm.Status, MatchID: lm.MatchID, Joined: lm.JoinedSeats()}, nil
+}
+
+// Finish records the game as a match created by the host and ends the live match. Seats still in
+// the game finish ahead of eliminated ones, and later eliminations place ahead of earlier ones.
+// winnerSeat picks the winner when more than one seat is still in, or none is. Finishing again
+// returns the same match. The live match is frozen before its log is read, so an update or abandon
+// racing the finish gets domain.ErrLiveMatchClosed rather than being lost.
+func (s *LiveMatchService) Finish(ctx context.Context, userID, liveID string, winnerSeat *int) (domain.Match, error) {
+	lm, err := s.Get(ctx, userID, liveID)
+	if err != nil {
+		return domain.Match{}, err
+	}
+	if lm.CreatedBy != userID {
+		return domain.Match{}, domain.ErrForbidden
+	}
+	if lm.Status == domain.LiveMatchFinished {
+		return s.Matches.GetMatch(ctx, userID, lm.MatchID)
+	}
+	if err := s.Store.FreezeLiveMatch(ctx, liveID); err != nil {
+		if errors.Is(err, domain.ErrLiveMatchClosed) {
+			return s.finishedMatch(ctx, userID, liveID)
+		}
+		return domain.Match{}, err
+	}
+
+	match, endedAt, err := s.recordLiveMatch(ctx, userID, liveID, winnerSeat)
+	if err != nil {
+		if reopenErr := s.Store.ReopenLiveMatch(ctx, liveID); reopenErr != nil && !errors.Is(reopenErr, domain.ErrLiveMatchClosed) {
+			return domain.Match{}, errors.Join(err, reopenErr)
+		}
+		return domain.Match{}, err
+	}
+	if err := s.Store.CloseLiveMatch(ctx, liveID, match.ID, endedAt); err != nil {
+		if errors.Is(err, domain.ErrLiveMatchClosed) {
+			return s.finishedMatch(ctx, userID, liveID)
+		}
+		return domain.Match{}, err
+	}
+	s.publish(liveID)
+	return match, nil
+}
+
+// recordLiveMatch creates the match from the frozen live match and copies its log. It returns
+// the match and when it ended.
+func (s *LiveMatchService) recordLiveMatch(ctx context.Context, userID, liveID string, winnerSeat *int) (domain.Match, time.Time, error) {
+	lm, err := s.load(ctx, liveID)
+	if err != nil {
+		return domain.Match{}, time.Time{}, err
+	}
+	events, err := s.Store.ListLiveMatchEvents(ctx, liveID, 0)
+	if err != nil {
+		return domain.Match{}, time.Time{}, err
+	}
+	endedAt := s.now()
+	players, err := liveMatchResults(lm, events, winnerSeat, endedAt)
+	if err != nil {
+		return domain.Match{}, time.Time{}, err
+	}
+
+	startingSeat := lm.StartingSeatIndex
+	if startingSeat == nil {
+		for _, e := range events {
+			if e.Kind == domain.MatchEventTurnPass {
+				seat := e.SeatIndex
+				startingSeat = &seat
+				break
+			}
+		}
+	}
+	startedAt := lm.StartedAt
+	match, _, err := s.Matches.CreateMatch(ctx, userID, CreateMatchParams{
+		StartedAt:            &startedAt,
+		EndedAt:              &endedAt,
+		Format:               lm.Format,
+		TotalDurationSeconds: int(endedAt.Sub(startedAt) / time.Second),
+		TurnCount:            lm.Turn,
+		StartingSeatIndex:    startingSeat,
+		GroupID:              lm.GroupID,
+		ClientMatchID:        "live:" + lm.ID,
+		UpdatedAt:            endedAt,
+		Players:              players,
+	})
+	if err != nil {
+		return domain.Match{}, time.Time{}, err
+	}
+
+	if s.Events != nil && len(events) > 0 {
+		if err := s.Events.AppendMatchEvents(ctx, match.ID, events); err != nil {
+			return domain.Match{}, time.Time{}, err
+		}
+	}
+	return match, endedAt, nil
+}
+
+// finishedMatch returns the match a concurrent finish recorded, or domain.ErrLiveMatchClosed if
+// the live match was abandoned.
+func (s *LiveMatchService) finishedMatch(ctx context.Context, userID, liveID string) (domain.Match, error) {
+	lm, err := s.Store.GetLiveMatch(ctx, liveID)
+	if err != nil {
+		return domain.Match{}, err
+	}
+	if lm.Status != domain.LiveMatchFinished {
+		return domain.Match{}, domain.ErrLiveMatchClosed
+	}
+	return s.Matches.GetMatch(ctx, userID, lm.MatchID)
+}
+
+// Abandon ends the live match without recording a match.
+func (s *LiveMatchService) Abandon(ctx context.Context, userID, liveID string) error {
+	lm, err := s.Get(ctx, userID, liveID)
+	if err != nil {
+		return err
+	}
+	if lm.CreatedBy != userID {
+		return domain.ErrForbidden
+	}
+	if err := s.Store.CloseLiveMatch(ctx, liveID, "", s.now()); err != nil {
+		return err
+	}
+	s.publish(liveID)
+	return nil
+}
+
+func (s *LiveMatchService) load(ctx context.Context, liveID string) (domain.LiveMatch, error) {
+	lm, err := s.Store.GetLiveMatch(ctx, liveID)
+	if err != nil {
+		return domain.LiveMatch{}, err
+	}
+	events, err := s.Store.ListLiveMatchEvents(ctx, liveID, 0)
+	if err != nil {
+		return domain.LiveMatch{}, err
+	}
+	life, err := startingLife(ctx, s.formats(), lm.Format)
+	if err != nil {
+		return domain.LiveMatch{}, err
+	}
+	applyLiveMatchEvents(&lm, events, life)
+	return lm, nil
+}
+
+func (s *LiveMatchService) publish(liveID string) {
+	if s.Hub != nil {
+		s.Hub.Publish(liveID)
+	}
+}
+
+func (s *LiveMatchService) now() time.Time {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	return now().UTC().Truncate(time.Millisecond)
+}
+
+// formats is the registry the match service checks formats against.
+func (s *LiveMatchService) formats() FormatResolver {
+	if s.Matches == nil {
+		return nil
+	}
+	return s.Matches.Formats
+}
+
+func liveMatchVisible(lm domain.LiveMatch, userID string) bool {
+	return lm.CreatedBy == userID || liveMatchSeat(lm, userID) != nil
+}
+
+func liveMatchSeat(lm domain.LiveMatch, userID string) *domain.LiveMatchSeat {
+	for i := range lm.Seats {
+		if lm.Seats[i].User != nil && lm.Seats[i].User.ID == userID {
+			return &lm.Seats[i]
+		}
+	}
+	return nil
+}
+
+// applyLiveMatchEvents plays events onto the seats, which start at life.
+func applyLiveMatchEvents(lm *domain.LiveMatch, events []domain.MatchEvent, life int) {
+	bySeat := make(map[int]*domain.LiveMatchSeat, len(lm.Seats))
+	for i := range lm.Seats {
+		lm.Seats[i].Life = life
+		bySeat[lm.Seats[i].SeatIndex] = &lm.Seats[i]
+	}
+	for _, e := range events {
+		lm.Seq = e.Seq
+		if e.Turn > lm.Turn {
+			lm.Turn = e.Turn
+		}
+		seat, ok := bySeat[e.SeatIndex]
+		if !ok {
+			continue
+		}
+		switch e.Kind {
+		case domain.MatchEventTurnPass:
+			active := e.SeatIndex
+			lm.ActiveSeatIndex = &active
+		case domain.MatchEventEliminated:
+			if !seat.Eliminated {
+				turn := e.Turn
+				seat.EliminatedTurn = &turn
+			}
+		}
+		applyMatchEvent(&seat.MatchReplaySeat, e)
+	}
+}
+
+// liveMatchResults turns the log into a recorded match's players: places, elimination turns and
+// per-seat turn counts and times measured between turn passes.
+func liveMatchResults(lm domain.LiveMatch, events []domain.MatchEvent, winnerSeat *int, endedAt time.Time) ([]domain.MatchParticipantInput, error) {
+	var (
+		active      *int
+		turnStart   time.Time
+		turnsTaken  = make(map[int]int)
+		turnTimeMs  = make(map[int]int64)
+		elimTurn    = make(map[int]int)
+		elimDuring  = make(map[int]*int)
+		eliminated  []int
+		passedTurns bool
+	)
+	for _, e := range events {
+		at := endedAt
+		if e.OccurredAt != nil {
+			at = *e.OccurredAt
+		}
+		switch e.Kind {
+		case domain.MatchEventTurnPass:
+			if active != nil {
+				turnTimeMs[*active] += at.Sub(turnStart).Milliseconds()
+			}
+			seat := e.SeatIndex
+			active = &seat
+			turnStart = at
+			turnsTaken[seat]++
+			passedTurns = true
+		case domain.MatchEventEliminated:
+			if _, seen := elimTurn[e.SeatIndex]; seen {
+				continue
+			}
+			elimTurn[e.SeatIndex] = e.Turn
+			if active != nil {
+				during := *active
+				elimDuring[e.SeatIndex] = &during
+			}
+			eliminated = append(eliminated, e.SeatIndex)
+		}
+	}
+	if active != nil {
+		turnTimeMs[*active] += endedAt.Sub(turnStart).Milliseconds()
+	}
+
+	var seats, survivors []int
+	for _, seat := range lm.Seats {
+		seats = append(seats, seat.SeatIndex)
+		if _, out := elimTurn[seat.SeatIndex]; !out {
+			survivors = append(survivors, seat.SeatIndex)
+		}
+	}
+	sort.Ints(survivors)
+
+	winner := -1
+	switch {
+	case winnerSeat != nil:
+		if !containsInt(seats, *winnerSeat) {
+			return nil, domain.NewValidationError(map[string]string{"winner_seat_index": "must be a seat in the match"})
+		}
+		if len(survivors) > 0 && !containsInt(survivors, *winnerSeat) {
+			return nil, domain.NewValidationError(map[string]string{"winner_seat_index": "must be a seat still in the game"})
+		}
+		winner = *winnerSeat
+	case len(survivors) == 1:
+		winner = survivors[0]
+	default:
+		return nil, domain.NewValidationError(map[string]string{"winner_seat_index": "required when more than one seat, or none, is still in the game"})
+	}
+
+	places := map[int]int{winner: 1}
+	others := 0
+	for _, seat := range survivors {
+		if seat != winner {
+			places[seat] = 2
+			others++
+		}
+	}
+	next := 2 + others
+	for i := len(eliminated) - 1; i >= 0; i-- {
+		if eliminated[i] == winner {
+			continue
+		}
+		places[eliminated[i]] = next
+		next++
+	}
+
+	players := make([]domain.MatchParticipantInput, 0, len(lm.Seats))
+	for _, seat := range lm.Seats {
+		p := domain.MatchParticipantInput{
+			SeatIndex:    seat.SeatIndex,
+			GuestName:    seat.GuestName,
+			GuestID:      seat.GuestID,
+			DeckID:       seat.DeckID,
+			CommanderIDs: seat.CommanderIDs,
+			DisplayName:  seat.DisplayName,
+			Place:        places[seat.SeatIndex],
+		}
+		if seat.User != nil {
+			p.UserID = seat.User.ID
+		}
+		if turn, ok := elimTurn[seat.SeatIndex]; ok && seat.SeatIndex != winner {
+			p.EliminatedTurn = &turn
+			p.EliminatedDuring = elimDuring[seat.SeatIndex]
+		}
+		if passedTurns {
+			taken := turnsTaken[seat.SeatIndex]
+			ms := turnTimeMs[seat.SeatIndex]
+			p.TurnsTaken = &taken
+			p.TotalTurnTimeMs = &ms
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
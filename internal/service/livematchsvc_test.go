@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type stubLiveMatchesStore struct {
+	match  domain.LiveMatch
+	events []domain.MatchEvent
+	// onListEvents runs before the events are listed, to race other calls against a finish.
+	onListEvents func()
+}
+
+func (s *stubLiveMatchesStore) CreateLiveMatch(ctx context.Context, in domain.LiveMatchInput, at time.Time) (string, error) {
+	s.match = domain.LiveMatch{ID: "live-1", CreatedBy: in.CreatedBy, Format: in.Format, Status: domain.LiveMatchActive, StartedAt: at}
+	for _, p := range in.Seats {
+		seat := domain.LiveMatchSeat{GuestName: p.GuestName, DisplayName: p.DisplayName}
+		seat.SeatIndex = p.SeatIndex
+		if p.UserID != "" {
+			seat.User = &domain.UserSummary{ID: p.UserID}
+			if p.UserID == in.CreatedBy {
+				seat.JoinedAt = &at
+			}
+		}
+		s.match.Seats = append(s.match.Seats, seat)
+	}
+	return s.match.ID, nil
+}
+
+func (s *stubLiveMatchesStore) GetLiveMatch(ctx context.Context, liveID string) (domain.LiveMatch, error) {
+	if liveID != s.match.ID {
+		return domain.LiveMatch{}, domain.ErrNotFound
+	}
+	lm := s.match
+	lm.Seats = append([]domain.LiveMatchSeat(nil), s.match.Seats...)
+	return lm, nil
+}
+
+func (s *stubLiveMatchesStore) ListLiveMatchesForUser(ctx context.Context, userID string) ([]domain.LiveMatch, error) {
+	return nil, nil
+}
+
+func (s *stubLiveMatchesStore) JoinLiveMatch(ctx context.Context, liveID, userID string, at time.Time) error {
+	for i := range s.match.Seats {
+		if s.match.Seats[i].User != nil && s.match.Seats[i].User.ID == userID {
+			s.match.Seats[i].JoinedAt = &at
+		}
+	}
+	return nil
+}
+
+func (s *stubLiveMatchesStore) AppendLiveMatchEvents(ctx context.Context, liveID, userID string, events []domain.MatchEvent, at time.Time) ([]domain.MatchEvent, error) {
+	if s.match.Status != domain.LiveMatchActive {
+		return nil, domain.ErrLiveMatchClosed
+	}
+	seq, turn := len(s.events), 0
+	if seq > 0 {
+		turn = s.events[seq-1].Turn
+	}
+	var out []domain.MatchEvent
+	for _, e := range events {
+		seq++
+		if e.Kind == domain.MatchEventTurnPass {
+			turn++
+		}
+		e.Seq, e.Turn = seq, turn
+		occurredAt := at
+		e.OccurredAt = &occurredAt
+		out = append(out, e)
+	}
+	s.events = append(s.events, out...)
+	return out, nil
+}
+
+func (s *stubLiveMatchesStore) ListLiveMatchEvents(ctx context.Context, liveID string, afterSeq int) ([]domain.MatchEvent, error) {
+	if s.onListEvents != nil {
+		s.onListEvents()
+	}
+	var out []domain.MatchEvent
+	for _, e := range s.events {
+		if e.Seq > afterSeq {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubLiveMatchesStore) FreezeLiveMatch(ctx context.Context, liveID string) error {
+	if s.match.Status != domain.LiveMatchActive && s.match.Status != domain.LiveMatchFinishing {
+		return domain.ErrLiveMatchClosed
+	}
+	s.match.Status = domain.LiveMatchFinishing
+	return nil
+}
+
+func (s *stubLiveMatchesStore) ReopenLiveMatch(ctx context.Context, liveID string) error {
+	if s.match.Status != domain.LiveMatchFinishing {
+		return domain.ErrLiveMatchClosed
+	}
+	s.match.Status = domain.LiveMatchActive
+	return nil
+}
+
+func (s *stubLiveMatchesStore) CloseLiveMatch(ctx context.Context, liveID, matchID string, at time.Time) error {
+	from, to := domain.LiveMatchActive, domain.LiveMatchAbandoned
+	if matchID != "" {
+		from, to = domain.LiveMatchFinishing, domain.LiveMatchFinished
+	}
+	if s.match.Status != from {
+		return domain.ErrLiveMatchClosed
+	}
+	s.match.Status = to
+	s.match.MatchID = matchID
+	s.match.FinishedAt = &at
+	return nil
+}
+
+func newLiveTestService(t *testing.T) (*LiveMatchService, *stubLiveMatchesStore, *stubMatchesStore, *stubMatchEventsStore) {
+	t.Helper()
+	matches := &stubMatchesStore{returnID: "match-1", createdFlag: true, matchForUser: domain.Match{ID: "match-1"}}
+	live := &stubLiveMatchesStore{}
+	events := &stubMatchEventsStore{}
+	now := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	svc := &LiveMatchService{
+		Store:   live,
+		Matches: &MatchService{Matches: matches},
+		Events:  events,
+		Hub:     NewLiveHub(),
+		Now: func() time.Time {
+			now = now.Add(time.Minute)
+			return now
+		},
+	}
+
+	if _, err := svc.Create(context.Background(), "u1", LiveMatchParams{
+		Format: "commander",
+		Players: []domain.MatchParticipantInput{
+			{SeatIndex: 0, UserID: "u1"},
+			{SeatIndex: 1, UserID: "u2"},
+			{SeatIndex: 2, GuestName: "Sam"},
+		},
+	}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	return svc, live, matches, events
+}
+
+func TestLiveMatchUpdatePermissions(t *testing.T) {
+	svc, _, _, _ := newLiveTestService(t)
+	ctx := context.Background()
+	pass := []domain.MatchEvent{{Kind: domain.MatchEventTurnPass, SeatIndex: 0}}
+
+	if _, err := svc.Get(ctx, "u3", "live-1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for a stranger, got %v", err)
+	}
+	if _, err := svc.Update(ctx, "u2", "live-1", pass); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden before joining, got %v", err)
+	}
+	if _, err := svc.Join(ctx, "u2", "live-1"); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if _, err := svc.Update(ctx, "u2", "live-1", []domain.MatchEvent{{Kind: domain.MatchEventLife, SeatIndex: 7, Amount: -1}}); err == nil {
+		t.Fatalf("expected validation error for an unknown seat")
+	} else {
+		expectValidation(t, err)
+	}
+	if _, err := svc.Update(ctx, "u2", "live-1", pass); err != nil {
+		t.Fatalf("update after joining: %v", err)
+	}
+	if err := svc.Abandon(ctx, "u2", "live-1"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected only the host to abandon, got %v", err)
+	}
+	if err := svc.Abandon(ctx, "u1", "live-1"); err != nil {
+		t.Fatalf("abandon: %v", err)
+	}
+	if _, err := svc.Update(ctx, "u1", "live-1", pass); !errors.Is(err, domain.ErrLiveMatchClosed) {
+		t.Fatalf("expected closed, got %v", err)
+	}
+}
+
+func TestLiveMatchFinish(t *testing.T) {
+	svc, live, matches, events := newLiveTestService(t)
+	ctx := context.Background()
+	changes, stop := svc.Subscribe("live-1")
+	defer stop()
+	ten := 10
+
+	updates := [][]domain.MatchEvent{
+		{{Kind: domain.MatchEventTurnPass, SeatIndex: 0}, {Kind: domain.MatchEventLife, SeatIndex: 2, Amount: -40}},
+		{{Kind: domain.MatchEventEliminated, SeatIndex: 2}},
+		{{Kind: domain.MatchEventTurnPass, SeatIndex: 1}},
+		{{Kind: domain.MatchEventPoison, SeatIndex: 0, Total: &ten}, {Kind: domain.MatchEventEliminated, SeatIndex: 0}},
+	}
+	for _, u := range updates {
+		if _, err := svc.Update(ctx, "u1", "live-1", u); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+	}
+	select {
+	case <-changes:
+	default:
+		t.Fatalf("expected a change signal")
+	}
+
+	lm, err := svc.Get(ctx, "u2", "live-1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if lm.Seq != 6 || lm.Turn != 2 || lm.ActiveSeatIndex == nil || *lm.ActiveSeatIndex != 1 {
+		t.Fatalf("unexpected live state: %+v", lm)
+	}
+	if lm.Seats[2].Life != 0 || !lm.Seats[2].Eliminated || lm.Seats[0].Poison != 10 {
+		t.Fatalf("unexpected seats: %+v", lm.Seats)
+	}
+
+	if _, err := svc.Finish(ctx, "u2", "live-1", nil); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected only the host to finish, got %v", err)
+	}
+	match, err := svc.Finish(ctx, "u1", "live-1", nil)
+	if err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	if match.ID != "match-1" || matches.created.clientRef != "live:live-1" || matches.created.turnCount != 2 {
+		t.Fatalf("unexpected match: %+v %+v", match, matches.created)
+	}
+	places := map[int]int{}
+	for _, p := range matches.created.participants {
+		places[p.SeatIndex] = p.Place
+	}
+	if places[1] != 1 || places[0] != 2 || places[2] != 3 {
+		t.Fatalf("unexpected places: %v", places)
+	}
+	seat0 := matches.created.participants[0]
+	if seat0.EliminatedTurn == nil || *seat0.EliminatedTurn != 2 || seat0.EliminatedDuring == nil || *seat0.EliminatedDuring != 1 || seat0.TurnsTaken == nil || *seat0.TurnsTaken != 1 {
+		t.Fatalf("unexpected seat 0 result: %+v", seat0)
+	}
+	if len(events.events) != 6 || live.match.Status != domain.LiveMatchFinished {
+		t.Fatalf("expected log copied and live match finished: %d %s", len(events.events), live.match.Status)
+	}
+
+	// Finishing again returns the recorded match without creating another.
+	matches.created.called = false
+	if _, err := svc.Finish(ctx, "u1", "live-1", nil); err != nil || matches.created.called {
+		t.Fatalf("expected the same match back, err=%v created=%v", err, matches.created.called)
+	}
+}
+
+func TestLiveMatchFinishNeedsWinner(t *testing.T) {
+	svc, _, _, _ := newLiveTestService(t)
+	ctx := context.Background()
+	zero, two := 0, 2
+
+	if _, err := svc.Finish(ctx, "u1", "live-1", nil); err == nil {
+		t.Fatalf("expected winner_seat_index to be required")
+	} else {
+		expectValidation(t, err)
+	}
+	if _, err := svc.Update(ctx, "u1", "live-1", []domain.MatchEvent{{Kind: domain.MatchEventEliminated, SeatIndex: 2}}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if _, err := svc.Finish(ctx, "u1", "live-1", &two); err == nil {
+		t.Fatalf("expected an eliminated winner to be rejected")
+	}
+	if _, err := svc.Finish(ctx, "u1", "live-1", &zero); err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+}
+
+func TestLiveMatchFinishFreezesLog(t *testing.T) {
+	svc, live, _, events := newLiveTestService(t)
+	ctx := context.Background()
+	zero := 0
+
+	if _, err := svc.Update(ctx, "u1", "live-1", []domain.MatchEvent{{Kind: domain.MatchEventTurnPass, SeatIndex: 0}}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	var updateErr, abandonErr error
+	live.onListEvents = func() {
+		if live.match.Status != domain.LiveMatchFinishing {
+			return
+		}
+		live.onListEvents = nil
+		_, updateErr = svc.Update(ctx, "u1", "live-1", []domain.MatchEvent{{Kind: domain.MatchEventLife, SeatIndex: 1, Amount: -5}})
+		abandonErr = svc.Abandon(ctx, "u1", "live-1")
+	}
+	if _, err := svc.Finish(ctx, "u1", "live-1", &zero); err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	if !errors.Is(updateErr, domain.ErrLiveMatchClosed) || !errors.Is(abandonErr, domain.ErrLiveMatchClosed) {
+		t.Fatalf("expected update and abandon during finish to conflict, got %v, %v", updateErr, abandonErr)
+	}
+	if len(live.events) != 1 || len(events.events) != 1 || live.match.Status != domain.LiveMatchFinished {
+		t.Fatalf("expected the frozen log recorded: %d %d %s", len(live.events), len(events.events), live.match.Status)
+	}
+}
+
+func TestLiveMatchFinishAfterAbandon(t *testing.T) {
+	svc, _, matches, _ := newLiveTestService(t)
+	ctx := context.Background()
+	zero := 0
+
+	if err := svc.Abandon(ctx, "u1", "live-1"); err != nil {
+		t.Fatalf("abandon: %v", err)
+	}
+	if _, err := svc.Finish(ctx, "u1", "live-1", &zero); !errors.Is(err, domain.ErrLiveMatchClosed) {
+		t.Fatalf("expected finishing an abandoned live match to conflict, got %v", err)
+	}
+	if matches.created.called {
+		t.Fatalf("expected no match recorded")
+	}
+}
+
+func TestLiveMatchJoinNotifiesStreams(t *testing.T) {
+	svc, _, _, _ := newLiveTestService(t)
+	ctx := context.Background()
+	ch, stop := svc.Subscribe("live-1")
+	defer stop()
+
+	if _, err := svc.Join(ctx, "u2", "live-1"); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	select {
+	case <-ch:
+	default:
+		t.Fatalf("expected join to notify subscribers")
+	}
+	c, err := svc.Changes(ctx, "live-1", 0)
+	if err != nil {
+		t.Fatalf("changes: %v", err)
+	}
+	if c.Joined != 2 {
+		t.Fatalf("expected 2 joined seats, got %d", c.Joined)
+	}
+}
+
+func TestLiveHubCoalescesSignals(t *testing.T) {
+	hub := NewLiveHub()
+	ch, stop := hub.Subscribe("a")
+	hub.Publish("a")
+	hub.Publish("a")
+	hub.Publish("b")
+
+	<-ch
+	select {
+	case <-ch:
+		t.Fatalf("expected signals to coalesce")
+	default:
+	}
+	stop()
+	hub.Publish("a")
+	if len(hub.subs) != 0 {
+		t.Fatalf("expected no subscribers left")
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type MatchEventsStore interface {
+	ListMatchEvents(ctx context.Context, matchID string) ([]domain.MatchEvent, error)
+	// AppendMatchEvents stores events with a seq past the last stored one and skips the rest.
+	// The first new event must directly follow the last stored seq.
+	AppendMatchEvents(ctx context.Context, matchID string, events []domain.MatchEvent) error
+}
+
+// MatchReader loads a match the user may see.
+type MatchReader interface {
+	GetMatchForUser(ctx context.Context, userID, matchID string) (domain.Match, error)
+}
+
+// MatchEventService keeps the turn-by-turn game log of a match. Anyone who can see the match can
+// read it; only the match creator, whose device tracked the game, appends to it.
+type MatchEventService struct {
+	Store   MatchEventsStore
+	Matches MatchReader
+	Formats FormatResolver
+}
+
+const maxMatchEventsPerRequest = 1000
+
+func (s *MatchEventService) List(ctx context.Context, userID, matchID string) ([]domain.MatchEvent, error) {
+	match, err := s.Matches.GetMatchForUser(ctx, userID, matchID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.Store.ListMatchEvents(ctx, match.ID)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []domain.MatchEvent{}
+	}
+	return events, nil
+}
+
+// Replay returns every seat's state at the end of each logged turn. See ReplayMatchEvents.
+func (s *MatchEventService) Replay(ctx context.Context, userID, matchID string) ([]domain.MatchReplayTurn, error) {
+	match, err := s.Matches.GetMatchForUser(ctx, userID, matchID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.Store.ListMatchEvents(ctx, match.ID)
+	if err != nil {
+		return nil, err
+	}
+	life, err := startingLife(ctx, s.Formats, match.Format)
+	if err != nil {
+		return nil, err
+	}
+	turns := ReplayMatchEvents(match, events, life)
+	if turns == nil {
+		turns = []domain.MatchReplayTurn{}
+	}
+	return turns, nil
+}
+
+// Append adds events to the end of the log and returns the whole log. Events already stored
+// (by seq) are skipped so uploads can be retried.
+func (s *MatchEventService) Append(ctx context.Context, userID, matchID string, events []domain.MatchEvent) ([]domain.MatchEvent, error) {
+	match, err := s.Matches.GetMatchForUser(ctx, userID, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if match.CreatedBy != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	switch {
+	case len(events) == 0:
+		return nil, domain.NewValidationError(map[string]string{"events": "required"})
+	case len(events) > maxMatchEventsPerRequest:
+		return nil, domain.NewValidationError(map[string]string{"events": fmt.Sprintf("at most %d events per request", maxMatchEventsPerRequest)})
+	}
+
+	seats := make(map[int]bool, len(match.Players))
+	for _, p := range match.Players {
+		if p.SeatIndex != nil {
+			seats[*p.SeatIndex] = true
+		}
+	}
+	out := make([]domain.MatchEvent, 0, len(events))
+	for i, e := range events {
+		msg := validateMatchEvent(e, seats)
+		switch {
+		case e.Seq < 1:
+			msg = "seq must be >= 1"
+		case e.Turn < 0:
+			msg = "turn must be >= 0"
+		}
+		if msg != "" {
+			return nil, domain.NewValidationError(map[string]string{"events": fmt.Sprintf("event %d: %s", i, msg)})
+		}
+		if i > 0 && e.Seq != events[i-1].Seq+1 {
+			return nil, domain.NewValidationError(map[string]string{"events": fmt.Sprintf("event %d: seq must follow %d", i, events[i-1].Seq)})
+		}
+		if e.OccurredAt != nil {
+			at := e.OccurredAt.UTC().Truncate(time.Millisecond)
+			e.OccurredAt = &at
+		}
+		out = append(out, e)
+	}
+
+	if err := s.Store.AppendMatchEvents(ctx, match.ID, out); err != nil {
+		return nil, err
+	}
+	return s.List(ctx, userID, match.ID)
+}
+
+// validateMatchEvent checks an event's kind, seats and values. Seq and turn are checked by the caller.
+func validateMatchEvent(e domain.MatchEvent, seats map[int]bool) string {
+	if !seats[e.SeatIndex] {
+		return "seat_index must be a seat in the match"
+	}
+
+	switch e.Kind {
+	case domain.MatchEventLife, domain.MatchEventPoison, domain.MatchEventCommanderDamage:
+		if e.Amount == 0 && e.Total == nil {
+			return "amount or total is required"
+		}
+		if e.Kind != domain.MatchEventLife && e.Total != nil && *e.Total < 0 {
+			return "total must be >= 0"
+		}
+	case domain.MatchEventEliminated, domain.MatchEventTurnPass:
+		if e.Amount != 0 || e.Total != nil {
+			return string(e.Kind) + " events have no amount or total"
+		}
+	default:
+		return "kind must be life, poison, commander_damage, eliminated, or turn_pass"
+	}
+
+	if e.Kind == domain.MatchEventCommanderDamage {
+		if e.SourceSeatIndex == nil || !seats[*e.SourceSeatIndex] {
+			return "source_seat_index must be a seat in the match"
+		}
+	} else if e.SourceSeatIndex != nil {
+		return "source_seat_index is only allowed on commander_damage events"
+	}
+	return ""
+}
+
+// ReplayMatchEvents plays the log forward from every seat starting at life and returns every
+// seat's state at the end of each turn that has events, in turn order.
+func ReplayMatchEvents(match domain.Match, events []domain.MatchEvent, life int) []domain.MatchReplayTurn {
+	var seatOrder []int
+	for _, p := range match.Players {
+		if p.SeatIndex != nil {
+			seatOrder = append(seatOrder, *p.SeatIndex)
+		}
+	}
+	sort.Ints(seatOrder)
+
+	state := make(map[int]*domain.MatchReplaySeat, len(seatOrder))
+	for _, seat := range seatOrder {
+		state[seat] = &domain.MatchReplaySeat{SeatIndex: seat, Life: life}
+	}
+
+	sorted := append([]domain.MatchEvent(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Seq < sorted[j].Seq })
+
+	var turns []domain.MatchReplayTurn
+	snapshot := func(turn int, events []domain.MatchEvent) {
+		row := domain.MatchReplayTurn{Turn: turn, Events: events, Seats: make([]domain.MatchReplaySeat, 0, len(seatOrder))}
+		for _, seat := range seatOrder {
+			st := *state[seat]
+			if len(st.CommanderDamage) > 0 {
+				damage := make(map[int]int, len(st.CommanderDamage))
+				for k, v := range st.CommanderDamage {
+					damage[k] = v
+				}
+				st.CommanderDamage = damage
+			}
+			row.Seats = append(row.Seats, st)
+		}
+		turns = append(turns, row)
+	}
+
+	var pending []domain.MatchEvent
+	for i, e := range sorted {
+		if len(pending) > 0 && e.Turn != pending[0].Turn {
+			snapshot(pending[0].Turn, pending)
+			pending = nil
+		}
+		pending = append(pending, sorted[i])
+
+		if seat, ok := state[e.SeatIndex]; ok {
+			applyMatchEvent(seat, e)
+		}
+	}
+	if len(pending) > 0 {
+		snapshot(pending[0].Turn, pending)
+	}
+	return turns
+}
+
+// applyMatchEvent updates the seat an event is about. Turn passes change nothing.
+func applyMatchEvent(seat *domain.MatchReplaySeat, e domain.MatchEvent) {
+	switch e.Kind {
+	case domain.MatchEventLife:
+		seat.Life = applyMatchEventAmount(seat.Life, e)
+	case domain.MatchEventPoison:
+		seat.Poison = applyMatchEventAmount(seat.Poison, e)
+	case domain.MatchEventCommanderDamage:
+		if e.SourceSeatIndex == nil {
+			return
+		}
+		if seat.CommanderDamage == nil {
+			seat.CommanderDamage = make(map[int]int)
+		}
+		seat.CommanderDamage[*e.SourceSeatIndex] = applyMatchEventAmount(seat.CommanderDamage[*e.SourceSeatIndex], e)
+	case domain.MatchEventEliminated:
+		seat.Eliminated = true
+	}
+}
+
+func applyMatchEventAmount(current int, e domain.MatchEvent) int {
+	if e.Total != nil {
+		return *e.Total
+	}
+	return current + e.Amount
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type stubMatchEventsStore struct {
+	events []domain.MatchEvent
+}
+
+func (s *stubMatchEventsStore) ListMatchEvents(ctx context.Context, matchID string) ([]domain.MatchEvent, error) {
+	return s.events, nil
+}
+
+func (s *stubMatchEventsStore) AppendMatchEvents(ctx context.Context, matchID string, events []domain.MatchEvent) error {
+	last := 0
+	if len(s.events) > 0 {
+		last = s.events[len(s.events)-1].Seq
+	}
+	for _, e := range events {
+		if e.Seq > last {
+			s.events = append(s.events, e)
+			last = e.Seq
+		}
+	}
+	return nil
+}
+
+type stubMatchReader struct {
+	match domain.Match
+}
+
+func (s stubMatchReader) GetMatchForUser(ctx context.Context, userID, matchID string) (domain.Match, error) {
+	if matchID != s.match.ID {
+		return domain.Match{}, domain.ErrNotFound
+	}
+	return s.match, nil
+}
+
+func eventTestMatch() domain.Match {
+	seat := func(i int) *int { return &i }
+	return domain.Match{
+		ID:        "m1",
+		CreatedBy: "u1",
+		Format:    domain.FormatCommander,
+		Players: []domain.MatchPlayer{
+			{User: domain.UserSummary{ID: "u1"}, SeatIndex: seat(0)},
+			{User: domain.UserSummary{ID: "u2"}, SeatIndex: seat(1)},
+		},
+	}
+}
+
+func TestMatchEventAppend(t *testing.T) {
+	store := &stubMatchEventsStore{}
+	svc := &MatchEventService{Store: store, Matches: stubMatchReader{match: eventTestMatch()}}
+	ctx := context.Background()
+	zero, one := 0, 1
+
+	if _, err := svc.Append(ctx, "u2", "m1", []domain.MatchEvent{{Seq: 1, Kind: domain.MatchEventTurnPass, SeatIndex: 0}}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+
+	bad := [][]domain.MatchEvent{
+		nil,
+		{{Seq: 0, Kind: domain.MatchEventTurnPass}},
+		{{Seq: 1, Kind: "mana", SeatIndex: 0}},
+		{{Seq: 1, Kind: domain.MatchEventLife, SeatIndex: 5, Amount: -1}},
+		{{Seq: 1, Kind: domain.MatchEventLife, SeatIndex: 0}},
+		{{Seq: 1, Kind: domain.MatchEventEliminated, SeatIndex: 0, Amount: 1}},
+		{{Seq: 1, Kind: domain.MatchEventCommanderDamage, SeatIndex: 0, Amount: 3}},
+		{{Seq: 1, Kind: domain.MatchEventLife, SeatIndex: 0, Amount: -3, SourceSeatIndex: &one}},
+		{{Seq: 1, Kind: domain.MatchEventTurnPass, SeatIndex: 0}, {Seq: 3, Kind: domain.MatchEventTurnPass, SeatIndex: 1}},
+	}
+	for i, events := range bad {
+		if _, err := svc.Append(ctx, "u1", "m1", events); err == nil {
+			t.Fatalf("case %d: expected validation error", i)
+		} else {
+			expectValidation(t, err)
+		}
+	}
+
+	batch := []domain.MatchEvent{
+		{Seq: 1, Kind: domain.MatchEventTurnPass, Turn: 1, SeatIndex: 0},
+		{Seq: 2, Kind: domain.MatchEventCommanderDamage, Turn: 1, SeatIndex: 1, SourceSeatIndex: &zero, Amount: 5},
+	}
+	if _, err := svc.Append(ctx, "u1", "m1", batch); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	// A retried upload with one new event only adds the new one.
+	retry := append(batch, domain.MatchEvent{Seq: 3, Kind: domain.MatchEventLife, Turn: 1, SeatIndex: 1, Amount: -5})
+	events, err := svc.Append(ctx, "u1", "m1", retry)
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if len(events) != 3 || events[2].Seq != 3 {
+		t.Fatalf("unexpected log: %+v", events)
+	}
+}
+
+func TestReplayMatchEvents(t *testing.T) {
+	zero, one, thirty := 0, 1, 30
+	events := []domain.MatchEvent{
+		{Seq: 1, Kind: domain.MatchEventTurnPass, Turn: 1, SeatIndex: 0},
+		{Seq: 2, Kind: domain.MatchEventLife, Turn: 1, SeatIndex: 1, Amount: -7},
+		{Seq: 3, Kind: domain.MatchEventCommanderDamage, Turn: 1, SeatIndex: 1, SourceSeatIndex: &zero, Amount: 7},
+		{Seq: 4, Kind: domain.MatchEventTurnPass, Turn: 2, SeatIndex: 1},
+		{Seq: 5, Kind: domain.MatchEventLife, Turn: 2, SeatIndex: 0, Total: &thirty},
+		{Seq: 6, Kind: domain.MatchEventPoison, Turn: 2, SeatIndex: 0, Amount: 2},
+		{Seq: 7, Kind: domain.MatchEventCommanderDamage, Turn: 2, SeatIndex: 0, SourceSeatIndex: &one, Amount: 4},
+		{Seq: 8, Kind: domain.MatchEventEliminated, Turn: 2, SeatIndex: 0},
+	}
+
+	turns := ReplayMatchEvents(eventTestMatch(), events, 40)
+	if len(turns) != 2 {
+		t.Fatalf("expected 2 turns, got %d", len(turns))
+	}
+	first := turns[0]
+	if first.Turn != 1 || len(first.Events) != 3 || first.Seats[0].Life != 40 || first.Seats[1].Life != 33 || first.Seats[1].CommanderDamage[0] != 7 {
+		t.Fatalf("unexpected first turn: %+v", first)
+	}
+	second := turns[1].Seats[0]
+	if second.Life != 30 || second.Poison != 2 || second.CommanderDamage[1] != 4 || !second.Eliminated {
+		t.Fatalf("unexpected second turn seat: %+v", second)
+	}
+	if len(first.Seats[0].CommanderDamage) != 0 {
+		t.Fatalf("later turns changed an earlier snapshot: %+v", first.Seats[0])
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+const (
+	maxMatchGames = 7
+	maxMulligans  = 7
+)
+
+// applyMatchGames checks the games of a best-of-N 1v1 match and derives the players' places from
+// them: the seat that won the match is placed 1, the other 2. A drawn match needs both players on
+// the same number of game wins and places both 1; an abandoned match must be undecided and has no
+// places. A place the client sent must agree. It returns the games in game order and the players
+// with their places filled in.
+func applyMatchGames(format domain.Format, outcome domain.MatchOutcome, bestOf int, games []domain.MatchGame, players []domain.MatchParticipantInput) ([]domain.MatchGame, []domain.MatchParticipantInput, error) {
+	switch {
+	case len(games) == 0:
+		return nil, nil, domain.NewValidationError(map[string]string{"games": "required when best_of is set"})
+	case format.Multiplayer:
+		return nil, nil, domain.NewValidationError(map[string]string{"games": "games are only recorded for 1v1 formats"})
+	case len(players) != 2:
+		return nil, nil, domain.NewValidationError(map[string]string{"games": "games need exactly 2 players"})
+	case bestOf != 0 && (bestOf < 1 || bestOf > maxMatchGames || bestOf%2 == 0):
+		return nil, nil, domain.NewValidationError(map[string]string{"best_of": "must be 1, 3, 5 or 7"})
+	case len(games) > maxMatchGames:
+		return nil, nil, domain.NewValidationError(map[string]string{"games": fmt.Sprintf("at most %d games", maxMatchGames)})
+	}
+
+	seats := map[int]bool{players[0].SeatIndex: true, players[1].SeatIndex: true}
+	sorted := make([]domain.MatchGame, len(games))
+	copy(sorted, games)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Number < sorted[j].Number })
+
+	needed := bestOf/2 + 1
+	wins := make(map[int]int, 2)
+	winner := -1
+	for i, g := range sorted {
+		msg := ""
+		switch {
+		case g.Number != i+1:
+			msg = "game_number values must run from 1 without gaps"
+		case !seats[g.WinnerSeatIndex]:
+			msg = "winner_seat_index must be a player's seat_index"
+		case g.OnPlaySeatIndex != nil && !seats[*g.OnPlaySeatIndex]:
+			msg = "on_play_seat_index must be a player's seat_index"
+		case len(g.Mulligans) != 0 && len(g.Mulligans) != len(players):
+			msg = "mulligans must have one entry per seat"
+		case g.DurationSeconds < 0:
+			msg = "duration_seconds must be >= 0"
+		case bestOf != 0 && winner >= 0:
+			msg = "the match was already decided"
+		}
+		for _, m := range g.Mulligans {
+			if m < 0 || m > maxMulligans {
+				msg = fmt.Sprintf("mulligans must be between 0 and %d", maxMulligans)
+			}
+		}
+		if msg != "" {
+			return nil, nil, domain.NewValidationError(map[string]string{"games": fmt.Sprintf("game %d: %s", g.Number, msg)})
+		}
+		wins[g.WinnerSeatIndex]++
+		if bestOf != 0 && wins[g.WinnerSeatIndex] == needed {
+			winner = g.WinnerSeatIndex
+		}
+	}
+
+	a, b := players[0].SeatIndex, players[1].SeatIndex
+	if bestOf == 0 && outcome != domain.MatchOutcomeAbandoned {
+		switch {
+		case wins[a] > wins[b]:
+			winner = a
+		case wins[b] > wins[a]:
+			winner = b
+		}
+	}
+	switch outcome {
+	case domain.MatchOutcomeWin:
+		if winner < 0 {
+			msg := "one player must win more games"
+			if bestOf != 0 {
+				msg = fmt.Sprintf("one player must win %d games", needed)
+			}
+			return nil, nil, domain.NewValidationError(map[string]string{"games": msg})
+		}
+	case domain.MatchOutcomeDraw:
+		if winner >= 0 || wins[a] != wins[b] {
+			return nil, nil, domain.NewValidationError(map[string]string{"games": "a draw needs both players on the same number of game wins"})
+		}
+	case domain.MatchOutcomeAbandoned:
+		if winner >= 0 {
+			return nil, nil, domain.NewValidationError(map[string]string{"games": "an abandoned match must be undecided"})
+		}
+	}
+
+	out := make([]domain.MatchParticipantInput, len(players))
+	for i, p := range players {
+		place := 2
+		switch {
+		case outcome == domain.MatchOutcomeAbandoned:
+			place = 0
+		case outcome == domain.MatchOutcomeDraw, p.SeatIndex == winner:
+			place = 1
+		}
+		if p.Place != 0 && p.Place != place {
+			return nil, nil, domain.NewValidationError(map[string]string{"players": "place must match the result of the games"})
+		}
+		p.Place = place
+		out[i] = p
+	}
+	return sorted, out, nil
+}
+
+// gamesDuration is the length of a match recorded game by game.
+func gamesDuration(games []domain.MatchGame) int {
+	total := 0
+	for _, g := range games {
+		total += g.DurationSeconds
+	}
+	return total
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"strings"
 	"time"
@@ -10,22 +11,58 @@ import (
 )
 
 type MatchesStore interface {
-	CreateMatch(ctx context.Context, createdBy string, startedAt, endedAt, playedAt *time.Time, winnerID string, participants []domain.MatchParticipantInput, format domain.GameFormat, totalDurationSeconds, turnCount int, clientRef string, updatedAt time.Time) (string, bool, error)
+	CreateMatch(ctx context.Context, in domain.MatchInput) (string, bool, error)
+	UpdateMatch(ctx context.Context, matchID string, in domain.MatchInput) (bool, error)
+	DeleteMatch(ctx context.Context, matchID, createdBy string, deletedAt time.Time) error
 	GetMatchByClientRef(ctx context.Context, createdBy, clientRef string) (domain.Match, error)
-	ListMatchesForUser(ctx context.Context, userID string, limit int) ([]domain.Match, error)
+	ListMatchesForUser(ctx context.Context, userID string, filter domain.MatchListFilter) ([]domain.Match, error)
 	GetMatchForUser(ctx context.Context, userID, matchID string) (domain.Match, error)
 	StatsSummary(ctx context.Context, userID string) (domain.StatsSummary, error)
 	HeadToHead(ctx context.Context, userID, opponentID string) (domain.HeadToHeadStats, error)
+	SeatStats(ctx context.Context, userID string) (domain.SeatStats, error)
+	GameStats(ctx context.Context, userID string) (domain.GameStats, error)
+	EliminationStats(ctx context.Context, userID string) (domain.EliminationStats, error)
+	TurnTimeStats(ctx context.Context, userID string) (domain.TurnTimeStats, error)
+	SetParticipantConfirmation(ctx context.Context, matchID, userID string, state domain.MatchConfirmation, reason string, at time.Time) error
+	SetParticipantDeck(ctx context.Context, matchID, userID, deckID string) error
 }
 
 type FriendshipChecker interface {
 	AreFriends(ctx context.Context, userA, userB string) (bool, error)
 }
 
+type GroupMembershipChecker interface {
+	IsGroupMember(ctx context.Context, groupID, userID string) (bool, error)
+	ShareGroup(ctx context.Context, userA, userB string) (bool, error)
+}
+
+// GuestRoster resolves guest_id values on match players against the creator's saved guests.
+type GuestRoster interface {
+	GetGuest(ctx context.Context, ownerID, guestID string) (domain.Guest, error)
+	// GuestIDForName returns the saved guest a guest name links to, or "" when it matches no alias.
+	GuestIDForName(ctx context.Context, ownerID, guestName string) (string, error)
+}
+
+// DeckLookup resolves deck_id values on match players against the seat owner's decks.
+type DeckLookup interface {
+	GetDeck(ctx context.Context, ownerID, deckID string) (domain.Deck, error)
+}
+
+// CardLookup resolves commander_ids on match players against the card database.
+type CardLookup interface {
+	GetCards(ctx context.Context, ids []string) ([]domain.Card, error)
+}
+
 type MatchService struct {
-	Matches MatchesStore
-	Friends FriendshipChecker
-	Now     func() time.Time
+	Matches  MatchesStore
+	Friends  FriendshipChecker
+	Groups   GroupMembershipChecker
+	Guests   GuestRoster
+	Decks    DeckLookup
+	Cards    CardLookup
+	Formats  FormatResolver
+	Notifier MatchTaggedNotifier
+	Now      func() time.Time
 }
 
 type CreateMatchParams struct {
@@ -37,10 +74,21 @@ type CreateMatchParams struct {
 	Format               domain.GameFormat
 	TotalDurationSeconds int
 	TurnCount            int
+	StartingSeatIndex    *int
+	GroupID              string
 	ClientMatchID        string
 	UpdatedAt            time.Time
 	Players              []domain.MatchParticipantInput
 	Results              []domain.MatchResultInput
+	// Outcome defaults to a win. Draws and abandoned matches need Players.
+	Outcome domain.MatchOutcome
+	// WinConditionTag names a custom win condition. WinningCard is free text.
+	WinCondition    domain.WinCondition
+	WinConditionTag string
+	WinningCard     string
+	// BestOf and Games record a 1v1 match game by game; the players' places come from the games.
+	BestOf int
+	Games  []domain.MatchGame
 }
 
 type MatchCreateResult int
@@ -50,6 +98,48 @@ const (
 	MatchCreateConflict
 )
 
+type ListMatchesParams struct {
+	Limit      int
+	Cursor     string
+	Format     domain.GameFormat
+	From       *time.Time
+	To         *time.Time
+	OpponentID string
+	GuestName  string
+	Result     domain.MatchResultFilter
+	// GroupID lists every match recorded in the group instead of the user's own matches.
+	GroupID string
+}
+
+type UpdateMatchParams struct {
+	// Format empty keeps the match's format.
+	Format               domain.GameFormat
+	TotalDurationSeconds int
+	TurnCount            int
+	StartingSeatIndex    *int
+	UpdatedAt            time.Time
+	Players              []domain.MatchParticipantInput
+	// Outcome empty keeps the match's outcome.
+	Outcome domain.MatchOutcome
+	// WinCondition, WinConditionTag and WinningCard nil keep the stored value; an empty string
+	// clears it. A kept tag is dropped unless the match's win condition stays custom, and kept
+	// values are dropped when the match becomes abandoned.
+	WinCondition    *string
+	WinConditionTag *string
+	WinningCard     *string
+	// Games nil keeps the match's games and best_of; an empty list removes them.
+	BestOf int
+	Games  []domain.MatchGame
+}
+
+type MatchUpdateResult int
+
+const (
+	MatchUpdateApplied MatchUpdateResult = iota
+	MatchUpdateNoop
+	MatchUpdateConflict
+)
+
 func (s *MatchService) CreateMatch(ctx context.Context, creatorID string, p CreateMatchParams) (domain.Match, MatchCreateResult, error) {
 	if s.Now == nil {
 		s.Now = time.Now
@@ -66,23 +156,77 @@ func (s *MatchService) CreateMatch(ctx context.Context, creatorID string, p Crea
 		}
 	}
 
-	format := normalizeFormat(p.Format)
-	if !validFormat(format) {
-		return domain.Match{}, MatchCreateConflict, domain.NewValidationError(map[string]string{"format": "must be commander, brawl, standard, or modern"})
+	format, err := s.validateMatchFields(ctx, p.Format, "", p.TotalDurationSeconds, p.TurnCount)
+	if err != nil {
+		return domain.Match{}, MatchCreateConflict, err
+	}
+
+	groupID := strings.TrimSpace(p.GroupID)
+	if groupID != "" {
+		if err := s.requireGroupMember(ctx, groupID, creatorID); err != nil {
+			return domain.Match{}, MatchCreateConflict, err
+		}
+	}
+	p.GroupID = groupID
+
+	p.Outcome, err = matchOutcome(p.Outcome, domain.MatchOutcomeWin)
+	if err != nil {
+		return domain.Match{}, MatchCreateConflict, err
 	}
-	if p.TotalDurationSeconds < 0 {
-		return domain.Match{}, MatchCreateConflict, domain.NewValidationError(map[string]string{"total_duration_seconds": "must be >= 0"})
+	ending, err := validateWinCondition(p.Outcome, p.WinCondition, p.WinConditionTag, p.WinningCard)
+	if err != nil {
+		return domain.Match{}, MatchCreateConflict, err
 	}
-	if p.TurnCount < 0 {
-		return domain.Match{}, MatchCreateConflict, domain.NewValidationError(map[string]string{"turn_count": "must be >= 0"})
+
+	var games []domain.MatchGame
+	if len(p.Games) > 0 || p.BestOf != 0 {
+		if len(p.Players) == 0 {
+			return domain.Match{}, MatchCreateConflict, domain.NewValidationError(map[string]string{"games": "games need players"})
+		}
+		games, p.Players, err = applyMatchGames(format, p.Outcome, p.BestOf, p.Games, p.Players)
+		if err != nil {
+			return domain.Match{}, MatchCreateConflict, err
+		}
+		if p.TotalDurationSeconds == 0 {
+			p.TotalDurationSeconds = gamesDuration(games)
+		}
 	}
 
 	participants, winnerID, err := s.buildParticipants(ctx, creatorID, p)
 	if err != nil {
 		return domain.Match{}, MatchCreateConflict, err
 	}
+	if err := validateStartingSeat(p.StartingSeatIndex, participants); err != nil {
+		return domain.Match{}, MatchCreateConflict, err
+	}
+	if err := checkTeams(format, participants); err != nil {
+		return domain.Match{}, MatchCreateConflict, err
+	}
+	if err := s.checkCommanders(ctx, format, participants); err != nil {
+		return domain.Match{}, MatchCreateConflict, err
+	}
 
-	matchID, created, err := s.Matches.CreateMatch(ctx, creatorID, p.StartedAt, p.EndedAt, p.PlayedAt, winnerID, participants, format, p.TotalDurationSeconds, p.TurnCount, clientRef, p.UpdatedAt)
+	matchID, created, err := s.Matches.CreateMatch(ctx, domain.MatchInput{
+		CreatedBy:            creatorID,
+		StartedAt:            p.StartedAt,
+		EndedAt:              p.EndedAt,
+		PlayedAt:             p.PlayedAt,
+		WinnerID:             winnerID,
+		Outcome:              p.Outcome,
+		WinCondition:         ending.WinCondition,
+		WinConditionTag:      ending.WinConditionTag,
+		WinningCard:          ending.WinningCard,
+		Participants:         participants,
+		Format:               format.Slug,
+		TotalDurationSeconds: p.TotalDurationSeconds,
+		TurnCount:            p.TurnCount,
+		StartingSeatIndex:    p.StartingSeatIndex,
+		GroupID:              groupID,
+		ClientRef:            clientRef,
+		UpdatedAt:            p.UpdatedAt,
+		BestOf:               p.BestOf,
+		Games:                games,
+	})
 	if err != nil {
 		return domain.Match{}, MatchCreateConflict, err
 	}
@@ -94,11 +238,281 @@ func (s *MatchService) CreateMatch(ctx context.Context, creatorID string, p Crea
 	if !created {
 		return match, MatchCreateConflict, nil
 	}
+	s.notifyTagged(ctx, creatorID, matchID, participants, nil)
 	return match, MatchCreateApplied, nil
 }
 
-func (s *MatchService) ListMatches(ctx context.Context, userID string, limit int) ([]domain.Match, error) {
-	return s.Matches.ListMatchesForUser(ctx, userID, limit)
+func (s *MatchService) UpdateMatch(ctx context.Context, userID, matchID string, p UpdateMatchParams) (domain.Match, MatchUpdateResult, error) {
+	if len(p.Players) == 0 {
+		return domain.Match{}, MatchUpdateConflict, domain.NewValidationError(map[string]string{"players": "required"})
+	}
+
+	existing, err := s.Matches.GetMatchForUser(ctx, userID, matchID)
+	if err != nil {
+		return domain.Match{}, MatchUpdateConflict, err
+	}
+	if existing.CreatedBy != userID {
+		return domain.Match{}, MatchUpdateConflict, domain.ErrForbidden
+	}
+	if strings.TrimSpace(string(p.Format)) == "" {
+		p.Format = existing.Format
+	}
+	format, err := s.validateMatchFields(ctx, p.Format, existing.Format, p.TotalDurationSeconds, p.TurnCount)
+	if err != nil {
+		return domain.Match{}, MatchUpdateConflict, err
+	}
+
+	outcome, err := matchOutcome(p.Outcome, existing.Outcome)
+	if err != nil {
+		return domain.Match{}, MatchUpdateConflict, err
+	}
+	condition, tag, card := updatedMatchEnding(existing, outcome, p)
+	ending, err := validateWinCondition(outcome, condition, tag, card)
+	if err != nil {
+		return domain.Match{}, MatchUpdateConflict, err
+	}
+
+	if p.Games == nil {
+		p.Games = existing.Games
+		p.BestOf = 0
+		if existing.BestOf != nil {
+			p.BestOf = *existing.BestOf
+		}
+	}
+	var games []domain.MatchGame
+	if len(p.Games) > 0 || p.BestOf != 0 {
+		games, p.Players, err = applyMatchGames(format, outcome, p.BestOf, p.Games, p.Players)
+		if err != nil {
+			return domain.Match{}, MatchUpdateConflict, err
+		}
+		if p.TotalDurationSeconds == 0 {
+			p.TotalDurationSeconds = gamesDuration(games)
+		}
+	}
+
+	participants, winnerID, err := s.buildParticipantsFromPayload(ctx, userID, existing.GroupID, outcome, p.Players)
+	if err != nil {
+		return domain.Match{}, MatchUpdateConflict, err
+	}
+	if err := validateStartingSeat(p.StartingSeatIndex, participants); err != nil {
+		return domain.Match{}, MatchUpdateConflict, err
+	}
+	if err := checkTeams(format, participants); err != nil {
+		return domain.Match{}, MatchUpdateConflict, err
+	}
+	if err := s.checkCommanders(ctx, format, participants); err != nil {
+		return domain.Match{}, MatchUpdateConflict, err
+	}
+
+	applied, err := s.Matches.UpdateMatch(ctx, existing.ID, domain.MatchInput{
+		CreatedBy:            userID,
+		WinnerID:             winnerID,
+		Outcome:              outcome,
+		WinCondition:         ending.WinCondition,
+		WinConditionTag:      ending.WinConditionTag,
+		WinningCard:          ending.WinningCard,
+		Participants:         participants,
+		Format:               format.Slug,
+		TotalDurationSeconds: p.TotalDurationSeconds,
+		TurnCount:            p.TurnCount,
+		StartingSeatIndex:    p.StartingSeatIndex,
+		UpdatedAt:            p.UpdatedAt,
+		BestOf:               p.BestOf,
+		Games:                games,
+	})
+	if err != nil {
+		return domain.Match{}, MatchUpdateConflict, err
+	}
+
+	match, err := s.Matches.GetMatchForUser(ctx, userID, existing.ID)
+	if err != nil {
+		return domain.Match{}, MatchUpdateConflict, err
+	}
+	if applied {
+		s.notifyTagged(ctx, userID, existing.ID, participants, existing.Players)
+		return match, MatchUpdateApplied, nil
+	}
+	if match.UpdatedAt.Equal(p.UpdatedAt) {
+		return match, MatchUpdateNoop, nil
+	}
+	return match, MatchUpdateConflict, nil
+}
+
+func (s *MatchService) DeleteMatch(ctx context.Context, userID, matchID string) error {
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+
+	existing, err := s.Matches.GetMatchForUser(ctx, userID, matchID)
+	if err != nil {
+		return err
+	}
+	if existing.CreatedBy != userID {
+		return domain.ErrForbidden
+	}
+	return s.Matches.DeleteMatch(ctx, existing.ID, userID, s.Now().UTC().Truncate(time.Millisecond))
+}
+
+// ConfirmMatch accepts the result of a match the user was named in.
+func (s *MatchService) ConfirmMatch(ctx context.Context, userID, matchID string) (domain.Match, error) {
+	return s.setConfirmation(ctx, userID, matchID, domain.MatchConfirmationConfirmed, "")
+}
+
+// DisputeMatch objects to the result of a match the user was named in. The creator cannot
+// dispute their own match; they can edit or delete it instead.
+func (s *MatchService) DisputeMatch(ctx context.Context, userID, matchID, reason string) (domain.Match, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return domain.Match{}, domain.NewValidationError(map[string]string{"reason": "required"})
+	}
+	if len(reason) > maxDisputeReasonLength {
+		return domain.Match{}, domain.NewValidationError(map[string]string{"reason": "must be 500 characters or less"})
+	}
+	return s.setConfirmation(ctx, userID, matchID, domain.MatchConfirmationDisputed, reason)
+}
+
+func (s *MatchService) setConfirmation(ctx context.Context, userID, matchID string, state domain.MatchConfirmation, reason string) (domain.Match, error) {
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+
+	existing, err := s.Matches.GetMatchForUser(ctx, userID, matchID)
+	if err != nil {
+		return domain.Match{}, err
+	}
+	seat := -1
+	for i, player := range existing.Players {
+		if player.User.ID == userID && player.Confirmation != "" {
+			seat = i
+			break
+		}
+	}
+	if seat < 0 {
+		return domain.Match{}, domain.ErrNotFound
+	}
+	if existing.CreatedBy == userID {
+		if state == domain.MatchConfirmationDisputed {
+			return domain.Match{}, domain.NewValidationError(map[string]string{"match": "creators edit or delete their own matches instead of disputing them"})
+		}
+		return existing, nil
+	}
+
+	at := s.Now().UTC().Truncate(time.Millisecond)
+	if err := s.Matches.SetParticipantConfirmation(ctx, existing.ID, userID, state, reason, at); err != nil {
+		return domain.Match{}, err
+	}
+	return s.Matches.GetMatchForUser(ctx, userID, existing.ID)
+}
+
+// SetDeck records the deck the user played in a match they have a seat in; an empty deckID clears it.
+func (s *MatchService) SetDeck(ctx context.Context, userID, matchID, deckID string) (domain.Match, error) {
+	existing, err := s.Matches.GetMatchForUser(ctx, userID, matchID)
+	if err != nil {
+		return domain.Match{}, err
+	}
+	seated := false
+	for _, player := range existing.Players {
+		if player.User.ID == userID && player.Confirmation != "" {
+			seated = true
+			break
+		}
+	}
+	if !seated {
+		return domain.Match{}, domain.ErrNotFound
+	}
+
+	deckID = strings.TrimSpace(deckID)
+	if deckID != "" {
+		if err := s.checkDeck(ctx, userID, deckID, "deck_id"); err != nil {
+			return domain.Match{}, err
+		}
+	}
+	if err := s.Matches.SetParticipantDeck(ctx, existing.ID, userID, deckID); err != nil {
+		return domain.Match{}, err
+	}
+	return s.Matches.GetMatchForUser(ctx, userID, existing.ID)
+}
+
+// notifyTagged pushes to registered participants other than the creator who were not already
+// in previous. Delivery failures are logged by the notifier and never fail the write.
+func (s *MatchService) notifyTagged(ctx context.Context, creatorID, matchID string, participants []domain.MatchParticipantInput, previous []domain.MatchPlayer) {
+	if s.Notifier == nil {
+		return
+	}
+	seen := make(map[string]bool, len(previous))
+	for _, player := range previous {
+		seen[player.User.ID] = true
+	}
+	for _, participant := range participants {
+		if participant.UserID == "" || participant.UserID == creatorID || seen[participant.UserID] {
+			continue
+		}
+		seen[participant.UserID] = true
+		_ = s.Notifier.NotifyMatchTagged(ctx, MatchTaggedNotification{
+			MatchID:       matchID,
+			CreatorID:     creatorID,
+			ParticipantID: participant.UserID,
+		})
+	}
+}
+
+// ListMatches returns one page of the user's matches and an opaque cursor for the next page.
+// The cursor is empty once the last page has been returned.
+func (s *MatchService) ListMatches(ctx context.Context, userID string, p ListMatchesParams) ([]domain.Match, string, error) {
+	limit := p.Limit
+	if limit <= 0 {
+		limit = defaultMatchPageSize
+	}
+	if limit > maxMatchPageSize {
+		limit = maxMatchPageSize
+	}
+
+	filter := domain.MatchListFilter{
+		Limit:      limit + 1,
+		From:       p.From,
+		To:         p.To,
+		OpponentID: strings.TrimSpace(p.OpponentID),
+		GuestName:  strings.TrimSpace(p.GuestName),
+		Result:     p.Result,
+		GroupID:    strings.TrimSpace(p.GroupID),
+	}
+	if filter.GroupID != "" {
+		if err := s.requireGroupMember(ctx, filter.GroupID, userID); err != nil {
+			return nil, "", err
+		}
+	}
+	format, err := filterFormat(ctx, s.Formats, p.Format)
+	if err != nil {
+		return nil, "", err
+	}
+	filter.Format = format
+	switch p.Result {
+	case "", domain.MatchResultWon, domain.MatchResultLost:
+	default:
+		return nil, "", domain.NewValidationError(map[string]string{"result": "must be won or lost"})
+	}
+	if p.From != nil && p.To != nil && !p.From.Before(*p.To) {
+		return nil, "", domain.NewValidationError(map[string]string{"to": "must be after from"})
+	}
+	if cursor := strings.TrimSpace(p.Cursor); cursor != "" {
+		before, id, err := decodeMatchCursor(cursor)
+		if err != nil {
+			return nil, "", domain.NewValidationError(map[string]string{"cursor": "invalid"})
+		}
+		filter.BeforeTime = &before
+		filter.BeforeID = id
+	}
+
+	matches, err := s.Matches.ListMatchesForUser(ctx, userID, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(matches) <= limit {
+		return matches, "", nil
+	}
+	matches = matches[:limit]
+	last := matches[len(matches)-1]
+	return matches, encodeMatchCursor(matchSortTime(last), last.ID), nil
 }
 
 func (s *MatchService) GetMatch(ctx context.Context, userID, matchID string) (domain.Match, error) {
@@ -113,37 +527,239 @@ func (s *MatchService) HeadToHead(ctx context.Context, userID, opponentID string
 	return s.Matches.HeadToHead(ctx, userID, opponentID)
 }
 
-func normalizeFormat(format domain.GameFormat) domain.GameFormat {
-	raw := strings.ToLower(strings.TrimSpace(string(format)))
-	if raw == "" {
-		return domain.FormatCommander
+func (s *MatchService) Seats(ctx context.Context, userID string) (domain.SeatStats, error) {
+	return s.Matches.SeatStats(ctx, userID)
+}
+
+func (s *MatchService) Games(ctx context.Context, userID string) (domain.GameStats, error) {
+	return s.Matches.GameStats(ctx, userID)
+}
+
+func (s *MatchService) Eliminations(ctx context.Context, userID string) (domain.EliminationStats, error) {
+	return s.Matches.EliminationStats(ctx, userID)
+}
+
+func (s *MatchService) TurnTimes(ctx context.Context, userID string) (domain.TurnTimeStats, error) {
+	return s.Matches.TurnTimeStats(ctx, userID)
+}
+
+const (
+	defaultMatchPageSize     = 25
+	maxMatchPageSize         = 100
+	maxDisputeReasonLength   = 500
+	maxSeatCommanders        = 2
+	maxWinConditionTagLength = 40
+	maxWinningCardLength     = 150
+)
+
+func matchSortTime(m domain.Match) time.Time {
+	if m.PlayedAt != nil {
+		return *m.PlayedAt
 	}
-	if raw == "edh" {
-		return domain.FormatCommander
+	return m.CreatedAt
+}
+
+func encodeMatchCursor(before time.Time, id string) string {
+	raw := before.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeMatchCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", errors.New("malformed cursor")
 	}
-	return domain.GameFormat(raw)
+	before, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return before, id, nil
 }
 
-func validFormat(format domain.GameFormat) bool {
-	switch format {
-	case domain.FormatCommander, domain.FormatBrawl, domain.FormatStandard, domain.FormatModern:
-		return true
-	default:
-		return false
+// validateMatchFields resolves the match's format; current is the format already stored, if any.
+func (s *MatchService) validateMatchFields(ctx context.Context, rawFormat, current domain.GameFormat, totalDurationSeconds, turnCount int) (domain.Format, error) {
+	format, msg, err := usableFormat(ctx, s.Formats, rawFormat, current)
+	if err != nil {
+		return domain.Format{}, err
 	}
+	if msg != "" {
+		return domain.Format{}, domain.NewValidationError(map[string]string{"format": msg})
+	}
+	if totalDurationSeconds < 0 {
+		return domain.Format{}, domain.NewValidationError(map[string]string{"total_duration_seconds": "must be >= 0"})
+	}
+	if turnCount < 0 {
+		return domain.Format{}, domain.NewValidationError(map[string]string{"turn_count": "must be >= 0"})
+	}
+	return format, nil
+}
+
+func validateStartingSeat(startingSeat *int, participants []domain.MatchParticipantInput) error {
+	if startingSeat == nil {
+		return nil
+	}
+	for _, p := range participants {
+		if p.SeatIndex == *startingSeat {
+			return nil
+		}
+	}
+	return domain.NewValidationError(map[string]string{"starting_seat_index": "must match a player's seat_index"})
 }
 
 func (s *MatchService) buildParticipants(ctx context.Context, creatorID string, p CreateMatchParams) ([]domain.MatchParticipantInput, string, error) {
 	if len(p.Players) > 0 {
-		return s.buildParticipantsFromPayload(ctx, creatorID, p.Players)
+		return s.buildParticipantsFromPayload(ctx, creatorID, p.GroupID, p.Outcome, p.Players)
+	}
+	if p.Outcome != domain.MatchOutcomeWin {
+		return nil, "", domain.NewValidationError(map[string]string{"outcome": "draws and abandoned matches need players"})
 	}
 	if len(p.Results) > 0 {
-		return s.buildParticipantsFromResults(ctx, creatorID, p.Results)
+		return s.buildParticipantsFromResults(ctx, creatorID, p.GroupID, p.Results)
+	}
+	return s.buildParticipantsFromLegacy(ctx, creatorID, p.GroupID, p.PlayerIDs, p.WinnerID)
+}
+
+// rosterGuest loads a guest from the creator's roster; unknown ids are a validation error.
+func (s *MatchService) rosterGuest(ctx context.Context, creatorID, guestID string) (domain.Guest, error) {
+	if s.Guests == nil {
+		return domain.Guest{}, domain.NewValidationError(map[string]string{"players": "guest_id is not supported"})
+	}
+	guest, err := s.Guests.GetGuest(ctx, creatorID, guestID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Guest{}, domain.NewValidationError(map[string]string{"players": "guest_id must be one of your saved guests"})
+	}
+	return guest, err
+}
+
+// checkDeck requires deckID to be one of the player's own decks.
+func (s *MatchService) checkDeck(ctx context.Context, userID, deckID, field string) error {
+	if s.Decks == nil {
+		return domain.NewValidationError(map[string]string{field: "deck_id is not supported"})
+	}
+	_, err := s.Decks.GetDeck(ctx, userID, deckID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewValidationError(map[string]string{field: "deck_id must be one of the player's decks"})
+	}
+	return err
+}
+
+// checkCommanders requires every commander on a seat to be a card in the card database that can
+// be a commander and is legal in the match's format.
+func (s *MatchService) checkCommanders(ctx context.Context, format domain.Format, participants []domain.MatchParticipantInput) error {
+	var ids []string
+	for _, p := range participants {
+		ids = append(ids, p.CommanderIDs...)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if !format.Commander {
+		return domain.NewValidationError(map[string]string{"players": "commander_ids are only allowed in formats with commanders"})
+	}
+	if s.Cards == nil {
+		return domain.NewValidationError(map[string]string{"players": "commander_ids is not supported"})
 	}
-	return s.buildParticipantsFromLegacy(ctx, creatorID, p.PlayerIDs, p.WinnerID)
+
+	cards, err := s.Cards.GetCards(ctx, ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]domain.Card, len(cards))
+	for _, c := range cards {
+		byID[c.ID] = c
+	}
+	for _, id := range ids {
+		card, ok := byID[id]
+		switch {
+		case !ok:
+			return domain.NewValidationError(map[string]string{"players": "commander_ids must be cards from /v1/cards/search"})
+		case !card.CanBeCommander:
+			return domain.NewValidationError(map[string]string{"players": card.Name + " cannot be a commander"})
+		case !card.LegalIn(domain.GameFormat(legalityKey(format))):
+			return domain.NewValidationError(map[string]string{"players": card.Name + " is not legal in " + format.Name})
+		}
+	}
+	return nil
+}
+
+// matchOutcome checks an outcome sent by a client. Empty means current.
+func matchOutcome(raw, current domain.MatchOutcome) (domain.MatchOutcome, error) {
+	outcome := domain.MatchOutcome(strings.ToLower(strings.TrimSpace(string(raw))))
+	if outcome == "" {
+		outcome = current
+	}
+	switch outcome {
+	case "":
+		return domain.MatchOutcomeWin, nil
+	case domain.MatchOutcomeWin, domain.MatchOutcomeDraw, domain.MatchOutcomeAbandoned:
+		return outcome, nil
+	}
+	return "", domain.NewValidationError(map[string]string{"outcome": "must be win, draw or abandoned"})
 }
 
-func (s *MatchService) buildParticipantsFromPayload(ctx context.Context, creatorID string, players []domain.MatchParticipantInput) ([]domain.MatchParticipantInput, string, error) {
+// matchEnding is a checked win condition, ready for domain.MatchInput.
+type matchEnding struct {
+	WinCondition    domain.WinCondition
+	WinConditionTag string
+	WinningCard     string
+}
+
+// validateWinCondition checks how a match ended. A custom condition needs a tag, which no other
+// condition takes. Abandoned matches have no win condition.
+func validateWinCondition(outcome domain.MatchOutcome, condition domain.WinCondition, tag, card string) (matchEnding, error) {
+	condition = domain.WinCondition(strings.ToLower(strings.TrimSpace(string(condition))))
+	tag = strings.TrimSpace(tag)
+	card = strings.TrimSpace(card)
+
+	switch condition {
+	case "", domain.WinConditionCombat, domain.WinConditionCommanderDamage, domain.WinConditionCombo,
+		domain.WinConditionMill, domain.WinConditionPoison, domain.WinConditionConcession,
+		domain.WinConditionAltWin, domain.WinConditionCustom:
+	default:
+		return matchEnding{}, domain.NewValidationError(map[string]string{"win_condition": "must be combat, commander_damage, combo, mill, poison, concession, alt_win or custom"})
+	}
+	switch {
+	case condition == domain.WinConditionCustom && tag == "":
+		return matchEnding{}, domain.NewValidationError(map[string]string{"win_condition_tag": "required for custom win conditions"})
+	case condition != domain.WinConditionCustom && tag != "":
+		return matchEnding{}, domain.NewValidationError(map[string]string{"win_condition_tag": "only allowed with custom win conditions"})
+	case len(tag) > maxWinConditionTagLength:
+		return matchEnding{}, domain.NewValidationError(map[string]string{"win_condition_tag": "must be 40 characters or less"})
+	case len(card) > maxWinningCardLength:
+		return matchEnding{}, domain.NewValidationError(map[string]string{"winning_card": "must be 150 characters or less"})
+	case outcome == domain.MatchOutcomeAbandoned && (condition != "" || card != ""):
+		return matchEnding{}, domain.NewValidationError(map[string]string{"win_condition": "abandoned matches have no win condition"})
+	}
+	return matchEnding{WinCondition: condition, WinConditionTag: tag, WinningCard: card}, nil
+}
+
+// updatedMatchEnding merges the win condition fields of a PATCH with the stored ones.
+func updatedMatchEnding(existing domain.Match, outcome domain.MatchOutcome, p UpdateMatchParams) (domain.WinCondition, string, string) {
+	var condition domain.WinCondition
+	var tag, card string
+	if outcome != domain.MatchOutcomeAbandoned {
+		condition, tag, card = existing.WinCondition, existing.WinConditionTag, existing.WinningCard
+	}
+	if p.WinCondition != nil {
+		condition = domain.WinCondition(*p.WinCondition)
+	}
+	switch {
+	case p.WinConditionTag != nil:
+		tag = *p.WinConditionTag
+	case domain.WinCondition(strings.ToLower(strings.TrimSpace(string(condition)))) != domain.WinConditionCustom:
+		tag = ""
+	}
+	if p.WinningCard != nil {
+		card = *p.WinningCard
+	}
+	return condition, tag, card
+}
+
+func (s *MatchService) buildParticipantsFromPayload(ctx context.Context, creatorID, groupID string, outcome domain.MatchOutcome, players []domain.MatchParticipantInput) ([]domain.MatchParticipantInput, string, error) {
 	if len(players) < 2 {
 		return nil, "", domain.NewValidationError(map[string]string{"players": "must have at least 2 players"})
 	}
@@ -151,20 +767,66 @@ func (s *MatchService) buildParticipantsFromPayload(ctx context.Context, creator
 	seatSeen := make(map[int]bool, len(players))
 	userSeen := make(map[string]bool, len(players))
 	guestSeen := make(map[string]bool, len(players))
+	guestIDSeen := make(map[string]bool, len(players))
 	winnerCount := 0
 	winnerID := ""
 	creatorFound := false
+	// teamPlaces holds each team's place; teamed counts the players on a team.
+	teamPlaces := make(map[int]int)
+	teamed := 0
 
 	out := make([]domain.MatchParticipantInput, 0, len(players))
 	for _, p := range players {
 		userID := strings.TrimSpace(p.UserID)
 		guestName := strings.TrimSpace(p.GuestName)
+		guestID := strings.TrimSpace(p.GuestID)
+		deckID := strings.TrimSpace(p.DeckID)
 		displayName := strings.TrimSpace(p.DisplayName)
 
+		if userID != "" && guestID != "" {
+			return nil, "", domain.NewValidationError(map[string]string{"players": "user_id players cannot have a guest_id"})
+		}
+		if guestID != "" {
+			guest, err := s.rosterGuest(ctx, creatorID, guestID)
+			if err != nil {
+				return nil, "", err
+			}
+			if guestName == "" {
+				guestName = guest.DisplayName
+			}
+		}
 		if (userID == "") == (guestName == "") {
 			return nil, "", domain.NewValidationError(map[string]string{"players": "each player must include either user_id or guest_name"})
 		}
-		if p.Place < 1 {
+		if deckID != "" {
+			if userID == "" {
+				return nil, "", domain.NewValidationError(map[string]string{"players": "deck_id is only allowed on user_id players"})
+			}
+			if err := s.checkDeck(ctx, userID, deckID, "players"); err != nil {
+				return nil, "", err
+			}
+		}
+		commanderIDs := make([]string, 0, len(p.CommanderIDs))
+		for _, id := range p.CommanderIDs {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			for _, seen := range commanderIDs {
+				if seen == id {
+					return nil, "", domain.NewValidationError(map[string]string{"players": "commander_ids values must be unique per player"})
+				}
+			}
+			commanderIDs = append(commanderIDs, id)
+		}
+		if len(commanderIDs) > maxSeatCommanders {
+			return nil, "", domain.NewValidationError(map[string]string{"players": "at most 2 commander_ids per player"})
+		}
+		if outcome == domain.MatchOutcomeAbandoned {
+			if p.Place != 0 {
+				return nil, "", domain.NewValidationError(map[string]string{"players": "abandoned matches have no places"})
+			}
+		} else if p.Place < 1 {
 			return nil, "", domain.NewValidationError(map[string]string{"players": "place must be >= 1"})
 		}
 		if p.SeatIndex < 0 {
@@ -188,6 +850,21 @@ func (s *MatchService) buildParticipantsFromPayload(ctx context.Context, creator
 				return nil, "", domain.NewValidationError(map[string]string{"players": "guest_name values must be unique"})
 			}
 			guestSeen[guestName] = true
+			// Seats named after aliases of one saved guest would all link to it.
+			linkedID := guestID
+			if linkedID == "" && s.Guests != nil {
+				id, err := s.Guests.GuestIDForName(ctx, creatorID, guestName)
+				if err != nil {
+					return nil, "", err
+				}
+				linkedID = id
+			}
+			if linkedID != "" {
+				if guestIDSeen[linkedID] {
+					return nil, "", domain.NewValidationError(map[string]string{"players": "each saved guest can only take one seat"})
+				}
+				guestIDSeen[linkedID] = true
+			}
 		}
 
 		if displayName == "" && guestName != "" {
@@ -201,6 +878,17 @@ func (s *MatchService) buildParticipantsFromPayload(ctx context.Context, creator
 			}
 		}
 
+		if p.Team < 0 || p.Team > len(players) {
+			return nil, "", domain.NewValidationError(map[string]string{"players": "team must be between 1 and the number of players"})
+		}
+		if p.Team > 0 {
+			teamed++
+			if place, ok := teamPlaces[p.Team]; ok && place != p.Place {
+				return nil, "", domain.NewValidationError(map[string]string{"players": "players on the same team must share a place"})
+			}
+			teamPlaces[p.Team] = p.Place
+		}
+
 		if p.EliminatedTurn != nil && *p.EliminatedTurn < 0 {
 			return nil, "", domain.NewValidationError(map[string]string{"players": "eliminated_turn_number must be >= 0"})
 		}
@@ -218,8 +906,12 @@ func (s *MatchService) buildParticipantsFromPayload(ctx context.Context, creator
 			SeatIndex:        p.SeatIndex,
 			UserID:           userID,
 			GuestName:        guestName,
+			GuestID:          guestID,
+			DeckID:           deckID,
+			CommanderIDs:     commanderIDs,
 			DisplayName:      displayName,
 			Place:            p.Place,
+			Team:             p.Team,
 			EliminatedTurn:   p.EliminatedTurn,
 			EliminatedDuring: p.EliminatedDuring,
 			TotalTurnTimeMs:  p.TotalTurnTimeMs,
@@ -230,8 +922,17 @@ func (s *MatchService) buildParticipantsFromPayload(ctx context.Context, creator
 	if !creatorFound {
 		return nil, "", domain.NewValidationError(map[string]string{"players": "creator must be included"})
 	}
-	if winnerCount != 1 {
-		return nil, "", domain.NewValidationError(map[string]string{"players": "exactly one player must have place 1"})
+	if teamed > 0 {
+		if err := validateTeamPlaces(outcome, teamed, len(out), teamPlaces); err != nil {
+			return nil, "", err
+		}
+		// Every member of the winning team has place 1 and is a winner.
+		winnerID = ""
+	} else if err := checkFirstPlaces(outcome, winnerCount, "player"); err != nil {
+		return nil, "", err
+	}
+	if outcome != domain.MatchOutcomeWin {
+		winnerID = ""
 	}
 
 	minSeat := out[0].SeatIndex
@@ -248,25 +949,61 @@ func (s *MatchService) buildParticipantsFromPayload(ctx context.Context, creator
 		return nil, "", domain.NewValidationError(map[string]string{"players": "seat_index values must be contiguous from 0"})
 	}
 
-	if s.Friends != nil {
-		for _, player := range out {
-			if player.UserID == "" || player.UserID == creatorID {
-				continue
-			}
-			ok, err := s.Friends.AreFriends(ctx, creatorID, player.UserID)
-			if err != nil {
-				return nil, "", err
-			}
-			if !ok {
-				return nil, "", domain.ErrForbidden
-			}
-		}
+	if err := s.checkPlayersAllowed(ctx, creatorID, groupID, out); err != nil {
+		return nil, "", err
 	}
 
 	return out, winnerID, nil
 }
 
-func (s *MatchService) buildParticipantsFromResults(ctx context.Context, creatorID string, results []domain.MatchResultInput) ([]domain.MatchParticipantInput, string, error) {
+// validateTeamPlaces checks a team match: every player is on one of at least two teams, and the
+// teams with place 1 fit the outcome.
+func validateTeamPlaces(outcome domain.MatchOutcome, teamed, players int, teamPlaces map[int]int) error {
+	if teamed != players {
+		return domain.NewValidationError(map[string]string{"players": "either every player or no player must have a team"})
+	}
+	if len(teamPlaces) < 2 {
+		return domain.NewValidationError(map[string]string{"players": "team matches need at least 2 teams"})
+	}
+	winners := 0
+	for _, place := range teamPlaces {
+		if place == 1 {
+			winners++
+		}
+	}
+	return checkFirstPlaces(outcome, winners, "team")
+}
+
+// checkFirstPlaces checks how many players or teams have place 1: exactly one in a win, at least
+// two in a draw. Abandoned matches have no places.
+func checkFirstPlaces(outcome domain.MatchOutcome, firsts int, side string) error {
+	switch outcome {
+	case domain.MatchOutcomeWin:
+		if firsts != 1 {
+			return domain.NewValidationError(map[string]string{"players": "exactly one " + side + " must have place 1"})
+		}
+	case domain.MatchOutcomeDraw:
+		if firsts < 2 {
+			return domain.NewValidationError(map[string]string{"players": "a draw needs at least 2 " + side + "s with place 1"})
+		}
+	}
+	return nil
+}
+
+// checkTeams requires teams in formats that are always played in teams.
+func checkTeams(format domain.Format, participants []domain.MatchParticipantInput) error {
+	if !format.Teams {
+		return nil
+	}
+	for _, p := range participants {
+		if p.Team == 0 {
+			return domain.NewValidationError(map[string]string{"players": format.Name + " matches need a team for every player"})
+		}
+	}
+	return nil
+}
+
+func (s *MatchService) buildParticipantsFromResults(ctx context.Context, creatorID, groupID string, results []domain.MatchResultInput) ([]domain.MatchParticipantInput, string, error) {
 	if len(results) < 2 {
 		return nil, "", domain.NewValidationError(map[string]string{"results": "must have at least 2 players"})
 	}
@@ -315,25 +1052,14 @@ func (s *MatchService) buildParticipantsFromResults(ctx context.Context, creator
 		return nil, "", domain.NewValidationError(map[string]string{"results": "exactly one player must have rank 1"})
 	}
 
-	if s.Friends != nil {
-		for _, player := range participants {
-			if player.UserID == creatorID {
-				continue
-			}
-			ok, err := s.Friends.AreFriends(ctx, creatorID, player.UserID)
-			if err != nil {
-				return nil, "", err
-			}
-			if !ok {
-				return nil, "", domain.ErrForbidden
-			}
-		}
+	if err := s.checkPlayersAllowed(ctx, creatorID, groupID, participants); err != nil {
+		return nil, "", err
 	}
 
 	return participants, winnerID, nil
 }
 
-func (s *MatchService) buildParticipantsFromLegacy(ctx context.Context, creatorID string, playerIDs []string, winnerRaw string) ([]domain.MatchParticipantInput, string, error) {
+func (s *MatchService) buildParticipantsFromLegacy(ctx context.Context, creatorID, groupID string, playerIDs []string, winnerRaw string) ([]domain.MatchParticipantInput, string, error) {
 	seen := map[string]bool{creatorID: false}
 	players := make([]string, 0, len(playerIDs)+1)
 	for _, id := range playerIDs {
@@ -372,20 +1098,61 @@ func (s *MatchService) buildParticipantsFromLegacy(ctx context.Context, creatorI
 		})
 	}
 
-	if s.Friends != nil {
-		for _, pid := range players {
-			if pid == creatorID {
-				continue
-			}
-			ok, err := s.Friends.AreFriends(ctx, creatorID, pid)
+	if err := s.checkPlayersAllowed(ctx, creatorID, groupID, participants); err != nil {
+		return nil, "", err
+	}
+
+	return participants, winnerID, nil
+}
+
+// checkPlayersAllowed requires every registered player to be a friend of the creator or to share
+// a group with them. Players in a group match must be members of that group.
+func (s *MatchService) checkPlayersAllowed(ctx context.Context, creatorID, groupID string, participants []domain.MatchParticipantInput) error {
+	for _, player := range participants {
+		if player.UserID == "" || player.UserID == creatorID {
+			continue
+		}
+		if groupID != "" && s.Groups != nil {
+			ok, err := s.Groups.IsGroupMember(ctx, groupID, player.UserID)
 			if err != nil {
-				return nil, "", err
+				return err
 			}
 			if !ok {
-				return nil, "", domain.ErrForbidden
+				return domain.ErrForbidden
+			}
+			continue
+		}
+		if s.Friends == nil {
+			continue
+		}
+		ok, err := s.Friends.AreFriends(ctx, creatorID, player.UserID)
+		if err != nil {
+			return err
+		}
+		if !ok && s.Groups != nil {
+			ok, err = s.Groups.ShareGroup(ctx, creatorID, player.UserID)
+			if err != nil {
+				return err
 			}
 		}
+		if !ok {
+			return domain.ErrForbidden
+		}
 	}
+	return nil
+}
 
-	return participants, winnerID, nil
+// requireGroupMember hides groups the user does not belong to.
+func (s *MatchService) requireGroupMember(ctx context.Context, groupID, userID string) error {
+	if s.Groups == nil {
+		return domain.ErrNotFound
+	}
+	ok, err := s.Groups.IsGroupMember(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrNotFound
+	}
+	return nil
 }
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,37 +25,116 @@ type stubMatchesStore struct {
 		format               domain.GameFormat
 		totalDurationSeconds int
 		turnCount            int
+		startingSeatIndex    *int
 		clientRef            string
 		updatedAt            time.Time
+		bestOf               int
+		games                []domain.MatchGame
+		outcome              domain.MatchOutcome
+		winCondition         domain.WinCondition
+		winConditionTag      string
+		winningCard          string
+	}
+
+	updated struct {
+		called       bool
+		matchID      string
+		winnerID     string
+		participants []domain.MatchParticipantInput
+		startingSeat *int
+		updatedAt    time.Time
+		format       domain.GameFormat
+		bestOf       int
+		games        []domain.MatchGame
+		// The win condition fields of the last update.
+		winCondition    domain.WinCondition
+		winConditionTag string
+		winningCard     string
 	}
 
 	returnID    string
 	createdFlag bool
 	err         error
 
+	updateApplied bool
+	updateErr     error
+
+	deleted struct {
+		called    bool
+		matchID   string
+		deletedAt time.Time
+	}
+	deleteErr error
+
+	listFilter  domain.MatchListFilter
+	listMatches []domain.Match
+
 	matchForUser    domain.Match
 	matchForUserErr error
 
 	matchByClientRef    domain.Match
 	matchByClientRefErr error
+
+	confirmation struct {
+		called bool
+		userID string
+		state  domain.MatchConfirmation
+		reason string
+	}
+
+	deck struct {
+		called bool
+		userID string
+		deckID string
+	}
 }
 
-func (s *stubMatchesStore) CreateMatch(ctx context.Context, createdBy string, startedAt, endedAt, playedAt *time.Time, winnerID string, participants []domain.MatchParticipantInput, format domain.GameFormat, totalDurationSeconds, turnCount int, clientRef string, updatedAt time.Time) (string, bool, error) {
+func (s *stubMatchesStore) CreateMatch(ctx context.Context, in domain.MatchInput) (string, bool, error) {
 	s.created.called = true
-	s.created.createdBy = createdBy
-	s.created.startedAt = startedAt
-	s.created.endedAt = endedAt
-	s.created.playedAt = playedAt
-	s.created.winnerID = winnerID
-	s.created.participants = append([]domain.MatchParticipantInput(nil), participants...)
-	s.created.format = format
-	s.created.totalDurationSeconds = totalDurationSeconds
-	s.created.turnCount = turnCount
-	s.created.clientRef = clientRef
-	s.created.updatedAt = updatedAt
+	s.created.createdBy = in.CreatedBy
+	s.created.startedAt = in.StartedAt
+	s.created.endedAt = in.EndedAt
+	s.created.playedAt = in.PlayedAt
+	s.created.winnerID = in.WinnerID
+	s.created.participants = append([]domain.MatchParticipantInput(nil), in.Participants...)
+	s.created.format = in.Format
+	s.created.totalDurationSeconds = in.TotalDurationSeconds
+	s.created.turnCount = in.TurnCount
+	s.created.startingSeatIndex = in.StartingSeatIndex
+	s.created.clientRef = in.ClientRef
+	s.created.updatedAt = in.UpdatedAt
+	s.created.bestOf = in.BestOf
+	s.created.games = in.Games
+	s.created.outcome = in.Outcome
+	s.created.winCondition = in.WinCondition
+	s.created.winConditionTag = in.WinConditionTag
+	s.created.winningCard = in.WinningCard
 	return s.returnID, s.createdFlag, s.err
 }
 
+func (s *stubMatchesStore) UpdateMatch(ctx context.Context, matchID string, in domain.MatchInput) (bool, error) {
+	s.updated.called = true
+	s.updated.matchID = matchID
+	s.updated.winnerID = in.WinnerID
+	s.updated.participants = append([]domain.MatchParticipantInput(nil), in.Participants...)
+	s.updated.startingSeat = in.StartingSeatIndex
+	s.updated.updatedAt = in.UpdatedAt
+	s.updated.format = in.Format
+	s.updated.winCondition = in.WinCondition
+	s.updated.winConditionTag = in.WinConditionTag
+	s.updated.winningCard = in.WinningCard
+	s.updated.bestOf = in.BestOf
+	s.updated.games = in.Games
+	return s.updateApplied, s.updateErr
+}
+
+func (s *stubMatchesStore) DeleteMatch(ctx context.Context, matchID, createdBy string, deletedAt time.Time) error {
+	s.deleted.called = true
+	s.deleted.matchID = matchID
+	s.deleted.deletedAt = deletedAt
+	return s.deleteErr
+}
+
 func (s *stubMatchesStore) GetMatchByClientRef(ctx context.Context, createdBy, clientRef string) (domain.Match, error) {
 	if s.matchByClientRefErr != nil {
 		return domain.Match{}, s.matchByClientRefErr
@@ -64,8 +145,13 @@ func (s *stubMatchesStore) GetMatchByClientRef(ctx context.Context, createdBy, c
 	return s.matchByClientRef, nil
 }
 
-func (s *stubMatchesStore) ListMatchesForUser(ctx context.Context, userID string, limit int) ([]domain.Match, error) {
-	return nil, nil
+func (s *stubMatchesStore) ListMatchesForUser(ctx context.Context, userID string, filter domain.MatchListFilter) ([]domain.Match, error) {
+	s.listFilter = filter
+	out := s.listMatches
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
 }
 
 func (s *stubMatchesStore) GetMatchForUser(ctx context.Context, userID, matchID string) (domain.Match, error) {
@@ -83,6 +169,37 @@ func (s *stubMatchesStore) HeadToHead(ctx context.Context, userID, opponentID st
 	return domain.HeadToHeadStats{}, nil
 }
 
+func (s *stubMatchesStore) SeatStats(ctx context.Context, userID string) (domain.SeatStats, error) {
+	return domain.SeatStats{}, nil
+}
+
+func (s *stubMatchesStore) GameStats(ctx context.Context, userID string) (domain.GameStats, error) {
+	return domain.GameStats{}, nil
+}
+
+func (s *stubMatchesStore) EliminationStats(ctx context.Context, userID string) (domain.EliminationStats, error) {
+	return domain.EliminationStats{}, nil
+}
+
+func (s *stubMatchesStore) TurnTimeStats(ctx context.Context, userID string) (domain.TurnTimeStats, error) {
+	return domain.TurnTimeStats{}, nil
+}
+
+func (s *stubMatchesStore) SetParticipantConfirmation(ctx context.Context, matchID, userID string, state domain.MatchConfirmation, reason string, at time.Time) error {
+	s.confirmation.called = true
+	s.confirmation.userID = userID
+	s.confirmation.state = state
+	s.confirmation.reason = reason
+	return nil
+}
+
+func (s *stubMatchesStore) SetParticipantDeck(ctx context.Context, matchID, userID, deckID string) error {
+	s.deck.called = true
+	s.deck.userID = userID
+	s.deck.deckID = deckID
+	return nil
+}
+
 func TestCreateMatchRejectsSinglePlayer(t *testing.T) {
 	store := &stubMatchesStore{}
 	svc := &MatchService{Matches: store}
@@ -289,6 +406,292 @@ func TestCreateMatchWithGuestParticipant(t *testing.T) {
 	}
 }
 
+func TestCreateMatchResolvesGuestID(t *testing.T) {
+	store := &stubMatchesStore{returnID: "match-11", createdFlag: true, matchForUser: domain.Match{ID: "match-11"}}
+	guests := &stubGuestsStore{guests: map[string]domain.Guest{
+		"g1": {ID: "g1", DisplayName: "Mike", Aliases: []string{"mike"}},
+	}}
+	svc := &MatchService{Matches: store, Guests: guests}
+	params := func(guestID string) CreateMatchParams {
+		return CreateMatchParams{
+			ClientMatchID: "client-11",
+			UpdatedAt:     time.Now(),
+			Players: []domain.MatchParticipantInput{
+				{SeatIndex: 0, UserID: "u1", Place: 1},
+				{SeatIndex: 1, GuestID: guestID, Place: 2},
+			},
+		}
+	}
+
+	_, _, err := svc.CreateMatch(context.Background(), "u1", params("unknown"))
+	expectValidation(t, err)
+
+	if _, _, err := svc.CreateMatch(context.Background(), "u1", params("g1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	guest := store.created.participants[1]
+	if guest.GuestID != "g1" || guest.GuestName != "Mike" || guest.DisplayName != "Mike" {
+		t.Fatalf("unexpected guest participant: %+v", guest)
+	}
+}
+
+func TestCreateMatchRejectsSavedGuestTwice(t *testing.T) {
+	store := &stubMatchesStore{returnID: "match-12", createdFlag: true, matchForUser: domain.Match{ID: "match-12"}}
+	guests := &stubGuestsStore{guests: map[string]domain.Guest{
+		"g1": {ID: "g1", DisplayName: "Mike", Aliases: []string{"mike", "mikey"}},
+	}}
+	svc := &MatchService{Matches: store, Guests: guests}
+	create := func(players ...domain.MatchParticipantInput) error {
+		_, _, err := svc.CreateMatch(context.Background(), "u1", CreateMatchParams{
+			UpdatedAt: time.Now(),
+			Players:   append([]domain.MatchParticipantInput{{SeatIndex: 0, UserID: "u1", Place: 1}}, players...),
+		})
+		return err
+	}
+
+	expectValidation(t, create(
+		domain.MatchParticipantInput{SeatIndex: 1, GuestName: "Mike", Place: 2},
+		domain.MatchParticipantInput{SeatIndex: 2, GuestName: "Mikey", Place: 3},
+	))
+	expectValidation(t, create(
+		domain.MatchParticipantInput{SeatIndex: 1, GuestID: "g1", Place: 2},
+		domain.MatchParticipantInput{SeatIndex: 2, GuestName: "mikey", Place: 3},
+	))
+	// A guest literally named like a guest id is not confused with it.
+	if err := create(
+		domain.MatchParticipantInput{SeatIndex: 1, GuestID: "g1", Place: 2},
+		domain.MatchParticipantInput{SeatIndex: 2, GuestName: "id:g1", Place: 3},
+	); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateMatchStoresStartingSeat(t *testing.T) {
+	store := &stubMatchesStore{
+		returnID:     "match-11",
+		createdFlag:  true,
+		matchForUser: domain.Match{ID: "match-11"},
+	}
+	svc := &MatchService{Matches: store}
+
+	seat := 1
+	_, _, err := svc.CreateMatch(context.Background(), "u1", CreateMatchParams{
+		UpdatedAt:         time.Now(),
+		StartingSeatIndex: &seat,
+		Players: []domain.MatchParticipantInput{
+			{SeatIndex: 0, UserID: "u1", Place: 1},
+			{SeatIndex: 1, GuestName: "Guest", Place: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.created.startingSeatIndex == nil || *store.created.startingSeatIndex != 1 {
+		t.Fatalf("expected starting seat 1, got %v", store.created.startingSeatIndex)
+	}
+}
+
+func TestCreateMatchRejectsUnknownStartingSeat(t *testing.T) {
+	store := &stubMatchesStore{}
+	svc := &MatchService{Matches: store}
+
+	seat := 2
+	_, _, err := svc.CreateMatch(context.Background(), "u1", CreateMatchParams{
+		UpdatedAt:         time.Now(),
+		StartingSeatIndex: &seat,
+		Players: []domain.MatchParticipantInput{
+			{SeatIndex: 0, UserID: "u1", Place: 1},
+			{SeatIndex: 1, GuestName: "Guest", Place: 2},
+		},
+	})
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if store.created.called {
+		t.Fatal("store should not be called on invalid input")
+	}
+}
+
+func TestUpdateMatchRejectsNonCreator(t *testing.T) {
+	store := &stubMatchesStore{
+		matchForUser: domain.Match{ID: "match-1", CreatedBy: "u2"},
+	}
+	svc := &MatchService{Matches: store}
+
+	_, _, err := svc.UpdateMatch(context.Background(), "u1", "match-1", UpdateMatchParams{
+		UpdatedAt: time.Now(),
+		Players: []domain.MatchParticipantInput{
+			{SeatIndex: 0, UserID: "u1", Place: 1},
+			{SeatIndex: 1, GuestName: "Guest", Place: 2},
+		},
+	})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+	if store.updated.called {
+		t.Fatal("store should not be updated")
+	}
+}
+
+func TestUpdateMatchValidatesParticipants(t *testing.T) {
+	store := &stubMatchesStore{
+		matchForUser: domain.Match{ID: "match-1", CreatedBy: "u1"},
+	}
+	svc := &MatchService{Matches: store}
+
+	_, _, err := svc.UpdateMatch(context.Background(), "u1", "match-1", UpdateMatchParams{
+		UpdatedAt: time.Now(),
+		Players: []domain.MatchParticipantInput{
+			{SeatIndex: 0, UserID: "u1", Place: 1},
+			{SeatIndex: 1, GuestName: "Guest", Place: 1},
+		},
+	})
+	expectValidation(t, err)
+	if store.updated.called {
+		t.Fatal("store should not be updated")
+	}
+}
+
+func TestUpdateMatchApplied(t *testing.T) {
+	updatedAt := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	store := &stubMatchesStore{
+		matchForUser:  domain.Match{ID: "match-1", CreatedBy: "u1"},
+		updateApplied: true,
+	}
+	svc := &MatchService{Matches: store}
+
+	_, result, err := svc.UpdateMatch(context.Background(), "u1", "match-1", UpdateMatchParams{
+		UpdatedAt: updatedAt,
+		Players: []domain.MatchParticipantInput{
+			{SeatIndex: 0, UserID: "u1", Place: 2},
+			{SeatIndex: 1, GuestName: "Guest", Place: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != MatchUpdateApplied {
+		t.Fatalf("expected applied result, got %v", result)
+	}
+	if store.updated.winnerID != "" {
+		t.Fatalf("expected guest winner to clear winner_id, got %q", store.updated.winnerID)
+	}
+	if !store.updated.updatedAt.Equal(updatedAt) {
+		t.Fatalf("unexpected updated_at: %v", store.updated.updatedAt)
+	}
+}
+
+func TestUpdateMatchConflictReturnsCurrent(t *testing.T) {
+	current := time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC)
+	store := &stubMatchesStore{
+		matchForUser: domain.Match{ID: "match-1", CreatedBy: "u1", UpdatedAt: current},
+	}
+	svc := &MatchService{Matches: store}
+
+	match, result, err := svc.UpdateMatch(context.Background(), "u1", "match-1", UpdateMatchParams{
+		UpdatedAt: current.Add(-time.Hour),
+		Players: []domain.MatchParticipantInput{
+			{SeatIndex: 0, UserID: "u1", Place: 1},
+			{SeatIndex: 1, GuestName: "Guest", Place: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != MatchUpdateConflict {
+		t.Fatalf("expected conflict result, got %v", result)
+	}
+	if !match.UpdatedAt.Equal(current) {
+		t.Fatalf("expected current match to be returned, got %v", match.UpdatedAt)
+	}
+}
+
+func TestDeleteMatchRejectsNonCreator(t *testing.T) {
+	store := &stubMatchesStore{
+		matchForUser: domain.Match{ID: "match-1", CreatedBy: "u2"},
+	}
+	svc := &MatchService{Matches: store}
+
+	err := svc.DeleteMatch(context.Background(), "u1", "match-1")
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+	if store.deleted.called {
+		t.Fatal("store should not be called")
+	}
+}
+
+func TestDeleteMatchStoresTombstone(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 123456789, time.UTC)
+	store := &stubMatchesStore{
+		matchForUser: domain.Match{ID: "match-1", CreatedBy: "u1"},
+	}
+	svc := &MatchService{Matches: store, Now: func() time.Time { return now }}
+
+	if err := svc.DeleteMatch(context.Background(), "u1", "match-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.deleted.matchID != "match-1" {
+		t.Fatalf("unexpected match id: %q", store.deleted.matchID)
+	}
+	if !store.deleted.deletedAt.Equal(now.Truncate(time.Millisecond)) {
+		t.Fatalf("unexpected deleted_at: %v", store.deleted.deletedAt)
+	}
+}
+
+func TestListMatchesReturnsNextCursor(t *testing.T) {
+	base := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	var matches []domain.Match
+	for i := 0; i < 3; i++ {
+		playedAt := base.Add(-time.Duration(i) * time.Hour)
+		matches = append(matches, domain.Match{ID: fmt.Sprintf("match-%d", i), PlayedAt: &playedAt})
+	}
+	store := &stubMatchesStore{listMatches: matches}
+	svc := &MatchService{Matches: store}
+
+	page, cursor, err := svc.ListMatches(context.Background(), "u1", ListMatchesParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(page))
+	}
+	if store.listFilter.Limit != 3 {
+		t.Fatalf("expected store to fetch limit+1, got %d", store.listFilter.Limit)
+	}
+	if cursor == "" {
+		t.Fatal("expected next cursor")
+	}
+
+	store.listMatches = matches[2:]
+	_, cursor, err = svc.ListMatches(context.Background(), "u1", ListMatchesParams{Limit: 2, Cursor: cursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.listFilter.BeforeID != "match-1" {
+		t.Fatalf("expected cursor to resume after match-1, got %q", store.listFilter.BeforeID)
+	}
+	if store.listFilter.BeforeTime == nil || !store.listFilter.BeforeTime.Equal(*matches[1].PlayedAt) {
+		t.Fatalf("unexpected cursor time: %v", store.listFilter.BeforeTime)
+	}
+	if cursor != "" {
+		t.Fatalf("expected no cursor on last page, got %q", cursor)
+	}
+}
+
+func TestListMatchesRejectsInvalidFilters(t *testing.T) {
+	svc := &MatchService{Matches: &stubMatchesStore{}}
+
+	_, _, err := svc.ListMatches(context.Background(), "u1", ListMatchesParams{Cursor: "not-a-cursor"})
+	expectValidation(t, err)
+
+	_, _, err = svc.ListMatches(context.Background(), "u1", ListMatchesParams{Result: "tied"})
+	expectValidation(t, err)
+
+	_, _, err = svc.ListMatches(context.Background(), "u1", ListMatchesParams{Format: "pauper"})
+	expectValidation(t, err)
+}
+
 func expectValidation(t *testing.T, err error) {
 	t.Helper()
 	if err == nil {
@@ -298,3 +701,562 @@ func expectValidation(t *testing.T, err error) {
 		t.Fatalf("expected validation error, got %v", err)
 	}
 }
+
+type recordingTagNotifier struct {
+	tagged []string
+}
+
+func (n *recordingTagNotifier) NotifyMatchTagged(ctx context.Context, notification MatchTaggedNotification) error {
+	n.tagged = append(n.tagged, notification.ParticipantID)
+	return nil
+}
+
+func TestCreateMatchNotifiesTaggedPlayers(t *testing.T) {
+	store := &stubMatchesStore{returnID: "match-11", createdFlag: true, matchForUser: domain.Match{ID: "match-11"}}
+	notifier := &recordingTagNotifier{}
+	svc := &MatchService{Matches: store, Notifier: notifier}
+
+	if _, _, err := svc.CreateMatch(context.Background(), "u1", CreateMatchParams{
+		ClientMatchID: "client-11",
+		UpdatedAt:     time.Now(),
+		Players: []domain.MatchParticipantInput{
+			{SeatIndex: 0, UserID: "u1", Place: 1},
+			{SeatIndex: 1, UserID: "u2", Place: 2},
+			{SeatIndex: 2, GuestName: "Guest", Place: 3},
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.tagged) != 1 || notifier.tagged[0] != "u2" {
+		t.Fatalf("expected only u2 to be notified, got %v", notifier.tagged)
+	}
+
+	store.createdFlag = false
+	notifier.tagged = nil
+	if _, _, err := svc.CreateMatch(context.Background(), "u1", CreateMatchParams{
+		ClientMatchID: "client-12",
+		UpdatedAt:     time.Now(),
+		Players: []domain.MatchParticipantInput{
+			{SeatIndex: 0, UserID: "u1", Place: 1},
+			{SeatIndex: 1, UserID: "u2", Place: 2},
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.tagged) != 0 {
+		t.Fatalf("expected replays not to notify, got %v", notifier.tagged)
+	}
+}
+
+func TestConfirmAndDisputeMatch(t *testing.T) {
+	store := &stubMatchesStore{
+		matchForUser: domain.Match{
+			ID:        "match-1",
+			CreatedBy: "u1",
+			Players: []domain.MatchPlayer{
+				{User: domain.UserSummary{ID: "u1"}, Confirmation: domain.MatchConfirmationConfirmed},
+				{User: domain.UserSummary{ID: "u2"}, Confirmation: domain.MatchConfirmationPending},
+				{GuestName: "Guest"},
+			},
+		},
+	}
+	svc := &MatchService{Matches: store}
+	ctx := context.Background()
+
+	if _, err := svc.ConfirmMatch(ctx, "u2", "match-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !store.confirmation.called || store.confirmation.userID != "u2" || store.confirmation.state != domain.MatchConfirmationConfirmed {
+		t.Fatalf("unexpected confirmation: %+v", store.confirmation)
+	}
+
+	_, err := svc.DisputeMatch(ctx, "u2", "match-1", "  ")
+	expectValidation(t, err)
+	if _, err := svc.DisputeMatch(ctx, "u2", "match-1", " I won that game "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.confirmation.state != domain.MatchConfirmationDisputed || store.confirmation.reason != "I won that game" {
+		t.Fatalf("unexpected dispute: %+v", store.confirmation)
+	}
+
+	store.confirmation.called = false
+	_, err = svc.DisputeMatch(ctx, "u1", "match-1", "typo")
+	expectValidation(t, err)
+	if _, err := svc.ConfirmMatch(ctx, "u3", "match-1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for non-participant, got %v", err)
+	}
+	if store.confirmation.called {
+		t.Fatal("store should not be called")
+	}
+}
+
+type stubDeckLookup map[string]string
+
+func (d stubDeckLookup) GetDeck(ctx context.Context, ownerID, deckID string) (domain.Deck, error) {
+	if d[deckID] != ownerID {
+		return domain.Deck{}, domain.ErrNotFound
+	}
+	return domain.Deck{ID: deckID}, nil
+}
+
+func TestCreateMatchValidatesDeckOwner(t *testing.T) {
+	store := &stubMatchesStore{returnID: "match-13", createdFlag: true, matchForUser: domain.Match{ID: "match-13"}}
+	svc := &MatchService{Matches: store, Decks: stubDeckLookup{"d1": "u1"}}
+	params := func(players ...domain.MatchParticipantInput) CreateMatchParams {
+		return CreateMatchParams{ClientMatchID: "client-13", UpdatedAt: time.Now(), Players: players}
+	}
+
+	_, _, err := svc.CreateMatch(context.Background(), "u1", params(
+		domain.MatchParticipantInput{SeatIndex: 0, UserID: "u1", Place: 1},
+		domain.MatchParticipantInput{SeatIndex: 1, GuestName: "Guest", DeckID: "d1", Place: 2},
+	))
+	expectValidation(t, err)
+	_, _, err = svc.CreateMatch(context.Background(), "u1", params(
+		domain.MatchParticipantInput{SeatIndex: 0, UserID: "u1", Place: 1},
+		domain.MatchParticipantInput{SeatIndex: 1, UserID: "u2", DeckID: "d1", Place: 2},
+	))
+	expectValidation(t, err)
+
+	if _, _, err := svc.CreateMatch(context.Background(), "u1", params(
+		domain.MatchParticipantInput{SeatIndex: 0, UserID: "u1", DeckID: " d1 ", Place: 1},
+		domain.MatchParticipantInput{SeatIndex: 1, GuestName: "Guest", Place: 2},
+	)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := store.created.participants[0].DeckID; got != "d1" {
+		t.Fatalf("expected deck d1, got %q", got)
+	}
+}
+
+func TestSetDeck(t *testing.T) {
+	store := &stubMatchesStore{
+		matchForUser: domain.Match{
+			ID:        "match-1",
+			CreatedBy: "u1",
+			Players: []domain.MatchPlayer{
+				{User: domain.UserSummary{ID: "u1"}, Confirmation: domain.MatchConfirmationConfirmed},
+				{User: domain.UserSummary{ID: "u2"}, Confirmation: domain.MatchConfirmationPending},
+			},
+		},
+	}
+	svc := &MatchService{Matches: store, Decks: stubDeckLookup{"d1": "u1", "d2": "u2"}}
+	ctx := context.Background()
+
+	_, err := svc.SetDeck(ctx, "u2", "match-1", "d1")
+	expectValidation(t, err)
+	if _, err := svc.SetDeck(ctx, "u3", "match-1", ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for non-participant, got %v", err)
+	}
+	if store.deck.called {
+		t.Fatal("store should not be called")
+	}
+
+	if _, err := svc.SetDeck(ctx, "u2", "match-1", "d2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.deck.userID != "u2" || store.deck.deckID != "d2" {
+		t.Fatalf("unexpected deck update: %+v", store.deck)
+	}
+	if _, err := svc.SetDeck(ctx, "u2", "match-1", ""); err != nil || store.deck.deckID != "" {
+		t.Fatalf("expected deck to be cleared, got %v (%q)", err, store.deck.deckID)
+	}
+}
+
+type stubCardLookup map[string]domain.Card
+
+func (c stubCardLookup) GetCards(ctx context.Context, ids []string) ([]domain.Card, error) {
+	var out []domain.Card
+	for _, id := range ids {
+		if card, ok := c[id]; ok {
+			out = append(out, card)
+		}
+	}
+	return out, nil
+}
+
+func TestCreateMatchValidatesCommanders(t *testing.T) {
+	store := &stubMatchesStore{returnID: "match-14", createdFlag: true, matchForUser: domain.Match{ID: "match-14"}}
+	svc := &MatchService{Matches: store, Cards: stubCardLookup{
+		"atraxa":  {ID: "atraxa", Name: "Atraxa", CanBeCommander: true, Legalities: map[string]string{"commander": "legal"}},
+		"golos":   {ID: "golos", Name: "Golos", CanBeCommander: true, Legalities: map[string]string{"commander": "banned"}},
+		"sol-rng": {ID: "sol-rng", Name: "Sol Ring", Legalities: map[string]string{"commander": "legal"}},
+	}}
+	params := func(format domain.GameFormat, commanders ...string) CreateMatchParams {
+		return CreateMatchParams{ClientMatchID: "client-14", Format: format, UpdatedAt: time.Now(), Players: []domain.MatchParticipantInput{
+			{SeatIndex: 0, UserID: "u1", CommanderIDs: commanders, Place: 1},
+			{SeatIndex: 1, GuestName: "Guest", Place: 2},
+		}}
+	}
+
+	for _, commanders := range [][]string{{"missing"}, {"golos"}, {"sol-rng"}, {"atraxa", "atraxa"}, {"atraxa", "golos", "sol-rng"}} {
+		_, _, err := svc.CreateMatch(context.Background(), "u1", params(domain.FormatCommander, commanders...))
+		expectValidation(t, err)
+	}
+	_, _, err := svc.CreateMatch(context.Background(), "u1", params(domain.FormatModern, "atraxa"))
+	expectValidation(t, err)
+
+	if _, _, err := svc.CreateMatch(context.Background(), "u1", params(domain.FormatCommander, " atraxa ")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := store.created.participants[0].CommanderIDs; len(got) != 1 || got[0] != "atraxa" {
+		t.Fatalf("expected commander atraxa, got %v", got)
+	}
+}
+
+func TestCreateMatchWithTeams(t *testing.T) {
+	store := &stubMatchesStore{returnID: "match-15", createdFlag: true, matchForUser: domain.Match{ID: "match-15"}}
+	formats := &FormatService{Store: &stubFormatsStore{formats: append(append([]domain.Format(nil), builtinFormats...), domain.Format{
+		Slug: "two-headed-giant", Name: "Two-Headed Giant", Multiplayer: true, MinPlayers: 4, MaxPlayers: 4, DefaultPlayers: 4,
+		StartingLife: 30, Teams: true, Legality: "two-headed-giant", Enabled: true, SortOrder: 50,
+	})}}
+	svc := &MatchService{Matches: store, Formats: formats}
+	params := func(format domain.GameFormat, seats ...domain.MatchParticipantInput) CreateMatchParams {
+		for i := range seats {
+			seats[i].SeatIndex = i
+		}
+		seats[0].UserID = "u1"
+		for i := 1; i < len(seats); i++ {
+			seats[i].GuestName = fmt.Sprintf("Guest %d", i)
+		}
+		return CreateMatchParams{ClientMatchID: "client-15", Format: format, UpdatedAt: time.Now(), Players: seats}
+	}
+
+	cases := map[string]CreateMatchParams{
+		"mixed":          params(domain.FormatCommander, domain.MatchParticipantInput{Team: 1, Place: 1}, domain.MatchParticipantInput{Team: 1, Place: 1}, domain.MatchParticipantInput{Place: 2}),
+		"split place":    params(domain.FormatCommander, domain.MatchParticipantInput{Team: 1, Place: 1}, domain.MatchParticipantInput{Team: 1, Place: 2}, domain.MatchParticipantInput{Team: 2, Place: 2}),
+		"one team":       params(domain.FormatCommander, domain.MatchParticipantInput{Team: 1, Place: 1}, domain.MatchParticipantInput{Team: 1, Place: 1}),
+		"two winners":    params(domain.FormatCommander, domain.MatchParticipantInput{Team: 1, Place: 1}, domain.MatchParticipantInput{Team: 2, Place: 1}),
+		"team too high":  params(domain.FormatCommander, domain.MatchParticipantInput{Team: 1, Place: 1}, domain.MatchParticipantInput{Team: 3, Place: 2}),
+		"format needs":   params("two-headed-giant", domain.MatchParticipantInput{Place: 1}, domain.MatchParticipantInput{Place: 2}, domain.MatchParticipantInput{Place: 3}, domain.MatchParticipantInput{Place: 4}),
+		"negative team":  params(domain.FormatCommander, domain.MatchParticipantInput{Team: -1, Place: 1}, domain.MatchParticipantInput{Team: 1, Place: 2}),
+		"no winner team": params(domain.FormatCommander, domain.MatchParticipantInput{Team: 1, Place: 2}, domain.MatchParticipantInput{Team: 2, Place: 2}),
+	}
+	for name, p := range cases {
+		_, _, err := svc.CreateMatch(context.Background(), "u1", p)
+		if err == nil || !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("%s: expected validation error, got %v", name, err)
+		}
+	}
+
+	_, _, err := svc.CreateMatch(context.Background(), "u1", params("two-headed-giant",
+		domain.MatchParticipantInput{Team: 1, Place: 2},
+		domain.MatchParticipantInput{Team: 2, Place: 1},
+		domain.MatchParticipantInput{Team: 1, Place: 2},
+		domain.MatchParticipantInput{Team: 2, Place: 1},
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.created.winnerID != "" {
+		t.Fatalf("expected no single winner for a team match, got %q", store.created.winnerID)
+	}
+	for _, p := range store.created.participants {
+		if p.Team == 0 {
+			t.Fatalf("expected every seat to keep its team, got %+v", p)
+		}
+	}
+}
+
+func TestCreateMatchWithGames(t *testing.T) {
+	store := &stubMatchesStore{returnID: "match-16", createdFlag: true, matchForUser: domain.Match{ID: "match-16"}}
+	svc := &MatchService{Matches: store}
+	params := func(bestOf int, games ...domain.MatchGame) CreateMatchParams {
+		return CreateMatchParams{
+			ClientMatchID: "client-16",
+			Format:        domain.FormatStandard,
+			UpdatedAt:     time.Now(),
+			BestOf:        bestOf,
+			Games:         games,
+			Players: []domain.MatchParticipantInput{
+				{UserID: "u1", SeatIndex: 0},
+				{GuestName: "Guest", SeatIndex: 1},
+			},
+		}
+	}
+	game := func(n, winner int) domain.MatchGame {
+		return domain.MatchGame{Number: n, WinnerSeatIndex: winner, DurationSeconds: 600}
+	}
+
+	cases := map[string]CreateMatchParams{
+		"no games":      params(3),
+		"even best_of":  params(2, game(1, 0), game(2, 0)),
+		"undecided":     params(3, game(1, 0), game(2, 1)),
+		"extra game":    params(3, game(1, 0), game(2, 0), game(3, 1)),
+		"gap":           params(3, game(1, 0), game(3, 0)),
+		"unknown seat":  params(3, game(1, 0), game(2, 4)),
+		"tie":           params(0, game(1, 0), game(2, 1)),
+		"bad mulligans": params(1, domain.MatchGame{Number: 1, WinnerSeatIndex: 0, Mulligans: []int{1}}),
+	}
+	multiplayer := params(3, game(1, 0), game(2, 0))
+	multiplayer.Format = domain.FormatCommander
+	cases["multiplayer"] = multiplayer
+	mismatch := params(3, game(1, 0), game(2, 0))
+	mismatch.Players[0].Place = 2
+	cases["place mismatch"] = mismatch
+	for name, p := range cases {
+		_, _, err := svc.CreateMatch(context.Background(), "u1", p)
+		if err == nil || !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("%s: expected validation error, got %v", name, err)
+		}
+	}
+
+	_, _, err := svc.CreateMatch(context.Background(), "u1", params(3, game(2, 0), game(1, 1), game(3, 1)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.created.bestOf != 3 || len(store.created.games) != 3 || store.created.games[0].Number != 1 {
+		t.Fatalf("expected three games in order, got %d %+v", store.created.bestOf, store.created.games)
+	}
+	if store.created.participants[0].Place != 2 || store.created.participants[1].Place != 1 {
+		t.Fatalf("expected places from the games, got %+v", store.created.participants)
+	}
+	if store.created.totalDurationSeconds != 1800 {
+		t.Fatalf("expected duration from the games, got %d", store.created.totalDurationSeconds)
+	}
+}
+
+func TestUpdateMatchKeepsGames(t *testing.T) {
+	bestOf := 3
+	store := &stubMatchesStore{
+		updateApplied: true,
+		matchForUser: domain.Match{
+			ID:        "match-17",
+			CreatedBy: "u1",
+			Format:    domain.FormatStandard,
+			BestOf:    &bestOf,
+			Games: []domain.MatchGame{
+				{Number: 1, WinnerSeatIndex: 1},
+				{Number: 2, WinnerSeatIndex: 1},
+			},
+		},
+	}
+	svc := &MatchService{Matches: store}
+	players := []domain.MatchParticipantInput{
+		{UserID: "u1", SeatIndex: 0},
+		{GuestName: "Guest", SeatIndex: 1},
+	}
+
+	_, _, err := svc.UpdateMatch(context.Background(), "u1", "match-17", UpdateMatchParams{Format: domain.FormatStandard, UpdatedAt: time.Now(), Players: players})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.updated.bestOf != 3 || len(store.updated.games) != 2 {
+		t.Fatalf("expected the existing games to be kept, got %d %+v", store.updated.bestOf, store.updated.games)
+	}
+	if store.updated.participants[1].Place != 1 {
+		t.Fatalf("expected seat 1 to win, got %+v", store.updated.participants)
+	}
+
+	_, _, err = svc.UpdateMatch(context.Background(), "u1", "match-17", UpdateMatchParams{
+		Format:    domain.FormatStandard,
+		UpdatedAt: time.Now(),
+		Players:   []domain.MatchParticipantInput{{UserID: "u1", SeatIndex: 0, Place: 1}, {GuestName: "Guest", SeatIndex: 1, Place: 2}},
+		Games:     []domain.MatchGame{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.updated.bestOf != 0 || len(store.updated.games) != 0 {
+		t.Fatalf("expected games to be removed, got %d %+v", store.updated.bestOf, store.updated.games)
+	}
+}
+
+func TestUpdateMatchKeepsFormat(t *testing.T) {
+	bestOf := 3
+	store := &stubMatchesStore{
+		updateApplied: true,
+		matchForUser: domain.Match{
+			ID:        "match-20",
+			CreatedBy: "u1",
+			Format:    domain.FormatModern,
+			BestOf:    &bestOf,
+			Games:     []domain.MatchGame{{Number: 1, WinnerSeatIndex: 0}, {Number: 2, WinnerSeatIndex: 0}},
+		},
+	}
+	svc := &MatchService{Matches: store}
+
+	_, _, err := svc.UpdateMatch(context.Background(), "u1", "match-20", UpdateMatchParams{
+		UpdatedAt: time.Now(),
+		Players:   []domain.MatchParticipantInput{{UserID: "u1", SeatIndex: 0}, {GuestName: "Guest", SeatIndex: 1}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.updated.format != domain.FormatModern {
+		t.Fatalf("expected the stored format to be kept, got %q", store.updated.format)
+	}
+	if store.updated.bestOf != 3 || len(store.updated.games) != 2 {
+		t.Fatalf("expected the existing games to be kept, got %d %+v", store.updated.bestOf, store.updated.games)
+	}
+}
+
+func TestCreateMatchOutcomes(t *testing.T) {
+	store := &stubMatchesStore{returnID: "match-18", createdFlag: true, matchForUser: domain.Match{ID: "match-18"}}
+	svc := &MatchService{Matches: store}
+	params := func(outcome domain.MatchOutcome, places ...int) CreateMatchParams {
+		players := make([]domain.MatchParticipantInput, len(places))
+		for i, place := range places {
+			players[i] = domain.MatchParticipantInput{SeatIndex: i, Place: place, GuestName: fmt.Sprintf("Guest %d", i)}
+		}
+		players[0].GuestName = ""
+		players[0].UserID = "u1"
+		return CreateMatchParams{ClientMatchID: "client-18", Format: domain.FormatCommander, UpdatedAt: time.Now(), Outcome: outcome, Players: players}
+	}
+
+	cases := map[string]CreateMatchParams{
+		"unknown":           params("forfeit", 1, 2),
+		"draw one first":    params(domain.MatchOutcomeDraw, 1, 2, 3),
+		"win two firsts":    params(domain.MatchOutcomeWin, 1, 1, 3),
+		"abandoned places":  params(domain.MatchOutcomeAbandoned, 1, 2),
+		"draw from results": {Format: domain.FormatCommander, UpdatedAt: time.Now(), Outcome: domain.MatchOutcomeDraw, Results: []domain.MatchResultInput{{ID: "u1", Rank: 1}, {ID: "u2", Rank: 1}}},
+	}
+	for name, p := range cases {
+		_, _, err := svc.CreateMatch(context.Background(), "u1", p)
+		if err == nil || !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("%s: expected validation error, got %v", name, err)
+		}
+	}
+
+	if _, _, err := svc.CreateMatch(context.Background(), "u1", params(domain.MatchOutcomeDraw, 1, 1, 1, 4)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.created.outcome != domain.MatchOutcomeDraw || store.created.winnerID != "" {
+		t.Fatalf("expected a draw without a winner, got %q %q", store.created.outcome, store.created.winnerID)
+	}
+
+	if _, _, err := svc.CreateMatch(context.Background(), "u1", params(domain.MatchOutcomeAbandoned, 0, 0, 0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.created.outcome != domain.MatchOutcomeAbandoned || store.created.winnerID != "" {
+		t.Fatalf("expected an abandoned match without a winner, got %q %q", store.created.outcome, store.created.winnerID)
+	}
+
+	if _, _, err := svc.CreateMatch(context.Background(), "u1", params("", 1, 2)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.created.outcome != domain.MatchOutcomeWin || store.created.winnerID != "u1" {
+		t.Fatalf("expected a win by default, got %q %q", store.created.outcome, store.created.winnerID)
+	}
+
+	drawn := params(domain.MatchOutcomeDraw, 0, 0)
+	drawn.Format = domain.FormatStandard
+	drawn.BestOf = 3
+	drawn.Games = []domain.MatchGame{{Number: 1, WinnerSeatIndex: 0}, {Number: 2, WinnerSeatIndex: 1}}
+	if _, _, err := svc.CreateMatch(context.Background(), "u1", drawn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.created.participants[0].Place != 1 || store.created.participants[1].Place != 1 {
+		t.Fatalf("expected both players to share first place, got %+v", store.created.participants)
+	}
+	drawn.Games = drawn.Games[:1]
+	if _, _, err := svc.CreateMatch(context.Background(), "u1", drawn); err == nil || !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected validation error for an uneven draw, got %v", err)
+	}
+}
+
+func TestCreateMatchWinConditions(t *testing.T) {
+	store := &stubMatchesStore{returnID: "match-19", createdFlag: true, matchForUser: domain.Match{ID: "match-19"}}
+	svc := &MatchService{Matches: store}
+	params := func(outcome domain.MatchOutcome, condition domain.WinCondition, tag string, places ...int) CreateMatchParams {
+		players := make([]domain.MatchParticipantInput, len(places))
+		for i, place := range places {
+			players[i] = domain.MatchParticipantInput{SeatIndex: i, Place: place, GuestName: fmt.Sprintf("Guest %d", i)}
+		}
+		players[0].GuestName = ""
+		players[0].UserID = "u1"
+		return CreateMatchParams{
+			ClientMatchID:   "client-19",
+			Format:          domain.FormatCommander,
+			UpdatedAt:       time.Now(),
+			Outcome:         outcome,
+			WinCondition:    condition,
+			WinConditionTag: tag,
+			Players:         players,
+		}
+	}
+
+	longCard := params(domain.MatchOutcomeWin, domain.WinConditionCombo, "", 1, 2)
+	longCard.WinningCard = strings.Repeat("x", 151)
+	abandonedCard := params(domain.MatchOutcomeAbandoned, "", "", 0, 0)
+	abandonedCard.WinningCard = "Thassa's Oracle"
+	cases := map[string]CreateMatchParams{
+		"unknown":            params(domain.MatchOutcomeWin, "timeout", "", 1, 2),
+		"custom without tag": params(domain.MatchOutcomeWin, domain.WinConditionCustom, "  ", 1, 2),
+		"tag without custom": params(domain.MatchOutcomeWin, domain.WinConditionCombat, "Voltron", 1, 2),
+		"long tag":           params(domain.MatchOutcomeWin, domain.WinConditionCustom, strings.Repeat("x", 41), 1, 2),
+		"long card":          longCard,
+		"abandoned":          params(domain.MatchOutcomeAbandoned, domain.WinConditionConcession, "", 0, 0),
+		"abandoned card":     abandonedCard,
+	}
+	for name, p := range cases {
+		_, _, err := svc.CreateMatch(context.Background(), "u1", p)
+		if err == nil || !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("%s: expected validation error, got %v", name, err)
+		}
+	}
+
+	custom := params(domain.MatchOutcomeWin, " Custom ", " Voltron ", 1, 2)
+	custom.WinningCard = " Sword of Feast and Famine "
+	if _, _, err := svc.CreateMatch(context.Background(), "u1", custom); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.created.winCondition != domain.WinConditionCustom || store.created.winConditionTag != "Voltron" || store.created.winningCard != "Sword of Feast and Famine" {
+		t.Fatalf("expected a trimmed custom ending, got %q %q %q", store.created.winCondition, store.created.winConditionTag, store.created.winningCard)
+	}
+
+	if _, _, err := svc.CreateMatch(context.Background(), "u1", params(domain.MatchOutcomeDraw, domain.WinConditionCombo, "", 1, 1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.created.winCondition != domain.WinConditionCombo {
+		t.Fatalf("expected a drawn match to keep its win condition, got %q", store.created.winCondition)
+	}
+}
+
+func TestUpdateMatchKeepsWinCondition(t *testing.T) {
+	store := &stubMatchesStore{
+		updateApplied: true,
+		matchForUser: domain.Match{
+			ID:              "match-22",
+			CreatedBy:       "u1",
+			Format:          domain.FormatCommander,
+			Outcome:         domain.MatchOutcomeWin,
+			WinCondition:    domain.WinConditionCustom,
+			WinConditionTag: "Voltron",
+			WinningCard:     "Sword of Feast and Famine",
+		},
+	}
+	svc := &MatchService{Matches: store}
+	players := []domain.MatchParticipantInput{{UserID: "u1", SeatIndex: 0, Place: 1}, {GuestName: "Guest", SeatIndex: 1, Place: 2}}
+	str := func(v string) *string { return &v }
+	update := func(p UpdateMatchParams) {
+		t.Helper()
+		p.UpdatedAt = time.Now()
+		if p.Players == nil {
+			p.Players = players
+		}
+		if _, _, err := svc.UpdateMatch(context.Background(), "u1", "match-22", p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expect := func(condition domain.WinCondition, tag, card string) {
+		t.Helper()
+		if store.updated.winCondition != condition || store.updated.winConditionTag != tag || store.updated.winningCard != card {
+			t.Fatalf("expected %q %q %q, got %q %q %q", condition, tag, card, store.updated.winCondition, store.updated.winConditionTag, store.updated.winningCard)
+		}
+	}
+
+	update(UpdateMatchParams{})
+	expect(domain.WinConditionCustom, "Voltron", "Sword of Feast and Famine")
+
+	update(UpdateMatchParams{WinCondition: str("combo")})
+	expect(domain.WinConditionCombo, "", "Sword of Feast and Famine")
+
+	update(UpdateMatchParams{WinCondition: str(""), WinningCard: str("")})
+	expect("", "", "")
+
+	update(UpdateMatchParams{
+		Outcome: domain.MatchOutcomeAbandoned,
+		Players: []domain.MatchParticipantInput{{UserID: "u1", SeatIndex: 0}, {GuestName: "Guest", SeatIndex: 1}},
+	})
+	expect("", "", "")
+}
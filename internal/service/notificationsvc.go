@@ -35,6 +35,17 @@ type FriendRequestNotifier interface {
 	NotifyFriendRequest(ctx context.Context, notification FriendRequestNotification) error
 }
 
+type MatchTaggedNotification struct {
+	MatchID       string
+	CreatorID     string
+	ParticipantID string
+}
+
+// MatchTaggedNotifier tells registered players they were named in a match that awaits their confirmation.
+type MatchTaggedNotifier interface {
+	NotifyMatchTagged(ctx context.Context, notification MatchTaggedNotification) error
+}
+
 type NotificationService struct {
 	Tokens NotificationTokensStore
 	Users  NotificationUsersStore
@@ -79,42 +90,69 @@ func (s *NotificationService) NotifyFriendRequest(ctx context.Context, notificat
 	if s.Tokens == nil || s.Sender == nil || s.Users == nil {
 		return nil
 	}
+	return s.notifyUser(ctx, notification.AddresseeID, notification.RequesterID, func(display, username string) (map[string]string, string, string) {
+		payload := map[string]string{
+			"type":         "friend_request",
+			"display_name": display,
+			"username":     username,
+			"request_id":   notification.RequestID,
+		}
+		body := "You received a friend request."
+		if display != "" {
+			body = display + " sent you a friend request."
+		}
+		return payload, "Friend request", body
+	})
+}
+
+func (s *NotificationService) NotifyMatchTagged(ctx context.Context, notification MatchTaggedNotification) error {
+	if s.Tokens == nil || s.Sender == nil || s.Users == nil {
+		return nil
+	}
+	return s.notifyUser(ctx, notification.ParticipantID, notification.CreatorID, func(display, username string) (map[string]string, string, string) {
+		payload := map[string]string{
+			"type":         "match_tagged",
+			"display_name": display,
+			"username":     username,
+			"match_id":     notification.MatchID,
+		}
+		body := "You were added to a match. Confirm or dispute the result."
+		if display != "" {
+			body = display + " added you to a match. Confirm or dispute the result."
+		}
+		return payload, "Match recorded", body
+	})
+}
+
+// notifyUser pushes a message about actorID to every device registered by userID. Android gets
+// data-only messages; iOS also gets an alert. Tokens the push service rejects are removed.
+func (s *NotificationService) notifyUser(ctx context.Context, userID, actorID string, build func(display, username string) (map[string]string, string, string)) error {
 	logger := s.Logger
 	if logger == nil {
 		logger = slog.Default()
 	}
 
-	tokens, err := s.Tokens.ListTokens(ctx, notification.AddresseeID)
+	tokens, err := s.Tokens.ListTokens(ctx, userID)
 	if err != nil {
-		logger.Error("notifications: list tokens failed", "err", err, "user_id", notification.AddresseeID)
+		logger.Error("notifications: list tokens failed", "err", err, "user_id", userID)
 		return err
 	}
 	if len(tokens) == 0 {
 		return nil
 	}
 
-	requester, err := s.Users.GetUserByID(ctx, notification.RequesterID)
+	actor, err := s.Users.GetUserByID(ctx, actorID)
 	if err != nil {
-		logger.Error("notifications: requester lookup failed", "err", err, "user_id", notification.RequesterID)
+		logger.Error("notifications: actor lookup failed", "err", err, "user_id", actorID)
 		return err
 	}
 
-	display := strings.TrimSpace(requester.DisplayName)
+	display := strings.TrimSpace(actor.DisplayName)
 	if display == "" {
-		display = requester.Username
-	}
-	payload := map[string]string{
-		"type":         "friend_request",
-		"display_name": display,
-		"username":     requester.Username,
-		"request_id":   notification.RequestID,
+		display = actor.Username
 	}
+	payload, title, body := build(display, actor.Username)
 
-	title := "Friend request"
-	body := "You received a friend request."
-	if display != "" {
-		body = display + " sent you a friend request."
-	}
 	dataOnlyMsg := notifications.Message{
 		Data: payload,
 	}
@@ -133,12 +171,12 @@ func (s *NotificationService) NotifyFriendRequest(ctx context.Context, notificat
 		}
 		if err := s.Sender.Send(ctx, token.Token, msg); err != nil {
 			if errors.Is(err, notifications.ErrInvalidToken) {
-				if delErr := s.Tokens.DeleteToken(ctx, notification.AddresseeID, token.Token); delErr != nil {
-					logger.Error("notifications: delete invalid token failed", "err", delErr, "user_id", notification.AddresseeID)
+				if delErr := s.Tokens.DeleteToken(ctx, userID, token.Token); delErr != nil {
+					logger.Error("notifications: delete invalid token failed", "err", delErr, "user_id", userID)
 				}
 				continue
 			}
-			logger.Error("notifications: send failed", "err", err, "user_id", notification.AddresseeID)
+			logger.Error("notifications: send failed", "err", err, "user_id", userID)
 		}
 	}
 
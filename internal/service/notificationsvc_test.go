@@ -132,3 +132,41 @@ func TestNotificationServiceNotifyFriendRequestDeletesInvalidToken(t *testing.T)
 		t.Fatalf("expected invalid token to be deleted")
 	}
 }
+
+func TestNotificationServiceNotifyMatchTaggedPayload(t *testing.T) {
+	tokens := &stubNotificationTokensStore{
+		listFunc: func(_ context.Context, userID string) ([]domain.NotificationToken, error) {
+			if userID != "user-2" {
+				t.Fatalf("unexpected user id: %s", userID)
+			}
+			return []domain.NotificationToken{{Token: "token-1", Platform: "android"}}, nil
+		},
+	}
+	users := &stubNotificationUsersStore{
+		getByIDFunc: func(_ context.Context, id string) (domain.User, error) {
+			return domain.User{ID: id, Username: "alice", DisplayName: "Alice"}, nil
+		},
+	}
+	var sent notifications.Message
+	sender := &stubPushSender{
+		sendFunc: func(_ context.Context, token string, msg notifications.Message) error {
+			sent = msg
+			return nil
+		},
+	}
+
+	svc := &NotificationService{Tokens: tokens, Users: users, Sender: sender}
+	if err := svc.NotifyMatchTagged(context.Background(), MatchTaggedNotification{
+		MatchID:       "match-1",
+		CreatorID:     "user-1",
+		ParticipantID: "user-2",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent.Notification != nil {
+		t.Fatalf("expected a data-only message for android")
+	}
+	if sent.Data["type"] != "match_tagged" || sent.Data["match_id"] != "match-1" || sent.Data["display_name"] != "Alice" {
+		t.Fatalf("unexpected payload: %v", sent.Data)
+	}
+}
@@ -11,6 +11,8 @@ import (
 type ProfileStore interface {
 	UpdateDisplayName(ctx context.Context, userID, displayName string, updatedAt time.Time) (domain.User, bool, error)
 	UpdateAvatar(ctx context.Context, userID, avatarPath string, updatedAt time.Time) (domain.User, bool, error)
+	GetStatsSettings(ctx context.Context, userID string) (domain.StatsSettings, error)
+	UpdateStatsSettings(ctx context.Context, userID string, settings domain.StatsSettings) error
 }
 
 type ProfileService struct {
@@ -66,3 +68,14 @@ func (s *ProfileService) UpdateAvatar(ctx context.Context, userID, avatarPath st
 	}
 	return u, ProfileUpdateConflict, nil
 }
+
+func (s *ProfileService) StatsSettings(ctx context.Context, userID string) (domain.StatsSettings, error) {
+	return s.Store.GetStatsSettings(ctx, userID)
+}
+
+func (s *ProfileService) UpdateStatsSettings(ctx context.Context, userID string, settings domain.StatsSettings) (domain.StatsSettings, error) {
+	if err := s.Store.UpdateStatsSettings(ctx, userID, settings); err != nil {
+		return domain.StatsSettings{}, err
+	}
+	return settings, nil
+}
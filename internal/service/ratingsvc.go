@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type RatingsStore interface {
+	LatestMatchSeq(ctx context.Context) (int64, error)
+	GetRatingRun(ctx context.Context) (domain.RatingRun, error)
+	// LoadRatingRebuilds returns the formats to replay for matches changed after since, or every
+	// format when full is set, and the match seq the result is current to.
+	LoadRatingRebuilds(ctx context.Context, since int64, full bool) (int64, []domain.RatingRebuild, error)
+	// SaveRatingRebuilds stores the replayed formats as run, unless the stored run is no longer base.
+	SaveRatingRebuilds(ctx context.Context, base, run domain.RatingRun, rebuilds []domain.RatingRebuild) error
+	ListRatingsForUsers(ctx context.Context, userIDs []string, format domain.GameFormat) ([]domain.Rating, error)
+	ListRatingHistory(ctx context.Context, userID string, format domain.GameFormat, limit int) ([]domain.RatingHistoryEntry, error)
+}
+
+// RatingService keeps one multiplayer Elo rating per user per format.
+// Ratings are replayed from completed matches, so edits and deletes are reflected. Reads never
+// wait for a rebuild: RunRefresher brings the ratings up to date in the background, replaying
+// only the formats whose matches changed, from the earliest changed match on.
+type RatingService struct {
+	Store   RatingsStore
+	Formats FormatResolver
+	Now     func() time.Time
+	Logger  *slog.Logger
+
+	mu sync.Mutex // held while rebuilding
+}
+
+const (
+	baseRating = 1500.0
+	ratingK    = 32.0
+	// ratingEngineVersion forces a rebuild when the rating rules change.
+	ratingEngineVersion = 2
+
+	defaultRatingHistoryLimit = 50
+	maxRatingHistoryLimit     = 500
+
+	// RatingRefreshInterval is how often the server checks for match changes to rate.
+	RatingRefreshInterval = 5 * time.Second
+)
+
+func (s *RatingService) Ratings(ctx context.Context, userID string) ([]domain.Rating, error) {
+	ratings, err := s.Store.ListRatingsForUsers(ctx, []string{userID}, "")
+	if err != nil {
+		return nil, err
+	}
+	if ratings == nil {
+		ratings = []domain.Rating{}
+	}
+	return ratings, nil
+}
+
+func (s *RatingService) History(ctx context.Context, userID string, rawFormat domain.GameFormat, limit int) ([]domain.RatingHistoryEntry, error) {
+	format, err := ratingFormat(ctx, s.Formats, rawFormat)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultRatingHistoryLimit
+	}
+	if limit > maxRatingHistoryLimit {
+		limit = maxRatingHistoryLimit
+	}
+	history, err := s.Store.ListRatingHistory(ctx, userID, format, limit)
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		history = []domain.RatingHistoryEntry{}
+	}
+	return history, nil
+}
+
+// Leaderboard ranks the user and the given friends by rating in one format.
+// Players without a rated match in the format are left out.
+func (s *RatingService) Leaderboard(ctx context.Context, self domain.UserSummary, rawFormat domain.GameFormat, friends []domain.UserSummary) ([]domain.RatingLeaderboardEntry, error) {
+	format, err := ratingFormat(ctx, s.Formats, rawFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[string]domain.UserSummary, len(friends)+1)
+	ids := make([]string, 0, len(friends)+1)
+	users[self.ID] = self
+	ids = append(ids, self.ID)
+	for _, f := range friends {
+		if _, ok := users[f.ID]; ok {
+			continue
+		}
+		users[f.ID] = f
+		ids = append(ids, f.ID)
+	}
+
+	ratings, err := s.Store.ListRatingsForUsers(ctx, ids, format)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(ratings, func(i, j int) bool { return ratings[i].Rating > ratings[j].Rating })
+
+	out := make([]domain.RatingLeaderboardEntry, 0, len(ratings))
+	for i, r := range ratings {
+		rank := i + 1
+		if i > 0 && ratings[i-1].Rating == r.Rating {
+			rank = out[i-1].Rank
+		}
+		out = append(out, domain.RatingLeaderboardEntry{
+			Rank:         rank,
+			User:         users[r.UserID],
+			Rating:       r.Rating,
+			MatchesRated: r.MatchesRated,
+		})
+	}
+	return out, nil
+}
+
+// RunRefresher refreshes the ratings once, then again on every tick, until ctx is done. Errors
+// are logged and retried on the next tick.
+func (s *RatingService) RunRefresher(ctx context.Context, ticks <-chan time.Time) {
+	for {
+		if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+			logger := s.Logger
+			if logger == nil {
+				logger = slog.Default()
+			}
+			logger.Error("ratings: refresh failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticks:
+		}
+	}
+}
+
+// Refresh replays the formats with matches changed since the last rebuild, from the earliest
+// changed match on, or every format when the rating rules changed. The change check uses the
+// store's commit watermark, so a match whose transaction commits late is still picked up by the
+// next refresh.
+func (s *RatingService) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+	latest, err := s.Store.LatestMatchSeq(ctx)
+	if err != nil {
+		return err
+	}
+	run, err := s.Store.GetRatingRun(ctx)
+	if err != nil {
+		return err
+	}
+	full := run.EngineVersion != ratingEngineVersion
+	if !full && run.SourceSeq >= latest {
+		return nil
+	}
+
+	seq, rebuilds, err := s.Store.LoadRatingRebuilds(ctx, run.SourceSeq, full)
+	if err != nil {
+		return err
+	}
+	for i := range rebuilds {
+		rebuilds[i].Ratings, rebuilds[i].History = ComputeRatingsFrom(rebuilds[i].Start, rebuilds[i].Matches)
+	}
+	return s.Store.SaveRatingRebuilds(ctx, run, domain.RatingRun{
+		SourceSeq:     seq,
+		EngineVersion: ratingEngineVersion,
+		ComputedAt:    s.Now().UTC(),
+	}, rebuilds)
+}
+
+// ComputeRatings replays matches in order with a pairwise multiplayer Elo.
+// Each player is scored against every other player in the pod: 1 for a better place, 0.5 for a tie
+// (competition ranking gives tied players the same place), 0 otherwise. The K factor is split across
+// the opponents so a pod of any size moves a rating by at most K. In team matches teammates are
+// not scored against each other. All deltas in a match are applied together. Guests play at the
+// base rating and are not stored.
+func ComputeRatings(matches []domain.RatedMatch) ([]domain.Rating, []domain.RatingHistoryEntry) {
+	return ComputeRatingsFrom(nil, matches)
+}
+
+// ComputeRatingsFrom replays matches on top of the start ratings. The returned ratings include
+// start players who played none of the matches.
+func ComputeRatingsFrom(start []domain.Rating, matches []domain.RatedMatch) ([]domain.Rating, []domain.RatingHistoryEntry) {
+	type key struct {
+		userID string
+		format domain.GameFormat
+	}
+	current := make(map[key]*domain.Rating, len(start))
+	for _, r := range start {
+		r.PeakRating = max(r.PeakRating, baseRating)
+		current[key{r.UserID, r.Format}] = &r
+	}
+	var history []domain.RatingHistoryEntry
+
+	for _, m := range matches {
+		if len(m.Players) < 2 {
+			continue
+		}
+		before := make([]float64, len(m.Players))
+		for i, p := range m.Players {
+			before[i] = baseRating
+			if p.UserID == "" {
+				continue
+			}
+			if r, ok := current[key{p.UserID, m.Format}]; ok {
+				before[i] = r.Rating
+			}
+		}
+
+		for i, p := range m.Players {
+			if p.UserID == "" {
+				continue
+			}
+			delta := 0.0
+			opponents := 0
+			for j, o := range m.Players {
+				if i == j || (p.Team != 0 && p.Team == o.Team) {
+					continue
+				}
+				opponents++
+				expected := 1 / (1 + math.Pow(10, (before[j]-before[i])/400))
+				actual := 0.0
+				switch {
+				case p.Place < o.Place:
+					actual = 1
+				case p.Place == o.Place:
+					actual = 0.5
+				}
+				delta += actual - expected
+			}
+			after := before[i]
+			if opponents > 0 {
+				after += ratingK / float64(opponents) * delta
+			}
+
+			k := key{p.UserID, m.Format}
+			r, ok := current[k]
+			if !ok {
+				r = &domain.Rating{UserID: p.UserID, Format: m.Format, PeakRating: baseRating}
+				current[k] = r
+			}
+			playedAt := m.PlayedAt
+			r.Rating = after
+			r.MatchesRated++
+			r.LastPlayedAt = &playedAt
+			if after > r.PeakRating {
+				r.PeakRating = after
+			}
+			history = append(history, domain.RatingHistoryEntry{
+				UserID:       p.UserID,
+				Format:       m.Format,
+				MatchID:      m.ID,
+				PlayedAt:     m.PlayedAt,
+				Place:        p.Place,
+				PodSize:      len(m.Players),
+				RatingBefore: before[i],
+				RatingAfter:  after,
+			})
+		}
+	}
+
+	ratings := make([]domain.Rating, 0, len(current))
+	for _, r := range current {
+		ratings = append(ratings, *r)
+	}
+	sort.Slice(ratings, func(i, j int) bool {
+		if ratings[i].Format != ratings[j].Format {
+			return ratings[i].Format < ratings[j].Format
+		}
+		return ratings[i].UserID < ratings[j].UserID
+	})
+	return ratings, history
+}
+
+func ratingFormat(ctx context.Context, formats FormatResolver, raw domain.GameFormat) (domain.GameFormat, error) {
+	if strings.TrimSpace(string(raw)) == "" {
+		return "", domain.NewValidationError(map[string]string{"format": "required"})
+	}
+	return filterFormat(ctx, formats, raw)
+}
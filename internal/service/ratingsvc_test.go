@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type stubRatingsStore struct {
+	mu        sync.Mutex
+	latestSeq int64
+	run       domain.RatingRun
+	matches   []domain.RatedMatch
+	start     []domain.Rating
+
+	loadedSince     int64
+	loadedFull      bool
+	replaced        bool
+	replacedCount   int
+	replacedRun     domain.RatingRun
+	replacedRatings []domain.Rating
+	replacedHistory []domain.RatingHistoryEntry
+	ratings         []domain.Rating
+	ratingsFormat   domain.GameFormat
+}
+
+func (s *stubRatingsStore) LatestMatchSeq(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latestSeq, nil
+}
+
+func (s *stubRatingsStore) GetRatingRun(ctx context.Context) (domain.RatingRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.run, nil
+}
+
+// LoadRatingRebuilds replays every stub match, grouped by format, on top of start.
+func (s *stubRatingsStore) LoadRatingRebuilds(ctx context.Context, since int64, full bool) (int64, []domain.RatingRebuild, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedSince, s.loadedFull = since, full
+	var out []domain.RatingRebuild
+	for _, m := range s.matches {
+		if len(out) == 0 || out[len(out)-1].Format != m.Format {
+			out = append(out, domain.RatingRebuild{Format: m.Format, Start: s.start})
+		}
+		out[len(out)-1].Matches = append(out[len(out)-1].Matches, m)
+	}
+	return s.latestSeq, out, nil
+}
+
+func (s *stubRatingsStore) SaveRatingRebuilds(ctx context.Context, base, run domain.RatingRun, rebuilds []domain.RatingRebuild) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replaced = true
+	s.replacedCount++
+	s.replacedRun = run
+	s.replacedRatings, s.replacedHistory = nil, nil
+	for _, rb := range rebuilds {
+		s.replacedRatings = append(s.replacedRatings, rb.Ratings...)
+		s.replacedHistory = append(s.replacedHistory, rb.History...)
+	}
+	s.run = run
+	return nil
+}
+
+func (s *stubRatingsStore) rebuilds() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.replacedCount
+}
+
+func (s *stubRatingsStore) ListRatingsForUsers(ctx context.Context, userIDs []string, format domain.GameFormat) ([]domain.Rating, error) {
+	s.ratingsFormat = format
+	return s.ratings, nil
+}
+
+func (s *stubRatingsStore) ListRatingHistory(ctx context.Context, userID string, format domain.GameFormat, limit int) ([]domain.RatingHistoryEntry, error) {
+	return nil, nil
+}
+
+func ratingsByUser(ratings []domain.Rating) map[string]float64 {
+	out := make(map[string]float64, len(ratings))
+	for _, r := range ratings {
+		out[r.UserID] = r.Rating
+	}
+	return out
+}
+
+func TestComputeRatingsMultiplayerIsZeroSum(t *testing.T) {
+	played := time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+	ratings, history := ComputeRatings([]domain.RatedMatch{{
+		ID:       "m1",
+		Format:   domain.FormatCommander,
+		PlayedAt: played,
+		Players: []domain.RatedPlayer{
+			{UserID: "a", Place: 1},
+			{UserID: "b", Place: 2},
+			{UserID: "c", Place: 3},
+			{UserID: "d", Place: 4},
+		},
+	}})
+
+	got := ratingsByUser(ratings)
+	if !(got["a"] > got["b"] && got["b"] > got["c"] && got["c"] > got["d"]) {
+		t.Fatalf("expected ratings ordered by place, got %v", got)
+	}
+	sum := 0.0
+	for _, r := range got {
+		sum += r - baseRating
+	}
+	if math.Abs(sum) > 1e-9 {
+		t.Fatalf("expected zero-sum deltas, got %v", sum)
+	}
+	if math.Abs(got["a"]-(baseRating+ratingK/2)) > 1e-9 {
+		t.Fatalf("expected winner to gain K/2 from an even pod, got %v", got["a"])
+	}
+	if len(history) != 4 {
+		t.Fatalf("expected 4 history entries, got %d", len(history))
+	}
+}
+
+func TestComputeRatingsTiesShareScore(t *testing.T) {
+	ratings, _ := ComputeRatings([]domain.RatedMatch{{
+		ID:       "m1",
+		Format:   domain.FormatCommander,
+		PlayedAt: time.Now(),
+		Players: []domain.RatedPlayer{
+			{UserID: "a", Place: 1},
+			{UserID: "b", Place: 2},
+			{UserID: "c", Place: 3},
+			{UserID: "d", Place: 3},
+			{UserID: "e", Place: 5},
+		},
+	}})
+
+	got := ratingsByUser(ratings)
+	if got["c"] != got["d"] {
+		t.Fatalf("expected tied players to move together, got %v and %v", got["c"], got["d"])
+	}
+	if !(got["b"] > got["c"] && got["c"] > got["e"]) {
+		t.Fatalf("unexpected ordering: %v", got)
+	}
+}
+
+func TestComputeRatingsKeepsFormatsSeparateAndSkipsGuests(t *testing.T) {
+	ratings, history := ComputeRatings([]domain.RatedMatch{
+		{
+			ID:       "m1",
+			Format:   domain.FormatCommander,
+			PlayedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Players:  []domain.RatedPlayer{{UserID: "a", Place: 1}, {Place: 2}},
+		},
+		{
+			ID:       "m2",
+			Format:   domain.FormatModern,
+			PlayedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			Players:  []domain.RatedPlayer{{UserID: "a", Place: 2}, {UserID: "b", Place: 1}},
+		},
+	})
+
+	if len(ratings) != 3 {
+		t.Fatalf("expected 3 ratings (a commander, a modern, b modern), got %d", len(ratings))
+	}
+	for _, r := range ratings {
+		if r.UserID == "" {
+			t.Fatal("guests must not be rated")
+		}
+		if r.UserID == "a" && r.Format == domain.FormatModern && r.Rating != baseRating-ratingK/2 {
+			t.Fatalf("expected modern rating to start from base, got %v", r.Rating)
+		}
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 history entries, got %d", len(history))
+	}
+}
+
+func TestRatingsRecomputesWhenMatchesChanged(t *testing.T) {
+	store := &stubRatingsStore{
+		latestSeq: 10,
+		run:       domain.RatingRun{SourceSeq: 4, EngineVersion: ratingEngineVersion},
+		matches: []domain.RatedMatch{{
+			ID:       "m1",
+			Format:   domain.FormatCommander,
+			PlayedAt: time.Now(),
+			Players:  []domain.RatedPlayer{{UserID: "u1", Place: 1}, {UserID: "u2", Place: 2}},
+		}},
+	}
+	svc := &RatingService{Store: store}
+
+	if err := svc.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !store.replaced {
+		t.Fatal("expected ratings to be rebuilt")
+	}
+	if store.replacedRun.SourceSeq != 10 {
+		t.Fatalf("expected source seq 10, got %d", store.replacedRun.SourceSeq)
+	}
+	if len(store.replacedRatings) != 2 {
+		t.Fatalf("expected 2 ratings, got %d", len(store.replacedRatings))
+	}
+
+	store.replaced = false
+	if err := svc.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.replaced {
+		t.Fatal("expected fresh ratings to be reused")
+	}
+}
+
+func TestRatingsRecomputesOnEngineVersionChange(t *testing.T) {
+	store := &stubRatingsStore{
+		latestSeq: 10,
+		run:       domain.RatingRun{SourceSeq: 10, EngineVersion: ratingEngineVersion - 1},
+	}
+	svc := &RatingService{Store: store}
+
+	if err := svc.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !store.replaced {
+		t.Fatal("expected ratings to be rebuilt for a new engine version")
+	}
+}
+
+func TestRatingRefresherRunsOnStartAndTicks(t *testing.T) {
+	store := &stubRatingsStore{latestSeq: 10}
+	svc := &RatingService{Store: store}
+	ctx, cancel := context.WithCancel(context.Background())
+	ticks := make(chan time.Time)
+	done := make(chan struct{})
+	go func() {
+		svc.RunRefresher(ctx, ticks)
+		close(done)
+	}()
+
+	// Each tick is only received once the refresh before it has finished.
+	ticks <- time.Time{}
+	if n := store.rebuilds(); n != 1 {
+		t.Fatalf("expected a rebuild on start, got %d", n)
+	}
+	if _, err := svc.Ratings(ctx, "u1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ticks <- time.Time{}
+	if n := store.rebuilds(); n != 1 {
+		t.Fatalf("expected reads and unchanged matches not to rebuild, got %d rebuilds", n)
+	}
+
+	store.mu.Lock()
+	store.latestSeq = 20
+	store.mu.Unlock()
+	ticks <- time.Time{}
+	ticks <- time.Time{}
+	if n := store.rebuilds(); n != 2 {
+		t.Fatalf("expected one rebuild after matches changed, got %d", n)
+	}
+	if store.loadedFull || store.loadedSince != 10 {
+		t.Fatalf("expected an incremental rebuild since 10, got full=%v since=%d", store.loadedFull, store.loadedSince)
+	}
+
+	cancel()
+	<-done
+}
+
+func TestRatingsReplayOnTopOfStart(t *testing.T) {
+	played := time.Date(2025, 3, 1, 20, 0, 0, 0, time.UTC)
+	store := &stubRatingsStore{
+		latestSeq: 12,
+		run:       domain.RatingRun{SourceSeq: 10, EngineVersion: ratingEngineVersion},
+		start: []domain.Rating{
+			{UserID: "u1", Format: domain.FormatCommander, Rating: 1600, PeakRating: 1620, MatchesRated: 5},
+			{UserID: "u3", Format: domain.FormatCommander, Rating: 1450, PeakRating: 1490, MatchesRated: 2},
+		},
+		matches: []domain.RatedMatch{{
+			ID:       "m9",
+			Format:   domain.FormatCommander,
+			PlayedAt: played,
+			Players:  []domain.RatedPlayer{{UserID: "u1", Place: 1}, {UserID: "u2", Place: 2}},
+		}},
+	}
+	svc := &RatingService{Store: store}
+
+	if err := svc.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.loadedFull || store.loadedSince != 10 {
+		t.Fatalf("expected an incremental rebuild since 10, got full=%v since=%d", store.loadedFull, store.loadedSince)
+	}
+	got := make(map[string]domain.Rating)
+	for _, r := range store.replacedRatings {
+		got[r.UserID] = r
+	}
+	if len(got) != 3 || got["u3"].Rating != 1450 || got["u3"].PeakRating != baseRating {
+		t.Fatalf("expected untouched players kept with peaks at least the base rating, got %+v", got)
+	}
+	if got["u1"].MatchesRated != 6 || got["u1"].Rating <= 1600 || got["u1"].PeakRating != 1620 {
+		t.Fatalf("expected u1 to continue from the start rating, got %+v", got["u1"])
+	}
+	if len(store.replacedHistory) != 2 || store.replacedHistory[0].RatingBefore != 1600 {
+		t.Fatalf("expected history only for the replayed match, got %+v", store.replacedHistory)
+	}
+}
+
+func TestRatingLeaderboardRanksTies(t *testing.T) {
+	store := &stubRatingsStore{
+		run: domain.RatingRun{EngineVersion: ratingEngineVersion},
+		ratings: []domain.Rating{
+			{UserID: "u2", Rating: 1510},
+			{UserID: "u1", Rating: 1530},
+			{UserID: "u3", Rating: 1510},
+		},
+	}
+	svc := &RatingService{Store: store}
+
+	board, err := svc.Leaderboard(context.Background(), domain.UserSummary{ID: "u1", Username: "me"}, "edh", []domain.UserSummary{
+		{ID: "u2", Username: "bob"},
+		{ID: "u3", Username: "cat"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.ratingsFormat != domain.FormatCommander {
+		t.Fatalf("expected normalized format, got %q", store.ratingsFormat)
+	}
+	if len(board) != 3 || board[0].User.Username != "me" || board[0].Rank != 1 {
+		t.Fatalf("unexpected leaderboard: %+v", board)
+	}
+	if board[1].Rank != 2 || board[2].Rank != 2 {
+		t.Fatalf("expected tied ranks, got %d and %d", board[1].Rank, board[2].Rank)
+	}
+}
+
+func TestRatingLeaderboardRequiresFormat(t *testing.T) {
+	svc := &RatingService{Store: &stubRatingsStore{}}
+
+	_, err := svc.Leaderboard(context.Background(), domain.UserSummary{ID: "u1"}, "", nil)
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestComputeRatingsSkipsTeammates(t *testing.T) {
+	ratings, _ := ComputeRatings([]domain.RatedMatch{{
+		ID:       "m1",
+		Format:   "two-headed-giant",
+		PlayedAt: time.Now(),
+		Players: []domain.RatedPlayer{
+			{UserID: "a", Place: 1, Team: 1},
+			{UserID: "b", Place: 1, Team: 1},
+			{UserID: "c", Place: 2, Team: 2},
+			{UserID: "d", Place: 2, Team: 2},
+		},
+	}})
+
+	got := ratingsByUser(ratings)
+	if math.Abs(got["a"]-(baseRating+ratingK/2)) > 1e-9 || got["a"] != got["b"] {
+		t.Fatalf("expected each winner to gain K/2 from the other team only, got %v", got)
+	}
+	if math.Abs(got["c"]-(baseRating-ratingK/2)) > 1e-9 || got["c"] != got["d"] {
+		t.Fatalf("expected each loser to lose K/2, got %v", got)
+	}
+}
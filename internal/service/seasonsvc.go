@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type SeasonsStore interface {
+	CreateSeason(ctx context.Context, ownerID string, in domain.SeasonInput) (domain.Season, error)
+	GetSeason(ctx context.Context, seasonID string) (domain.Season, error)
+	ListSeasons(ctx context.Context, ownerID string, archived bool) ([]domain.Season, error)
+	UpdateSeason(ctx context.Context, seasonID string, in domain.SeasonInput) (domain.Season, error)
+	DeleteSeason(ctx context.Context, seasonID string) error
+	ListSeasonResults(ctx context.Context, season domain.Season) ([]domain.MatchPlacement, error)
+	ArchiveSeason(ctx context.Context, seasonID string, archivedAt time.Time, matchesPlayed int, standings []domain.SeasonStanding) error
+	GetSeasonSnapshot(ctx context.Context, seasonID string) (int, []domain.SeasonStanding, error)
+}
+
+// SeasonGroupRoles resolves a user's role in a playgroup; ErrNotFound means not a member.
+type SeasonGroupRoles interface {
+	MemberRole(ctx context.Context, groupID, userID string) (domain.GroupRole, error)
+}
+
+// SeasonService scores the owner's matches inside a season's date range by place.
+// Group seasons score the group's matches instead, are visible to every member and
+// are managed by the group's owner and admins.
+// Standings are computed live until the season is archived, which freezes them.
+type SeasonService struct {
+	Store   SeasonsStore
+	Groups  SeasonGroupRoles
+	Formats FormatResolver
+	Now     func() time.Time
+}
+
+const (
+	maxSeasonNameLength  = 100
+	maxSeasonPointsPlace = 32
+	maxSeasonPoints      = 1000
+)
+
+type SeasonParams struct {
+	GroupID  string
+	Name     string
+	Format   domain.GameFormat
+	StartsAt time.Time
+	EndsAt   time.Time
+	Points   []int
+}
+
+// SeasonPatch leaves nil fields unchanged.
+type SeasonPatch struct {
+	Name     *string
+	Format   *domain.GameFormat
+	StartsAt *time.Time
+	EndsAt   *time.Time
+	Points   []int
+}
+
+func (s *SeasonService) Create(ctx context.Context, userID string, p SeasonParams) (domain.Season, error) {
+	in, err := validateSeason(ctx, s.Formats, p)
+	if err != nil {
+		return domain.Season{}, err
+	}
+	if in.GroupID != "" {
+		role, err := s.groupRole(ctx, in.GroupID, userID)
+		if err != nil {
+			return domain.Season{}, err
+		}
+		if !role.CanManage() {
+			return domain.Season{}, domain.ErrForbidden
+		}
+	}
+	return s.Store.CreateSeason(ctx, userID, in)
+}
+
+func (s *SeasonService) List(ctx context.Context, userID string, archived bool) ([]domain.Season, error) {
+	seasons, err := s.Store.ListSeasons(ctx, userID, archived)
+	if err != nil {
+		return nil, err
+	}
+	if seasons == nil {
+		seasons = []domain.Season{}
+	}
+	return seasons, nil
+}
+
+func (s *SeasonService) Get(ctx context.Context, userID, seasonID string) (domain.Season, error) {
+	season, _, err := s.access(ctx, userID, seasonID)
+	return season, err
+}
+
+// access loads a season the user may see and reports whether they may change it.
+// Group seasons go by the user's current role in the group, even for the member who created them.
+func (s *SeasonService) access(ctx context.Context, userID, seasonID string) (domain.Season, bool, error) {
+	season, err := s.Store.GetSeason(ctx, seasonID)
+	if err != nil {
+		return domain.Season{}, false, err
+	}
+	if season.GroupID == "" {
+		if season.OwnerID != userID {
+			return domain.Season{}, false, domain.ErrNotFound
+		}
+		return season, true, nil
+	}
+	role, err := s.groupRole(ctx, season.GroupID, userID)
+	if err != nil {
+		return domain.Season{}, false, err
+	}
+	return season, role.CanManage(), nil
+}
+
+func (s *SeasonService) manage(ctx context.Context, userID, seasonID string) (domain.Season, error) {
+	season, canManage, err := s.access(ctx, userID, seasonID)
+	if err != nil {
+		return domain.Season{}, err
+	}
+	if !canManage {
+		return domain.Season{}, domain.ErrForbidden
+	}
+	return season, nil
+}
+
+func (s *SeasonService) groupRole(ctx context.Context, groupID, userID string) (domain.GroupRole, error) {
+	if s.Groups == nil {
+		return "", domain.ErrNotFound
+	}
+	return s.Groups.MemberRole(ctx, groupID, userID)
+}
+
+func (s *SeasonService) Update(ctx context.Context, userID, seasonID string, p SeasonPatch) (domain.Season, error) {
+	season, err := s.manage(ctx, userID, seasonID)
+	if err != nil {
+		return domain.Season{}, err
+	}
+	if season.ArchivedAt != nil {
+		return domain.Season{}, domain.NewValidationError(map[string]string{"season": "archived seasons cannot be changed"})
+	}
+
+	merged := SeasonParams{
+		GroupID:  season.GroupID,
+		Name:     season.Name,
+		Format:   season.Format,
+		StartsAt: season.StartsAt,
+		EndsAt:   season.EndsAt,
+		Points:   season.Points,
+	}
+	if p.Name != nil {
+		merged.Name = *p.Name
+	}
+	if p.Format != nil {
+		merged.Format = *p.Format
+	}
+	if p.StartsAt != nil {
+		merged.StartsAt = *p.StartsAt
+	}
+	if p.EndsAt != nil {
+		merged.EndsAt = *p.EndsAt
+	}
+	if p.Points != nil {
+		merged.Points = p.Points
+	}
+
+	in, err := validateSeason(ctx, s.Formats, merged)
+	if err != nil {
+		return domain.Season{}, err
+	}
+	return s.Store.UpdateSeason(ctx, seasonID, in)
+}
+
+func (s *SeasonService) Delete(ctx context.Context, userID, seasonID string) error {
+	if _, err := s.manage(ctx, userID, seasonID); err != nil {
+		return err
+	}
+	return s.Store.DeleteSeason(ctx, seasonID)
+}
+
+func (s *SeasonService) Standings(ctx context.Context, userID, seasonID string) (domain.SeasonStandings, error) {
+	season, err := s.Get(ctx, userID, seasonID)
+	if err != nil {
+		return domain.SeasonStandings{}, err
+	}
+
+	if season.ArchivedAt != nil {
+		matchesPlayed, standings, err := s.Store.GetSeasonSnapshot(ctx, seasonID)
+		if err != nil {
+			return domain.SeasonStandings{}, err
+		}
+		if standings == nil {
+			standings = []domain.SeasonStanding{}
+		}
+		return domain.SeasonStandings{Season: season, Final: true, MatchesPlayed: matchesPlayed, Standings: standings}, nil
+	}
+
+	results, err := s.Store.ListSeasonResults(ctx, season)
+	if err != nil {
+		return domain.SeasonStandings{}, err
+	}
+	matchesPlayed, standings := ComputeSeasonStandings(results, season.Points)
+	return domain.SeasonStandings{Season: season, MatchesPlayed: matchesPlayed, Standings: standings}, nil
+}
+
+// Archive freezes the current standings. Matches uploaded or edited afterwards no longer change them.
+func (s *SeasonService) Archive(ctx context.Context, userID, seasonID string) (domain.SeasonStandings, error) {
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+
+	season, err := s.manage(ctx, userID, seasonID)
+	if err != nil {
+		return domain.SeasonStandings{}, err
+	}
+	if season.ArchivedAt != nil {
+		return s.Standings(ctx, userID, seasonID)
+	}
+	now := s.Now().UTC().Truncate(time.Millisecond)
+	if now.Before(season.EndsAt) {
+		return domain.SeasonStandings{}, domain.NewValidationError(map[string]string{"ends_at": "season has not ended yet"})
+	}
+
+	results, err := s.Store.ListSeasonResults(ctx, season)
+	if err != nil {
+		return domain.SeasonStandings{}, err
+	}
+	matchesPlayed, standings := ComputeSeasonStandings(results, season.Points)
+	if err := s.Store.ArchiveSeason(ctx, seasonID, now, matchesPlayed, standings); err != nil {
+		return domain.SeasonStandings{}, err
+	}
+	season.ArchivedAt = &now
+	return domain.SeasonStandings{Season: season, Final: true, MatchesPlayed: matchesPlayed, Standings: standings}, nil
+}
+
+// ComputeSeasonStandings awards points[place-1] per match and ranks players by points, then wins,
+// then average place. Players equal on all three share a rank. Guests are grouped by roster entry,
+// or by name when unlinked.
+func ComputeSeasonStandings(results []domain.MatchPlacement, points []int) (int, []domain.SeasonStanding) {
+	type tally struct {
+		standing   domain.SeasonStanding
+		placeTotal int
+	}
+	byPlayer := make(map[string]*tally)
+	var order []string
+	matches := make(map[string]struct{})
+
+	for _, r := range results {
+		matches[r.MatchID] = struct{}{}
+
+		key := placementKey(r)
+		t, ok := byPlayer[key]
+		if !ok {
+			t = &tally{standing: domain.SeasonStanding{User: r.User, GuestID: r.GuestID}}
+			if r.User == nil {
+				t.standing.GuestName = strings.TrimSpace(r.GuestName)
+			}
+			byPlayer[key] = t
+			order = append(order, key)
+		}
+		t.standing.MatchesPlayed++
+		t.placeTotal += r.Place
+		if r.Place == 1 && !r.Draw {
+			t.standing.Wins++
+		}
+		if r.Place >= 1 && r.Place <= len(points) {
+			t.standing.Points += points[r.Place-1]
+		}
+	}
+
+	standings := make([]domain.SeasonStanding, 0, len(order))
+	for _, key := range order {
+		t := byPlayer[key]
+		st := t.standing
+		if st.MatchesPlayed > 0 {
+			st.WinPct = float64(st.Wins) / float64(st.MatchesPlayed)
+			st.AvgPlace = float64(t.placeTotal) / float64(st.MatchesPlayed)
+		}
+		standings = append(standings, st)
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if a.AvgPlace != b.AvgPlace {
+			return a.AvgPlace < b.AvgPlace
+		}
+		return seasonStandingName(a) < seasonStandingName(b)
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 {
+			prev := standings[i-1]
+			if prev.Points == standings[i].Points && prev.Wins == standings[i].Wins && prev.AvgPlace == standings[i].AvgPlace {
+				standings[i].Rank = prev.Rank
+			}
+		}
+	}
+	return len(matches), standings
+}
+
+func seasonStandingName(st domain.SeasonStanding) string {
+	if st.User != nil {
+		return strings.ToLower(st.User.Username)
+	}
+	return strings.ToLower(st.GuestName)
+}
+
+func validateSeason(ctx context.Context, formats FormatResolver, p SeasonParams) (domain.SeasonInput, error) {
+	fields := make(map[string]string)
+
+	name := strings.TrimSpace(p.Name)
+	switch {
+	case name == "":
+		fields["name"] = "required"
+	case utf8.RuneCountInString(name) > maxSeasonNameLength:
+		fields["name"] = fmt.Sprintf("must be at most %d characters", maxSeasonNameLength)
+	}
+
+	var format domain.GameFormat
+	if strings.TrimSpace(string(p.Format)) != "" {
+		f, msg, err := resolveFormat(ctx, formats, p.Format)
+		if err != nil {
+			return domain.SeasonInput{}, err
+		}
+		if msg != "" {
+			fields["format"] = msg
+		}
+		format = f.Slug
+	}
+
+	switch {
+	case p.StartsAt.IsZero():
+		fields["starts_at"] = "required"
+	case p.EndsAt.IsZero():
+		fields["ends_at"] = "required"
+	case !p.EndsAt.After(p.StartsAt):
+		fields["ends_at"] = "must be after starts_at"
+	}
+
+	points := p.Points
+	if points == nil {
+		points = domain.DefaultSeasonPoints
+	}
+	switch {
+	case len(points) == 0:
+		fields["points"] = "must have at least one place"
+	case len(points) > maxSeasonPointsPlace:
+		fields["points"] = fmt.Sprintf("must have at most %d places", maxSeasonPointsPlace)
+	default:
+		for _, v := range points {
+			if v < 0 || v > maxSeasonPoints {
+				fields["points"] = fmt.Sprintf("values must be between 0 and %d", maxSeasonPoints)
+				break
+			}
+		}
+	}
+
+	if len(fields) > 0 {
+		return domain.SeasonInput{}, domain.NewValidationError(fields)
+	}
+	return domain.SeasonInput{
+		GroupID:  strings.TrimSpace(p.GroupID),
+		Name:     name,
+		Format:   format,
+		StartsAt: p.StartsAt.UTC(),
+		EndsAt:   p.EndsAt.UTC(),
+		Points:   append([]int(nil), points...),
+	}, nil
+}
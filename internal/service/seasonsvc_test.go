@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type stubSeasonsStore struct {
+	season  domain.Season
+	results []domain.MatchPlacement
+
+	created       *domain.SeasonInput
+	archivedAt    time.Time
+	archivedCount int
+	archived      []domain.SeasonStanding
+}
+
+func (s *stubSeasonsStore) CreateSeason(ctx context.Context, ownerID string, in domain.SeasonInput) (domain.Season, error) {
+	s.created = &in
+	return domain.Season{ID: "s1", OwnerID: ownerID, Name: in.Name, Format: in.Format, StartsAt: in.StartsAt, EndsAt: in.EndsAt, Points: in.Points}, nil
+}
+
+func (s *stubSeasonsStore) GetSeason(ctx context.Context, seasonID string) (domain.Season, error) {
+	if seasonID != s.season.ID {
+		return domain.Season{}, domain.ErrNotFound
+	}
+	return s.season, nil
+}
+
+func (s *stubSeasonsStore) ListSeasons(ctx context.Context, ownerID string, archived bool) ([]domain.Season, error) {
+	return nil, nil
+}
+
+func (s *stubSeasonsStore) UpdateSeason(ctx context.Context, seasonID string, in domain.SeasonInput) (domain.Season, error) {
+	return domain.Season{}, errors.New("unexpected update")
+}
+
+func (s *stubSeasonsStore) DeleteSeason(ctx context.Context, seasonID string) error {
+	return nil
+}
+
+func (s *stubSeasonsStore) ListSeasonResults(ctx context.Context, season domain.Season) ([]domain.MatchPlacement, error) {
+	return s.results, nil
+}
+
+func (s *stubSeasonsStore) ArchiveSeason(ctx context.Context, seasonID string, archivedAt time.Time, matchesPlayed int, standings []domain.SeasonStanding) error {
+	s.archivedAt = archivedAt
+	s.archivedCount = matchesPlayed
+	s.archived = standings
+	return nil
+}
+
+func (s *stubSeasonsStore) GetSeasonSnapshot(ctx context.Context, seasonID string) (int, []domain.SeasonStanding, error) {
+	return s.archivedCount, s.archived, nil
+}
+
+func TestComputeSeasonStandings(t *testing.T) {
+	alice := &domain.UserSummary{ID: "a", Username: "alice"}
+	bob := &domain.UserSummary{ID: "b", Username: "bob"}
+	results := []domain.MatchPlacement{
+		{MatchID: "m1", User: alice, Place: 1},
+		{MatchID: "m1", User: bob, Place: 2},
+		{MatchID: "m1", GuestName: "Mike", Place: 3},
+		{MatchID: "m2", GuestName: "mike ", Place: 1},
+		{MatchID: "m2", User: alice, Place: 2},
+		{MatchID: "m2", User: bob, Place: 3},
+	}
+
+	matches, standings := ComputeSeasonStandings(results, []int{3, 1})
+	if matches != 2 {
+		t.Fatalf("expected 2 matches, got %d", matches)
+	}
+	if len(standings) != 3 {
+		t.Fatalf("expected 3 players, got %d", len(standings))
+	}
+
+	first := standings[0]
+	if first.User == nil || first.User.ID != "a" || first.Points != 4 || first.Wins != 1 || first.Rank != 1 {
+		t.Fatalf("unexpected leader: %+v", first)
+	}
+	if first.AvgPlace != 1.5 || first.WinPct != 0.5 {
+		t.Fatalf("unexpected leader stats: %+v", first)
+	}
+	// Mike (3 points, 1 win) beats bob (1 point) and guests merge by name.
+	if standings[1].GuestName != "Mike" || standings[1].Points != 3 || standings[1].MatchesPlayed != 2 {
+		t.Fatalf("unexpected second place: %+v", standings[1])
+	}
+	if standings[2].User == nil || standings[2].User.ID != "b" || standings[2].Points != 1 || standings[2].Rank != 3 {
+		t.Fatalf("unexpected third place: %+v", standings[2])
+	}
+}
+
+func TestComputeSeasonStandingsSharesRankOnTies(t *testing.T) {
+	results := []domain.MatchPlacement{
+		{MatchID: "m1", User: &domain.UserSummary{ID: "a", Username: "alice"}, Place: 1},
+		{MatchID: "m1", User: &domain.UserSummary{ID: "b", Username: "bob"}, Place: 2},
+		{MatchID: "m2", User: &domain.UserSummary{ID: "b", Username: "bob"}, Place: 1},
+		{MatchID: "m2", User: &domain.UserSummary{ID: "a", Username: "alice"}, Place: 2},
+	}
+
+	_, standings := ComputeSeasonStandings(results, []int{3, 1})
+	if standings[0].Rank != 1 || standings[1].Rank != 1 {
+		t.Fatalf("expected shared rank, got %d and %d", standings[0].Rank, standings[1].Rank)
+	}
+}
+
+func TestSeasonCreateValidation(t *testing.T) {
+	svc := &SeasonService{Store: &stubSeasonsStore{}}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := svc.Create(context.Background(), "u1", SeasonParams{
+		Name:     " ",
+		Format:   "vintage",
+		StartsAt: start,
+		EndsAt:   start,
+		Points:   []int{3, -1},
+	})
+	var ve *domain.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	for _, field := range []string{"name", "format", "ends_at", "points"} {
+		if _, ok := ve.Fields[field]; !ok {
+			t.Fatalf("expected %s error, got %v", field, ve.Fields)
+		}
+	}
+}
+
+func TestSeasonCreateDefaultsPointsAndNormalizesFormat(t *testing.T) {
+	store := &stubSeasonsStore{}
+	svc := &SeasonService{Store: store}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := svc.Create(context.Background(), "u1", SeasonParams{
+		Name:     "Q1",
+		Format:   "EDH",
+		StartsAt: start,
+		EndsAt:   start.AddDate(0, 3, 0),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.created.Format != domain.FormatCommander {
+		t.Fatalf("expected commander, got %q", store.created.Format)
+	}
+	if len(store.created.Points) != 2 || store.created.Points[0] != 3 || store.created.Points[1] != 1 {
+		t.Fatalf("expected default points, got %v", store.created.Points)
+	}
+}
+
+func TestSeasonHiddenFromOtherUsers(t *testing.T) {
+	store := &stubSeasonsStore{season: domain.Season{ID: "s1", OwnerID: "owner"}}
+	svc := &SeasonService{Store: store}
+
+	if _, err := svc.Standings(context.Background(), "someone-else", "s1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestGroupSeasonAccess(t *testing.T) {
+	groups, _ := newTestGroupService()
+	store := &stubSeasonsStore{season: domain.Season{ID: "s1", OwnerID: "admin", GroupID: "g1"}}
+	svc := &SeasonService{Store: store, Groups: groups}
+	ctx := context.Background()
+
+	if _, err := svc.Get(ctx, "member", "s1"); err != nil {
+		t.Fatalf("expected group member to see the season, got %v", err)
+	}
+	if err := svc.Delete(ctx, "member", "s1"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden for plain member, got %v", err)
+	}
+	if err := svc.Delete(ctx, "owner", "s1"); err != nil {
+		t.Fatalf("expected group owner to manage the season, got %v", err)
+	}
+	if _, err := svc.Get(ctx, "stranger", "s1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for non-member, got %v", err)
+	}
+	if _, err := svc.Create(ctx, "member", SeasonParams{
+		GroupID:  "g1",
+		Name:     "Group league",
+		StartsAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndsAt:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+	}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden creating a group season as member, got %v", err)
+	}
+}
+
+func TestGroupSeasonCreatorNeedsMembership(t *testing.T) {
+	groups, _ := newTestGroupService()
+	ctx := context.Background()
+
+	store := &stubSeasonsStore{season: domain.Season{ID: "s1", OwnerID: "former", GroupID: "g1"}}
+	svc := &SeasonService{Store: store, Groups: groups}
+	if _, err := svc.Get(ctx, "former", "s1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for a creator who left the group, got %v", err)
+	}
+	if err := svc.Delete(ctx, "former", "s1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found deleting as a creator who left the group, got %v", err)
+	}
+
+	store.season.OwnerID = "member"
+	if _, err := svc.Get(ctx, "member", "s1"); err != nil {
+		t.Fatalf("expected the creator to still see the season, got %v", err)
+	}
+	if err := svc.Delete(ctx, "member", "s1"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden for a creator demoted to member, got %v", err)
+	}
+}
+
+func TestSeasonArchiveFreezesStandings(t *testing.T) {
+	now := time.Date(2025, 4, 2, 12, 0, 0, 0, time.UTC)
+	store := &stubSeasonsStore{
+		season: domain.Season{
+			ID:       "s1",
+			OwnerID:  "u1",
+			StartsAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			EndsAt:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			Points:   []int{3, 1},
+		},
+		results: []domain.MatchPlacement{
+			{MatchID: "m1", User: &domain.UserSummary{ID: "u1"}, Place: 1},
+			{MatchID: "m1", GuestName: "Mike", Place: 2},
+		},
+	}
+	svc := &SeasonService{Store: store, Now: func() time.Time { return now }}
+
+	out, err := svc.Archive(context.Background(), "u1", "s1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !out.Final || out.Season.ArchivedAt == nil || out.MatchesPlayed != 1 {
+		t.Fatalf("unexpected archive result: %+v", out)
+	}
+	if !store.archivedAt.Equal(now) || len(store.archived) != 2 {
+		t.Fatalf("expected snapshot to be stored, got %v %d", store.archivedAt, len(store.archived))
+	}
+}
+
+func TestSeasonArchiveRejectsRunningSeason(t *testing.T) {
+	store := &stubSeasonsStore{season: domain.Season{
+		ID:       "s1",
+		OwnerID:  "u1",
+		StartsAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndsAt:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	svc := &SeasonService{Store: store, Now: func() time.Time { return time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC) }}
+
+	if _, err := svc.Archive(context.Background(), "u1", "s1"); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type SyncStore interface {
+	ChangesSince(ctx context.Context, userID string, since int64, matchLimit int) (domain.SyncChanges, error)
+}
+
+type SyncService struct {
+	Store SyncStore
+}
+
+const syncMatchPageSize = 200
+
+// Changes returns everything visible to the user since cursor plus the cursor to send next time.
+// An empty cursor returns a full snapshot.
+func (s *SyncService) Changes(ctx context.Context, userID, cursor string) (domain.SyncChanges, string, error) {
+	var since int64
+	if cursor = strings.TrimSpace(cursor); cursor != "" {
+		seq, err := decodeSyncCursor(cursor)
+		if err != nil {
+			return domain.SyncChanges{}, "", domain.NewValidationError(map[string]string{"cursor": "invalid"})
+		}
+		since = seq
+	}
+
+	changes, err := s.Store.ChangesSince(ctx, userID, since, syncMatchPageSize)
+	if err != nil {
+		return domain.SyncChanges{}, "", err
+	}
+	return changes, encodeSyncCursor(max(changes.MaxSeq, since)), nil
+}
+
+func encodeSyncCursor(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("s" + strconv.FormatInt(seq, 10)))
+}
+
+func decodeSyncCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	digits, ok := strings.CutPrefix(string(raw), "s")
+	if !ok {
+		return 0, strconv.ErrSyntax
+	}
+	seq, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if seq < 0 {
+		return 0, strconv.ErrRange
+	}
+	return seq, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+type stubSyncStore struct {
+	since   int64
+	changes domain.SyncChanges
+}
+
+func (s *stubSyncStore) ChangesSince(ctx context.Context, userID string, since int64, matchLimit int) (domain.SyncChanges, error) {
+	s.since = since
+	return s.changes, nil
+}
+
+func TestSyncChangesCursorRoundTrip(t *testing.T) {
+	store := &stubSyncStore{changes: domain.SyncChanges{MaxSeq: 42}}
+	svc := &SyncService{Store: store}
+
+	_, cursor, err := svc.Changes(context.Background(), "u1", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.since != 0 {
+		t.Fatalf("expected full snapshot, got since=%d", store.since)
+	}
+
+	store.changes = domain.SyncChanges{}
+	_, next, err := svc.Changes(context.Background(), "u1", cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.since != 42 {
+		t.Fatalf("expected since=42, got %d", store.since)
+	}
+	if next != cursor {
+		t.Fatalf("expected cursor to stay put when nothing changed, got %q want %q", next, cursor)
+	}
+}
+
+func TestSyncChangesRejectsInvalidCursor(t *testing.T) {
+	svc := &SyncService{Store: &stubSyncStore{}}
+
+	_, _, err := svc.Changes(context.Background(), "u1", "%%%")
+	expectValidation(t, err)
+}
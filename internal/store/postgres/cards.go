@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CardsStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewCardsStore(pool *pgxpool.Pool) *CardsStore {
+	return &CardsStore{pool: pool}
+}
+
+const cardColumns = `c.id, c.name, c.type_line, c.color_identity, c.legalities, c.can_be_commander`
+
+func scanCard(row pgx.Row) (domain.Card, error) {
+	var (
+		card   domain.Card
+		idUUID pgtype.UUID
+	)
+	if err := row.Scan(&idUUID, &card.Name, &card.TypeLine, &card.ColorIdentity, &card.Legalities, &card.CanBeCommander); err != nil {
+		return domain.Card{}, err
+	}
+	card.ID = uuidOrEmpty(idUUID)
+	if card.Legalities == nil {
+		card.Legalities = map[string]string{}
+	}
+	return card, nil
+}
+
+// UpsertCards writes the cards in one transaction, replacing rows with the same oracle id.
+func (s *CardsStore) UpsertCards(ctx context.Context, cards []domain.Card) error {
+	const q = `
+		INSERT INTO cards (id, name, type_line, color_identity, legalities, can_be_commander)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name,
+		    type_line = EXCLUDED.type_line,
+		    color_identity = EXCLUDED.color_identity,
+		    legalities = EXCLUDED.legalities,
+		    can_be_commander = EXCLUDED.can_be_commander,
+		    imported_at = date_trunc('milliseconds', now())
+	`
+	batch := &pgx.Batch{}
+	for _, c := range cards {
+		batch.Queue(q, c.ID, c.Name, c.TypeLine, c.ColorIdentity, c.Legalities, c.CanBeCommander)
+	}
+
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("upsert cards: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// SearchCards matches the start of the name or of any word in it, case-insensitively.
+func (s *CardsStore) SearchCards(ctx context.Context, query string, format domain.GameFormat, commanderOnly bool, limit int) ([]domain.Card, error) {
+	const q = `
+		SELECT ` + cardColumns + `
+		FROM cards c
+		WHERE (lower(c.name) LIKE $1::text || '%' OR lower(c.name) LIKE '% ' || $1::text || '%')
+		  AND ($2::text = '' OR c.legalities->>$2::text IN ('legal', 'restricted'))
+		  AND (NOT $3 OR c.can_be_commander)
+		ORDER BY lower(c.name) = $5::text DESC, lower(c.name) LIKE $1::text || '%' DESC, lower(c.name), c.id
+		LIMIT $4
+	`
+	query = strings.ToLower(query)
+	rows, err := s.pool.Query(ctx, q, escapeLike(query), string(format), commanderOnly, limit, query)
+	if err != nil {
+		return nil, fmt.Errorf("search cards: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Card
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan card: %w", err)
+		}
+		out = append(out, card)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search cards: %w", err)
+	}
+	return out, nil
+}
+
+// GetCards returns the cards with the given oracle ids. Unknown and malformed ids are left out.
+func (s *CardsStore) GetCards(ctx context.Context, ids []string) ([]domain.Card, error) {
+	uuids := make([]pgtype.UUID, 0, len(ids))
+	for _, id := range ids {
+		var u pgtype.UUID
+		if err := u.Scan(id); err == nil {
+			uuids = append(uuids, u)
+		}
+	}
+
+	const q = `SELECT ` + cardColumns + ` FROM cards c WHERE c.id = ANY($1)`
+	rows, err := s.pool.Query(ctx, q, uuids)
+	if err != nil {
+		return nil, fmt.Errorf("get cards: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Card
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan card: %w", err)
+		}
+		out = append(out, card)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get cards: %w", err)
+	}
+	return out, nil
+}
+
+// FindCardsByName returns cards whose lower-cased name, or front face name for double-faced
+// cards, is one of names.
+func (s *CardsStore) FindCardsByName(ctx context.Context, names []string) ([]domain.Card, error) {
+	const q = `
+		SELECT ` + cardColumns + `
+		FROM cards c
+		WHERE lower(c.name) = ANY($1::text[]) OR lower(split_part(c.name, ' // ', 1)) = ANY($1::text[])
+	`
+	rows, err := s.pool.Query(ctx, q, names)
+	if err != nil {
+		return nil, fmt.Errorf("find cards: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Card
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan card: %w", err)
+		}
+		out = append(out, card)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("find cards: %w", err)
+	}
+	return out, nil
+}
+
+// escapeLike makes s match literally inside a LIKE pattern. Some card names contain underscores.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,314 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DecksStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewDecksStore(pool *pgxpool.Pool) *DecksStore {
+	return &DecksStore{pool: pool}
+}
+
+const deckColumns = `
+	d.id, d.name, d.format, d.commanders, d.color_identity, d.archived, d.created_at, d.updated_at
+`
+
+func scanDeck(row pgx.Row, extra ...any) (domain.Deck, error) {
+	var (
+		deck   domain.Deck
+		idUUID pgtype.UUID
+		format string
+	)
+	dest := []any{&idUUID, &deck.Name, &format, &deck.Commanders, &deck.ColorIdentity, &deck.Archived, &deck.CreatedAt, &deck.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return domain.Deck{}, err
+	}
+	deck.ID = uuidOrEmpty(idUUID)
+	deck.Format = domain.GameFormat(format)
+	if deck.Commanders == nil {
+		deck.Commanders = []string{}
+	}
+	return deck, nil
+}
+
+func (s *DecksStore) ListDecks(ctx context.Context, ownerID string, includeArchived bool) ([]domain.Deck, error) {
+	const q = `
+		SELECT ` + deckColumns + `
+		FROM decks d
+		WHERE d.owner_id = $1 AND ($2 OR NOT d.archived)
+		ORDER BY d.archived, lower(d.name), d.id
+	`
+	rows, err := s.pool.Query(ctx, q, ownerID, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("list decks: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Deck
+	for rows.Next() {
+		deck, err := scanDeck(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan deck: %w", err)
+		}
+		out = append(out, deck)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list decks: %w", err)
+	}
+	return out, nil
+}
+
+func (s *DecksStore) GetDeck(ctx context.Context, ownerID, deckID string) (domain.Deck, error) {
+	const q = `SELECT ` + deckColumns + ` FROM decks d WHERE d.id = $1 AND d.owner_id = $2`
+	deck, err := scanDeck(s.pool.QueryRow(ctx, q, deckID, ownerID))
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.Deck{}, domain.ErrNotFound
+		}
+		return domain.Deck{}, fmt.Errorf("get deck: %w", err)
+	}
+	return deck, nil
+}
+
+const insertDeck = `
+	INSERT INTO decks AS d (owner_id, name, format, commanders, color_identity, archived)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING ` + deckColumns
+
+func (s *DecksStore) CreateDeck(ctx context.Context, ownerID string, in domain.DeckInput) (domain.Deck, error) {
+	deck, err := scanDeck(s.pool.QueryRow(ctx, insertDeck, ownerID, in.Name, string(in.Format), in.Commanders, in.ColorIdentity, in.Archived))
+	if err != nil {
+		return domain.Deck{}, fmt.Errorf("create deck: %w", err)
+	}
+	return deck, nil
+}
+
+func (s *DecksStore) UpdateDeck(ctx context.Context, ownerID, deckID string, in domain.DeckInput) (domain.Deck, error) {
+	const q = `
+		UPDATE decks AS d
+		SET name = $3, format = $4, commanders = $5, color_identity = $6, archived = $7,
+		    updated_at = date_trunc('milliseconds', now())
+		WHERE d.id = $1 AND d.owner_id = $2
+		RETURNING ` + deckColumns
+	deck, err := scanDeck(s.pool.QueryRow(ctx, q, deckID, ownerID, in.Name, string(in.Format), in.Commanders, in.ColorIdentity, in.Archived))
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.Deck{}, domain.ErrNotFound
+		}
+		return domain.Deck{}, fmt.Errorf("update deck: %w", err)
+	}
+	return deck, nil
+}
+
+// ImportDeck creates the deck and its card list in one transaction.
+func (s *DecksStore) ImportDeck(ctx context.Context, ownerID string, in domain.DeckInput, cards []domain.DeckCard) (domain.Deck, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return domain.Deck{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	deck, err := scanDeck(tx.QueryRow(ctx, insertDeck, ownerID, in.Name, string(in.Format), in.Commanders, in.ColorIdentity, in.Archived))
+	if err != nil {
+		return domain.Deck{}, fmt.Errorf("create deck: %w", err)
+	}
+
+	const q = `
+		INSERT INTO deck_cards (deck_id, position, section, quantity, name, set_code, collector_number, card_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	batch := &pgx.Batch{}
+	for i, c := range cards {
+		batch.Queue(q, deck.ID, i, string(c.Section), c.Quantity, c.Name, c.SetCode, c.CollectorNumber, nullIfEmpty(c.CardID))
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return domain.Deck{}, fmt.Errorf("insert deck cards: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Deck{}, fmt.Errorf("commit tx: %w", err)
+	}
+	return deck, nil
+}
+
+func (s *DecksStore) ListDeckCards(ctx context.Context, deckID string) ([]domain.DeckCard, error) {
+	const q = `
+		SELECT section, quantity, name, set_code, collector_number, card_id
+		FROM deck_cards
+		WHERE deck_id = $1
+		ORDER BY position
+	`
+	rows, err := s.pool.Query(ctx, q, deckID)
+	if err != nil {
+		return nil, fmt.Errorf("list deck cards: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.DeckCard
+	for rows.Next() {
+		var (
+			card     domain.DeckCard
+			section  string
+			cardUUID pgtype.UUID
+		)
+		if err := rows.Scan(&section, &card.Quantity, &card.Name, &card.SetCode, &card.CollectorNumber, &cardUUID); err != nil {
+			return nil, fmt.Errorf("scan deck card: %w", err)
+		}
+		card.Section = domain.DeckSection(section)
+		card.CardID = uuidOrEmpty(cardUUID)
+		out = append(out, card)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list deck cards: %w", err)
+	}
+	return out, nil
+}
+
+// DeleteDeck removes the deck. Match seats that used it keep their result and lose the deck link.
+func (s *DecksStore) DeleteDeck(ctx context.Context, ownerID, deckID string) error {
+	ct, err := s.pool.Exec(ctx, `DELETE FROM decks WHERE id = $1 AND owner_id = $2`, deckID, ownerID)
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("delete deck: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// deckSeatsCTE lists seats of live, completed matches. $1 must be the viewing user so their
+// confirmed-only setting applies. Legacy matches have no decks and are left out.
+const deckSeatsCTE = `
+		WITH seats AS (
+			SELECT p.match_id, p.user_id, p.deck_id, p.place, p.team, m.outcome
+			FROM match_participants p
+			JOIN matches m ON m.id = p.match_id
+			WHERE m.deleted_at IS NULL
+			  AND EXISTS (SELECT 1 FROM match_participants w WHERE w.match_id = p.match_id AND w.place = 1)` + confirmedOnlyFilter + `
+		)`
+
+const deckStatsSelect = deckSeatsCTE + `
+		SELECT ` + deckColumns + `,
+		       COUNT(s.match_id)::int,
+		       COALESCE(SUM(CASE WHEN s.place = 1 AND s.outcome <> 'draw' THEN 1 ELSE 0 END), 0)::int,
+		       COALESCE(SUM(CASE WHEN s.place = 1 AND s.outcome = 'draw' THEN 1 ELSE 0 END), 0)::int,
+		       COALESCE(AVG(s.place), 0)::float8
+		FROM decks d
+		LEFT JOIN seats s ON s.deck_id = d.id AND s.user_id = $1
+		WHERE d.owner_id = $1`
+
+func scanDeckStats(row pgx.Row) (domain.DeckStats, error) {
+	var st domain.DeckStats
+	deck, err := scanDeck(row, &st.MatchesPlayed, &st.Wins, &st.Draws, &st.AvgPlace)
+	if err != nil {
+		return domain.DeckStats{}, err
+	}
+	st.Deck = deck
+	st.Losses = st.MatchesPlayed - st.Wins - st.Draws
+	if st.MatchesPlayed > 0 {
+		st.WinPct = float64(st.Wins) / float64(st.MatchesPlayed)
+	}
+	return st, nil
+}
+
+// ListDeckStats returns every deck the user owns, including archived ones, most played first.
+func (s *DecksStore) ListDeckStats(ctx context.Context, userID string) ([]domain.DeckStats, error) {
+	const q = deckStatsSelect + `
+		GROUP BY d.id
+		ORDER BY COUNT(s.match_id) DESC, lower(d.name), d.id
+	`
+	rows, err := s.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list deck stats: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.DeckStats
+	for rows.Next() {
+		st, err := scanDeckStats(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan deck stats: %w", err)
+		}
+		out = append(out, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list deck stats: %w", err)
+	}
+	return out, nil
+}
+
+// DeckStats returns one of the user's decks with its record against each opposing deck.
+func (s *DecksStore) DeckStats(ctx context.Context, userID, deckID string) (domain.DeckStats, error) {
+	const q = deckStatsSelect + ` AND d.id = $2
+		GROUP BY d.id
+	`
+	st, err := scanDeckStats(s.pool.QueryRow(ctx, q, userID, deckID))
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.DeckStats{}, domain.ErrNotFound
+		}
+		return domain.DeckStats{}, fmt.Errorf("deck stats: %w", err)
+	}
+
+	h2h, err := s.deckHeadToHead(ctx, userID, deckID)
+	if err != nil {
+		return domain.DeckStats{}, err
+	}
+	st.HeadToHead = h2h
+	return st, nil
+}
+
+func (s *DecksStore) deckHeadToHead(ctx context.Context, userID, deckID string) ([]domain.DeckHeadToHeadStat, error) {
+	const q = deckSeatsCTE + `
+		SELECT od.id, od.name, u.id, u.username, u.display_name,
+		       COUNT(*)::int,
+		       COALESCE(SUM(CASE WHEN me.place = 1 AND me.outcome <> 'draw' THEN 1 ELSE 0 END), 0)::int,
+		       COALESCE(SUM(CASE WHEN o.place = 1 AND o.outcome <> 'draw' THEN 1 ELSE 0 END), 0)::int,
+		       COALESCE(SUM(CASE WHEN me.outcome = 'draw' AND (me.place = 1 OR o.place = 1) THEN 1 ELSE 0 END), 0)::int
+		FROM seats me
+		JOIN seats o ON o.match_id = me.match_id AND o.user_id <> $1 AND (o.team IS NULL OR o.team <> me.team)
+		JOIN decks od ON od.id = o.deck_id
+		JOIN users u ON u.id = od.owner_id
+		WHERE me.user_id = $1 AND me.deck_id = $2
+		GROUP BY od.id, od.name, u.id, u.username, u.display_name
+		ORDER BY COUNT(*) DESC, lower(od.name), od.id
+	`
+	rows, err := s.pool.Query(ctx, q, userID, deckID)
+	if err != nil {
+		return nil, fmt.Errorf("deck head-to-head: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.DeckHeadToHeadStat
+	for rows.Next() {
+		var (
+			st          domain.DeckHeadToHeadStat
+			deckUUID    pgtype.UUID
+			ownerUUID   pgtype.UUID
+			displayName pgtype.Text
+		)
+		if err := rows.Scan(&deckUUID, &st.DeckName, &ownerUUID, &st.Owner.Username, &displayName, &st.MatchesPlayed, &st.Wins, &st.Losses, &st.Draws); err != nil {
+			return nil, fmt.Errorf("scan deck head-to-head: %w", err)
+		}
+		st.DeckID = uuidOrEmpty(deckUUID)
+		st.Owner.ID = uuidOrEmpty(ownerUUID)
+		st.Owner.DisplayName = textOrEmpty(displayName)
+		out = append(out, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("deck head-to-head: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// EliminationStats only uses match_participants; legacy match_players rows have no seat data.
+func (s *MatchesStore) EliminationStats(ctx context.Context, userID string) (domain.EliminationStats, error) {
+	const qPairs = `
+		WITH kills AS (
+			SELECT m.format,
+			       killer.user_id AS killer_user_id, killer.guest_name AS killer_guest, killer.guest_id AS killer_guest_id,
+			       victim.user_id AS victim_user_id, victim.guest_name AS victim_guest, victim.guest_id AS victim_guest_id
+			FROM match_participants victim
+			JOIN matches m ON m.id = victim.match_id
+			JOIN match_participants killer
+			  ON killer.match_id = victim.match_id
+			 AND killer.seat_index = victim.eliminated_during_seat_index
+			WHERE m.deleted_at IS NULL
+			  AND killer.seat_index <> victim.seat_index
+			  AND (victim.team IS NULL OR victim.team <> killer.team)
+			  AND (killer.user_id = $1 OR victim.user_id = $1)` + confirmedOnlyFilter + `
+		),
+		pairs AS (
+			SELECT format,
+			       killer_user_id IS NOT DISTINCT FROM $1::uuid AS by_me,
+			       CASE WHEN killer_user_id = $1 THEN victim_user_id ELSE killer_user_id END AS other_user_id,
+			       CASE WHEN killer_user_id = $1 THEN victim_guest ELSE killer_guest END AS other_guest,
+			       CASE WHEN killer_user_id = $1 THEN victim_guest_id ELSE killer_guest_id END AS other_guest_id
+			FROM kills
+		)
+		SELECT p.format, p.by_me, p.other_user_id, u.username, u.display_name,
+		       p.other_guest_id, COALESCE(r.display_name, p.other_guest), COUNT(*)::int
+		FROM pairs p
+		LEFT JOIN users u ON u.id = p.other_user_id
+		LEFT JOIN guests r ON r.id = p.other_guest_id
+		GROUP BY p.format, p.by_me, p.other_user_id, u.username, u.display_name, p.other_guest_id, r.display_name, p.other_guest
+	`
+
+	rows, err := s.pool.Query(ctx, qPairs, userID)
+	if err != nil {
+		return domain.EliminationStats{}, fmt.Errorf("elimination stats: %w", err)
+	}
+	defer rows.Close()
+
+	overall := newEliminationAccumulator()
+	byFormat := make(map[string]*eliminationAccumulator)
+	formatAcc := func(format string) *eliminationAccumulator {
+		acc, ok := byFormat[format]
+		if !ok {
+			acc = newEliminationAccumulator()
+			byFormat[format] = acc
+		}
+		return acc
+	}
+	for rows.Next() {
+		var (
+			formatText   pgtype.Text
+			byMe         bool
+			otherID      pgtype.UUID
+			username     pgtype.Text
+			userDisplay  pgtype.Text
+			otherGuestID pgtype.UUID
+			otherGuest   pgtype.Text
+			count        int
+		)
+		if err := rows.Scan(&formatText, &byMe, &otherID, &username, &userDisplay, &otherGuestID, &otherGuest, &count); err != nil {
+			return domain.EliminationStats{}, fmt.Errorf("scan elimination stats: %w", err)
+		}
+		opp := domain.EliminationOpponent{GuestID: uuidOrEmpty(otherGuestID), GuestName: textOrEmpty(otherGuest), Count: count}
+		if id := uuidOrEmpty(otherID); id != "" {
+			opp.Opponent = &domain.UserSummary{ID: id, Username: textOrEmpty(username), DisplayName: textOrEmpty(userDisplay)}
+		}
+		overall.add(byMe, opp)
+		formatAcc(string(normalizeFormat(formatText))).add(byMe, opp)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.EliminationStats{}, fmt.Errorf("elimination stats: %w", err)
+	}
+
+	const qTurns = `
+		SELECT m.format,
+		       COUNT(*)::int AS times_eliminated,
+		       COUNT(p.eliminated_turn_number)::int AS turns_recorded,
+		       COALESCE(SUM(p.eliminated_turn_number), 0)::int AS turn_total
+		FROM match_participants p
+		JOIN matches m ON m.id = p.match_id
+		WHERE m.deleted_at IS NULL
+		  AND p.user_id = $1
+		  AND (p.eliminated_turn_number IS NOT NULL OR p.eliminated_during_seat_index IS NOT NULL)` + confirmedOnlyFilter + `
+		GROUP BY m.format
+	`
+	turnRows, err := s.pool.Query(ctx, qTurns, userID)
+	if err != nil {
+		return domain.EliminationStats{}, fmt.Errorf("elimination turns: %w", err)
+	}
+	defer turnRows.Close()
+	for turnRows.Next() {
+		var (
+			formatText    pgtype.Text
+			times         int
+			turnsRecorded int
+			turnTotal     int
+		)
+		if err := turnRows.Scan(&formatText, &times, &turnsRecorded, &turnTotal); err != nil {
+			return domain.EliminationStats{}, fmt.Errorf("scan elimination turns: %w", err)
+		}
+		overall.addTurns(times, turnsRecorded, turnTotal)
+		formatAcc(string(normalizeFormat(formatText))).addTurns(times, turnsRecorded, turnTotal)
+	}
+	if err := turnRows.Err(); err != nil {
+		return domain.EliminationStats{}, fmt.Errorf("elimination turns: %w", err)
+	}
+
+	out := overall.stats()
+	if len(byFormat) > 0 {
+		out.ByFormat = make(map[string]domain.EliminationStats, len(byFormat))
+		for format, acc := range byFormat {
+			out.ByFormat[format] = acc.stats()
+		}
+	}
+	return out, nil
+}
+
+type eliminationAccumulator struct {
+	eliminated      map[string]*domain.EliminationOpponent
+	eliminatedBy    map[string]*domain.EliminationOpponent
+	timesEliminated int
+	turnsRecorded   int
+	turnTotal       int
+}
+
+func newEliminationAccumulator() *eliminationAccumulator {
+	return &eliminationAccumulator{
+		eliminated:   make(map[string]*domain.EliminationOpponent),
+		eliminatedBy: make(map[string]*domain.EliminationOpponent),
+	}
+}
+
+func (a *eliminationAccumulator) add(byMe bool, opp domain.EliminationOpponent) {
+	target := a.eliminatedBy
+	if byMe {
+		target = a.eliminated
+	}
+	key := "guest:" + strings.ToLower(opp.GuestName)
+	if opp.Opponent != nil {
+		key = "user:" + opp.Opponent.ID
+	} else if opp.GuestID != "" {
+		key = "roster:" + opp.GuestID
+	}
+	if existing, ok := target[key]; ok {
+		existing.Count += opp.Count
+		return
+	}
+	target[key] = &opp
+}
+
+func (a *eliminationAccumulator) addTurns(times, turnsRecorded, turnTotal int) {
+	a.timesEliminated += times
+	a.turnsRecorded += turnsRecorded
+	a.turnTotal += turnTotal
+}
+
+func (a *eliminationAccumulator) stats() domain.EliminationStats {
+	out := domain.EliminationStats{
+		TimesEliminated: a.timesEliminated,
+		Eliminated:      sortedEliminationOpponents(a.eliminated),
+		EliminatedBy:    sortedEliminationOpponents(a.eliminatedBy),
+	}
+	for _, opp := range out.Eliminated {
+		out.Eliminations += opp.Count
+	}
+	if a.turnsRecorded > 0 {
+		out.AvgEliminationTurn = float64(a.turnTotal) / float64(a.turnsRecorded)
+	}
+	if len(out.Eliminated) > 0 {
+		top := out.Eliminated[0]
+		out.MostEliminated = &top
+	}
+	if len(out.EliminatedBy) > 0 {
+		top := out.EliminatedBy[0]
+		out.MostEliminatedBy = &top
+	}
+	return out
+}
+
+func sortedEliminationOpponents(m map[string]*domain.EliminationOpponent) []domain.EliminationOpponent {
+	out := make([]domain.EliminationOpponent, 0, len(m))
+	for _, opp := range m {
+		out = append(out, *opp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return eliminationOpponentName(out[i]) < eliminationOpponentName(out[j])
+	})
+	return out
+}
+
+func eliminationOpponentName(opp domain.EliminationOpponent) string {
+	if opp.Opponent != nil {
+		return strings.ToLower(opp.Opponent.Username)
+	}
+	return strings.ToLower(opp.GuestName)
+}
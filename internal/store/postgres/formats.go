@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FormatsStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewFormatsStore(pool *pgxpool.Pool) *FormatsStore {
+	return &FormatsStore{pool: pool}
+}
+
+func (s *FormatsStore) ListFormats(ctx context.Context) ([]domain.Format, error) {
+	const q = `
+		SELECT f.slug, f.name, f.multiplayer, f.min_players, f.max_players, f.default_players,
+		       f.starting_life, f.commander, f.teams, f.legality, f.enabled, f.sort_order,
+		       COALESCE((SELECT array_agg(a.alias ORDER BY a.alias) FROM format_aliases a WHERE a.format = f.slug), '{}')
+		FROM formats f
+		ORDER BY f.sort_order, f.slug
+	`
+	rows, err := s.pool.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("list formats: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Format
+	for rows.Next() {
+		var f domain.Format
+		if err := rows.Scan(&f.Slug, &f.Name, &f.Multiplayer, &f.MinPlayers, &f.MaxPlayers, &f.DefaultPlayers,
+			&f.StartingLife, &f.Commander, &f.Teams, &f.Legality, &f.Enabled, &f.SortOrder, &f.Aliases); err != nil {
+			return nil, fmt.Errorf("scan format: %w", err)
+		}
+		out = append(out, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list formats: %w", err)
+	}
+	return out, nil
+}
+
+func (s *FormatsStore) CreateFormat(ctx context.Context, f domain.Format) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO formats (slug, name, multiplayer, min_players, max_players, default_players, starting_life, commander, legality, enabled, sort_order, teams)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, string(f.Slug), f.Name, f.Multiplayer, f.MinPlayers, f.MaxPlayers, f.DefaultPlayers, f.StartingLife, f.Commander, f.Legality, f.Enabled, f.SortOrder, f.Teams)
+	if err != nil {
+		return formatWriteError("create format", err)
+	}
+	if err := replaceFormatAliases(ctx, tx, f); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (s *FormatsStore) UpdateFormat(ctx context.Context, f domain.Format) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE formats
+		SET name = $2, multiplayer = $3, min_players = $4, max_players = $5, default_players = $6,
+		    starting_life = $7, commander = $8, legality = $9, enabled = $10, sort_order = $11, teams = $12,
+		    updated_at = date_trunc('milliseconds', now())
+		WHERE slug = $1
+	`, string(f.Slug), f.Name, f.Multiplayer, f.MinPlayers, f.MaxPlayers, f.DefaultPlayers, f.StartingLife, f.Commander, f.Legality, f.Enabled, f.SortOrder, f.Teams)
+	if err != nil {
+		return fmt.Errorf("update format: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM format_aliases WHERE format = $1`, string(f.Slug)); err != nil {
+		return fmt.Errorf("clear format aliases: %w", err)
+	}
+	if err := replaceFormatAliases(ctx, tx, f); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// replaceFormatAliases inserts f's aliases. An alias that is another format's slug is rejected
+// too, so every name resolves to one format.
+func replaceFormatAliases(ctx context.Context, tx pgx.Tx, f domain.Format) error {
+	if len(f.Aliases) == 0 {
+		return nil
+	}
+	var clash bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM formats WHERE slug = ANY($1::text[]))`, f.Aliases).Scan(&clash); err != nil {
+		return fmt.Errorf("check format aliases: %w", err)
+	}
+	if clash {
+		return domain.ErrFormatExists
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO format_aliases (alias, format)
+		SELECT a, $1 FROM unnest($2::text[]) AS a
+	`, string(f.Slug), f.Aliases)
+	if err != nil {
+		return formatWriteError("add format aliases", err)
+	}
+	return nil
+}
+
+func formatWriteError(op string, err error) error {
+	var pgerr *pgconn.PgError
+	if errors.As(err, &pgerr) && pgerr.Code == "23505" {
+		return domain.ErrFormatExists
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
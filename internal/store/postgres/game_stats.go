@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GameStats only counts matches recorded game by game; their places are derived from the games.
+func (s *MatchesStore) GameStats(ctx context.Context, userID string) (domain.GameStats, error) {
+	const q = `
+		WITH mine AS (
+			SELECT p.match_id, p.seat_index, p.place, m.format, m.outcome
+			FROM match_participants p
+			JOIN matches m ON m.id = p.match_id
+			WHERE m.deleted_at IS NULL
+			  AND p.user_id = $1` + confirmedOnlyFilter + `
+		)
+		SELECT
+			me.format,
+			COUNT(DISTINCT me.match_id)::int AS matches_played,
+			COUNT(DISTINCT CASE WHEN me.place = 1 AND me.outcome <> 'draw' THEN me.match_id END)::int AS match_wins,
+			COUNT(*)::int AS games_played,
+			COALESCE(SUM(CASE WHEN g.winner_seat_index = me.seat_index THEN 1 ELSE 0 END), 0)::int AS game_wins,
+			COALESCE(SUM(CASE WHEN g.on_play_seat_index = me.seat_index THEN 1 ELSE 0 END), 0)::int AS play_games,
+			COALESCE(SUM(CASE WHEN g.on_play_seat_index = me.seat_index AND g.winner_seat_index = me.seat_index THEN 1 ELSE 0 END), 0)::int AS play_wins,
+			COALESCE(SUM(CASE WHEN g.on_play_seat_index <> me.seat_index THEN 1 ELSE 0 END), 0)::int AS draw_games,
+			COALESCE(SUM(CASE WHEN g.on_play_seat_index <> me.seat_index AND g.winner_seat_index = me.seat_index THEN 1 ELSE 0 END), 0)::int AS draw_wins
+		FROM mine me
+		JOIN match_games g ON g.match_id = me.match_id
+		GROUP BY me.format
+	`
+	rows, err := s.pool.Query(ctx, q, userID)
+	if err != nil {
+		return domain.GameStats{}, fmt.Errorf("game stats: %w", err)
+	}
+	defer rows.Close()
+
+	var total gameCount
+	byFormat := make(map[string]domain.GameStats)
+	for rows.Next() {
+		var (
+			formatText pgtype.Text
+			c          gameCount
+		)
+		if err := rows.Scan(&formatText, &c.matches, &c.matchWins, &c.games, &c.gameWins, &c.playGames, &c.playWins, &c.drawGames, &c.drawWins); err != nil {
+			return domain.GameStats{}, fmt.Errorf("scan game stats: %w", err)
+		}
+		total.add(c)
+		byFormat[string(normalizeFormat(formatText))] = c.stats()
+	}
+	if err := rows.Err(); err != nil {
+		return domain.GameStats{}, fmt.Errorf("game stats: %w", err)
+	}
+
+	out := total.stats()
+	if len(byFormat) > 0 {
+		out.ByFormat = byFormat
+	}
+	return out, nil
+}
+
+type gameCount struct {
+	matches   int
+	matchWins int
+	games     int
+	gameWins  int
+	playGames int
+	playWins  int
+	drawGames int
+	drawWins  int
+}
+
+func (c *gameCount) add(o gameCount) {
+	c.matches += o.matches
+	c.matchWins += o.matchWins
+	c.games += o.games
+	c.gameWins += o.gameWins
+	c.playGames += o.playGames
+	c.playWins += o.playWins
+	c.drawGames += o.drawGames
+	c.drawWins += o.drawWins
+}
+
+func (c gameCount) stats() domain.GameStats {
+	return domain.GameStats{
+		MatchesPlayed: c.matches,
+		MatchWins:     c.matchWins,
+		MatchWinPct:   winPct(c.matchWins, c.matches),
+		GamesPlayed:   c.games,
+		GameWins:      c.gameWins,
+		GameWinPct:    winPct(c.gameWins, c.games),
+		OnThePlay:     domain.GameRecord{GamesPlayed: c.playGames, Wins: c.playWins, WinPct: winPct(c.playWins, c.playGames)},
+		OnTheDraw:     domain.GameRecord{GamesPlayed: c.drawGames, Wins: c.drawWins, WinPct: winPct(c.drawWins, c.drawGames)},
+	}
+}
+
+func winPct(wins, played int) float64 {
+	if played == 0 {
+		return 0
+	}
+	return float64(wins) / float64(played)
+}
@@ -0,0 +1,540 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type GroupsStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewGroupsStore(pool *pgxpool.Pool) *GroupsStore {
+	return &GroupsStore{pool: pool}
+}
+
+// isMissingRow treats malformed ids like unknown ones so path parameters yield 404s.
+func isMissingRow(err error) bool {
+	var pgerr *pgconn.PgError
+	return errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgerr) && pgerr.Code == "22P02")
+}
+
+func (s *GroupsStore) CreateGroup(ctx context.Context, ownerID, name, description, joinCode string) (string, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return "", fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var idUUID pgtype.UUID
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO groups (name, description, created_by, join_code)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, name, description, ownerID, joinCode).Scan(&idUUID); err != nil {
+		return "", fmt.Errorf("create group: %w", err)
+	}
+	groupID := uuidOrEmpty(idUUID)
+
+	if _, err := tx.Exec(ctx, `INSERT INTO group_members (group_id, user_id, role) VALUES ($1, $2, 'owner')`, groupID, ownerID); err != nil {
+		return "", fmt.Errorf("add group owner: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("commit tx: %w", err)
+	}
+	return groupID, nil
+}
+
+const groupColumns = `
+	g.id, g.name, g.description, g.created_by, me.role,
+	(SELECT COUNT(*)::int FROM group_members c WHERE c.group_id = g.id),
+	g.join_code, g.created_at, g.updated_at
+`
+
+func scanGroup(row pgx.Row) (domain.Group, error) {
+	var (
+		g         domain.Group
+		idUUID    pgtype.UUID
+		createdBy pgtype.UUID
+		role      string
+		joinCode  string
+	)
+	if err := row.Scan(&idUUID, &g.Name, &g.Description, &createdBy, &role, &g.MemberCount, &joinCode, &g.CreatedAt, &g.UpdatedAt); err != nil {
+		return domain.Group{}, err
+	}
+	g.ID = uuidOrEmpty(idUUID)
+	g.CreatedBy = uuidOrEmpty(createdBy)
+	g.Role = domain.GroupRole(role)
+	if g.Role.CanManage() {
+		g.JoinCode = joinCode
+	}
+	return g, nil
+}
+
+func (s *GroupsStore) GetGroupForUser(ctx context.Context, groupID, userID string) (domain.Group, error) {
+	const q = `
+		SELECT ` + groupColumns + `
+		FROM groups g
+		JOIN group_members me ON me.group_id = g.id AND me.user_id = $2
+		WHERE g.id = $1
+	`
+	g, err := scanGroup(s.pool.QueryRow(ctx, q, groupID, userID))
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.Group{}, domain.ErrNotFound
+		}
+		return domain.Group{}, fmt.Errorf("get group: %w", err)
+	}
+	return g, nil
+}
+
+func (s *GroupsStore) GetGroupByJoinCode(ctx context.Context, joinCode string) (domain.GroupSummary, error) {
+	var (
+		g      domain.GroupSummary
+		idUUID pgtype.UUID
+	)
+	if err := s.pool.QueryRow(ctx, `SELECT id, name FROM groups WHERE join_code = $1`, joinCode).Scan(&idUUID, &g.Name); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.GroupSummary{}, domain.ErrNotFound
+		}
+		return domain.GroupSummary{}, fmt.Errorf("get group by join code: %w", err)
+	}
+	g.ID = uuidOrEmpty(idUUID)
+	return g, nil
+}
+
+func (s *GroupsStore) ListGroupsForUser(ctx context.Context, userID string) ([]domain.Group, error) {
+	const q = `
+		SELECT ` + groupColumns + `
+		FROM groups g
+		JOIN group_members me ON me.group_id = g.id AND me.user_id = $1
+		ORDER BY lower(g.name), g.id
+	`
+	rows, err := s.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list groups: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Group
+	for rows.Next() {
+		g, err := scanGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan group: %w", err)
+		}
+		out = append(out, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list groups: %w", err)
+	}
+	return out, nil
+}
+
+func (s *GroupsStore) UpdateGroup(ctx context.Context, groupID, name, description string) error {
+	ct, err := s.pool.Exec(ctx, `
+		UPDATE groups
+		SET name = $2, description = $3, updated_at = date_trunc('milliseconds', now())
+		WHERE id = $1
+	`, groupID, name, description)
+	if err != nil {
+		return fmt.Errorf("update group: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *GroupsStore) SetJoinCode(ctx context.Context, groupID, joinCode string) error {
+	ct, err := s.pool.Exec(ctx, `
+		UPDATE groups
+		SET join_code = $2, updated_at = date_trunc('milliseconds', now())
+		WHERE id = $1
+	`, groupID, joinCode)
+	if err != nil {
+		return fmt.Errorf("set join code: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *GroupsStore) DeleteGroup(ctx context.Context, groupID string) error {
+	ct, err := s.pool.Exec(ctx, `DELETE FROM groups WHERE id = $1`, groupID)
+	if err != nil {
+		return fmt.Errorf("delete group: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *GroupsStore) ListMembers(ctx context.Context, groupID string) ([]domain.GroupMember, error) {
+	const q = `
+		SELECT u.id, u.username, u.display_name, u.avatar_path, u.avatar_updated_at, gm.role, gm.joined_at
+		FROM group_members gm
+		JOIN users u ON u.id = gm.user_id
+		WHERE gm.group_id = $1
+		ORDER BY CASE gm.role WHEN 'owner' THEN 0 WHEN 'admin' THEN 1 ELSE 2 END, lower(u.username)
+	`
+	rows, err := s.pool.Query(ctx, q, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("list group members: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.GroupMember
+	for rows.Next() {
+		var (
+			m               domain.GroupMember
+			idUUID          pgtype.UUID
+			displayName     pgtype.Text
+			avatarPath      pgtype.Text
+			avatarUpdatedAt pgtype.Timestamptz
+			role            string
+		)
+		if err := rows.Scan(&idUUID, &m.User.Username, &displayName, &avatarPath, &avatarUpdatedAt, &role, &m.JoinedAt); err != nil {
+			return nil, fmt.Errorf("scan group member: %w", err)
+		}
+		m.User.ID = uuidOrEmpty(idUUID)
+		m.User.DisplayName = textOrEmpty(displayName)
+		m.User.AvatarPath = textOrEmpty(avatarPath)
+		m.User.AvatarUpdatedAt = timestamptzPtr(avatarUpdatedAt)
+		m.Role = domain.GroupRole(role)
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list group members: %w", err)
+	}
+	return out, nil
+}
+
+func (s *GroupsStore) GetMemberRole(ctx context.Context, groupID, userID string) (domain.GroupRole, error) {
+	var role string
+	err := s.pool.QueryRow(ctx, `SELECT role FROM group_members WHERE group_id = $1 AND user_id = $2`, groupID, userID).Scan(&role)
+	if err != nil {
+		if isMissingRow(err) {
+			return "", domain.ErrNotFound
+		}
+		return "", fmt.Errorf("get group member: %w", err)
+	}
+	return domain.GroupRole(role), nil
+}
+
+func (s *GroupsStore) AddMember(ctx context.Context, groupID, userID string, role domain.GroupRole) error {
+	_, err := s.pool.Exec(ctx, `INSERT INTO group_members (group_id, user_id, role) VALUES ($1, $2, $3)`, groupID, userID, string(role))
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == "23505" {
+			return domain.ErrAlreadyGroupMember
+		}
+		return fmt.Errorf("add group member: %w", err)
+	}
+	// A pending invite is moot once the user is in.
+	if _, err := s.pool.Exec(ctx, `DELETE FROM group_invites WHERE group_id = $1 AND user_id = $2`, groupID, userID); err != nil {
+		return fmt.Errorf("clear group invite: %w", err)
+	}
+	return nil
+}
+
+func (s *GroupsStore) SetMemberRole(ctx context.Context, groupID, userID string, role domain.GroupRole) error {
+	ct, err := s.pool.Exec(ctx, `
+		UPDATE group_members SET role = $3
+		WHERE group_id = $1 AND user_id = $2 AND role <> 'owner'
+	`, groupID, userID, string(role))
+	if err != nil {
+		return fmt.Errorf("set group role: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *GroupsStore) TransferOwnership(ctx context.Context, groupID, fromUserID, toUserID string) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ct, err := tx.Exec(ctx, `UPDATE group_members SET role = 'admin' WHERE group_id = $1 AND user_id = $2 AND role = 'owner'`, groupID, fromUserID)
+	if err != nil {
+		return fmt.Errorf("demote group owner: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrForbidden
+	}
+	ct, err = tx.Exec(ctx, `UPDATE group_members SET role = 'owner' WHERE group_id = $1 AND user_id = $2`, groupID, toUserID)
+	if err != nil {
+		return fmt.Errorf("promote group owner: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (s *GroupsStore) RemoveMember(ctx context.Context, groupID, userID string) error {
+	ct, err := s.pool.Exec(ctx, `DELETE FROM group_members WHERE group_id = $1 AND user_id = $2 AND role <> 'owner'`, groupID, userID)
+	if err != nil {
+		return fmt.Errorf("remove group member: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *GroupsStore) ShareGroup(ctx context.Context, userA, userB string) (bool, error) {
+	const q = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM group_members a
+			JOIN group_members b ON b.group_id = a.group_id
+			WHERE a.user_id = $1 AND b.user_id = $2
+		)
+	`
+	var ok bool
+	if err := s.pool.QueryRow(ctx, q, userA, userB).Scan(&ok); err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == "22P02" {
+			return false, nil
+		}
+		return false, fmt.Errorf("share group: %w", err)
+	}
+	return ok, nil
+}
+
+func (s *GroupsStore) CreateInvite(ctx context.Context, groupID, userID, invitedBy string) (string, error) {
+	// Re-inviting refreshes the existing invite instead of failing.
+	const q = `
+		INSERT INTO group_invites (group_id, user_id, invited_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT ON CONSTRAINT group_invites_group_user_uq DO UPDATE
+		SET invited_by = EXCLUDED.invited_by, created_at = date_trunc('milliseconds', now())
+		RETURNING id
+	`
+	var idUUID pgtype.UUID
+	if err := s.pool.QueryRow(ctx, q, groupID, userID, invitedBy).Scan(&idUUID); err != nil {
+		return "", fmt.Errorf("create group invite: %w", err)
+	}
+	return uuidOrEmpty(idUUID), nil
+}
+
+const groupInviteSelect = `
+	SELECT i.id, g.id, g.name,
+	       u.id, u.username, u.display_name, u.avatar_path, u.avatar_updated_at,
+	       b.id, b.username, b.display_name, b.avatar_path, b.avatar_updated_at,
+	       i.created_at
+	FROM group_invites i
+	JOIN groups g ON g.id = i.group_id
+	JOIN users u ON u.id = i.user_id
+	JOIN users b ON b.id = i.invited_by
+`
+
+func scanGroupInvite(row pgx.Row) (domain.GroupInvite, error) {
+	var (
+		inv                      domain.GroupInvite
+		idUUID, groupUUID        pgtype.UUID
+		userUUID, byUUID         pgtype.UUID
+		userDisplay, byDisplay   pgtype.Text
+		userAvatar, byAvatar     pgtype.Text
+		userAvatarAt, byAvatarAt pgtype.Timestamptz
+		createdAt                time.Time
+	)
+	if err := row.Scan(
+		&idUUID, &groupUUID, &inv.Group.Name,
+		&userUUID, &inv.User.Username, &userDisplay, &userAvatar, &userAvatarAt,
+		&byUUID, &inv.InvitedBy.Username, &byDisplay, &byAvatar, &byAvatarAt,
+		&createdAt,
+	); err != nil {
+		return domain.GroupInvite{}, err
+	}
+	inv.ID = uuidOrEmpty(idUUID)
+	inv.Group.ID = uuidOrEmpty(groupUUID)
+	inv.User.ID = uuidOrEmpty(userUUID)
+	inv.User.DisplayName = textOrEmpty(userDisplay)
+	inv.User.AvatarPath = textOrEmpty(userAvatar)
+	inv.User.AvatarUpdatedAt = timestamptzPtr(userAvatarAt)
+	inv.InvitedBy.ID = uuidOrEmpty(byUUID)
+	inv.InvitedBy.DisplayName = textOrEmpty(byDisplay)
+	inv.InvitedBy.AvatarPath = textOrEmpty(byAvatar)
+	inv.InvitedBy.AvatarUpdatedAt = timestamptzPtr(byAvatarAt)
+	inv.CreatedAt = createdAt
+	return inv, nil
+}
+
+func (s *GroupsStore) GetInvite(ctx context.Context, inviteID string) (domain.GroupInvite, error) {
+	inv, err := scanGroupInvite(s.pool.QueryRow(ctx, groupInviteSelect+` WHERE i.id = $1`, inviteID))
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.GroupInvite{}, domain.ErrNotFound
+		}
+		return domain.GroupInvite{}, fmt.Errorf("get group invite: %w", err)
+	}
+	return inv, nil
+}
+
+func (s *GroupsStore) ListInvitesForUser(ctx context.Context, userID string) ([]domain.GroupInvite, error) {
+	return s.listInvites(ctx, groupInviteSelect+` WHERE i.user_id = $1 ORDER BY i.created_at DESC`, userID)
+}
+
+func (s *GroupsStore) ListInvitesForGroup(ctx context.Context, groupID string) ([]domain.GroupInvite, error) {
+	return s.listInvites(ctx, groupInviteSelect+` WHERE i.group_id = $1 ORDER BY i.created_at DESC`, groupID)
+}
+
+func (s *GroupsStore) listInvites(ctx context.Context, q string, arg string) ([]domain.GroupInvite, error) {
+	rows, err := s.pool.Query(ctx, q, arg)
+	if err != nil {
+		return nil, fmt.Errorf("list group invites: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.GroupInvite
+	for rows.Next() {
+		inv, err := scanGroupInvite(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan group invite: %w", err)
+		}
+		out = append(out, inv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list group invites: %w", err)
+	}
+	return out, nil
+}
+
+func (s *GroupsStore) AcceptInvite(ctx context.Context, inviteID, userID string) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var groupUUID pgtype.UUID
+	err = tx.QueryRow(ctx, `DELETE FROM group_invites WHERE id = $1 AND user_id = $2 RETURNING group_id`, inviteID, userID).Scan(&groupUUID)
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("accept group invite: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO group_members (group_id, user_id, role)
+		VALUES ($1, $2, 'member')
+		ON CONFLICT (group_id, user_id) DO NOTHING
+	`, uuidOrEmpty(groupUUID), userID); err != nil {
+		return fmt.Errorf("add group member: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (s *GroupsStore) DeleteInvite(ctx context.Context, inviteID string) error {
+	ct, err := s.pool.Exec(ctx, `DELETE FROM group_invites WHERE id = $1`, inviteID)
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("delete group invite: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListGroupResults returns every participant of completed matches recorded in the group.
+func (s *GroupsStore) ListGroupResults(ctx context.Context, groupID string, format domain.GameFormat) ([]domain.MatchPlacement, error) {
+	const q = participantsCTE + `,
+		group_matches AS (
+			SELECT m.id, m.format
+			FROM matches m
+			WHERE m.deleted_at IS NULL
+			  AND m.group_id = $1
+			  AND ($2::text IS NULL OR m.format = $2)
+			  AND EXISTS (SELECT 1 FROM participants p WHERE p.match_id = m.id AND p.place = 1)
+		)
+		SELECT p.match_id, gm.format, p.user_id, u.username, u.display_name, u.avatar_path, u.avatar_updated_at,
+		       p.guest_id, COALESCE(r.display_name, p.guest_name), p.place, p.outcome = 'draw'
+		FROM participants p
+		JOIN group_matches gm ON gm.id = p.match_id
+		LEFT JOIN users u ON u.id = p.user_id
+		LEFT JOIN guests r ON r.id = p.guest_id
+		WHERE p.place IS NOT NULL
+		ORDER BY p.match_id, p.place
+	`
+	rows, err := s.pool.Query(ctx, q, groupID, nullIfEmpty(string(format)))
+	if err != nil {
+		return nil, fmt.Errorf("list group results: %w", err)
+	}
+	defer rows.Close()
+	return scanMatchPlacements(rows)
+}
+
+// scanMatchPlacements reads (match_id, format, user_id, username, display_name, avatar_path,
+// avatar_updated_at, guest_id, guest_name, place, draw) rows. guest_name should already be the roster name
+// for linked guests.
+func scanMatchPlacements(rows pgx.Rows) ([]domain.MatchPlacement, error) {
+	var out []domain.MatchPlacement
+	for rows.Next() {
+		var (
+			matchID         pgtype.UUID
+			formatText      pgtype.Text
+			userID          pgtype.UUID
+			username        pgtype.Text
+			displayName     pgtype.Text
+			avatarPath      pgtype.Text
+			avatarUpdatedAt pgtype.Timestamptz
+			guestID         pgtype.UUID
+			guestName       pgtype.Text
+			place           int
+			draw            bool
+		)
+		if err := rows.Scan(&matchID, &formatText, &userID, &username, &displayName, &avatarPath, &avatarUpdatedAt, &guestID, &guestName, &place, &draw); err != nil {
+			return nil, fmt.Errorf("scan match placement: %w", err)
+		}
+		r := domain.MatchPlacement{
+			MatchID:   uuidOrEmpty(matchID),
+			Format:    normalizeFormat(formatText),
+			GuestID:   uuidOrEmpty(guestID),
+			GuestName: textOrEmpty(guestName),
+			Place:     place,
+			Draw:      draw,
+		}
+		if id := uuidOrEmpty(userID); id != "" {
+			r.User = &domain.UserSummary{
+				ID:              id,
+				Username:        textOrEmpty(username),
+				DisplayName:     textOrEmpty(displayName),
+				AvatarPath:      textOrEmpty(avatarPath),
+				AvatarUpdatedAt: timestamptzPtr(avatarUpdatedAt),
+			}
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list match placements: %w", err)
+	}
+	return out, nil
+}
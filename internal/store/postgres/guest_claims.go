@@ -0,0 +1,262 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type GuestClaimsStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewGuestClaimsStore(pool *pgxpool.Pool) *GuestClaimsStore {
+	return &GuestClaimsStore{pool: pool}
+}
+
+// guestSeatsFilter matches the live guest seats a creator recorded for a guest: seats under the
+// guest name and, when a saved guest is given, seats linked to it or named after one of its aliases.
+// The arguments are the SQL expressions for the creator, the guest name and the saved guest id.
+func guestSeatsFilter(createdBy, guestName, guestID string) string {
+	return `
+	m.created_by = ` + createdBy + `
+	AND m.deleted_at IS NULL
+	AND p.user_id IS NULL
+	AND (
+	  lower(btrim(p.guest_name)) = lower(btrim(` + guestName + `))
+	  OR p.guest_id = ` + guestID + `
+	  OR lower(btrim(p.guest_name)) IN (
+	    SELECT a.alias FROM guest_aliases a WHERE a.owner_id = m.created_by AND a.guest_id = ` + guestID + `
+	  )
+	)
+`
+}
+
+// CountGuestMatches counts the creator's matches that have a guest seat for the guest name or saved guest.
+func (s *GuestClaimsStore) CountGuestMatches(ctx context.Context, createdBy, guestName, guestID string) (int, error) {
+	q := `
+		SELECT COUNT(DISTINCT p.match_id)
+		FROM match_participants p
+		JOIN matches m ON m.id = p.match_id
+		WHERE ` + guestSeatsFilter("$1", "$2", "$3::uuid")
+	var n int
+	if err := s.pool.QueryRow(ctx, q, createdBy, guestName, nullIfEmpty(guestID)).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count guest matches: %w", err)
+	}
+	return n, nil
+}
+
+// CreateGuestClaim stores the offer. Without a guestID, a guest name that is an alias of a saved
+// guest claims that guest.
+func (s *GuestClaimsStore) CreateGuestClaim(ctx context.Context, createdBy, guestName, guestID, userID string) (string, error) {
+	// Offering the same guest name again moves the claim to the new user.
+	const q = `
+		INSERT INTO guest_claims (created_by, guest_name, guest_id, user_id)
+		VALUES ($1, $2, COALESCE($3::uuid, (
+		  SELECT a.guest_id FROM guest_aliases a WHERE a.owner_id = $1 AND a.alias = lower(btrim($2))
+		)), $4)
+		ON CONFLICT (created_by, lower(guest_name)) DO UPDATE
+		SET guest_name = EXCLUDED.guest_name, guest_id = EXCLUDED.guest_id, user_id = EXCLUDED.user_id,
+		    created_at = date_trunc('milliseconds', now())
+		RETURNING id
+	`
+	var idUUID pgtype.UUID
+	if err := s.pool.QueryRow(ctx, q, createdBy, guestName, nullIfEmpty(guestID), userID).Scan(&idUUID); err != nil {
+		return "", fmt.Errorf("create guest claim: %w", err)
+	}
+	return uuidOrEmpty(idUUID), nil
+}
+
+var guestClaimSelect = `
+	SELECT c.id, c.guest_name, c.guest_id,
+	       b.id, b.username, b.display_name, b.avatar_path, b.avatar_updated_at,
+	       u.id, u.username, u.display_name, u.avatar_path, u.avatar_updated_at,
+	       (
+	         SELECT COUNT(DISTINCT p.match_id)
+	         FROM match_participants p
+	         JOIN matches m ON m.id = p.match_id
+	         WHERE ` + guestSeatsFilter("c.created_by", "c.guest_name", "c.guest_id") + `
+	       ),
+	       c.created_at
+	FROM guest_claims c
+	JOIN users b ON b.id = c.created_by
+	JOIN users u ON u.id = c.user_id
+`
+
+func scanGuestClaim(row pgx.Row) (domain.GuestClaim, error) {
+	var (
+		claim                    domain.GuestClaim
+		idUUID, byUUID, userUUID pgtype.UUID
+		guestUUID                pgtype.UUID
+		byDisplay, userDisplay   pgtype.Text
+		byAvatar, userAvatar     pgtype.Text
+		byAvatarAt, userAvatarAt pgtype.Timestamptz
+		createdAt                time.Time
+	)
+	if err := row.Scan(
+		&idUUID, &claim.GuestName, &guestUUID,
+		&byUUID, &claim.InvitedBy.Username, &byDisplay, &byAvatar, &byAvatarAt,
+		&userUUID, &claim.User.Username, &userDisplay, &userAvatar, &userAvatarAt,
+		&claim.MatchCount,
+		&createdAt,
+	); err != nil {
+		return domain.GuestClaim{}, err
+	}
+	claim.ID = uuidOrEmpty(idUUID)
+	claim.GuestID = uuidOrEmpty(guestUUID)
+	claim.InvitedBy.ID = uuidOrEmpty(byUUID)
+	claim.InvitedBy.DisplayName = textOrEmpty(byDisplay)
+	claim.InvitedBy.AvatarPath = textOrEmpty(byAvatar)
+	claim.InvitedBy.AvatarUpdatedAt = timestamptzPtr(byAvatarAt)
+	claim.User.ID = uuidOrEmpty(userUUID)
+	claim.User.DisplayName = textOrEmpty(userDisplay)
+	claim.User.AvatarPath = textOrEmpty(userAvatar)
+	claim.User.AvatarUpdatedAt = timestamptzPtr(userAvatarAt)
+	claim.CreatedAt = createdAt
+	return claim, nil
+}
+
+func (s *GuestClaimsStore) GetGuestClaim(ctx context.Context, claimID string) (domain.GuestClaim, error) {
+	claim, err := scanGuestClaim(s.pool.QueryRow(ctx, guestClaimSelect+` WHERE c.id = $1`, claimID))
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.GuestClaim{}, domain.ErrNotFound
+		}
+		return domain.GuestClaim{}, fmt.Errorf("get guest claim: %w", err)
+	}
+	return claim, nil
+}
+
+func (s *GuestClaimsStore) ListIncomingGuestClaims(ctx context.Context, userID string) ([]domain.GuestClaim, error) {
+	return s.listGuestClaims(ctx, guestClaimSelect+` WHERE c.user_id = $1 ORDER BY c.created_at DESC`, userID)
+}
+
+func (s *GuestClaimsStore) ListOutgoingGuestClaims(ctx context.Context, userID string) ([]domain.GuestClaim, error) {
+	return s.listGuestClaims(ctx, guestClaimSelect+` WHERE c.created_by = $1 ORDER BY c.created_at DESC`, userID)
+}
+
+func (s *GuestClaimsStore) listGuestClaims(ctx context.Context, q string, arg string) ([]domain.GuestClaim, error) {
+	rows, err := s.pool.Query(ctx, q, arg)
+	if err != nil {
+		return nil, fmt.Errorf("list guest claims: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.GuestClaim
+	for rows.Next() {
+		claim, err := scanGuestClaim(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan guest claim: %w", err)
+		}
+		out = append(out, claim)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list guest claims: %w", err)
+	}
+	return out, nil
+}
+
+// AcceptGuestClaim consumes the claim and moves the matching guest seats to userID. Matches the
+// user already has a seat in keep their guest seat. A claimed saved guest is removed from the
+// creator's roster, since its seats now belong to the user. It returns the number of matches converted.
+func (s *GuestClaimsStore) AcceptGuestClaim(ctx context.Context, claimID, userID string, at time.Time) (int, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var (
+		createdByUUID pgtype.UUID
+		guestName     string
+		guestUUID     pgtype.UUID
+	)
+	err = tx.QueryRow(ctx, `DELETE FROM guest_claims WHERE id = $1 AND user_id = $2 RETURNING created_by, guest_name, guest_id`, claimID, userID).Scan(&createdByUUID, &guestName, &guestUUID)
+	if err != nil {
+		if isMissingRow(err) {
+			return 0, domain.ErrNotFound
+		}
+		return 0, fmt.Errorf("accept guest claim: %w", err)
+	}
+
+	// One seat per match: a guest used twice in the same match only converts its first seat.
+	q := `
+		WITH targets AS (
+			SELECT DISTINCT ON (p.match_id) p.match_id, p.seat_index
+			FROM match_participants p
+			JOIN matches m ON m.id = p.match_id
+			WHERE ` + guestSeatsFilter("$1", "$2", "$5::uuid") + `
+			  AND NOT EXISTS (
+			    SELECT 1 FROM match_participants o
+			    WHERE o.match_id = p.match_id AND o.user_id = $3
+			  )
+			ORDER BY p.match_id, p.seat_index
+		)
+		UPDATE match_participants p
+		SET user_id = $3,
+		    guest_name = NULL,
+		    guest_id = NULL,
+		    confirmation = 'confirmed',
+		    dispute_reason = NULL,
+		    confirmation_updated_at = $4
+		FROM targets t
+		WHERE p.match_id = t.match_id AND p.seat_index = t.seat_index
+		RETURNING p.match_id
+	`
+	createdBy, guestID := uuidOrEmpty(createdByUUID), uuidOrEmpty(guestUUID)
+	rows, err := tx.Query(ctx, q, createdBy, guestName, userID, at, nullIfEmpty(guestID))
+	if err != nil {
+		return 0, fmt.Errorf("convert guest seats: %w", err)
+	}
+	var matchIDs []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan converted match: %w", err)
+		}
+		matchIDs = append(matchIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("convert guest seats: %w", err)
+	}
+
+	// Bump updated_at so clients holding these matches pick up the new seat on their next sync.
+	if len(matchIDs) > 0 {
+		if _, err := tx.Exec(ctx, `UPDATE matches SET updated_at = GREATEST(updated_at, $2) WHERE id = ANY($1)`, matchIDs, at); err != nil {
+			return 0, fmt.Errorf("touch converted matches: %w", err)
+		}
+	}
+
+	if guestID != "" {
+		if _, err := tx.Exec(ctx, `DELETE FROM guests WHERE id = $1 AND owner_id = $2`, guestID, createdBy); err != nil {
+			return 0, fmt.Errorf("remove claimed guest: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return len(matchIDs), nil
+}
+
+func (s *GuestClaimsStore) DeleteGuestClaim(ctx context.Context, claimID string) error {
+	ct, err := s.pool.Exec(ctx, `DELETE FROM guest_claims WHERE id = $1`, claimID)
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("delete guest claim: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
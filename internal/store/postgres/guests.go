@@ -0,0 +1,272 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type GuestsStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewGuestsStore(pool *pgxpool.Pool) *GuestsStore {
+	return &GuestsStore{pool: pool}
+}
+
+const guestSelect = `
+	SELECT g.id, g.display_name,
+	       COALESCE((SELECT array_agg(a.alias ORDER BY a.alias) FROM guest_aliases a WHERE a.guest_id = g.id), '{}'),
+	       (
+	         SELECT COUNT(DISTINCT p.match_id)
+	         FROM match_participants p
+	         JOIN matches m ON m.id = p.match_id
+	         WHERE p.guest_id = g.id AND m.deleted_at IS NULL
+	       ),
+	       g.created_at, g.updated_at
+	FROM guests g
+`
+
+func scanGuest(row pgx.Row) (domain.Guest, error) {
+	var (
+		g      domain.Guest
+		idUUID pgtype.UUID
+	)
+	if err := row.Scan(&idUUID, &g.DisplayName, &g.Aliases, &g.MatchCount, &g.CreatedAt, &g.UpdatedAt); err != nil {
+		return domain.Guest{}, err
+	}
+	g.ID = uuidOrEmpty(idUUID)
+	return g, nil
+}
+
+func (s *GuestsStore) ListGuests(ctx context.Context, ownerID string) ([]domain.Guest, error) {
+	rows, err := s.pool.Query(ctx, guestSelect+` WHERE g.owner_id = $1 ORDER BY lower(g.display_name), g.id`, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("list guests: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Guest
+	for rows.Next() {
+		g, err := scanGuest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan guest: %w", err)
+		}
+		out = append(out, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list guests: %w", err)
+	}
+	return out, nil
+}
+
+func (s *GuestsStore) GetGuest(ctx context.Context, ownerID, guestID string) (domain.Guest, error) {
+	g, err := scanGuest(s.pool.QueryRow(ctx, guestSelect+` WHERE g.id = $1 AND g.owner_id = $2`, guestID, ownerID))
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.Guest{}, domain.ErrNotFound
+		}
+		return domain.Guest{}, fmt.Errorf("get guest: %w", err)
+	}
+	return g, nil
+}
+
+// GuestIDForName returns the owner's saved guest with guestName as an alias, or "" if there is none.
+func (s *GuestsStore) GuestIDForName(ctx context.Context, ownerID, guestName string) (string, error) {
+	var idUUID pgtype.UUID
+	err := s.pool.QueryRow(ctx, `
+		SELECT guest_id FROM guest_aliases WHERE owner_id = $1 AND alias = lower(btrim($2))
+	`, ownerID, guestName).Scan(&idUUID)
+	if err != nil {
+		if isMissingRow(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("resolve guest name: %w", err)
+	}
+	return uuidOrEmpty(idUUID), nil
+}
+
+// CreateGuest adds a roster entry and links the owner's existing guest seats that match its aliases.
+func (s *GuestsStore) CreateGuest(ctx context.Context, ownerID, displayName string, aliases []string, at time.Time) (string, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return "", fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var idUUID pgtype.UUID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO guests (owner_id, display_name, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		RETURNING id
+	`, ownerID, displayName, at).Scan(&idUUID)
+	if err != nil {
+		return "", fmt.Errorf("create guest: %w", err)
+	}
+	guestID := uuidOrEmpty(idUUID)
+	if err := addGuestAliases(ctx, tx, ownerID, guestID, aliases); err != nil {
+		return "", err
+	}
+	if err := linkGuestSeats(ctx, tx, ownerID, guestID); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("commit tx: %w", err)
+	}
+	return guestID, nil
+}
+
+// UpdateGuest renames the guest and replaces its aliases. Seats already linked stay linked;
+// seats matching a new alias are linked.
+func (s *GuestsStore) UpdateGuest(ctx context.Context, ownerID, guestID, displayName string, aliases []string, at time.Time) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ct, err := tx.Exec(ctx, `UPDATE guests SET display_name = $3, updated_at = $4 WHERE id = $1 AND owner_id = $2`, guestID, ownerID, displayName, at)
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("update guest: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM guest_aliases WHERE guest_id = $1`, guestID); err != nil {
+		return fmt.Errorf("clear guest aliases: %w", err)
+	}
+	if err := addGuestAliases(ctx, tx, ownerID, guestID, aliases); err != nil {
+		return err
+	}
+	if err := linkGuestSeats(ctx, tx, ownerID, guestID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// DeleteGuest removes the roster entry. Its seats keep their guest_name and become unlinked.
+func (s *GuestsStore) DeleteGuest(ctx context.Context, ownerID, guestID string) error {
+	ct, err := s.pool.Exec(ctx, `DELETE FROM guests WHERE id = $1 AND owner_id = $2`, guestID, ownerID)
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("delete guest: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// MergeGuests folds the source guests into the target: their aliases and linked seats move over
+// and the sources are deleted. Every id must belong to the owner. Guests that would end up with two
+// seats in the same match are not merged (domain.ErrGuestsShareMatch). Archived season standings
+// are snapshots and are not moved.
+func (s *GuestsStore) MergeGuests(ctx context.Context, ownerID, targetID string, sourceIDs []string, at time.Time) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ids := append([]string{targetID}, sourceIDs...)
+	var owned int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM (SELECT id FROM guests WHERE owner_id = $1 AND id = ANY($2::uuid[]) FOR UPDATE) g`, ownerID, ids).Scan(&owned); err != nil {
+		if isMissingRow(err) {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("lock guests: %w", err)
+	}
+	if owned != len(ids) {
+		return domain.ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE guest_aliases SET guest_id = $1 WHERE guest_id = ANY($2::uuid[])`, targetID, sourceIDs); err != nil {
+		return fmt.Errorf("move guest aliases: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE match_participants SET guest_id = $1 WHERE guest_id = ANY($2::uuid[])`, targetID, sourceIDs); err != nil {
+		return fmt.Errorf("move guest seats: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM guests WHERE id = ANY($1::uuid[])`, sourceIDs); err != nil {
+		return fmt.Errorf("delete merged guests: %w", err)
+	}
+	if err := linkGuestSeats(ctx, tx, ownerID, targetID); err != nil {
+		return err
+	}
+	var shared bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+		  SELECT 1 FROM match_participants
+		  WHERE guest_id = $1
+		  GROUP BY match_id
+		  HAVING COUNT(*) > 1
+		)
+	`, targetID).Scan(&shared); err != nil {
+		return fmt.Errorf("check merged guest seats: %w", err)
+	}
+	if shared {
+		return domain.ErrGuestsShareMatch
+	}
+	if _, err := tx.Exec(ctx, `UPDATE guests SET updated_at = $2 WHERE id = $1`, targetID, at); err != nil {
+		return fmt.Errorf("touch guest: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func addGuestAliases(ctx context.Context, tx pgx.Tx, ownerID, guestID string, aliases []string) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO guest_aliases (owner_id, alias, guest_id)
+		SELECT DISTINCT $1::uuid, lower(btrim(a)), $2::uuid
+		FROM unnest($3::text[]) AS a
+		WHERE btrim(a) <> ''
+	`, ownerID, guestID, aliases)
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == "23505" {
+			return domain.ErrGuestAliasTaken
+		}
+		return fmt.Errorf("add guest aliases: %w", err)
+	}
+	return nil
+}
+
+// linkGuestSeats attaches the owner's unlinked guest seats whose name matches one of the guest's aliases.
+func linkGuestSeats(ctx context.Context, tx pgx.Tx, ownerID, guestID string) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE match_participants p
+		SET guest_id = a.guest_id
+		FROM matches m, guest_aliases a
+		WHERE m.id = p.match_id
+		  AND m.created_by = $1
+		  AND p.user_id IS NULL
+		  AND p.guest_id IS NULL
+		  AND a.owner_id = $1
+		  AND a.guest_id = $2
+		  AND a.alias = lower(btrim(p.guest_name))
+	`, ownerID, guestID)
+	if err != nil {
+		return fmt.Errorf("link guest seats: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LiveMatchesStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewLiveMatchesStore(pool *pgxpool.Pool) *LiveMatchesStore {
+	return &LiveMatchesStore{pool: pool}
+}
+
+func (s *LiveMatchesStore) CreateLiveMatch(ctx context.Context, in domain.LiveMatchInput, at time.Time) (string, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return "", fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var idUUID pgtype.UUID
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO live_matches (created_by, format, group_id, starting_seat_index, started_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, in.CreatedBy, string(in.Format), nullIfEmpty(in.GroupID), in.StartingSeatIndex, at).Scan(&idUUID); err != nil {
+		return "", fmt.Errorf("insert live match: %w", err)
+	}
+	liveID := uuidOrEmpty(idUUID)
+
+	// The host is joined from the start.
+	const q = `
+		INSERT INTO live_match_seats (live_match_id, seat_index, user_id, guest_name, guest_id, display_name, deck_id, commander_card_ids, joined_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::uuid[], CASE WHEN $3::uuid = $9::uuid THEN $10::timestamptz END)
+	`
+	batch := &pgx.Batch{}
+	for _, seat := range in.Seats {
+		commanderIDs := seat.CommanderIDs
+		if commanderIDs == nil {
+			commanderIDs = []string{}
+		}
+		batch.Queue(q, liveID, seat.SeatIndex, nullIfEmpty(seat.UserID), seat.GuestName, nullIfEmpty(seat.GuestID), seat.DisplayName, nullIfEmpty(seat.DeckID), commanderIDs, in.CreatedBy, at)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return "", fmt.Errorf("insert live match seats: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("commit tx: %w", err)
+	}
+	return liveID, nil
+}
+
+const liveMatchColumns = `lm.id, lm.created_by, lm.format, lm.group_id, lm.starting_seat_index, lm.status, lm.match_id, lm.started_at, lm.finished_at`
+
+func scanLiveMatch(row pgx.Row) (domain.LiveMatch, error) {
+	var (
+		lm           domain.LiveMatch
+		idUUID       pgtype.UUID
+		createdBy    pgtype.UUID
+		format       string
+		groupID      pgtype.UUID
+		startingSeat pgtype.Int4
+		status       string
+		matchID      pgtype.UUID
+		finishedAt   pgtype.Timestamptz
+	)
+	if err := row.Scan(&idUUID, &createdBy, &format, &groupID, &startingSeat, &status, &matchID, &lm.StartedAt, &finishedAt); err != nil {
+		return domain.LiveMatch{}, err
+	}
+	lm.ID = uuidOrEmpty(idUUID)
+	lm.CreatedBy = uuidOrEmpty(createdBy)
+	lm.Format = domain.GameFormat(format)
+	lm.GroupID = uuidOrEmpty(groupID)
+	lm.StartingSeatIndex = int4Ptr(startingSeat)
+	lm.Status = domain.LiveMatchStatus(status)
+	lm.MatchID = uuidOrEmpty(matchID)
+	lm.FinishedAt = timestamptzPtr(finishedAt)
+	return lm, nil
+}
+
+func (s *LiveMatchesStore) GetLiveMatch(ctx context.Context, liveID string) (domain.LiveMatch, error) {
+	lm, err := scanLiveMatch(s.pool.QueryRow(ctx, `SELECT `+liveMatchColumns+` FROM live_matches lm WHERE lm.id = $1`, liveID))
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.LiveMatch{}, domain.ErrNotFound
+		}
+		return domain.LiveMatch{}, fmt.Errorf("get live match: %w", err)
+	}
+	if lm.Seats, err = s.listSeats(ctx, lm.ID); err != nil {
+		return domain.LiveMatch{}, err
+	}
+	return lm, nil
+}
+
+func (s *LiveMatchesStore) ListLiveMatchesForUser(ctx context.Context, userID string) ([]domain.LiveMatch, error) {
+	const q = `
+		SELECT ` + liveMatchColumns + `
+		FROM live_matches lm
+		WHERE lm.status IN ('active', 'finishing')
+		  AND (lm.created_by = $1 OR EXISTS (
+			SELECT 1 FROM live_match_seats ls WHERE ls.live_match_id = lm.id AND ls.user_id = $1
+		  ))
+		ORDER BY lm.started_at DESC, lm.id
+	`
+	rows, err := s.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list live matches: %w", err)
+	}
+	var out []domain.LiveMatch
+	for rows.Next() {
+		lm, err := scanLiveMatch(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan live match: %w", err)
+		}
+		out = append(out, lm)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list live matches: %w", err)
+	}
+
+	for i := range out {
+		if out[i].Seats, err = s.listSeats(ctx, out[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (s *LiveMatchesStore) listSeats(ctx context.Context, liveID string) ([]domain.LiveMatchSeat, error) {
+	const q = `
+		SELECT ls.seat_index, ls.user_id, u.username, u.display_name, ls.guest_name, ls.guest_id,
+		       ls.display_name, ls.deck_id, ls.commander_card_ids::text[], ls.joined_at
+		FROM live_match_seats ls
+		LEFT JOIN users u ON u.id = ls.user_id
+		WHERE ls.live_match_id = $1
+		ORDER BY ls.seat_index
+	`
+	rows, err := s.pool.Query(ctx, q, liveID)
+	if err != nil {
+		return nil, fmt.Errorf("list live match seats: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.LiveMatchSeat
+	for rows.Next() {
+		var (
+			seat            domain.LiveMatchSeat
+			userID          pgtype.UUID
+			username        pgtype.Text
+			userDisplayName pgtype.Text
+			guestID         pgtype.UUID
+			deckID          pgtype.UUID
+			joinedAt        pgtype.Timestamptz
+		)
+		if err := rows.Scan(&seat.SeatIndex, &userID, &username, &userDisplayName, &seat.GuestName, &guestID, &seat.DisplayName, &deckID, &seat.CommanderIDs, &joinedAt); err != nil {
+			return nil, fmt.Errorf("scan live match seat: %w", err)
+		}
+		if userID.Valid {
+			seat.User = &domain.UserSummary{ID: uuidOrEmpty(userID), Username: textOrEmpty(username), DisplayName: textOrEmpty(userDisplayName)}
+			if strings.TrimSpace(seat.DisplayName) == "" {
+				seat.DisplayName = textOrEmpty(userDisplayName)
+			}
+		}
+		seat.GuestID = uuidOrEmpty(guestID)
+		seat.DeckID = uuidOrEmpty(deckID)
+		seat.JoinedAt = timestamptzPtr(joinedAt)
+		if len(seat.CommanderIDs) == 0 {
+			seat.CommanderIDs = nil
+		}
+		out = append(out, seat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list live match seats: %w", err)
+	}
+	return out, nil
+}
+
+func (s *LiveMatchesStore) JoinLiveMatch(ctx context.Context, liveID, userID string, at time.Time) error {
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE live_match_seats
+		SET joined_at = COALESCE(joined_at, $3)
+		WHERE live_match_id = $1 AND user_id = $2
+	`, liveID, userID, at)
+	if err != nil {
+		return fmt.Errorf("join live match: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *LiveMatchesStore) AppendLiveMatchEvents(ctx context.Context, liveID, userID string, events []domain.MatchEvent, at time.Time) ([]domain.MatchEvent, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Lock the live match so concurrent updates from several phones are numbered in turn.
+	var status string
+	if err := tx.QueryRow(ctx, `SELECT status FROM live_matches WHERE id = $1 FOR UPDATE`, liveID).Scan(&status); err != nil {
+		if isMissingRow(err) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("lock live match: %w", err)
+	}
+	if status != string(domain.LiveMatchActive) {
+		return nil, domain.ErrLiveMatchClosed
+	}
+	var seq, turn int
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(MAX(seq), 0), COALESCE(MAX(turn), 0) FROM live_match_events WHERE live_match_id = $1
+	`, liveID).Scan(&seq, &turn); err != nil {
+		return nil, fmt.Errorf("last live match event: %w", err)
+	}
+
+	const q = `
+		INSERT INTO live_match_events (live_match_id, seq, kind, turn, seat_index, source_seat_index, amount, total, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	out := make([]domain.MatchEvent, 0, len(events))
+	batch := &pgx.Batch{}
+	for _, e := range events {
+		seq++
+		if e.Kind == domain.MatchEventTurnPass {
+			turn++
+		}
+		e.Seq = seq
+		e.Turn = turn
+		occurredAt := at
+		e.OccurredAt = &occurredAt
+		batch.Queue(q, liveID, e.Seq, string(e.Kind), e.Turn, e.SeatIndex, e.SourceSeatIndex, e.Amount, e.Total, userID, at)
+		out = append(out, e)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("insert live match events: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return out, nil
+}
+
+func (s *LiveMatchesStore) ListLiveMatchEvents(ctx context.Context, liveID string, afterSeq int) ([]domain.MatchEvent, error) {
+	const q = `
+		SELECT seq, kind, turn, seat_index, source_seat_index, amount, total, created_at
+		FROM live_match_events
+		WHERE live_match_id = $1 AND seq > $2
+		ORDER BY seq
+	`
+	rows, err := s.pool.Query(ctx, q, liveID, afterSeq)
+	if err != nil {
+		return nil, fmt.Errorf("list live match events: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.MatchEvent
+	for rows.Next() {
+		var (
+			e         domain.MatchEvent
+			kind      string
+			source    pgtype.Int4
+			total     pgtype.Int4
+			createdAt time.Time
+		)
+		if err := rows.Scan(&e.Seq, &kind, &e.Turn, &e.SeatIndex, &source, &e.Amount, &total, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan live match event: %w", err)
+		}
+		e.Kind = domain.MatchEventKind(kind)
+		e.SourceSeatIndex = int4Ptr(source)
+		e.Total = int4Ptr(total)
+		e.OccurredAt = &createdAt
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list live match events: %w", err)
+	}
+	return out, nil
+}
+
+func (s *LiveMatchesStore) FreezeLiveMatch(ctx context.Context, liveID string) error {
+	return s.setLiveMatchStatus(ctx, "freeze live match", liveID,
+		`UPDATE live_matches SET status = 'finishing' WHERE id = $1 AND status IN ('active', 'finishing')`)
+}
+
+func (s *LiveMatchesStore) ReopenLiveMatch(ctx context.Context, liveID string) error {
+	return s.setLiveMatchStatus(ctx, "reopen live match", liveID,
+		`UPDATE live_matches SET status = 'active' WHERE id = $1 AND status = 'finishing'`)
+}
+
+func (s *LiveMatchesStore) CloseLiveMatch(ctx context.Context, liveID, matchID string, at time.Time) error {
+	if matchID == "" {
+		return s.setLiveMatchStatus(ctx, "close live match", liveID, `
+			UPDATE live_matches
+			SET status = 'abandoned', finished_at = $2
+			WHERE id = $1 AND status = 'active'
+		`, at)
+	}
+	return s.setLiveMatchStatus(ctx, "close live match", liveID, `
+		UPDATE live_matches
+		SET status = 'finished', match_id = $2, finished_at = $3
+		WHERE id = $1 AND status = 'finishing'
+	`, matchID, at)
+}
+
+// setLiveMatchStatus runs a status transition on liveID, telling a missing live match apart from
+// one that is not in the state the transition starts from.
+func (s *LiveMatchesStore) setLiveMatchStatus(ctx context.Context, op, liveID, q string, args ...any) error {
+	tag, err := s.pool.Exec(ctx, q, append([]any{liveID}, args...)...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		if err := s.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM live_matches WHERE id = $1)`, liveID).Scan(&exists); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+		return domain.ErrLiveMatchClosed
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MatchEventsStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewMatchEventsStore(pool *pgxpool.Pool) *MatchEventsStore {
+	return &MatchEventsStore{pool: pool}
+}
+
+func (s *MatchEventsStore) ListMatchEvents(ctx context.Context, matchID string) ([]domain.MatchEvent, error) {
+	const q = `
+		SELECT seq, kind, turn, seat_index, source_seat_index, amount, total, occurred_at
+		FROM match_events
+		WHERE match_id = $1
+		ORDER BY seq
+	`
+	rows, err := s.pool.Query(ctx, q, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("list match events: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.MatchEvent
+	for rows.Next() {
+		var (
+			e          domain.MatchEvent
+			kind       string
+			source     pgtype.Int4
+			total      pgtype.Int4
+			occurredAt pgtype.Timestamptz
+		)
+		if err := rows.Scan(&e.Seq, &kind, &e.Turn, &e.SeatIndex, &source, &e.Amount, &total, &occurredAt); err != nil {
+			return nil, fmt.Errorf("scan match event: %w", err)
+		}
+		e.Kind = domain.MatchEventKind(kind)
+		e.SourceSeatIndex = int4Ptr(source)
+		e.Total = int4Ptr(total)
+		e.OccurredAt = timestamptzPtr(occurredAt)
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list match events: %w", err)
+	}
+	return out, nil
+}
+
+func (s *MatchEventsStore) AppendMatchEvents(ctx context.Context, matchID string, events []domain.MatchEvent) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Lock the match so concurrent uploads see each other's seq.
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM matches WHERE id = $1 FOR UPDATE`, matchID); err != nil {
+		return fmt.Errorf("lock match: %w", err)
+	}
+	var last int
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(MAX(seq), 0) FROM match_events WHERE match_id = $1`, matchID).Scan(&last); err != nil {
+		return fmt.Errorf("last match event: %w", err)
+	}
+
+	var fresh []domain.MatchEvent
+	for _, e := range events {
+		if e.Seq > last {
+			fresh = append(fresh, e)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+	if fresh[0].Seq != last+1 {
+		return domain.NewValidationError(map[string]string{"events": fmt.Sprintf("seq must continue from %d", last+1)})
+	}
+
+	const q = `
+		INSERT INTO match_events (match_id, seq, kind, turn, seat_index, source_seat_index, amount, total, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	batch := &pgx.Batch{}
+	for _, e := range fresh {
+		batch.Queue(q, matchID, e.Seq, string(e.Kind), e.Turn, e.SeatIndex, e.SourceSeatIndex, e.Amount, e.Total, e.OccurredAt)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("insert match events: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
@@ -31,10 +31,10 @@ func normalizeFormat(format pgtype.Text) domain.GameFormat {
 	return domain.GameFormat(f)
 }
 
-func (s *MatchesStore) CreateMatch(ctx context.Context, createdBy string, startedAt, endedAt, playedAt *time.Time, winnerID string, participants []domain.MatchParticipantInput, format domain.GameFormat, totalDurationSeconds, turnCount int, clientRef string, updatedAt time.Time) (string, bool, error) {
-	if clientRef != "" {
+func (s *MatchesStore) CreateMatch(ctx context.Context, in domain.MatchInput) (string, bool, error) {
+	if in.ClientRef != "" {
 		var existingID pgtype.UUID
-		err := s.pool.QueryRow(ctx, `SELECT id FROM matches WHERE created_by = $1 AND client_ref = $2`, createdBy, clientRef).Scan(&existingID)
+		err := s.pool.QueryRow(ctx, `SELECT id FROM matches WHERE created_by = $1 AND client_ref = $2`, in.CreatedBy, in.ClientRef).Scan(&existingID)
 		if err == nil {
 			return uuidOrEmpty(existingID), false, nil
 		}
@@ -50,35 +50,40 @@ func (s *MatchesStore) CreateMatch(ctx context.Context, createdBy string, starte
 	defer func() { _ = tx.Rollback(ctx) }()
 
 	const insertMatch = `
-		INSERT INTO matches (created_by, played_at, winner_id, format, total_duration_seconds, turn_count, client_ref, updated_at, started_at, ended_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO matches (created_by, played_at, winner_id, format, total_duration_seconds, turn_count, client_ref, updated_at, started_at, ended_at, starting_seat_index, group_id, best_of, outcome,
+		                     win_condition, win_condition_tag, winning_card)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 		RETURNING id
 	`
 
 	var matchIDUUID pgtype.UUID
 	var playedAtAny any
-	if playedAt != nil {
-		playedAtAny = *playedAt
-	} else if endedAt != nil {
-		playedAtAny = *endedAt
+	if in.PlayedAt != nil {
+		playedAtAny = *in.PlayedAt
+	} else if in.EndedAt != nil {
+		playedAtAny = *in.EndedAt
 	}
 	var winnerIDAny any
-	if winnerID != "" {
-		winnerIDAny = winnerID
+	if in.WinnerID != "" {
+		winnerIDAny = in.WinnerID
 	}
 	var startedAtAny any
-	if startedAt != nil {
-		startedAtAny = *startedAt
+	if in.StartedAt != nil {
+		startedAtAny = *in.StartedAt
 	}
 	var endedAtAny any
-	if endedAt != nil {
-		endedAtAny = *endedAt
+	if in.EndedAt != nil {
+		endedAtAny = *in.EndedAt
 	}
-	if err := tx.QueryRow(ctx, insertMatch, createdBy, playedAtAny, winnerIDAny, format, totalDurationSeconds, turnCount, nullIfEmpty(clientRef), updatedAt, startedAtAny, endedAtAny).Scan(&matchIDUUID); err != nil {
+	var startingSeatAny any
+	if in.StartingSeatIndex != nil {
+		startingSeatAny = *in.StartingSeatIndex
+	}
+	if err := tx.QueryRow(ctx, insertMatch, in.CreatedBy, playedAtAny, winnerIDAny, in.Format, in.TotalDurationSeconds, in.TurnCount, nullIfEmpty(in.ClientRef), in.UpdatedAt, startedAtAny, endedAtAny, startingSeatAny, nullIfEmpty(in.GroupID), nullIfZero(in.BestOf), matchOutcome(in.Outcome), nullIfEmpty(string(in.WinCondition)), nullIfEmpty(in.WinConditionTag), nullIfEmpty(in.WinningCard)).Scan(&matchIDUUID); err != nil {
 		var pgerr *pgconn.PgError
 		if errors.As(err, &pgerr) && pgerr.ConstraintName == "matches_client_ref_uq" {
 			var existingID pgtype.UUID
-			if lookupErr := s.pool.QueryRow(ctx, `SELECT id FROM matches WHERE created_by = $1 AND client_ref = $2`, createdBy, clientRef).Scan(&existingID); lookupErr == nil {
+			if lookupErr := s.pool.QueryRow(ctx, `SELECT id FROM matches WHERE created_by = $1 AND client_ref = $2`, in.CreatedBy, in.ClientRef).Scan(&existingID); lookupErr == nil {
 				return uuidOrEmpty(existingID), false, nil
 			}
 			return "", false, domain.NewValidationError(map[string]string{"client_ref": "already used"})
@@ -87,18 +92,173 @@ func (s *MatchesStore) CreateMatch(ctx context.Context, createdBy string, starte
 	}
 	matchID := uuidOrEmpty(matchIDUUID)
 
+	if err := insertParticipants(ctx, tx, matchID, in.CreatedBy, in.Participants, nil); err != nil {
+		return "", false, err
+	}
+	if err := insertGames(ctx, tx, matchID, in.Games); err != nil {
+		return "", false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", false, fmt.Errorf("commit tx: %w", err)
+	}
+	return matchID, true, nil
+}
+
+func (s *MatchesStore) UpdateMatch(ctx context.Context, matchID string, in domain.MatchInput) (bool, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const q = `
+		UPDATE matches
+		SET winner_id = $3,
+		    format = $4,
+		    total_duration_seconds = $5,
+		    turn_count = $6,
+		    updated_at = $7,
+		    starting_seat_index = $8,
+		    best_of = $9,
+		    outcome = $10,
+		    win_condition = $11,
+		    win_condition_tag = $12,
+		    winning_card = $13
+		WHERE id = $1 AND created_by = $2 AND deleted_at IS NULL AND updated_at < $7
+	`
+	var winnerIDAny any
+	if in.WinnerID != "" {
+		winnerIDAny = in.WinnerID
+	}
+	var startingSeatAny any
+	if in.StartingSeatIndex != nil {
+		startingSeatAny = *in.StartingSeatIndex
+	}
+	tag, err := tx.Exec(ctx, q, matchID, in.CreatedBy, winnerIDAny, in.Format, in.TotalDurationSeconds, in.TurnCount, in.UpdatedAt, startingSeatAny, nullIfZero(in.BestOf), matchOutcome(in.Outcome),
+		nullIfEmpty(string(in.WinCondition)), nullIfEmpty(in.WinConditionTag), nullIfEmpty(in.WinningCard))
+	if err != nil {
+		return false, fmt.Errorf("update match: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	previous, err := participantConfirmations(ctx, tx, matchID)
+	if err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM match_participants WHERE match_id = $1`, matchID); err != nil {
+		return false, fmt.Errorf("delete match participants: %w", err)
+	}
+	if err := insertParticipants(ctx, tx, matchID, in.CreatedBy, in.Participants, previous); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM match_games WHERE match_id = $1`, matchID); err != nil {
+		return false, fmt.Errorf("delete match games: %w", err)
+	}
+	if err := insertGames(ctx, tx, matchID, in.Games); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit tx: %w", err)
+	}
+	return true, nil
+}
+
+// matchOutcome is the stored outcome; matches recorded without one are wins.
+func matchOutcome(outcome domain.MatchOutcome) string {
+	if outcome == "" {
+		return string(domain.MatchOutcomeWin)
+	}
+	return string(outcome)
+}
+
+// participantConfirmation is a registered seat's review, kept across edits that leave the seat's place and
+// team alone, and its deck, kept when an edit does not name one.
+type participantConfirmation struct {
+	place     pgtype.Int4
+	team      pgtype.Int4
+	state     string
+	reason    pgtype.Text
+	updatedAt pgtype.Timestamptz
+	deckID    pgtype.UUID
+}
+
+func participantConfirmations(ctx context.Context, tx pgx.Tx, matchID string) (map[string]participantConfirmation, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT user_id, place, team, confirmation, dispute_reason, confirmation_updated_at, deck_id
+		FROM match_participants
+		WHERE match_id = $1 AND user_id IS NOT NULL
+	`, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("list participant confirmations: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]participantConfirmation)
+	for rows.Next() {
+		var (
+			userID pgtype.UUID
+			c      participantConfirmation
+		)
+		if err := rows.Scan(&userID, &c.place, &c.team, &c.state, &c.reason, &c.updatedAt, &c.deckID); err != nil {
+			return nil, fmt.Errorf("scan participant confirmation: %w", err)
+		}
+		out[uuidOrEmpty(userID)] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list participant confirmations: %w", err)
+	}
+	return out, nil
+}
+
+// insertParticipants writes the seats of a match. Guests and the creator are confirmed up front;
+// other registered players start pending unless previous holds their review for the same place.
+// Registered seats without a deck keep the one previous holds for them.
+func insertParticipants(ctx context.Context, tx pgx.Tx, matchID, createdBy string, participants []domain.MatchParticipantInput, previous map[string]participantConfirmation) error {
 	const insertParticipant = `
 		INSERT INTO match_participants (
 			match_id, seat_index, user_id, guest_name, display_name, place,
-			eliminated_turn_number, eliminated_during_seat_index, total_turn_time_ms, turns_taken
+			eliminated_turn_number, eliminated_during_seat_index, total_turn_time_ms, turns_taken,
+			confirmation, dispute_reason, confirmation_updated_at, guest_id, deck_id, commander_card_ids, team
+		)
+		VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
+			COALESCE($14::uuid, (
+				SELECT a.guest_id FROM guest_aliases a
+				WHERE a.owner_id = $15 AND a.alias = lower(btrim($4::text))
+			)),
+			$16, $17::uuid[], $18
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 	for _, participant := range participants {
 		var userIDAny any
 		if participant.UserID != "" {
 			userIDAny = participant.UserID
 		}
+		confirmation := domain.MatchConfirmationConfirmed
+		var disputeReason pgtype.Text
+		var confirmedAt pgtype.Timestamptz
+		var deckIDAny any
+		if participant.DeckID != "" {
+			deckIDAny = participant.DeckID
+		} else if prev, ok := previous[participant.UserID]; ok && participant.UserID != "" && prev.deckID.Valid {
+			deckIDAny = prev.deckID
+		}
+		if participant.UserID != "" && participant.UserID != createdBy {
+			confirmation = domain.MatchConfirmationPending
+			if prev, ok := previous[participant.UserID]; ok && int(prev.place.Int32) == participant.Place && int(prev.team.Int32) == participant.Team {
+				confirmation = domain.MatchConfirmation(prev.state)
+				disputeReason = prev.reason
+				confirmedAt = prev.updatedAt
+			}
+		}
+		commanderIDs := participant.CommanderIDs
+		if commanderIDs == nil {
+			commanderIDs = []string{}
+		}
 		var guestNameAny any
 		if participant.GuestName != "" {
 			guestNameAny = participant.GuestName
@@ -119,6 +279,10 @@ func (s *MatchesStore) CreateMatch(ctx context.Context, createdBy string, starte
 		if participant.TurnsTaken != nil {
 			turnsTakenAny = *participant.TurnsTaken
 		}
+		var teamAny any
+		if participant.Team > 0 {
+			teamAny = participant.Team
+		}
 		if _, err := tx.Exec(
 			ctx,
 			insertParticipant,
@@ -127,123 +291,251 @@ func (s *MatchesStore) CreateMatch(ctx context.Context, createdBy string, starte
 			userIDAny,
 			guestNameAny,
 			participant.DisplayName,
-			participant.Place,
+			nullIfZero(participant.Place),
 			eliminatedTurnAny,
 			eliminatedDuringAny,
 			totalTurnTimeAny,
 			turnsTakenAny,
+			string(confirmation),
+			disputeReason,
+			confirmedAt,
+			nullIfEmpty(participant.GuestID),
+			createdBy,
+			deckIDAny,
+			commanderIDs,
+			teamAny,
 		); err != nil {
 			var pgerr *pgconn.PgError
 			if errors.As(err, &pgerr) && pgerr.Code == "23503" {
-				return "", false, domain.ErrValidation
+				return domain.ErrValidation
 			}
-			return "", false, fmt.Errorf("insert match participant: %w", err)
+			return fmt.Errorf("insert match participant: %w", err)
 		}
 	}
-
-	if err := tx.Commit(ctx); err != nil {
-		return "", false, fmt.Errorf("commit tx: %w", err)
-	}
-	return matchID, true, nil
+	return nil
 }
 
-func (s *MatchesStore) ListMatchesForUser(ctx context.Context, userID string, limit int) ([]domain.Match, error) {
-	if limit <= 0 || limit > 100 {
+func (s *MatchesStore) ListMatchesForUser(ctx context.Context, userID string, filter domain.MatchListFilter) ([]domain.Match, error) {
+	limit := filter.Limit
+	if limit <= 0 {
 		limit = 25
 	}
 
-	// List matches where user participated (new participants table or legacy match_players).
-	const q = `
-		SELECT m.id, m.created_by, m.created_at, m.updated_at, m.started_at, m.ended_at, m.played_at, m.winner_id,
-		       m.format, m.total_duration_seconds, m.turn_count, m.client_ref
-		FROM matches m
-		WHERE
-			EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = m.id AND p.user_id = $1)
-			OR (
-				NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = m.id)
-				AND EXISTS (SELECT 1 FROM match_players mp WHERE mp.match_id = m.id AND mp.user_id = $1)
-			)
-		ORDER BY m.created_at DESC
-		LIMIT $2
+	// List matches where user participated (new participants table or legacy match_players),
+	// newest first by played_at (falling back to created_at) with id as the keyset tiebreaker.
+	// A group filter lists every match recorded in the group instead; the caller checks membership.
+	scope := `
+		JOIN participants me ON me.match_id = m.id AND me.user_id = $1
+		WHERE m.deleted_at IS NULL
+		  AND $11::uuid IS NULL`
+	if filter.GroupID != "" {
+		scope = `
+		LEFT JOIN participants me ON me.match_id = m.id AND me.user_id = $1
+		WHERE m.deleted_at IS NULL
+		  AND m.group_id = $11`
+	}
+	q := participantsCTE + `
+		SELECT ` + matchColumns + `
+		FROM matches m` + scope + `
+		  AND ($2::text IS NULL OR m.format = $2)
+		  AND ($3::timestamptz IS NULL OR COALESCE(m.played_at, m.created_at) >= $3)
+		  AND ($4::timestamptz IS NULL OR COALESCE(m.played_at, m.created_at) < $4)
+		  AND ($5::uuid IS NULL OR EXISTS (SELECT 1 FROM participants o WHERE o.match_id = m.id AND o.user_id = $5))
+		  AND ($6::text IS NULL OR EXISTS (SELECT 1 FROM participants o WHERE o.match_id = m.id AND lower(o.guest_name) = lower($6)))
+		  AND (
+		    $7::text IS NULL
+		    OR ($7 = 'won' AND me.place = 1 AND m.outcome <> 'draw')
+		    OR ($7 = 'lost' AND me.place > 1)
+		  )
+		  AND ($8::timestamptz IS NULL OR (COALESCE(m.played_at, m.created_at), m.id) < ($8, $9::uuid))
+		ORDER BY COALESCE(m.played_at, m.created_at) DESC, m.id DESC
+		LIMIT $10
 	`
 
-	rows, err := s.pool.Query(ctx, q, userID, limit)
+	var beforeTime, beforeID any
+	if filter.BeforeTime != nil && filter.BeforeID != "" {
+		beforeTime = *filter.BeforeTime
+		beforeID = filter.BeforeID
+	}
+	var from, to any
+	if filter.From != nil {
+		from = *filter.From
+	}
+	if filter.To != nil {
+		to = *filter.To
+	}
+
+	rows, err := s.pool.Query(
+		ctx,
+		q,
+		userID,
+		nullIfEmpty(string(filter.Format)),
+		from,
+		to,
+		nullIfEmpty(filter.OpponentID),
+		nullIfEmpty(filter.GuestName),
+		nullIfEmpty(string(filter.Result)),
+		beforeTime,
+		beforeID,
+		limit,
+		nullIfEmpty(filter.GroupID),
+	)
 	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == "22P02" {
+			return nil, domain.ErrValidation
+		}
 		return nil, fmt.Errorf("list matches: %w", err)
 	}
 	defer rows.Close()
 
-	type matchRow struct {
-		idUUID       pgtype.UUID
-		createdBy    pgtype.UUID
-		createdAt    time.Time
-		updatedAt    time.Time
-		startedAt    pgtype.Timestamptz
-		endedAt      pgtype.Timestamptz
-		playedAt     pgtype.Timestamptz
-		winnerID     pgtype.UUID
-		format       pgtype.Text
-		durationSecs int
-		turnCount    int
-		clientRef    pgtype.Text
-	}
-
-	var tmp []matchRow
+	var out []domain.Match
 	for rows.Next() {
-		var r matchRow
-		if err := rows.Scan(&r.idUUID, &r.createdBy, &r.createdAt, &r.updatedAt, &r.startedAt, &r.endedAt, &r.playedAt, &r.winnerID, &r.format, &r.durationSecs, &r.turnCount, &r.clientRef); err != nil {
+		m, err := scanMatch(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan match: %w", err)
 		}
-		tmp = append(tmp, r)
+		out = append(out, m)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("list matches: %w", err)
 	}
 
-	out := make([]domain.Match, 0, len(tmp))
-	for _, r := range tmp {
-		matchID := uuidOrEmpty(r.idUUID)
-		winnerID := uuidOrEmpty(r.winnerID)
-		players, err := s.listPlayers(ctx, matchID, winnerID)
-		if err != nil {
+	for i := range out {
+		if err := s.fillMatch(ctx, &out[i]); err != nil {
 			return nil, err
 		}
-
-		out = append(out, domain.Match{
-			ID:                   matchID,
-			CreatedBy:            uuidOrEmpty(r.createdBy),
-			CreatedAt:            r.createdAt,
-			UpdatedAt:            r.updatedAt,
-			ClientMatchID:        textOrEmpty(r.clientRef),
-			StartedAt:            timestamptzPtr(r.startedAt),
-			EndedAt:              timestamptzPtr(r.endedAt),
-			PlayedAt:             timestamptzPtr(r.playedAt),
-			WinnerID:             winnerID,
-			Format:               normalizeFormat(r.format),
-			TotalDurationSeconds: r.durationSecs,
-			TurnCount:            r.turnCount,
-			Players:              players,
-		})
 	}
-
+	if out == nil {
+		out = []domain.Match{}
+	}
 	return out, nil
 }
 
 func (s *MatchesStore) GetMatchForUser(ctx context.Context, userID, matchID string) (domain.Match, error) {
 	const q = `
-		SELECT m.id, m.created_by, m.created_at, m.updated_at, m.started_at, m.ended_at, m.played_at, m.winner_id,
-		       m.format, m.total_duration_seconds, m.turn_count, m.client_ref
+		SELECT ` + matchColumns + `
 		FROM matches m
 		WHERE m.id = $1
+		  AND m.deleted_at IS NULL
 		  AND (
 		    EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = m.id AND p.user_id = $2)
 		    OR (
 		      NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = m.id)
 		      AND EXISTS (SELECT 1 FROM match_players mp WHERE mp.match_id = m.id AND mp.user_id = $2)
 		    )
+		    OR EXISTS (SELECT 1 FROM group_members gm WHERE gm.group_id = m.group_id AND gm.user_id = $2)
 		  )
 		LIMIT 1
 	`
+	m, err := scanMatch(s.pool.QueryRow(ctx, q, matchID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Match{}, domain.ErrNotFound
+		}
+		return domain.Match{}, fmt.Errorf("get match: %w", err)
+	}
+
+	if err := s.fillMatch(ctx, &m); err != nil {
+		return domain.Match{}, err
+	}
+	return m, nil
+}
+
+// GetMatchByClientRef also returns deleted matches so offline clients see the tombstone.
+func (s *MatchesStore) GetMatchByClientRef(ctx context.Context, createdBy, clientRef string) (domain.Match, error) {
+	if strings.TrimSpace(clientRef) == "" {
+		return domain.Match{}, domain.ErrNotFound
+	}
+
+	const q = `
+		SELECT ` + matchColumns + `
+		FROM matches m
+		WHERE m.created_by = $1 AND m.client_ref = $2
+		LIMIT 1
+	`
+	m, err := scanMatch(s.pool.QueryRow(ctx, q, createdBy, clientRef))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Match{}, domain.ErrNotFound
+		}
+		return domain.Match{}, fmt.Errorf("get match by client_ref: %w", err)
+	}
+
+	if err := s.fillMatch(ctx, &m); err != nil {
+		return domain.Match{}, err
+	}
+	return m, nil
+}
+
+func (s *MatchesStore) DeleteMatch(ctx context.Context, matchID, createdBy string, deletedAt time.Time) error {
+	const q = `
+		UPDATE matches
+		SET deleted_at = $3,
+		    updated_at = GREATEST(updated_at, $3)
+		WHERE id = $1 AND created_by = $2 AND deleted_at IS NULL
+	`
+	tag, err := s.pool.Exec(ctx, q, matchID, createdBy, deletedAt)
+	if err != nil {
+		return fmt.Errorf("delete match: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// SetParticipantConfirmation records a registered participant's review of a live match.
+func (s *MatchesStore) SetParticipantConfirmation(ctx context.Context, matchID, userID string, state domain.MatchConfirmation, reason string, at time.Time) error {
+	const q = `
+		UPDATE match_participants p
+		SET confirmation = $3,
+		    dispute_reason = $4,
+		    confirmation_updated_at = $5
+		FROM matches m
+		WHERE p.match_id = $1 AND p.user_id = $2
+		  AND m.id = p.match_id AND m.deleted_at IS NULL
+	`
+	tag, err := s.pool.Exec(ctx, q, matchID, userID, string(state), nullIfEmpty(reason), at)
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("set participant confirmation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// SetParticipantDeck records the deck a registered participant played; an empty deckID clears it.
+func (s *MatchesStore) SetParticipantDeck(ctx context.Context, matchID, userID, deckID string) error {
+	const q = `
+		UPDATE match_participants p
+		SET deck_id = $3
+		FROM matches m
+		WHERE p.match_id = $1 AND p.user_id = $2
+		  AND m.id = p.match_id AND m.deleted_at IS NULL
+	`
+	tag, err := s.pool.Exec(ctx, q, matchID, userID, nullIfEmpty(deckID))
+	if err != nil {
+		if isMissingRow(err) {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("set participant deck: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+const matchColumns = `m.id, m.created_by, m.created_at, m.updated_at, m.started_at, m.ended_at, m.played_at, m.winner_id,
+		       m.format, m.total_duration_seconds, m.turn_count, m.client_ref, m.deleted_at, m.starting_seat_index, m.group_id, m.best_of, m.outcome,
+		       m.win_condition, m.win_condition_tag, m.winning_card`
+
+func scanMatch(row pgx.Row, extra ...any) (domain.Match, error) {
 	var (
 		idUUID       pgtype.UUID
 		createdBy    pgtype.UUID
@@ -257,8 +549,16 @@ func (s *MatchesStore) GetMatchForUser(ctx context.Context, userID, matchID stri
 		durationSecs int
 		turnCount    int
 		clientRef    pgtype.Text
+		deletedAt    pgtype.Timestamptz
+		startingSeat pgtype.Int4
+		groupID      pgtype.UUID
+		bestOf       pgtype.Int4
+		outcome      string
+		winCondition pgtype.Text
+		winTag       pgtype.Text
+		winningCard  pgtype.Text
 	)
-	if err := s.pool.QueryRow(ctx, q, matchID, userID).Scan(
+	dest := []any{
 		&idUUID,
 		&createdBy,
 		&createdAt,
@@ -271,22 +571,20 @@ func (s *MatchesStore) GetMatchForUser(ctx context.Context, userID, matchID stri
 		&durationSecs,
 		&turnCount,
 		&clientRef,
-	); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.Match{}, domain.ErrNotFound
-		}
-		return domain.Match{}, fmt.Errorf("get match: %w", err)
+		&deletedAt,
+		&startingSeat,
+		&groupID,
+		&bestOf,
+		&outcome,
+		&winCondition,
+		&winTag,
+		&winningCard,
 	}
-
-	matchID = uuidOrEmpty(idUUID)
-	winner := uuidOrEmpty(winnerID)
-	players, err := s.listPlayers(ctx, matchID, winner)
-	if err != nil {
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return domain.Match{}, err
 	}
-
 	return domain.Match{
-		ID:                   matchID,
+		ID:                   uuidOrEmpty(idUUID),
 		CreatedBy:            uuidOrEmpty(createdBy),
 		CreatedAt:            createdAt,
 		UpdatedAt:            updatedAt,
@@ -294,82 +592,96 @@ func (s *MatchesStore) GetMatchForUser(ctx context.Context, userID, matchID stri
 		StartedAt:            timestamptzPtr(startedAt),
 		EndedAt:              timestamptzPtr(endedAt),
 		PlayedAt:             timestamptzPtr(playedAt),
-		WinnerID:             winner,
+		WinnerID:             uuidOrEmpty(winnerID),
+		Outcome:              domain.MatchOutcome(outcome),
+		WinCondition:         domain.WinCondition(textOrEmpty(winCondition)),
+		WinConditionTag:      textOrEmpty(winTag),
+		WinningCard:          textOrEmpty(winningCard),
 		Format:               normalizeFormat(format),
 		TotalDurationSeconds: durationSecs,
 		TurnCount:            turnCount,
-		Players:              players,
+		StartingSeatIndex:    int4Ptr(startingSeat),
+		GroupID:              uuidOrEmpty(groupID),
+		BestOf:               int4Ptr(bestOf),
+		DeletedAt:            timestamptzPtr(deletedAt),
 	}, nil
 }
 
-func (s *MatchesStore) GetMatchByClientRef(ctx context.Context, createdBy, clientRef string) (domain.Match, error) {
-	if strings.TrimSpace(clientRef) == "" {
-		return domain.Match{}, domain.ErrNotFound
+// fillMatch loads the players and games of a scanned match.
+func (s *MatchesStore) fillMatch(ctx context.Context, m *domain.Match) error {
+	players, err := s.listPlayers(ctx, m.ID, m.WinnerID)
+	if err != nil {
+		return err
+	}
+	games, err := s.listGames(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+	if m.Outcome != domain.MatchOutcomeWin {
+		// Players sharing first place in a draw did not win.
+		for i := range players {
+			players[i].IsWinner = false
+		}
 	}
+	m.Players = players
+	m.Games = games
+	return nil
+}
 
+func (s *MatchesStore) listGames(ctx context.Context, matchID string) ([]domain.MatchGame, error) {
 	const q = `
-		SELECT m.id, m.created_by, m.created_at, m.updated_at, m.started_at, m.ended_at, m.played_at, m.winner_id,
-		       m.format, m.total_duration_seconds, m.turn_count, m.client_ref
-		FROM matches m
-		WHERE m.created_by = $1 AND m.client_ref = $2
-		LIMIT 1
+		SELECT game_number, winner_seat_index, on_play_seat_index, mulligans, duration_seconds
+		FROM match_games
+		WHERE match_id = $1
+		ORDER BY game_number
 	`
-	var (
-		idUUID        pgtype.UUID
-		createdByUUID pgtype.UUID
-		createdAt     time.Time
-		updatedAt     time.Time
-		startedAt     pgtype.Timestamptz
-		endedAt       pgtype.Timestamptz
-		playedAt      pgtype.Timestamptz
-		winnerID      pgtype.UUID
-		format        pgtype.Text
-		durationSecs  int
-		turnCount     int
-		clientRefText pgtype.Text
-	)
-	if err := s.pool.QueryRow(ctx, q, createdBy, clientRef).Scan(
-		&idUUID,
-		&createdByUUID,
-		&createdAt,
-		&updatedAt,
-		&startedAt,
-		&endedAt,
-		&playedAt,
-		&winnerID,
-		&format,
-		&durationSecs,
-		&turnCount,
-		&clientRefText,
-	); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.Match{}, domain.ErrNotFound
-		}
-		return domain.Match{}, fmt.Errorf("get match by client_ref: %w", err)
+	rows, err := s.pool.Query(ctx, q, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("list match games: %w", err)
 	}
+	defer rows.Close()
 
-	matchID := uuidOrEmpty(idUUID)
-	winner := uuidOrEmpty(winnerID)
-	players, err := s.listPlayers(ctx, matchID, winner)
-	if err != nil {
-		return domain.Match{}, err
+	var out []domain.MatchGame
+	for rows.Next() {
+		var (
+			g      domain.MatchGame
+			onPlay pgtype.Int4
+		)
+		if err := rows.Scan(&g.Number, &g.WinnerSeatIndex, &onPlay, &g.Mulligans, &g.DurationSeconds); err != nil {
+			return nil, fmt.Errorf("scan match game: %w", err)
+		}
+		g.OnPlaySeatIndex = int4Ptr(onPlay)
+		if len(g.Mulligans) == 0 {
+			g.Mulligans = nil
+		}
+		out = append(out, g)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list match games: %w", err)
+	}
+	return out, nil
+}
 
-	return domain.Match{
-		ID:                   matchID,
-		CreatedBy:            uuidOrEmpty(createdByUUID),
-		CreatedAt:            createdAt,
-		UpdatedAt:            updatedAt,
-		ClientMatchID:        textOrEmpty(clientRefText),
-		StartedAt:            timestamptzPtr(startedAt),
-		EndedAt:              timestamptzPtr(endedAt),
-		PlayedAt:             timestamptzPtr(playedAt),
-		WinnerID:             winner,
-		Format:               normalizeFormat(format),
-		TotalDurationSeconds: durationSecs,
-		TurnCount:            turnCount,
-		Players:              players,
-	}, nil
+// insertGames writes the games of a best-of-N match.
+func insertGames(ctx context.Context, tx pgx.Tx, matchID string, games []domain.MatchGame) error {
+	const q = `
+		INSERT INTO match_games (match_id, game_number, winner_seat_index, on_play_seat_index, mulligans, duration_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for _, g := range games {
+		var onPlayAny any
+		if g.OnPlaySeatIndex != nil {
+			onPlayAny = *g.OnPlaySeatIndex
+		}
+		mulligans := g.Mulligans
+		if mulligans == nil {
+			mulligans = []int{}
+		}
+		if _, err := tx.Exec(ctx, q, matchID, g.Number, g.WinnerSeatIndex, onPlayAny, mulligans, g.DurationSeconds); err != nil {
+			return fmt.Errorf("insert match game: %w", err)
+		}
+	}
+	return nil
 }
 
 func (s *MatchesStore) listPlayers(ctx context.Context, matchID string, winnerID string) ([]domain.MatchPlayer, error) {
@@ -385,9 +697,23 @@ func (s *MatchesStore) listPlayers(ctx context.Context, matchID string, winnerID
 			p.eliminated_turn_number,
 			p.eliminated_during_seat_index,
 			p.total_turn_time_ms,
-			p.turns_taken
+			p.turns_taken,
+			p.confirmation,
+			p.dispute_reason,
+			p.guest_id,
+			p.deck_id,
+			d.name,
+			p.commander_card_ids::text[],
+			p.team,
+			ARRAY(
+				SELECT COALESCE(c.name, '')
+				FROM unnest(p.commander_card_ids) WITH ORDINALITY AS cc(id, n)
+				LEFT JOIN cards c ON c.id = cc.id
+				ORDER BY cc.n
+			)
 		FROM match_participants p
 		LEFT JOIN users u ON u.id = p.user_id
+		LEFT JOIN decks d ON d.id = p.deck_id
 		WHERE p.match_id = $1
 		ORDER BY p.place ASC, p.seat_index ASC
 	`
@@ -407,13 +733,21 @@ func (s *MatchesStore) listPlayers(ctx context.Context, matchID string, winnerID
 			userDisplayName  pgtype.Text
 			guestName        pgtype.Text
 			displayName      string
-			place            int
+			place            pgtype.Int4
 			eliminatedTurn   pgtype.Int4
 			eliminatedDuring pgtype.Int4
 			totalTurnTimeMs  pgtype.Int8
 			turnsTaken       pgtype.Int4
+			confirmation     string
+			disputeReason    pgtype.Text
+			guestID          pgtype.UUID
+			deckID           pgtype.UUID
+			deckName         pgtype.Text
+			commanderIDs     []string
+			team             pgtype.Int4
+			commanderNames   []string
 		)
-		if err := rows.Scan(&seatIndex, &userID, &username, &userDisplayName, &guestName, &displayName, &place, &eliminatedTurn, &eliminatedDuring, &totalTurnTimeMs, &turnsTaken); err != nil {
+		if err := rows.Scan(&seatIndex, &userID, &username, &userDisplayName, &guestName, &displayName, &place, &eliminatedTurn, &eliminatedDuring, &totalTurnTimeMs, &turnsTaken, &confirmation, &disputeReason, &guestID, &deckID, &deckName, &commanderIDs, &team, &commanderNames); err != nil {
 			return nil, fmt.Errorf("scan match participant: %w", err)
 		}
 		id := uuidOrEmpty(userID)
@@ -423,7 +757,7 @@ func (s *MatchesStore) listPlayers(ctx context.Context, matchID string, winnerID
 			display = textOrEmpty(userDisplayName)
 		}
 
-		placeCopy := place
+		placeCopy := int4Ptr(place)
 		seatCopy := seatIndex
 		elimTurn := int4Ptr(eliminatedTurn)
 		elimDuring := int4Ptr(eliminatedDuring)
@@ -437,23 +771,40 @@ func (s *MatchesStore) listPlayers(ctx context.Context, matchID string, winnerID
 		isWinner := false
 		if winnerID != "" {
 			isWinner = id != "" && id == winnerID
-		} else if place == 1 {
+		} else if place.Valid && place.Int32 == 1 {
 			isWinner = true
 		}
 
+		var state domain.MatchConfirmation
+		if id != "" {
+			state = domain.MatchConfirmation(confirmation)
+		}
+
+		var commanders []domain.CardSummary
+		for i, cardID := range commanderIDs {
+			commanders = append(commanders, domain.CardSummary{ID: cardID, Name: commanderNames[i]})
+		}
+
 		out = append(out, domain.MatchPlayer{
 			User:             domain.UserSummary{ID: id, Username: name, DisplayName: display},
 			IsWinner:         isWinner,
-			Rank:             &placeCopy,
+			Rank:             placeCopy,
 			SeatIndex:        &seatCopy,
 			GuestName:        textOrEmpty(guestName),
+			GuestID:          uuidOrEmpty(guestID),
+			DeckID:           uuidOrEmpty(deckID),
+			DeckName:         textOrEmpty(deckName),
+			Commanders:       commanders,
 			DisplayName:      display,
-			Place:            &placeCopy,
+			Place:            placeCopy,
+			Team:             int4Ptr(team),
 			EliminatedTurn:   elimTurn,
 			EliminationTurn:  elimTurn,
 			EliminatedDuring: elimDuring,
 			TotalTurnTimeMs:  totalMs,
 			TurnsTaken:       turns,
+			Confirmation:     state,
+			DisputeReason:    textOrEmpty(disputeReason),
 		})
 	}
 	if err := rows.Err(); err != nil {
@@ -509,22 +860,63 @@ func (s *MatchesStore) listLegacyPlayers(ctx context.Context, matchID string, wi
 	return out, nil
 }
 
-func (s *MatchesStore) StatsSummary(ctx context.Context, userID string) (domain.StatsSummary, error) {
-	const q = `
+// participantsCTE unions match_participants with legacy match_players and skips deleted matches.
+// team is NULL outside team matches; two participants are opponents unless they share a team.
+// Place 1 in a match with outcome 'draw' is a share of a draw, not a win.
+const participantsCTE = `
 		WITH participants AS (
-			SELECT match_id, user_id, guest_name, place
-			FROM match_participants
+			SELECT p.match_id, p.user_id, p.guest_name, p.guest_id, p.place, p.seat_index, p.team, m.outcome
+			FROM match_participants p
+			JOIN matches m ON m.id = p.match_id
+			WHERE m.deleted_at IS NULL
 			UNION ALL
-			SELECT mp.match_id, mp.user_id, NULL::text AS guest_name,
+			SELECT mp.match_id, mp.user_id, NULL::text AS guest_name, NULL::uuid AS guest_id,
 			       CASE
 			         WHEN m.winner_id = mp.user_id THEN 1
 			         WHEN m.winner_id IS NOT NULL THEN 2
 			         ELSE NULL
-			       END AS place
+			       END AS place,
+			       NULL::int AS seat_index, NULL::int AS team, m.outcome
 			FROM match_players mp
 			JOIN matches m ON m.id = mp.match_id
-			WHERE NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id)
-		),
+			WHERE m.deleted_at IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id)
+		)`
+
+// confirmedOnlyFilter keeps a match (aliased m) unless the user in $1 counts confirmed matches only
+// and some registered participant has not confirmed it. Legacy matches have no seats to confirm.
+const confirmedOnlyFilter = `
+			  AND (
+			    NOT COALESCE((SELECT u.stats_confirmed_only FROM users u WHERE u.id = $1), false)
+			    OR NOT EXISTS (
+			      SELECT 1 FROM match_participants c
+			      WHERE c.match_id = m.id AND c.confirmation <> 'confirmed'
+			    )
+			  )`
+
+// statsParticipantsCTE is participantsCTE for per-user stats queries where $1 is the viewing user.
+const statsParticipantsCTE = `
+		WITH participants AS (
+			SELECT p.match_id, p.user_id, p.guest_name, p.guest_id, p.place, p.seat_index, p.team, m.outcome
+			FROM match_participants p
+			JOIN matches m ON m.id = p.match_id
+			WHERE m.deleted_at IS NULL` + confirmedOnlyFilter + `
+			UNION ALL
+			SELECT mp.match_id, mp.user_id, NULL::text AS guest_name, NULL::uuid AS guest_id,
+			       CASE
+			         WHEN m.winner_id = mp.user_id THEN 1
+			         WHEN m.winner_id IS NOT NULL THEN 2
+			         ELSE NULL
+			       END AS place,
+			       NULL::int AS seat_index, NULL::int AS team, m.outcome
+			FROM match_players mp
+			JOIN matches m ON m.id = mp.match_id
+			WHERE m.deleted_at IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id)
+		)`
+
+func (s *MatchesStore) StatsSummary(ctx context.Context, userID string) (domain.StatsSummary, error) {
+	const q = statsParticipantsCTE + `,
 		completed AS (
 			SELECT DISTINCT match_id FROM participants WHERE place = 1
 		),
@@ -536,18 +928,19 @@ func (s *MatchesStore) StatsSummary(ctx context.Context, userID string) (domain.
 		)
 		SELECT
 			COUNT(*)::int AS matches_played,
-			COALESCE(SUM(CASE WHEN p.place = 1 THEN 1 ELSE 0 END), 0)::int AS wins,
+			COALESCE(SUM(CASE WHEN p.place = 1 AND p.outcome <> 'draw' THEN 1 ELSE 0 END), 0)::int AS wins,
+			COALESCE(SUM(CASE WHEN p.place = 1 AND p.outcome = 'draw' THEN 1 ELSE 0 END), 0)::int AS draws,
 			COALESCE(SUM(CASE WHEN m.turn_count > 0 THEN m.total_duration_seconds ELSE 0 END), 0)::int AS total_seconds,
 			COALESCE(SUM(CASE WHEN m.turn_count > 0 THEN m.turn_count ELSE 0 END), 0)::int AS total_turns
 		FROM user_matches um
 		JOIN matches m ON m.id = um.match_id
 		JOIN participants p ON p.match_id = um.match_id AND p.user_id = $1
 	`
-	var played, wins, totalSeconds, totalTurns int
-	if err := s.pool.QueryRow(ctx, q, userID).Scan(&played, &wins, &totalSeconds, &totalTurns); err != nil {
+	var played, wins, draws, totalSeconds, totalTurns int
+	if err := s.pool.QueryRow(ctx, q, userID).Scan(&played, &wins, &draws, &totalSeconds, &totalTurns); err != nil {
 		return domain.StatsSummary{}, fmt.Errorf("stats summary: %w", err)
 	}
-	losses := played - wins
+	losses := played - wins - draws
 	avgTurn := 0
 	if totalTurns > 0 {
 		avgTurn = totalSeconds / totalTurns
@@ -569,6 +962,18 @@ func (s *MatchesStore) StatsSummary(ctx context.Context, userID string) (domain.
 	if err != nil {
 		return domain.StatsSummary{}, err
 	}
+	winConditions, winConditionsByFormat, err := s.winConditionStats(ctx, userID)
+	if err != nil {
+		return domain.StatsSummary{}, err
+	}
+	if len(winConditionsByFormat) > 0 && byFormat == nil {
+		byFormat = make(map[string]domain.StatsSummary, len(winConditionsByFormat))
+	}
+	for format, wc := range winConditionsByFormat {
+		stats := byFormat[format]
+		stats.WinConditions = wc
+		byFormat[format] = stats
+	}
 	winPct := 0.0
 	if played > 0 {
 		winPct = float64(wins) / float64(played)
@@ -578,31 +983,19 @@ func (s *MatchesStore) StatsSummary(ctx context.Context, userID string) (domain.
 		MatchesPlayed:     played,
 		Wins:              wins,
 		Losses:            losses,
+		Draws:             draws,
 		WinPct:            winPct,
 		AvgTurnSeconds:    avgTurn,
 		ByFormat:          byFormat,
 		MostOftenBeat:     mostBeat,
 		MostOftenBeatsYou: mostBeats,
 		GuestHeadToHead:   guestHeadToHead,
+		WinConditions:     winConditions,
 	}, nil
 }
 
 func (s *MatchesStore) statsSummaryByFormat(ctx context.Context, userID string) (map[string]domain.StatsSummary, error) {
-	const q = `
-		WITH participants AS (
-			SELECT match_id, user_id, guest_name, place
-			FROM match_participants
-			UNION ALL
-			SELECT mp.match_id, mp.user_id, NULL::text AS guest_name,
-			       CASE
-			         WHEN m.winner_id = mp.user_id THEN 1
-			         WHEN m.winner_id IS NOT NULL THEN 2
-			         ELSE NULL
-			       END AS place
-			FROM match_players mp
-			JOIN matches m ON m.id = mp.match_id
-			WHERE NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id)
-		),
+	const q = statsParticipantsCTE + `,
 		completed AS (
 			SELECT DISTINCT match_id FROM participants WHERE place = 1
 		),
@@ -615,7 +1008,8 @@ func (s *MatchesStore) statsSummaryByFormat(ctx context.Context, userID string)
 		SELECT
 			m.format,
 			COUNT(*)::int AS matches_played,
-			COALESCE(SUM(CASE WHEN p.place = 1 THEN 1 ELSE 0 END), 0)::int AS wins,
+			COALESCE(SUM(CASE WHEN p.place = 1 AND p.outcome <> 'draw' THEN 1 ELSE 0 END), 0)::int AS wins,
+			COALESCE(SUM(CASE WHEN p.place = 1 AND p.outcome = 'draw' THEN 1 ELSE 0 END), 0)::int AS draws,
 			COALESCE(SUM(CASE WHEN m.turn_count > 0 THEN m.total_duration_seconds ELSE 0 END), 0)::int AS total_seconds,
 			COALESCE(SUM(CASE WHEN m.turn_count > 0 THEN m.turn_count ELSE 0 END), 0)::int AS total_turns
 		FROM user_matches um
@@ -636,10 +1030,11 @@ func (s *MatchesStore) statsSummaryByFormat(ctx context.Context, userID string)
 			formatText   pgtype.Text
 			played       int
 			wins         int
+			draws        int
 			totalSeconds int
 			totalTurns   int
 		)
-		if err := rows.Scan(&formatText, &played, &wins, &totalSeconds, &totalTurns); err != nil {
+		if err := rows.Scan(&formatText, &played, &wins, &draws, &totalSeconds, &totalTurns); err != nil {
 			return nil, fmt.Errorf("scan stats by format: %w", err)
 		}
 		avgTurn := 0
@@ -647,11 +1042,12 @@ func (s *MatchesStore) statsSummaryByFormat(ctx context.Context, userID string)
 			avgTurn = totalSeconds / totalTurns
 		}
 		format := string(normalizeFormat(formatText))
-		losses := played - wins
+		losses := played - wins - draws
 		out[format] = domain.StatsSummary{
 			MatchesPlayed:  played,
 			Wins:           wins,
 			Losses:         losses,
+			Draws:          draws,
 			AvgTurnSeconds: avgTurn,
 		}
 	}
@@ -665,31 +1061,18 @@ func (s *MatchesStore) statsSummaryByFormat(ctx context.Context, userID string)
 }
 
 func (s *MatchesStore) mostOftenBeat(ctx context.Context, userID string) (*domain.OpponentStat, error) {
-	const q = `
-		WITH participants AS (
-			SELECT match_id, user_id, guest_name, place
-			FROM match_participants
-			UNION ALL
-			SELECT mp.match_id, mp.user_id, NULL::text AS guest_name,
-			       CASE
-			         WHEN m.winner_id = mp.user_id THEN 1
-			         WHEN m.winner_id IS NOT NULL THEN 2
-			         ELSE NULL
-			       END AS place
-			FROM match_players mp
-			JOIN matches m ON m.id = mp.match_id
-			WHERE NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id)
-		),
+	const q = statsParticipantsCTE + `,
 		winners AS (
-			SELECT match_id
+			SELECT match_id, team
 			FROM participants
-			WHERE user_id = $1 AND place = 1
+			WHERE user_id = $1 AND place = 1 AND outcome <> 'draw'
 		)
 		SELECT u.id, u.username, COUNT(*)::int AS wins
 		FROM participants p
 		JOIN winners w ON w.match_id = p.match_id
 		JOIN users u ON u.id = p.user_id
 		WHERE p.user_id <> $1
+		  AND (p.team IS NULL OR p.team <> w.team)
 		GROUP BY u.id, u.username
 		ORDER BY wins DESC, u.username ASC
 		LIMIT 1
@@ -711,31 +1094,20 @@ func (s *MatchesStore) mostOftenBeat(ctx context.Context, userID string) (*domai
 }
 
 func (s *MatchesStore) mostOftenBeatsYou(ctx context.Context, userID string) (*domain.OpponentStat, error) {
-	const q = `
-		WITH participants AS (
-			SELECT match_id, user_id, guest_name, place
-			FROM match_participants
-			UNION ALL
-			SELECT mp.match_id, mp.user_id, NULL::text AS guest_name,
-			       CASE
-			         WHEN m.winner_id = mp.user_id THEN 1
-			         WHEN m.winner_id IS NOT NULL THEN 2
-			         ELSE NULL
-			       END AS place
-			FROM match_players mp
-			JOIN matches m ON m.id = mp.match_id
-			WHERE NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id)
-		),
+	const q = statsParticipantsCTE + `,
 		winners AS (
-			SELECT match_id, user_id
+			SELECT match_id, user_id, team
 			FROM participants
-			WHERE place = 1 AND user_id IS NOT NULL
+			WHERE place = 1 AND outcome <> 'draw' AND user_id IS NOT NULL
 		)
 		SELECT u.id, u.username, COUNT(*)::int AS wins
 		FROM winners w
 		JOIN users u ON u.id = w.user_id
 		WHERE w.user_id <> $1
-		  AND EXISTS (SELECT 1 FROM participants p WHERE p.match_id = w.match_id AND p.user_id = $1)
+		  AND EXISTS (
+		    SELECT 1 FROM participants p
+		    WHERE p.match_id = w.match_id AND p.user_id = $1 AND (p.team IS NULL OR p.team <> w.team)
+		  )
 		GROUP BY u.id, u.username
 		ORDER BY wins DESC, u.username ASC
 		LIMIT 1
@@ -757,21 +1129,7 @@ func (s *MatchesStore) mostOftenBeatsYou(ctx context.Context, userID string) (*d
 }
 
 func (s *MatchesStore) guestHeadToHead(ctx context.Context, userID string) ([]domain.GuestHeadToHeadStat, error) {
-	const q = `
-		WITH participants AS (
-			SELECT match_id, user_id, guest_name, place
-			FROM match_participants
-			UNION ALL
-			SELECT mp.match_id, mp.user_id, NULL::text AS guest_name,
-			       CASE
-			         WHEN m.winner_id = mp.user_id THEN 1
-			         WHEN m.winner_id IS NOT NULL THEN 2
-			         ELSE NULL
-			       END AS place
-			FROM match_players mp
-			JOIN matches m ON m.id = mp.match_id
-			WHERE NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id)
-		),
+	const q = statsParticipantsCTE + `,
 		completed AS (
 			SELECT DISTINCT match_id FROM participants WHERE place = 1
 		),
@@ -780,22 +1138,21 @@ func (s *MatchesStore) guestHeadToHead(ctx context.Context, userID string) ([]do
 			FROM participants p
 			JOIN completed c ON c.match_id = p.match_id
 			WHERE p.user_id = $1
-		),
-		winners AS (
-			SELECT match_id, user_id, guest_name
-			FROM participants
-			WHERE place = 1
 		)
 		SELECT
-			g.guest_name,
-			COALESCE(SUM(CASE WHEN w.user_id = $1 THEN 1 ELSE 0 END), 0)::int AS wins,
-			COALESCE(SUM(CASE WHEN w.guest_name = g.guest_name THEN 1 ELSE 0 END), 0)::int AS losses
+			g.guest_id,
+			COALESCE(r.display_name, MIN(g.guest_name)) AS name,
+			COALESCE(SUM(CASE WHEN me.place = 1 AND NOT (me.outcome = 'draw' AND g.place = 1) THEN 1 ELSE 0 END), 0)::int AS wins,
+			COALESCE(SUM(CASE WHEN g.place = 1 AND NOT (me.outcome = 'draw' AND me.place = 1) THEN 1 ELSE 0 END), 0)::int AS losses,
+			COALESCE(SUM(CASE WHEN me.outcome = 'draw' AND me.place = 1 AND g.place = 1 THEN 1 ELSE 0 END), 0)::int AS draws
 		FROM participants g
 		JOIN user_matches um ON um.match_id = g.match_id
-		JOIN winners w ON w.match_id = g.match_id
+		JOIN participants me ON me.match_id = g.match_id AND me.user_id = $1
+		LEFT JOIN guests r ON r.id = g.guest_id
 		WHERE g.guest_name IS NOT NULL
-		GROUP BY g.guest_name
-		ORDER BY g.guest_name ASC
+		  AND (g.team IS NULL OR g.team <> me.team)
+		GROUP BY g.guest_id, r.display_name, CASE WHEN g.guest_id IS NULL THEN g.guest_name END
+		ORDER BY name ASC
 	`
 
 	rows, err := s.pool.Query(ctx, q, userID)
@@ -806,15 +1163,18 @@ func (s *MatchesStore) guestHeadToHead(ctx context.Context, userID string) ([]do
 
 	var out []domain.GuestHeadToHeadStat
 	for rows.Next() {
+		var guestID pgtype.UUID
 		var name string
-		var wins, losses int
-		if err := rows.Scan(&name, &wins, &losses); err != nil {
+		var wins, losses, draws int
+		if err := rows.Scan(&guestID, &name, &wins, &losses, &draws); err != nil {
 			return nil, fmt.Errorf("scan guest head-to-head: %w", err)
 		}
 		out = append(out, domain.GuestHeadToHeadStat{
+			GuestID:   uuidOrEmpty(guestID),
 			GuestName: name,
 			Wins:      wins,
 			Losses:    losses,
+			Draws:     draws,
 		})
 	}
 	if err := rows.Err(); err != nil {
@@ -834,36 +1194,11 @@ func (s *MatchesStore) HeadToHead(ctx context.Context, userID, opponentID string
 		return domain.HeadToHeadStats{}, fmt.Errorf("head-to-head opponent: %w", err)
 	}
 
-	const q = `
-		WITH participants AS (
-			SELECT match_id, user_id, guest_name, place
-			FROM match_participants
-			UNION ALL
-			SELECT mp.match_id, mp.user_id, NULL::text AS guest_name,
-			       CASE
-			         WHEN m.winner_id = mp.user_id THEN 1
-			         WHEN m.winner_id IS NOT NULL THEN 2
-			         ELSE NULL
-			       END AS place
-			FROM match_players mp
-			JOIN matches m ON m.id = mp.match_id
-			WHERE NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id)
-		),
-		winners AS (
-			SELECT match_id, user_id
-			FROM participants
-			WHERE place = 1 AND user_id IS NOT NULL
-		)
-		SELECT
-			COALESCE(SUM(CASE WHEN w.user_id = $1 THEN 1 ELSE 0 END), 0)::int AS wins,
-			COALESCE(SUM(CASE WHEN w.user_id = $2 THEN 1 ELSE 0 END), 0)::int AS losses
-		FROM winners w
-		WHERE
-			EXISTS (SELECT 1 FROM participants p WHERE p.match_id = w.match_id AND p.user_id = $1)
-			AND EXISTS (SELECT 1 FROM participants p WHERE p.match_id = w.match_id AND p.user_id = $2)
+	const q = statsParticipantsCTE + headToHeadSelect + `
+		FROM pairs
 	`
-	var wins, losses int
-	if err := s.pool.QueryRow(ctx, q, userID, opponentID).Scan(&wins, &losses); err != nil {
+	var wins, losses, draws, asTeammates, winsTogether int
+	if err := s.pool.QueryRow(ctx, q, userID, opponentID).Scan(&wins, &losses, &draws, &asTeammates, &winsTogether); err != nil {
 		return domain.HeadToHeadStats{}, fmt.Errorf("head-to-head: %w", err)
 	}
 
@@ -871,47 +1206,59 @@ func (s *MatchesStore) HeadToHead(ctx context.Context, userID, opponentID string
 	if err != nil {
 		return domain.HeadToHeadStats{}, err
 	}
+	turnTime, turnTimeByFormat, err := s.turnTimeHeadToHead(ctx, userID, opponentID)
+	if err != nil {
+		return domain.HeadToHeadStats{}, err
+	}
+	if len(turnTimeByFormat) > 0 && byFormat == nil {
+		byFormat = make(map[string]domain.HeadToHeadStats, len(turnTimeByFormat))
+	}
+	for format, tt := range turnTimeByFormat {
+		stats := byFormat[format]
+		ttCopy := tt
+		stats.TurnTime = &ttCopy
+		byFormat[format] = stats
+	}
 
 	return domain.HeadToHeadStats{
-		Opponent: domain.UserSummary{ID: uuidOrEmpty(oppIDUUID), Username: oppUsername},
-		Total:    wins + losses,
-		Wins:     wins,
-		Losses:   losses,
-		CoLosses: 0,
-		ByFormat: byFormat,
+		Opponent:     domain.UserSummary{ID: uuidOrEmpty(oppIDUUID), Username: oppUsername},
+		Total:        wins + losses + draws,
+		Wins:         wins,
+		Losses:       losses,
+		Draws:        draws,
+		CoLosses:     0,
+		AsTeammates:  asTeammates,
+		WinsTogether: winsTogether,
+		TurnTime:     turnTime,
+		ByFormat:     byFormat,
 	}, nil
 }
 
-func (s *MatchesStore) headToHeadByFormat(ctx context.Context, userID, opponentID string) (map[string]domain.HeadToHeadStats, error) {
-	const q = `
-		WITH participants AS (
-			SELECT match_id, user_id, guest_name, place
-			FROM match_participants
-			UNION ALL
-			SELECT mp.match_id, mp.user_id, NULL::text AS guest_name,
-			       CASE
-			         WHEN m.winner_id = mp.user_id THEN 1
-			         WHEN m.winner_id IS NOT NULL THEN 2
-			         ELSE NULL
-			       END AS place
-			FROM match_players mp
-			JOIN matches m ON m.id = mp.match_id
-			WHERE NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id)
-		),
-		winners AS (
-			SELECT match_id, user_id
-			FROM participants
-			WHERE place = 1 AND user_id IS NOT NULL
+// headToHeadSelect follows statsParticipantsCTE. It pairs $1 and $2 in each completed match and
+// counts wins, losses and draws as opponents, then matches and wins as teammates. A draw needs both
+// players in the shared first place; otherwise the player who placed first won, as in other matches.
+const headToHeadSelect = `,
+		pairs AS (
+			SELECT me.match_id, me.place AS my_place, opp.place AS opp_place,
+			       me.team IS NOT NULL AND me.team = opp.team AS teammates,
+			       me.outcome = 'draw' AS drawn
+			FROM participants me
+			JOIN participants opp ON opp.match_id = me.match_id AND opp.user_id = $2
+			WHERE me.user_id = $1
+			  AND EXISTS (SELECT 1 FROM participants w WHERE w.match_id = me.match_id AND w.place = 1)
 		)
 		SELECT
-			m.format,
-			COALESCE(SUM(CASE WHEN w.user_id = $1 THEN 1 ELSE 0 END), 0)::int AS wins,
-			COALESCE(SUM(CASE WHEN w.user_id = $2 THEN 1 ELSE 0 END), 0)::int AS losses
-		FROM winners w
-		JOIN matches m ON m.id = w.match_id
-		WHERE
-			EXISTS (SELECT 1 FROM participants p WHERE p.match_id = w.match_id AND p.user_id = $1)
-			AND EXISTS (SELECT 1 FROM participants p WHERE p.match_id = w.match_id AND p.user_id = $2)
+			COALESCE(SUM(CASE WHEN NOT teammates AND my_place = 1 AND NOT (drawn AND opp_place = 1) THEN 1 ELSE 0 END), 0)::int AS wins,
+			COALESCE(SUM(CASE WHEN NOT teammates AND opp_place = 1 AND NOT (drawn AND my_place = 1) THEN 1 ELSE 0 END), 0)::int AS losses,
+			COALESCE(SUM(CASE WHEN NOT teammates AND drawn AND my_place = 1 AND opp_place = 1 THEN 1 ELSE 0 END), 0)::int AS draws,
+			COALESCE(SUM(CASE WHEN teammates THEN 1 ELSE 0 END), 0)::int AS as_teammates,
+			COALESCE(SUM(CASE WHEN teammates AND NOT drawn AND my_place = 1 THEN 1 ELSE 0 END), 0)::int AS wins_together`
+
+func (s *MatchesStore) headToHeadByFormat(ctx context.Context, userID, opponentID string) (map[string]domain.HeadToHeadStats, error) {
+	const q = statsParticipantsCTE + headToHeadSelect + `,
+			m.format
+		FROM pairs
+		JOIN matches m ON m.id = pairs.match_id
 		GROUP BY m.format
 	`
 	rows, err := s.pool.Query(ctx, q, userID, opponentID)
@@ -923,19 +1270,25 @@ func (s *MatchesStore) headToHeadByFormat(ctx context.Context, userID, opponentI
 	out := make(map[string]domain.HeadToHeadStats)
 	for rows.Next() {
 		var (
-			formatText pgtype.Text
-			wins       int
-			losses     int
+			formatText   pgtype.Text
+			wins         int
+			losses       int
+			draws        int
+			asTeammates  int
+			winsTogether int
 		)
-		if err := rows.Scan(&formatText, &wins, &losses); err != nil {
+		if err := rows.Scan(&wins, &losses, &draws, &asTeammates, &winsTogether, &formatText); err != nil {
 			return nil, fmt.Errorf("scan head-to-head by format: %w", err)
 		}
 		format := string(normalizeFormat(formatText))
 		out[format] = domain.HeadToHeadStats{
-			Total:    wins + losses,
-			Wins:     wins,
-			Losses:   losses,
-			CoLosses: 0,
+			Total:        wins + losses + draws,
+			Wins:         wins,
+			Losses:       losses,
+			Draws:        draws,
+			CoLosses:     0,
+			AsTeammates:  asTeammates,
+			WinsTogether: winsTogether,
 		}
 	}
 	if err := rows.Err(); err != nil {
@@ -0,0 +1,420 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RatingsStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewRatingsStore(pool *pgxpool.Pool) *RatingsStore {
+	return &RatingsStore{pool: pool}
+}
+
+// latestMatchSeqQuery is the newest match sync_seq at or below the commit watermark (see
+// syncWatermark), so no match with a lower seq can still be waiting to commit.
+const latestMatchSeqQuery = `
+	WITH w AS (SELECT sync_seq_watermark() AS seq)
+	SELECT COALESCE(MAX(m.sync_seq), 0) FROM matches m, w WHERE m.sync_seq <= w.seq
+`
+
+func (s *RatingsStore) LatestMatchSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	if err := s.pool.QueryRow(ctx, latestMatchSeqQuery).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("latest match seq: %w", err)
+	}
+	return seq, nil
+}
+
+// GetRatingRun returns a zero run before the first recompute.
+func (s *RatingsStore) GetRatingRun(ctx context.Context) (domain.RatingRun, error) {
+	var run domain.RatingRun
+	err := s.pool.QueryRow(ctx, `SELECT source_seq, engine_version, computed_at FROM rating_runs`).Scan(&run.SourceSeq, &run.EngineVersion, &run.ComputedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.RatingRun{}, nil
+		}
+		return domain.RatingRun{}, fmt.Errorf("get rating run: %w", err)
+	}
+	return run, nil
+}
+
+// LoadRatingRebuilds reads, from one snapshot, what has to be replayed to bring the ratings up to
+// date: every format with a match whose sync_seq is past since, from the earliest date that match
+// was or is played at. With full set every format is replayed from the start. It also returns the
+// match seq the result will be current to.
+func (s *RatingsStore) LoadRatingRebuilds(ctx context.Context, since int64, full bool) (int64, []domain.RatingRebuild, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return 0, nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var latest int64
+	if err := tx.QueryRow(ctx, latestMatchSeqQuery).Scan(&latest); err != nil {
+		return 0, nil, fmt.Errorf("latest match seq: %w", err)
+	}
+
+	if full {
+		matches, err := listRatedMatches(ctx, tx, "", time.Time{})
+		if err != nil {
+			return 0, nil, err
+		}
+		byFormat := make(map[domain.GameFormat]*domain.RatingRebuild)
+		var out []domain.RatingRebuild
+		var order []domain.GameFormat
+		for _, m := range matches {
+			rb, ok := byFormat[m.Format]
+			if !ok {
+				rb = &domain.RatingRebuild{Format: m.Format}
+				byFormat[m.Format] = rb
+				order = append(order, m.Format)
+			}
+			rb.Matches = append(rb.Matches, m)
+		}
+		for _, f := range order {
+			out = append(out, *byFormat[f])
+		}
+		return latest, out, nil
+	}
+
+	changed, err := changedRatingFormats(ctx, tx, since)
+	if err != nil {
+		return 0, nil, err
+	}
+	out := make([]domain.RatingRebuild, 0, len(changed))
+	for _, rb := range changed {
+		if rb.Start, err = listRatingsBefore(ctx, tx, rb.Format, rb.From); err != nil {
+			return 0, nil, err
+		}
+		if rb.Matches, err = listRatedMatches(ctx, tx, rb.Format, rb.From); err != nil {
+			return 0, nil, err
+		}
+		out = append(out, rb)
+	}
+	return latest, out, nil
+}
+
+// changedRatingFormats returns each format touched by a match whose sync_seq is past since, with
+// the earliest played_at involved. A match's previous format and date come from rating_history, so
+// moving a match to another date or format replays both places.
+func changedRatingFormats(ctx context.Context, tx pgx.Tx, since int64) ([]domain.RatingRebuild, error) {
+	const q = `
+		WITH changed AS (
+			SELECT id, format, COALESCE(played_at, created_at) AS played_at
+			FROM matches
+			WHERE sync_seq > $1
+		)
+		SELECT format, MIN(played_at)
+		FROM (
+			SELECT format, played_at FROM changed
+			UNION ALL
+			SELECT h.format, h.played_at FROM rating_history h JOIN changed c ON c.id = h.match_id
+		) x
+		GROUP BY format
+	`
+	rows, err := tx.Query(ctx, q, since)
+	if err != nil {
+		return nil, fmt.Errorf("list changed rating formats: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.RatingRebuild
+	index := make(map[domain.GameFormat]int)
+	for rows.Next() {
+		var (
+			formatText pgtype.Text
+			from       time.Time
+		)
+		if err := rows.Scan(&formatText, &from); err != nil {
+			return nil, fmt.Errorf("scan changed rating format: %w", err)
+		}
+		format := normalizeFormat(formatText)
+		if i, ok := index[format]; ok {
+			if from.Before(out[i].From) {
+				out[i].From = from
+			}
+			continue
+		}
+		index[format] = len(out)
+		out = append(out, domain.RatingRebuild{Format: format, From: from})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list changed rating formats: %w", err)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Format < out[j].Format })
+	return out, nil
+}
+
+// listRatingsBefore rebuilds each player's rating in format from their history before from.
+// PeakRating is the best rating reached, which may be below the base rating.
+func listRatingsBefore(ctx context.Context, tx pgx.Tx, format domain.GameFormat, from time.Time) ([]domain.Rating, error) {
+	const q = `
+		SELECT user_id,
+		       (array_agg(rating_after ORDER BY played_at DESC, match_id DESC))[1],
+		       MAX(rating_after), COUNT(*)::int, MAX(played_at)
+		FROM rating_history
+		WHERE format = $1 AND played_at < $2
+		GROUP BY user_id
+		ORDER BY user_id
+	`
+	rows, err := tx.Query(ctx, q, string(format), from)
+	if err != nil {
+		return nil, fmt.Errorf("list ratings before: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Rating
+	for rows.Next() {
+		var (
+			userID     pgtype.UUID
+			lastPlayed time.Time
+		)
+		r := domain.Rating{Format: format}
+		if err := rows.Scan(&userID, &r.Rating, &r.PeakRating, &r.MatchesRated, &lastPlayed); err != nil {
+			return nil, fmt.Errorf("scan rating before: %w", err)
+		}
+		r.UserID = uuidOrEmpty(userID)
+		r.LastPlayedAt = &lastPlayed
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list ratings before: %w", err)
+	}
+	return out, nil
+}
+
+// listRatedMatches returns completed, non-deleted matches ordered by played_at (falling back to
+// created_at), limited to format and to matches played at or after from when those are set.
+func listRatedMatches(ctx context.Context, tx pgx.Tx, format domain.GameFormat, from time.Time) ([]domain.RatedMatch, error) {
+	const q = participantsCTE + `,
+		completed AS (
+			SELECT DISTINCT match_id FROM participants WHERE place = 1
+		)
+		SELECT m.id, m.format, COALESCE(m.played_at, m.created_at) AS played_at, p.user_id, p.place, p.team
+		FROM completed c
+		JOIN matches m ON m.id = c.match_id
+		JOIN participants p ON p.match_id = m.id
+		WHERE p.place IS NOT NULL
+		  AND ($1::text IS NULL OR COALESCE(NULLIF(m.format, ''), $3) = $1)
+		  AND ($2::timestamptz IS NULL OR COALESCE(m.played_at, m.created_at) >= $2)
+		ORDER BY COALESCE(m.played_at, m.created_at), m.id, p.place
+	`
+	var fromArg any
+	if !from.IsZero() {
+		fromArg = from
+	}
+	rows, err := tx.Query(ctx, q, nullIfEmpty(string(format)), fromArg, string(domain.FormatCommander))
+	if err != nil {
+		return nil, fmt.Errorf("list rated matches: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.RatedMatch
+	for rows.Next() {
+		var (
+			idUUID     pgtype.UUID
+			formatText pgtype.Text
+			playedAt   time.Time
+			userID     pgtype.UUID
+			place      int
+			team       pgtype.Int4
+		)
+		if err := rows.Scan(&idUUID, &formatText, &playedAt, &userID, &place, &team); err != nil {
+			return nil, fmt.Errorf("scan rated match: %w", err)
+		}
+		id := uuidOrEmpty(idUUID)
+		if len(out) == 0 || out[len(out)-1].ID != id {
+			out = append(out, domain.RatedMatch{
+				ID:       id,
+				Format:   normalizeFormat(formatText),
+				PlayedAt: playedAt,
+			})
+		}
+		last := &out[len(out)-1]
+		last.Players = append(last.Players, domain.RatedPlayer{UserID: uuidOrEmpty(userID), Place: place, Team: int(team.Int32)})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list rated matches: %w", err)
+	}
+	return out, nil
+}
+
+// SaveRatingRebuilds stores the rebuilt formats and records run. Each rebuilt format's ratings
+// are replaced, along with its history from From onward; when run has a new engine version every
+// stored rating is replaced. Nothing is written unless the stored run is still base, so
+// concurrent rebuilds from several server instances cannot overwrite each other.
+func (s *RatingsStore) SaveRatingRebuilds(ctx context.Context, base, run domain.RatingRun, rebuilds []domain.RatingRebuild) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('ratings'))`); err != nil {
+		return fmt.Errorf("lock ratings: %w", err)
+	}
+	var stored domain.RatingRun
+	err = tx.QueryRow(ctx, `SELECT source_seq, engine_version FROM rating_runs`).Scan(&stored.SourceSeq, &stored.EngineVersion)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("get rating run: %w", err)
+	}
+	if stored.SourceSeq != base.SourceSeq || stored.EngineVersion != base.EngineVersion {
+		return nil
+	}
+
+	if base.EngineVersion != run.EngineVersion {
+		if _, err := tx.Exec(ctx, `DELETE FROM rating_history`); err != nil {
+			return fmt.Errorf("clear rating history: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM ratings`); err != nil {
+			return fmt.Errorf("clear ratings: %w", err)
+		}
+	}
+	var (
+		ratings []domain.Rating
+		history []domain.RatingHistoryEntry
+	)
+	for _, rb := range rebuilds {
+		if _, err := tx.Exec(ctx, `DELETE FROM rating_history WHERE format = $1 AND played_at >= $2`, string(rb.Format), rb.From); err != nil {
+			return fmt.Errorf("clear rating history: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM ratings WHERE format = $1`, string(rb.Format)); err != nil {
+			return fmt.Errorf("clear ratings: %w", err)
+		}
+		ratings = append(ratings, rb.Ratings...)
+		history = append(history, rb.History...)
+	}
+
+	if _, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"ratings"},
+		[]string{"user_id", "format", "rating", "peak_rating", "matches_rated", "last_played_at"},
+		pgx.CopyFromSlice(len(ratings), func(i int) ([]any, error) {
+			r := ratings[i]
+			var lastPlayed any
+			if r.LastPlayedAt != nil {
+				lastPlayed = *r.LastPlayedAt
+			}
+			return []any{r.UserID, string(r.Format), r.Rating, r.PeakRating, r.MatchesRated, lastPlayed}, nil
+		}),
+	); err != nil {
+		return fmt.Errorf("insert ratings: %w", err)
+	}
+	if _, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"rating_history"},
+		[]string{"user_id", "format", "match_id", "played_at", "place", "pod_size", "rating_before", "rating_after"},
+		pgx.CopyFromSlice(len(history), func(i int) ([]any, error) {
+			h := history[i]
+			return []any{h.UserID, string(h.Format), h.MatchID, h.PlayedAt, h.Place, h.PodSize, h.RatingBefore, h.RatingAfter}, nil
+		}),
+	); err != nil {
+		return fmt.Errorf("insert rating history: %w", err)
+	}
+
+	const upsertRun = `
+		INSERT INTO rating_runs (id, source_seq, engine_version, computed_at)
+		VALUES (TRUE, $1, $2, $3)
+		ON CONFLICT (id) DO UPDATE
+		SET source_seq = EXCLUDED.source_seq,
+		    engine_version = EXCLUDED.engine_version,
+		    computed_at = EXCLUDED.computed_at
+	`
+	if _, err := tx.Exec(ctx, upsertRun, run.SourceSeq, run.EngineVersion, run.ComputedAt); err != nil {
+		return fmt.Errorf("record rating run: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// ListRatingsForUsers returns every format when format is empty.
+func (s *RatingsStore) ListRatingsForUsers(ctx context.Context, userIDs []string, format domain.GameFormat) ([]domain.Rating, error) {
+	const q = `
+		SELECT user_id, format, rating, peak_rating, matches_rated, last_played_at
+		FROM ratings
+		WHERE user_id = ANY($1::uuid[])
+		  AND ($2::text IS NULL OR format = $2)
+		ORDER BY format ASC, rating DESC
+	`
+	rows, err := s.pool.Query(ctx, q, userIDs, nullIfEmpty(string(format)))
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == "22P02" {
+			return nil, domain.ErrValidation
+		}
+		return nil, fmt.Errorf("list ratings: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Rating
+	for rows.Next() {
+		var (
+			userID     pgtype.UUID
+			formatText pgtype.Text
+			r          domain.Rating
+			lastPlayed pgtype.Timestamptz
+		)
+		if err := rows.Scan(&userID, &formatText, &r.Rating, &r.PeakRating, &r.MatchesRated, &lastPlayed); err != nil {
+			return nil, fmt.Errorf("scan rating: %w", err)
+		}
+		r.UserID = uuidOrEmpty(userID)
+		r.Format = normalizeFormat(formatText)
+		r.LastPlayedAt = timestamptzPtr(lastPlayed)
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list ratings: %w", err)
+	}
+	return out, nil
+}
+
+func (s *RatingsStore) ListRatingHistory(ctx context.Context, userID string, format domain.GameFormat, limit int) ([]domain.RatingHistoryEntry, error) {
+	const q = `
+		SELECT match_id, format, played_at, place, pod_size, rating_before, rating_after
+		FROM rating_history
+		WHERE user_id = $1 AND format = $2
+		ORDER BY played_at DESC, match_id DESC
+		LIMIT $3
+	`
+	rows, err := s.pool.Query(ctx, q, userID, string(format), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list rating history: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.RatingHistoryEntry
+	for rows.Next() {
+		var (
+			matchID    pgtype.UUID
+			formatText pgtype.Text
+			h          domain.RatingHistoryEntry
+		)
+		if err := rows.Scan(&matchID, &formatText, &h.PlayedAt, &h.Place, &h.PodSize, &h.RatingBefore, &h.RatingAfter); err != nil {
+			return nil, fmt.Errorf("scan rating history: %w", err)
+		}
+		h.UserID = userID
+		h.MatchID = uuidOrEmpty(matchID)
+		h.Format = normalizeFormat(formatText)
+		out = append(out, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list rating history: %w", err)
+	}
+	return out, nil
+}
@@ -14,6 +14,13 @@ func nullIfEmpty(s string) any {
 	return s
 }
 
+func nullIfZero(n int) any {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
 func textOrEmpty(t pgtype.Text) string {
 	if t.Valid {
 		return t.String
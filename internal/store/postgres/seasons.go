@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SeasonsStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewSeasonsStore(pool *pgxpool.Pool) *SeasonsStore {
+	return &SeasonsStore{pool: pool}
+}
+
+const seasonColumns = `
+	s.id, s.owner_id, s.group_id, s.name, s.format, s.starts_at, s.ends_at, s.points, s.archived_at, s.created_at, s.updated_at
+`
+
+func scanSeason(row pgx.Row) (domain.Season, error) {
+	var (
+		season     domain.Season
+		idUUID     pgtype.UUID
+		ownerUUID  pgtype.UUID
+		groupUUID  pgtype.UUID
+		formatText pgtype.Text
+		archivedAt pgtype.Timestamptz
+	)
+	if err := row.Scan(&idUUID, &ownerUUID, &groupUUID, &season.Name, &formatText, &season.StartsAt, &season.EndsAt, &season.Points, &archivedAt, &season.CreatedAt, &season.UpdatedAt); err != nil {
+		return domain.Season{}, err
+	}
+	season.ID = uuidOrEmpty(idUUID)
+	season.OwnerID = uuidOrEmpty(ownerUUID)
+	season.GroupID = uuidOrEmpty(groupUUID)
+	season.Format = domain.GameFormat(textOrEmpty(formatText))
+	season.ArchivedAt = timestamptzPtr(archivedAt)
+	return season, nil
+}
+
+func (s *SeasonsStore) CreateSeason(ctx context.Context, ownerID string, in domain.SeasonInput) (domain.Season, error) {
+	const q = `
+		INSERT INTO seasons AS s (owner_id, group_id, name, format, starts_at, ends_at, points)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + seasonColumns
+	season, err := scanSeason(s.pool.QueryRow(ctx, q, ownerID, nullIfEmpty(in.GroupID), in.Name, nullIfEmpty(string(in.Format)), in.StartsAt, in.EndsAt, in.Points))
+	if err != nil {
+		return domain.Season{}, fmt.Errorf("create season: %w", err)
+	}
+	return season, nil
+}
+
+func (s *SeasonsStore) GetSeason(ctx context.Context, seasonID string) (domain.Season, error) {
+	const q = `SELECT ` + seasonColumns + ` FROM seasons s WHERE s.id = $1`
+	season, err := scanSeason(s.pool.QueryRow(ctx, q, seasonID))
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgerr) && pgerr.Code == "22P02") {
+			return domain.Season{}, domain.ErrNotFound
+		}
+		return domain.Season{}, fmt.Errorf("get season: %w", err)
+	}
+	return season, nil
+}
+
+// ListSeasons returns the user's personal seasons and the seasons of groups they belong to.
+func (s *SeasonsStore) ListSeasons(ctx context.Context, userID string, archived bool) ([]domain.Season, error) {
+	const q = `
+		SELECT ` + seasonColumns + `
+		FROM seasons s
+		WHERE ((s.group_id IS NULL AND s.owner_id = $1) OR EXISTS (
+		        SELECT 1 FROM group_members gm WHERE gm.group_id = s.group_id AND gm.user_id = $1
+		      ))
+		  AND (s.archived_at IS NOT NULL) = $2
+		ORDER BY s.starts_at DESC, s.id DESC
+	`
+	rows, err := s.pool.Query(ctx, q, userID, archived)
+	if err != nil {
+		return nil, fmt.Errorf("list seasons: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Season
+	for rows.Next() {
+		season, err := scanSeason(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan season: %w", err)
+		}
+		out = append(out, season)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list seasons: %w", err)
+	}
+	return out, nil
+}
+
+func (s *SeasonsStore) UpdateSeason(ctx context.Context, seasonID string, in domain.SeasonInput) (domain.Season, error) {
+	const q = `
+		UPDATE seasons AS s
+		SET name = $2, format = $3, starts_at = $4, ends_at = $5, points = $6,
+		    updated_at = date_trunc('milliseconds', now())
+		WHERE s.id = $1 AND s.archived_at IS NULL
+		RETURNING ` + seasonColumns
+	season, err := scanSeason(s.pool.QueryRow(ctx, q, seasonID, in.Name, nullIfEmpty(string(in.Format)), in.StartsAt, in.EndsAt, in.Points))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Season{}, domain.ErrNotFound
+		}
+		return domain.Season{}, fmt.Errorf("update season: %w", err)
+	}
+	return season, nil
+}
+
+func (s *SeasonsStore) DeleteSeason(ctx context.Context, seasonID string) error {
+	ct, err := s.pool.Exec(ctx, `DELETE FROM seasons WHERE id = $1`, seasonID)
+	if err != nil {
+		return fmt.Errorf("delete season: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListSeasonResults returns every participant of completed matches inside the season, with
+// played_at (falling back to created_at) in range. Group seasons score the group's matches;
+// personal seasons score the matches the owner played in.
+func (s *SeasonsStore) ListSeasonResults(ctx context.Context, season domain.Season) ([]domain.MatchPlacement, error) {
+	const q = participantsCTE + `,
+		season_matches AS (
+			SELECT m.id, m.format
+			FROM matches m
+			WHERE m.deleted_at IS NULL
+			  AND COALESCE(m.played_at, m.created_at) >= $2
+			  AND COALESCE(m.played_at, m.created_at) < $3
+			  AND ($4::text IS NULL OR m.format = $4)
+			  AND CASE
+			        WHEN $5::uuid IS NULL THEN EXISTS (SELECT 1 FROM participants p WHERE p.match_id = m.id AND p.user_id = $1)
+			        ELSE m.group_id = $5
+			      END
+			  AND EXISTS (SELECT 1 FROM participants p WHERE p.match_id = m.id AND p.place = 1)
+		)
+		SELECT p.match_id, sm.format, p.user_id, u.username, u.display_name, u.avatar_path, u.avatar_updated_at,
+		       p.guest_id, COALESCE(r.display_name, p.guest_name), p.place, p.outcome = 'draw'
+		FROM participants p
+		JOIN season_matches sm ON sm.id = p.match_id
+		LEFT JOIN users u ON u.id = p.user_id
+		LEFT JOIN guests r ON r.id = p.guest_id
+		WHERE p.place IS NOT NULL
+		ORDER BY p.match_id, p.place
+	`
+	rows, err := s.pool.Query(ctx, q, nullIfEmpty(season.OwnerID), season.StartsAt, season.EndsAt, nullIfEmpty(string(season.Format)), nullIfEmpty(season.GroupID))
+	if err != nil {
+		return nil, fmt.Errorf("list season results: %w", err)
+	}
+	defer rows.Close()
+	return scanMatchPlacements(rows)
+}
+
+func (s *SeasonsStore) ArchiveSeason(ctx context.Context, seasonID string, archivedAt time.Time, matchesPlayed int, standings []domain.SeasonStanding) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ct, err := tx.Exec(ctx, `
+		UPDATE seasons
+		SET archived_at = $2, archived_matches = $3, updated_at = $2
+		WHERE id = $1 AND archived_at IS NULL
+	`, seasonID, archivedAt, matchesPlayed)
+	if err != nil {
+		return fmt.Errorf("archive season: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		// Already archived by a concurrent request; keep the first snapshot.
+		return nil
+	}
+
+	if _, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"season_standings"},
+		[]string{"season_id", "position", "rank", "user_id", "guest_id", "guest_name", "points", "matches_played", "wins", "avg_place"},
+		pgx.CopyFromSlice(len(standings), func(i int) ([]any, error) {
+			st := standings[i]
+			var userID any
+			if st.User != nil {
+				userID = st.User.ID
+			}
+			return []any{seasonID, i, st.Rank, userID, nullIfEmpty(st.GuestID), nullIfEmpty(st.GuestName), st.Points, st.MatchesPlayed, st.Wins, st.AvgPlace}, nil
+		}),
+	); err != nil {
+		return fmt.Errorf("insert season standings: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (s *SeasonsStore) GetSeasonSnapshot(ctx context.Context, seasonID string) (int, []domain.SeasonStanding, error) {
+	var matchesPlayed pgtype.Int4
+	if err := s.pool.QueryRow(ctx, `SELECT archived_matches FROM seasons WHERE id = $1`, seasonID).Scan(&matchesPlayed); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil, domain.ErrNotFound
+		}
+		return 0, nil, fmt.Errorf("get season snapshot: %w", err)
+	}
+
+	const q = `
+		SELECT st.rank, st.user_id, u.username, u.display_name, u.avatar_path, u.avatar_updated_at,
+		       st.guest_id, st.guest_name, st.points, st.matches_played, st.wins, st.avg_place
+		FROM season_standings st
+		LEFT JOIN users u ON u.id = st.user_id
+		WHERE st.season_id = $1
+		ORDER BY st.position
+	`
+	rows, err := s.pool.Query(ctx, q, seasonID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("get season snapshot: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.SeasonStanding
+	for rows.Next() {
+		var (
+			st              domain.SeasonStanding
+			userID          pgtype.UUID
+			username        pgtype.Text
+			displayName     pgtype.Text
+			avatarPath      pgtype.Text
+			avatarUpdatedAt pgtype.Timestamptz
+			guestID         pgtype.UUID
+			guestName       pgtype.Text
+		)
+		if err := rows.Scan(&st.Rank, &userID, &username, &displayName, &avatarPath, &avatarUpdatedAt, &guestID, &guestName, &st.Points, &st.MatchesPlayed, &st.Wins, &st.AvgPlace); err != nil {
+			return 0, nil, fmt.Errorf("scan season standing: %w", err)
+		}
+		if id := uuidOrEmpty(userID); id != "" {
+			st.User = &domain.UserSummary{
+				ID:              id,
+				Username:        textOrEmpty(username),
+				DisplayName:     textOrEmpty(displayName),
+				AvatarPath:      textOrEmpty(avatarPath),
+				AvatarUpdatedAt: timestamptzPtr(avatarUpdatedAt),
+			}
+		}
+		st.GuestID = uuidOrEmpty(guestID)
+		st.GuestName = textOrEmpty(guestName)
+		if st.MatchesPlayed > 0 {
+			st.WinPct = float64(st.Wins) / float64(st.MatchesPlayed)
+		}
+		out = append(out, st)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("get season snapshot: %w", err)
+	}
+	return int(matchesPlayed.Int32), out, nil
+}
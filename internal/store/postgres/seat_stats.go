@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (s *MatchesStore) SeatStats(ctx context.Context, userID string) (domain.SeatStats, error) {
+	const q = statsParticipantsCTE + `,
+		completed AS (
+			SELECT DISTINCT match_id FROM participants WHERE place = 1
+		),
+		pods AS (
+			SELECT match_id, COUNT(*)::int AS pod_size FROM participants GROUP BY match_id
+		)
+		SELECT
+			m.format,
+			p.seat_index,
+			((p.seat_index - m.starting_seat_index + pods.pod_size) % pods.pod_size)::int AS turn_position,
+			COUNT(*)::int AS matches_played,
+			COALESCE(SUM(CASE WHEN p.place = 1 AND p.outcome <> 'draw' THEN 1 ELSE 0 END), 0)::int AS wins
+		FROM participants p
+		JOIN completed c ON c.match_id = p.match_id
+		JOIN pods ON pods.match_id = p.match_id
+		JOIN matches m ON m.id = p.match_id
+		WHERE p.user_id = $1
+		  AND p.seat_index IS NOT NULL
+		  AND m.starting_seat_index IS NOT NULL
+		GROUP BY m.format, p.seat_index, turn_position
+	`
+
+	rows, err := s.pool.Query(ctx, q, userID)
+	if err != nil {
+		return domain.SeatStats{}, fmt.Errorf("seat stats: %w", err)
+	}
+	defer rows.Close()
+
+	overall := newSeatAccumulator()
+	byFormat := make(map[string]*seatAccumulator)
+	for rows.Next() {
+		var (
+			formatText   pgtype.Text
+			seatIndex    int
+			turnPosition int
+			played       int
+			wins         int
+		)
+		if err := rows.Scan(&formatText, &seatIndex, &turnPosition, &played, &wins); err != nil {
+			return domain.SeatStats{}, fmt.Errorf("scan seat stats: %w", err)
+		}
+		format := string(normalizeFormat(formatText))
+		acc, ok := byFormat[format]
+		if !ok {
+			acc = newSeatAccumulator()
+			byFormat[format] = acc
+		}
+		overall.add(seatIndex, turnPosition, played, wins)
+		acc.add(seatIndex, turnPosition, played, wins)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.SeatStats{}, fmt.Errorf("seat stats: %w", err)
+	}
+
+	out := overall.stats()
+	if len(byFormat) > 0 {
+		out.ByFormat = make(map[string]domain.SeatStats, len(byFormat))
+		for format, acc := range byFormat {
+			out.ByFormat[format] = acc.stats()
+		}
+	}
+	return out, nil
+}
+
+type seatCount struct {
+	played int
+	wins   int
+}
+
+type seatAccumulator struct {
+	bySeat      map[int]*seatCount
+	byTurnOrder map[int]*seatCount
+	first       seatCount
+	notFirst    seatCount
+}
+
+func newSeatAccumulator() *seatAccumulator {
+	return &seatAccumulator{
+		bySeat:      make(map[int]*seatCount),
+		byTurnOrder: make(map[int]*seatCount),
+	}
+}
+
+func (a *seatAccumulator) add(seatIndex, turnPosition, played, wins int) {
+	addSeatCount(a.bySeat, seatIndex, played, wins)
+	addSeatCount(a.byTurnOrder, turnPosition, played, wins)
+	if turnPosition == 0 {
+		a.first.played += played
+		a.first.wins += wins
+	} else {
+		a.notFirst.played += played
+		a.notFirst.wins += wins
+	}
+}
+
+func (a *seatAccumulator) stats() domain.SeatStats {
+	return domain.SeatStats{
+		MatchesPlayed: a.first.played + a.notFirst.played,
+		BySeat:        positionStats(a.bySeat),
+		ByTurnOrder:   positionStats(a.byTurnOrder),
+		WentFirst:     seatRecord(a.first),
+		NotFirst:      seatRecord(a.notFirst),
+	}
+}
+
+func addSeatCount(m map[int]*seatCount, key, played, wins int) {
+	c, ok := m[key]
+	if !ok {
+		c = &seatCount{}
+		m[key] = c
+	}
+	c.played += played
+	c.wins += wins
+}
+
+func positionStats(m map[int]*seatCount) []domain.PositionStat {
+	out := make([]domain.PositionStat, 0, len(m))
+	for position, c := range m {
+		rec := seatRecord(*c)
+		out = append(out, domain.PositionStat{
+			Position:      position,
+			MatchesPlayed: rec.MatchesPlayed,
+			Wins:          rec.Wins,
+			WinPct:        rec.WinPct,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Position < out[j].Position })
+	return out
+}
+
+func seatRecord(c seatCount) domain.SeatRecord {
+	winPct := 0.0
+	if c.played > 0 {
+		winPct = float64(c.wins) / float64(c.played)
+	}
+	return domain.SeatRecord{MatchesPlayed: c.played, Wins: c.wins, WinPct: winPct}
+}
@@ -0,0 +1,338 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SyncStore struct {
+	pool    *pgxpool.Pool
+	users   *UsersStore
+	matches *MatchesStore
+}
+
+func NewSyncStore(pool *pgxpool.Pool) *SyncStore {
+	return &SyncStore{
+		pool:    pool,
+		users:   NewUsersStore(pool),
+		matches: NewMatchesStore(pool),
+	}
+}
+
+// ChangesSince returns everything visible to userID whose sync_seq is greater than since.
+// Matches are paged by matchLimit; when more remain, MaxSeq stops at the last match returned
+// so the next call resumes from there (other sections may be repeated, which is harmless).
+// Only changes at or below the commit watermark are returned and MaxSeq never passes it, so a
+// change whose transaction commits late is still returned by the next call.
+func (s *SyncStore) ChangesSince(ctx context.Context, userID string, since int64, matchLimit int) (domain.SyncChanges, error) {
+	var out domain.SyncChanges
+	out.MaxSeq = since
+
+	watermark, err := syncWatermark(ctx, s.pool)
+	if err != nil {
+		return domain.SyncChanges{}, err
+	}
+
+	var profileSeq int64
+	if err := s.pool.QueryRow(ctx, `SELECT sync_seq FROM users WHERE id = $1`, userID).Scan(&profileSeq); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.SyncChanges{}, domain.ErrNotFound
+		}
+		return domain.SyncChanges{}, fmt.Errorf("sync profile seq: %w", err)
+	}
+	if profileSeq > since && profileSeq <= watermark {
+		u, err := s.users.GetUserByID(ctx, userID)
+		if err != nil {
+			return domain.SyncChanges{}, err
+		}
+		out.Profile = &u
+		out.MaxSeq = max(out.MaxSeq, profileSeq)
+	}
+
+	friends, friendsSeq, err := s.friendChanges(ctx, userID, since, watermark)
+	if err != nil {
+		return domain.SyncChanges{}, err
+	}
+	out.Friends = friends
+	out.MaxSeq = max(out.MaxSeq, friendsSeq)
+
+	removed, removedSeq, err := s.removedMatches(ctx, userID, since, watermark)
+	if err != nil {
+		return domain.SyncChanges{}, err
+	}
+	out.RemovedMatchIDs = removed
+	out.MaxSeq = max(out.MaxSeq, removedSeq)
+
+	matches, lastMatchSeq, hasMore, err := s.matchChanges(ctx, userID, since, watermark, matchLimit)
+	if err != nil {
+		return domain.SyncChanges{}, err
+	}
+	out.Matches = matches
+	if hasMore {
+		out.HasMore = true
+		out.MaxSeq = lastMatchSeq
+	} else {
+		out.MaxSeq = max(out.MaxSeq, lastMatchSeq)
+	}
+	out.MaxSeq = max(min(out.MaxSeq, watermark), since)
+	return out, nil
+}
+
+// syncWatermark returns the highest sync_seq no unfinished transaction can still write; see
+// sync_seq_watermark() in the migrations. It does not wait for writers.
+func syncWatermark(ctx context.Context, pool *pgxpool.Pool) (int64, error) {
+	var seq int64
+	if err := pool.QueryRow(ctx, `SELECT sync_seq_watermark()`).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("sync watermark: %w", err)
+	}
+	return seq, nil
+}
+
+func (s *SyncStore) friendChanges(ctx context.Context, userID string, since, until int64) ([]domain.FriendConnection, int64, error) {
+	const q = `
+		SELECT f.id, f.requester_id, f.status, f.created_at, f.updated_at,
+		       GREATEST(CASE WHEN f.sync_seq <= $3 THEN f.sync_seq END, CASE WHEN u.sync_seq <= $3 THEN u.sync_seq END),
+		       u.id, u.username, u.display_name, u.avatar_path, u.avatar_updated_at, u.updated_at
+		FROM friendships f
+		JOIN users u ON u.id = CASE
+			WHEN f.requester_id = $1 THEN f.addressee_id
+			ELSE f.requester_id
+		END
+		WHERE (f.requester_id = $1 OR f.addressee_id = $1)
+		  AND ((f.sync_seq > $2 AND f.sync_seq <= $3)
+		    OR (f.status = 'accepted' AND u.sync_seq > $2 AND u.sync_seq <= $3))
+		ORDER BY 6 ASC
+	`
+
+	rows, err := s.pool.Query(ctx, q, userID, since, until)
+	if err != nil {
+		return nil, 0, fmt.Errorf("sync friendships: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		out    []domain.FriendConnection
+		maxSeq int64
+	)
+	for rows.Next() {
+		var (
+			reqIDUUID     pgtype.UUID
+			requesterUUID pgtype.UUID
+			status        string
+			createdAt     time.Time
+			updatedAt     time.Time
+			seq           int64
+			otherUUID     pgtype.UUID
+			username      string
+			displayName   pgtype.Text
+			avatarPath    pgtype.Text
+			avatarUpdated pgtype.Timestamptz
+			userUpdated   pgtype.Timestamptz
+		)
+		if err := rows.Scan(&reqIDUUID, &requesterUUID, &status, &createdAt, &updatedAt, &seq, &otherUUID, &username, &displayName, &avatarPath, &avatarUpdated, &userUpdated); err != nil {
+			return nil, 0, fmt.Errorf("scan sync friendship: %w", err)
+		}
+
+		conn := domain.FriendConnection{
+			User: domain.UserSummary{
+				ID:              uuidOrEmpty(otherUUID),
+				Username:        username,
+				DisplayName:     textOrEmpty(displayName),
+				AvatarPath:      textOrEmpty(avatarPath),
+				AvatarUpdatedAt: timestamptzPtr(avatarUpdated),
+				UpdatedAt:       timestamptzPtr(userUpdated),
+			},
+			RequestID: uuidOrEmpty(reqIDUUID),
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
+		}
+		switch {
+		case status == "accepted":
+			conn.Status = domain.FriendStatusAccepted
+		case status == "pending" && uuidOrEmpty(requesterUUID) == userID:
+			conn.Status = domain.FriendStatusOutgoing
+		case status == "pending":
+			conn.Status = domain.FriendStatusIncoming
+		default:
+			conn.Status = domain.FriendStatusRemoved
+		}
+		out = append(out, conn)
+		maxSeq = max(maxSeq, seq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("sync friendships: %w", err)
+	}
+
+	// Friendship rows that were deleted outright (re-requests after a decline, account deletion).
+	const qRemoved = `
+		SELECT r.ref_id, MAX(r.seq), MAX(r.created_at), u.username, u.display_name, u.avatar_path, u.avatar_updated_at, u.updated_at
+		FROM sync_removals r
+		LEFT JOIN users u ON u.id = r.ref_id
+		WHERE r.user_id = $1 AND r.kind = 'friendship' AND r.seq > $2 AND r.seq <= $3
+		  AND NOT EXISTS (
+		    SELECT 1 FROM friendships f
+		    WHERE (f.requester_id = $1 AND f.addressee_id = r.ref_id)
+		       OR (f.requester_id = r.ref_id AND f.addressee_id = $1)
+		  )
+		GROUP BY r.ref_id, u.username, u.display_name, u.avatar_path, u.avatar_updated_at, u.updated_at
+		ORDER BY 2 ASC
+	`
+	removedRows, err := s.pool.Query(ctx, qRemoved, userID, since, until)
+	if err != nil {
+		return nil, 0, fmt.Errorf("sync removed friendships: %w", err)
+	}
+	defer removedRows.Close()
+
+	for removedRows.Next() {
+		var (
+			otherUUID     pgtype.UUID
+			seq           int64
+			removedAt     time.Time
+			username      pgtype.Text
+			displayName   pgtype.Text
+			avatarPath    pgtype.Text
+			avatarUpdated pgtype.Timestamptz
+			userUpdated   pgtype.Timestamptz
+		)
+		if err := removedRows.Scan(&otherUUID, &seq, &removedAt, &username, &displayName, &avatarPath, &avatarUpdated, &userUpdated); err != nil {
+			return nil, 0, fmt.Errorf("scan removed friendship: %w", err)
+		}
+		out = append(out, domain.FriendConnection{
+			User: domain.UserSummary{
+				ID:              uuidOrEmpty(otherUUID),
+				Username:        textOrEmpty(username),
+				DisplayName:     textOrEmpty(displayName),
+				AvatarPath:      textOrEmpty(avatarPath),
+				AvatarUpdatedAt: timestamptzPtr(avatarUpdated),
+				UpdatedAt:       timestamptzPtr(userUpdated),
+			},
+			Status:    domain.FriendStatusRemoved,
+			UpdatedAt: removedAt,
+		})
+		maxSeq = max(maxSeq, seq)
+	}
+	if err := removedRows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("sync removed friendships: %w", err)
+	}
+	return out, maxSeq, nil
+}
+
+func (s *SyncStore) removedMatches(ctx context.Context, userID string, since, until int64) ([]string, int64, error) {
+	const q = `
+		SELECT r.ref_id, MAX(r.seq)
+		FROM sync_removals r
+		WHERE r.user_id = $1 AND r.kind = 'match' AND r.seq > $2 AND r.seq <= $3
+		  AND NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = r.ref_id AND p.user_id = $1)
+		  AND NOT EXISTS (
+		    SELECT 1 FROM matches m
+		    JOIN group_members gm ON gm.group_id = m.group_id AND gm.user_id = $1
+		    WHERE m.id = r.ref_id
+		  )
+		GROUP BY r.ref_id
+		ORDER BY 2 ASC
+	`
+	rows, err := s.pool.Query(ctx, q, userID, since, until)
+	if err != nil {
+		return nil, 0, fmt.Errorf("sync removed matches: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		out    []string
+		maxSeq int64
+	)
+	for rows.Next() {
+		var idUUID pgtype.UUID
+		var seq int64
+		if err := rows.Scan(&idUUID, &seq); err != nil {
+			return nil, 0, fmt.Errorf("scan removed match: %w", err)
+		}
+		out = append(out, uuidOrEmpty(idUUID))
+		maxSeq = max(maxSeq, seq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("sync removed matches: %w", err)
+	}
+	return out, maxSeq, nil
+}
+
+func (s *SyncStore) matchChanges(ctx context.Context, userID string, since, until int64, limit int) ([]domain.Match, int64, bool, error) {
+	// Deleted matches are included so clients receive the tombstone. A group match is also sent
+	// when the user joins the group, so its seq is the later of the match's and the membership's.
+	const q = `
+		SELECT ` + matchColumns + `, v.seq
+		FROM (
+		  SELECT id, MAX(seq) AS seq
+		  FROM (
+		    SELECT m.id, m.sync_seq AS seq
+		    FROM matches m
+		    WHERE m.sync_seq > $2 AND m.sync_seq <= $4
+		      AND (
+		        EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = m.id AND p.user_id = $1)
+		        OR (
+		          NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = m.id)
+		          AND EXISTS (SELECT 1 FROM match_players mp WHERE mp.match_id = m.id AND mp.user_id = $1)
+		        )
+		      )
+		    UNION ALL
+		    SELECT m.id, GREATEST(m.sync_seq, gm.sync_seq)
+		    FROM group_members gm
+		    JOIN matches m ON m.group_id = gm.group_id
+		    WHERE gm.user_id = $1
+		      AND GREATEST(m.sync_seq, gm.sync_seq) > $2
+		      AND m.sync_seq <= $4 AND gm.sync_seq <= $4
+		  ) visible
+		  GROUP BY id
+		) v
+		JOIN matches m ON m.id = v.id
+		ORDER BY v.seq ASC
+		LIMIT $3
+	`
+	rows, err := s.pool.Query(ctx, q, userID, since, limit+1, until)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("sync matches: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		out  []domain.Match
+		seqs []int64
+	)
+	for rows.Next() {
+		var seq int64
+		m, err := scanMatch(rows, &seq)
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("scan sync match: %w", err)
+		}
+		out = append(out, m)
+		seqs = append(seqs, seq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, false, fmt.Errorf("sync matches: %w", err)
+	}
+
+	hasMore := len(out) > limit
+	if hasMore {
+		out = out[:limit]
+		seqs = seqs[:limit]
+	}
+	for i := range out {
+		if err := s.matches.fillMatch(ctx, &out[i]); err != nil {
+			return nil, 0, false, err
+		}
+	}
+
+	var lastSeq int64
+	if len(seqs) > 0 {
+		lastSeq = seqs[len(seqs)-1]
+	}
+	return out, lastSeq, hasMore, nil
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testPool connects to a migrated database named by APP_TEST_DB_DSN; tests skip without one.
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv("APP_TEST_DB_DSN")
+	if dsn == "" {
+		t.Skip("APP_TEST_DB_DSN not set")
+	}
+	pool, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func testUser(t *testing.T, pool *pgxpool.Pool, name string) string {
+	t.Helper()
+	name = fmt.Sprintf("%s%d", name, time.Now().UnixNano())
+	var id string
+	err := pool.QueryRow(context.Background(), `
+		INSERT INTO users (email, username, password_hash, created_at, updated_at)
+		VALUES ($1, $2, 'x', now(), now())
+		RETURNING id::text
+	`, name+"@example.com", name).Scan(&id)
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), `DELETE FROM users WHERE id = $1`, id)
+	})
+	return id
+}
+
+func TestChangesSinceInterleavedWriters(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	store := NewSyncStore(pool)
+
+	userID := testUser(t, pool, "synca")
+	friendID := testUser(t, pool, "syncb")
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO friendships (requester_id, addressee_id, status, created_at, updated_at)
+		VALUES ($1, $2, 'accepted', now(), now())
+	`, userID, friendID); err != nil {
+		t.Fatalf("create friendship: %v", err)
+	}
+	start, err := store.ChangesSince(ctx, userID, 0, 10)
+	if err != nil {
+		t.Fatalf("initial sync: %v", err)
+	}
+
+	// Writer A takes the lower seq but commits after writer B.
+	txA, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin A: %v", err)
+	}
+	defer txA.Rollback(ctx)
+	if _, err := txA.Exec(ctx, `UPDATE users SET updated_at = now() + interval '1 second' WHERE id = $1`, userID); err != nil {
+		t.Fatalf("write A: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `UPDATE users SET updated_at = now() + interval '1 second' WHERE id = $1`, friendID); err != nil {
+		t.Fatalf("write B: %v", err)
+	}
+
+	// Syncing does not wait for writer A, and its cursor stays below A's change.
+	first, err := store.ChangesSince(ctx, userID, start.MaxSeq, 10)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if first.Profile != nil {
+		t.Fatalf("uncommitted profile change returned")
+	}
+	if err := txA.Commit(ctx); err != nil {
+		t.Fatalf("commit A: %v", err)
+	}
+
+	second, err := store.ChangesSince(ctx, userID, first.MaxSeq, 10)
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if second.Profile == nil {
+		t.Fatalf("profile change committed late was skipped by cursor %d", first.MaxSeq)
+	}
+}
+
+func TestChangesSinceGroupMatches(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	store := NewSyncStore(pool)
+
+	ownerID := testUser(t, pool, "syncowner")
+	memberID := testUser(t, pool, "syncmember")
+	var groupID, matchID string
+	if err := pool.QueryRow(ctx, `
+		INSERT INTO groups (name, created_by, join_code) VALUES ('Sync', $1, $2) RETURNING id::text
+	`, ownerID, fmt.Sprintf("sync%d", time.Now().UnixNano())).Scan(&groupID); err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	t.Cleanup(func() { _, _ = pool.Exec(context.Background(), `DELETE FROM groups WHERE id = $1`, groupID) })
+	if err := pool.QueryRow(ctx, `
+		INSERT INTO matches (created_by, played_at, group_id) VALUES ($1, now(), $2) RETURNING id::text
+	`, ownerID, groupID).Scan(&matchID); err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+	t.Cleanup(func() { _, _ = pool.Exec(context.Background(), `DELETE FROM matches WHERE id = $1`, matchID) })
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO match_participants (match_id, seat_index, user_id, place) VALUES ($1, 0, $2, 1)
+	`, matchID, ownerID); err != nil {
+		t.Fatalf("seat owner: %v", err)
+	}
+
+	start, err := store.ChangesSince(ctx, memberID, 0, 10)
+	if err != nil {
+		t.Fatalf("initial sync: %v", err)
+	}
+	if len(start.Matches) != 0 {
+		t.Fatalf("non-member received %d matches", len(start.Matches))
+	}
+
+	// Joining sends the match recorded before the member joined.
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO group_members (group_id, user_id, role) VALUES ($1, $2, 'member')
+	`, groupID, memberID); err != nil {
+		t.Fatalf("join group: %v", err)
+	}
+	joined, err := store.ChangesSince(ctx, memberID, start.MaxSeq, 10)
+	if err != nil {
+		t.Fatalf("sync after join: %v", err)
+	}
+	if len(joined.Matches) != 1 || joined.Matches[0].ID != matchID {
+		t.Fatalf("sync after join: got %d matches, want group match %s", len(joined.Matches), matchID)
+	}
+
+	// Clearing the match's group removes it.
+	if _, err := pool.Exec(ctx, `UPDATE matches SET group_id = NULL WHERE id = $1`, matchID); err != nil {
+		t.Fatalf("clear group: %v", err)
+	}
+	cleared, err := store.ChangesSince(ctx, memberID, joined.MaxSeq, 10)
+	if err != nil {
+		t.Fatalf("sync after clear: %v", err)
+	}
+	if len(cleared.Matches) != 0 || len(cleared.RemovedMatchIDs) != 1 || cleared.RemovedMatchIDs[0] != matchID {
+		t.Fatalf("sync after clear: matches %d, removed %v; want only %s removed", len(cleared.Matches), cleared.RemovedMatchIDs, matchID)
+	}
+
+	// Leaving the group removes its matches too.
+	if _, err := pool.Exec(ctx, `UPDATE matches SET group_id = $2 WHERE id = $1`, matchID, groupID); err != nil {
+		t.Fatalf("restore group: %v", err)
+	}
+	restored, err := store.ChangesSince(ctx, memberID, cleared.MaxSeq, 10)
+	if err != nil {
+		t.Fatalf("sync after restore: %v", err)
+	}
+	if len(restored.Matches) != 1 || len(restored.RemovedMatchIDs) != 0 {
+		t.Fatalf("sync after restore: matches %d, removed %v; want the match back", len(restored.Matches), restored.RemovedMatchIDs)
+	}
+	if _, err := pool.Exec(ctx, `DELETE FROM group_members WHERE group_id = $1 AND user_id = $2`, groupID, memberID); err != nil {
+		t.Fatalf("leave group: %v", err)
+	}
+	left, err := store.ChangesSince(ctx, memberID, restored.MaxSeq, 10)
+	if err != nil {
+		t.Fatalf("sync after leave: %v", err)
+	}
+	if len(left.RemovedMatchIDs) != 1 || left.RemovedMatchIDs[0] != matchID {
+		t.Fatalf("sync after leave: removed %v, want %s", left.RemovedMatchIDs, matchID)
+	}
+}
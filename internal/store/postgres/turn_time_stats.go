@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// timedParticipantsCTE limits participants to rows with usable per-player turn timing.
+// $1 must be the viewing user so their confirmed-only setting applies.
+const timedParticipantsCTE = `
+		WITH timed AS (
+			SELECT p.match_id, p.user_id, p.total_turn_time_ms, p.turns_taken,
+			       p.total_turn_time_ms::float8 / p.turns_taken AS avg_ms
+			FROM match_participants p
+			JOIN matches m ON m.id = p.match_id
+			WHERE m.deleted_at IS NULL
+			  AND p.total_turn_time_ms IS NOT NULL
+			  AND p.turns_taken > 0` + confirmedOnlyFilter + `
+		)`
+
+func (s *MatchesStore) TurnTimeStats(ctx context.Context, userID string) (domain.TurnTimeStats, error) {
+	const q = timedParticipantsCTE + `,
+		pods AS (
+			SELECT match_id,
+			       SUM(total_turn_time_ms)::bigint AS pod_ms,
+			       SUM(turns_taken)::bigint AS pod_turns,
+			       MAX(avg_ms) AS slowest,
+			       MIN(avg_ms) AS fastest,
+			       COUNT(*)::int AS timed_players
+			FROM timed
+			GROUP BY match_id
+		)
+		SELECT
+			m.format,
+			to_char(date_trunc('month', COALESCE(m.played_at, m.created_at) AT TIME ZONE 'UTC'), 'YYYY-MM') AS month,
+			COUNT(*)::int AS matches_timed,
+			SUM(t.total_turn_time_ms)::bigint AS my_ms,
+			SUM(t.turns_taken)::bigint AS my_turns,
+			SUM(pods.pod_ms - t.total_turn_time_ms)::bigint AS others_ms,
+			SUM(pods.pod_turns - t.turns_taken)::bigint AS others_turns,
+			COALESCE(SUM(CASE WHEN pods.timed_players > 1 AND t.avg_ms >= pods.slowest THEN 1 ELSE 0 END), 0)::int AS slowest,
+			COALESCE(SUM(CASE WHEN pods.timed_players > 1 AND t.avg_ms <= pods.fastest THEN 1 ELSE 0 END), 0)::int AS fastest
+		FROM timed t
+		JOIN pods ON pods.match_id = t.match_id
+		JOIN matches m ON m.id = t.match_id
+		WHERE t.user_id = $1
+		GROUP BY m.format, month
+	`
+
+	rows, err := s.pool.Query(ctx, q, userID)
+	if err != nil {
+		return domain.TurnTimeStats{}, fmt.Errorf("turn time stats: %w", err)
+	}
+	defer rows.Close()
+
+	overall := newTurnTimeAccumulator()
+	byFormat := make(map[string]*turnTimeAccumulator)
+	for rows.Next() {
+		var (
+			formatText pgtype.Text
+			month      string
+			row        turnTimeCount
+		)
+		if err := rows.Scan(&formatText, &month, &row.matches, &row.myMs, &row.myTurns, &row.othersMs, &row.othersTurns, &row.slowest, &row.fastest); err != nil {
+			return domain.TurnTimeStats{}, fmt.Errorf("scan turn time stats: %w", err)
+		}
+		format := string(normalizeFormat(formatText))
+		acc, ok := byFormat[format]
+		if !ok {
+			acc = newTurnTimeAccumulator()
+			byFormat[format] = acc
+		}
+		overall.add(month, row)
+		acc.add(month, row)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.TurnTimeStats{}, fmt.Errorf("turn time stats: %w", err)
+	}
+
+	out := overall.stats()
+	if len(byFormat) > 0 {
+		out.ByFormat = make(map[string]domain.TurnTimeStats, len(byFormat))
+		for format, acc := range byFormat {
+			out.ByFormat[format] = acc.stats()
+		}
+	}
+	return out, nil
+}
+
+// turnTimeHeadToHead returns the overall comparison plus one per format; nil when no shared match was timed.
+func (s *MatchesStore) turnTimeHeadToHead(ctx context.Context, userID, opponentID string) (*domain.TurnTimeHeadToHead, map[string]domain.TurnTimeHeadToHead, error) {
+	const q = timedParticipantsCTE + `
+		SELECT
+			m.format,
+			COUNT(*)::int AS matches_timed,
+			SUM(me.total_turn_time_ms)::bigint AS my_ms,
+			SUM(me.turns_taken)::bigint AS my_turns,
+			SUM(opp.total_turn_time_ms)::bigint AS opp_ms,
+			SUM(opp.turns_taken)::bigint AS opp_turns,
+			COALESCE(SUM(CASE WHEN me.avg_ms > opp.avg_ms THEN 1 ELSE 0 END), 0)::int AS you_slower,
+			COALESCE(SUM(CASE WHEN opp.avg_ms > me.avg_ms THEN 1 ELSE 0 END), 0)::int AS opp_slower
+		FROM timed me
+		JOIN timed opp ON opp.match_id = me.match_id AND opp.user_id = $2
+		JOIN matches m ON m.id = me.match_id
+		WHERE me.user_id = $1
+		GROUP BY m.format
+	`
+	rows, err := s.pool.Query(ctx, q, userID, opponentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("turn time head-to-head: %w", err)
+	}
+	defer rows.Close()
+
+	var total turnTimeCount
+	byFormat := make(map[string]domain.TurnTimeHeadToHead)
+	for rows.Next() {
+		var (
+			formatText pgtype.Text
+			row        turnTimeCount
+		)
+		// others* hold the opponent's totals; slowest/fastest hold you_slower/opp_slower.
+		if err := rows.Scan(&formatText, &row.matches, &row.myMs, &row.myTurns, &row.othersMs, &row.othersTurns, &row.slowest, &row.fastest); err != nil {
+			return nil, nil, fmt.Errorf("scan turn time head-to-head: %w", err)
+		}
+		total.add(row)
+		byFormat[string(normalizeFormat(formatText))] = row.headToHead()
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("turn time head-to-head: %w", err)
+	}
+	if total.matches == 0 {
+		return nil, nil, nil
+	}
+	overall := total.headToHead()
+	return &overall, byFormat, nil
+}
+
+type turnTimeCount struct {
+	matches     int
+	myMs        int64
+	myTurns     int64
+	othersMs    int64
+	othersTurns int64
+	slowest     int
+	fastest     int
+}
+
+func (c *turnTimeCount) add(o turnTimeCount) {
+	c.matches += o.matches
+	c.myMs += o.myMs
+	c.myTurns += o.myTurns
+	c.othersMs += o.othersMs
+	c.othersTurns += o.othersTurns
+	c.slowest += o.slowest
+	c.fastest += o.fastest
+}
+
+func (c turnTimeCount) pace() float64 {
+	mine := avgTurnMs(c.myMs, c.myTurns)
+	others := avgTurnMs(c.othersMs, c.othersTurns)
+	if mine == 0 || others == 0 {
+		return 0
+	}
+	return float64(mine) / float64(others)
+}
+
+func (c turnTimeCount) headToHead() domain.TurnTimeHeadToHead {
+	return domain.TurnTimeHeadToHead{
+		MatchesTimed:      c.matches,
+		AvgTurnMs:         avgTurnMs(c.myMs, c.myTurns),
+		OpponentAvgTurnMs: avgTurnMs(c.othersMs, c.othersTurns),
+		YouSlower:         c.slowest,
+		OpponentSlower:    c.fastest,
+	}
+}
+
+type turnTimeAccumulator struct {
+	total   turnTimeCount
+	byMonth map[string]*turnTimeCount
+}
+
+func newTurnTimeAccumulator() *turnTimeAccumulator {
+	return &turnTimeAccumulator{byMonth: make(map[string]*turnTimeCount)}
+}
+
+func (a *turnTimeAccumulator) add(month string, row turnTimeCount) {
+	a.total.add(row)
+	c, ok := a.byMonth[month]
+	if !ok {
+		c = &turnTimeCount{}
+		a.byMonth[month] = c
+	}
+	c.add(row)
+}
+
+func (a *turnTimeAccumulator) stats() domain.TurnTimeStats {
+	trend := make([]domain.TurnTimeTrendPoint, 0, len(a.byMonth))
+	for month, c := range a.byMonth {
+		trend = append(trend, domain.TurnTimeTrendPoint{
+			Month:        month,
+			MatchesTimed: c.matches,
+			AvgTurnMs:    avgTurnMs(c.myMs, c.myTurns),
+			Pace:         c.pace(),
+		})
+	}
+	sort.Slice(trend, func(i, j int) bool { return trend[i].Month < trend[j].Month })
+
+	return domain.TurnTimeStats{
+		MatchesTimed: a.total.matches,
+		AvgTurnMs:    avgTurnMs(a.total.myMs, a.total.myTurns),
+		PodAvgTurnMs: avgTurnMs(a.total.othersMs, a.total.othersTurns),
+		Pace:         a.total.pace(),
+		SlowestInPod: a.total.slowest,
+		FastestInPod: a.total.fastest,
+		Trend:        trend,
+	}
+}
+
+func avgTurnMs(totalMs, turns int64) int64 {
+	if turns <= 0 {
+		return 0
+	}
+	return totalMs / turns
+}
@@ -229,6 +229,9 @@ func (s *UsersStore) SetPasswordHash(ctx context.Context, userID, passwordHash s
 	return nil
 }
 
+// DeleteUser removes the account. The users_anonymize_matches trigger keeps the user's match seats
+// as "Deleted player" guests so other players' history survives, and users_hand_over_groups passes
+// the user's groups to another member.
 func (s *UsersStore) DeleteUser(ctx context.Context, userID string) error {
 	const q = `
 		DELETE FROM users
@@ -280,6 +283,29 @@ func (s *UsersStore) UpdateAvatar(ctx context.Context, userID, avatarPath string
 	return u, tag.RowsAffected() > 0, nil
 }
 
+func (s *UsersStore) GetStatsSettings(ctx context.Context, userID string) (domain.StatsSettings, error) {
+	var settings domain.StatsSettings
+	err := s.pool.QueryRow(ctx, `SELECT stats_confirmed_only FROM users WHERE id = $1`, userID).Scan(&settings.ConfirmedOnly)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.StatsSettings{}, domain.ErrNotFound
+		}
+		return domain.StatsSettings{}, fmt.Errorf("get stats settings: %w", err)
+	}
+	return settings, nil
+}
+
+func (s *UsersStore) UpdateStatsSettings(ctx context.Context, userID string, settings domain.StatsSettings) error {
+	tag, err := s.pool.Exec(ctx, `UPDATE users SET stats_confirmed_only = $2 WHERE id = $1`, userID, settings.ConfirmedOnly)
+	if err != nil {
+		return fmt.Errorf("update stats settings: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
 func (s *UsersStore) GetUserByExternalAccount(ctx context.Context, provider, providerID string) (domain.User, domain.ExternalAccount, error) {
 	const q = `
 		SELECT
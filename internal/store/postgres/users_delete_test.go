@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestDeleteUserAnonymizesAndHandsOver(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+
+	goneID := testUser(t, pool, "delgone")
+	otherID := testUser(t, pool, "delother")
+	adminID := testUser(t, pool, "deladmin")
+	memberID := testUser(t, pool, "delmember")
+
+	exec := func(q string, args ...any) {
+		t.Helper()
+		if _, err := pool.Exec(ctx, q, args...); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	insertID := func(q string, args ...any) string {
+		t.Helper()
+		var id string
+		if err := pool.QueryRow(ctx, q, args...).Scan(&id); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+		return id
+	}
+
+	// A legacy match only lists its players in match_players.
+	legacyID := insertID(`
+		INSERT INTO matches (created_by, played_at, winner_id) VALUES ($1, now(), $1) RETURNING id::text
+	`, otherID)
+	t.Cleanup(func() { _, _ = pool.Exec(ctx, `DELETE FROM matches WHERE id = $1`, legacyID) })
+	exec(`INSERT INTO match_players (match_id, user_id) VALUES ($1, $2), ($1, $3)`, legacyID, goneID, otherID)
+
+	seatedID := insertID(`
+		INSERT INTO matches (created_by, played_at) VALUES ($1, now()) RETURNING id::text
+	`, goneID)
+	t.Cleanup(func() { _, _ = pool.Exec(ctx, `DELETE FROM matches WHERE id = $1`, seatedID) })
+	exec(`
+		INSERT INTO match_participants (match_id, seat_index, user_id, place)
+		VALUES ($1, 0, $2, 1), ($1, 1, $3, 2)
+	`, seatedID, goneID, otherID)
+
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	handedID := insertID(`
+		INSERT INTO groups (name, created_by, join_code) VALUES ('Handed', $1, $2) RETURNING id::text
+	`, goneID, "hand"+suffix)
+	t.Cleanup(func() { _, _ = pool.Exec(ctx, `DELETE FROM groups WHERE id = $1`, handedID) })
+	exec(`
+		INSERT INTO group_members (group_id, user_id, role, joined_at)
+		VALUES ($1, $2, 'owner', now() - interval '3 days'),
+		       ($1, $3, 'member', now() - interval '2 days'),
+		       ($1, $4, 'admin', now() - interval '1 day')
+	`, handedID, goneID, memberID, adminID)
+
+	soloID := insertID(`
+		INSERT INTO groups (name, created_by, join_code) VALUES ('Solo', $1, $2) RETURNING id::text
+	`, goneID, "solo"+suffix)
+	t.Cleanup(func() { _, _ = pool.Exec(ctx, `DELETE FROM groups WHERE id = $1`, soloID) })
+	exec(`INSERT INTO group_members (group_id, user_id, role) VALUES ($1, $2, 'owner')`, soloID, goneID)
+
+	personalID := insertID(`
+		INSERT INTO seasons (owner_id, name, starts_at, ends_at, points)
+		VALUES ($1, 'Personal', now() - interval '7 days', now(), '{3,2,1}') RETURNING id::text
+	`, goneID)
+	groupSeasonID := insertID(`
+		INSERT INTO seasons (owner_id, group_id, name, starts_at, ends_at, points, archived_at, archived_matches)
+		VALUES ($1, $2, 'Group', now() - interval '7 days', now(), '{3,2,1}', now(), 1) RETURNING id::text
+	`, goneID, handedID)
+	t.Cleanup(func() { _, _ = pool.Exec(ctx, `DELETE FROM seasons WHERE id = $1`, groupSeasonID) })
+	exec(`
+		INSERT INTO season_standings (season_id, position, rank, user_id, points, matches_played, wins, avg_place)
+		VALUES ($1, 0, 1, $2, 3, 1, 1, 1), ($1, 1, 2, $3, 2, 1, 0, 2)
+	`, groupSeasonID, goneID, otherID)
+
+	exec(`DELETE FROM users WHERE id = $1`, goneID)
+
+	var tombstone string
+	if err := pool.QueryRow(ctx, `
+		SELECT guest_name FROM match_participants WHERE match_id = $1 AND seat_index = 0
+	`, seatedID).Scan(&tombstone); err != nil {
+		t.Fatalf("read tombstone seat: %v", err)
+	}
+	if tombstone == "" {
+		t.Fatal("deleted seat has no guest name")
+	}
+
+	seatedOther := countRows(t, pool, `
+		SELECT count(*) FROM match_participants WHERE match_id = $1 AND user_id = $2 AND place = 2
+	`, seatedID, otherID)
+	if seatedOther != 1 {
+		t.Fatalf("other player's seat: got %d rows, want 1", seatedOther)
+	}
+
+	legacyWinner := countRows(t, pool, `
+		SELECT count(*) FROM match_participants
+		WHERE match_id = $1 AND user_id IS NULL AND guest_name = $2 AND place = 1
+	`, legacyID, tombstone)
+	legacyOther := countRows(t, pool, `
+		SELECT count(*) FROM match_participants WHERE match_id = $1 AND user_id = $2 AND place = 2
+	`, legacyID, otherID)
+	if legacyWinner != 1 || legacyOther != 1 {
+		t.Fatalf("legacy match seats: tombstone winner %d, other player %d; want 1 and 1", legacyWinner, legacyOther)
+	}
+
+	var ownerID string
+	if err := pool.QueryRow(ctx, `
+		SELECT user_id::text FROM group_members WHERE group_id = $1 AND role = 'owner'
+	`, handedID).Scan(&ownerID); err != nil {
+		t.Fatalf("read new owner: %v", err)
+	}
+	if ownerID != adminID {
+		t.Fatalf("new owner: got %s, want admin %s", ownerID, adminID)
+	}
+	if n := countRows(t, pool, `SELECT count(*) FROM groups WHERE id = $1`, soloID); n != 0 {
+		t.Fatal("group without members was not deleted")
+	}
+
+	if n := countRows(t, pool, `SELECT count(*) FROM seasons WHERE id = $1`, personalID); n != 0 {
+		t.Fatal("personal season was not deleted")
+	}
+	if n := countRows(t, pool, `SELECT count(*) FROM seasons WHERE id = $1 AND owner_id IS NULL`, groupSeasonID); n != 1 {
+		t.Fatal("group season was not kept without an owner")
+	}
+	var standingName *string
+	if err := pool.QueryRow(ctx, `
+		SELECT guest_name FROM season_standings WHERE season_id = $1 AND position = 0 AND user_id IS NULL
+	`, groupSeasonID).Scan(&standingName); err != nil {
+		t.Fatalf("read standing: %v", err)
+	}
+	if standingName == nil || *standingName != tombstone {
+		t.Fatalf("standing name: got %v, want %q", standingName, tombstone)
+	}
+}
+
+func countRows(t *testing.T, pool *pgxpool.Pool, q string, args ...any) int {
+	t.Helper()
+	var n int
+	if err := pool.QueryRow(context.Background(), q, args...).Scan(&n); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	return n
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"MtgLeaderwebserver/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// winConditionStats counts how the user's won and lost matches ended, overall and per format.
+// Untagged and drawn matches are left out. Both results are nil when nothing is tagged.
+func (s *MatchesStore) winConditionStats(ctx context.Context, userID string) (*domain.WinConditionStats, map[string]*domain.WinConditionStats, error) {
+	const q = statsParticipantsCTE + `
+		SELECT
+			m.format,
+			m.win_condition,
+			COALESCE(m.win_condition_tag, '') AS tag,
+			p.place = 1 AS won,
+			COUNT(*)::int AS matches
+		FROM participants p
+		JOIN matches m ON m.id = p.match_id
+		WHERE p.user_id = $1
+		  AND p.outcome = 'win'
+		  AND m.win_condition IS NOT NULL
+		  AND EXISTS (SELECT 1 FROM participants w WHERE w.match_id = p.match_id AND w.place = 1)
+		GROUP BY m.format, m.win_condition, tag, won
+	`
+	rows, err := s.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("win condition stats: %w", err)
+	}
+	defer rows.Close()
+
+	var total winConditionCounts
+	byFormat := make(map[string]*winConditionCounts)
+	for rows.Next() {
+		var (
+			formatText pgtype.Text
+			key        domain.WinConditionCount
+			won        bool
+		)
+		if err := rows.Scan(&formatText, &key.Condition, &key.Tag, &won, &key.Count); err != nil {
+			return nil, nil, fmt.Errorf("scan win condition stats: %w", err)
+		}
+		format := string(normalizeFormat(formatText))
+		if byFormat[format] == nil {
+			byFormat[format] = &winConditionCounts{}
+		}
+		total.add(key, won)
+		byFormat[format].add(key, won)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("win condition stats: %w", err)
+	}
+	if len(byFormat) == 0 {
+		return nil, nil, nil
+	}
+
+	out := make(map[string]*domain.WinConditionStats, len(byFormat))
+	for format, c := range byFormat {
+		out[format] = c.stats()
+	}
+	return total.stats(), out, nil
+}
+
+// winConditionCounts adds up matches per win condition and custom tag.
+type winConditionCounts struct {
+	wins   map[domain.WinConditionCount]int
+	losses map[domain.WinConditionCount]int
+}
+
+func (c *winConditionCounts) add(row domain.WinConditionCount, won bool) {
+	if c.wins == nil {
+		c.wins = make(map[domain.WinConditionCount]int)
+		c.losses = make(map[domain.WinConditionCount]int)
+	}
+	count := row.Count
+	row.Count = 0
+	if won {
+		c.wins[row] += count
+	} else {
+		c.losses[row] += count
+	}
+}
+
+func (c *winConditionCounts) stats() *domain.WinConditionStats {
+	return &domain.WinConditionStats{
+		Wins:   sortedWinConditions(c.wins),
+		Losses: sortedWinConditions(c.losses),
+	}
+}
+
+// sortedWinConditions lists the most common endings first, then by condition and tag.
+func sortedWinConditions(counts map[domain.WinConditionCount]int) []domain.WinConditionCount {
+	out := make([]domain.WinConditionCount, 0, len(counts))
+	for key, count := range counts {
+		key.Count = count
+		out = append(out, key)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		if out[i].Condition != out[j].Condition {
+			return out[i].Condition < out[j].Condition
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	return out
+}
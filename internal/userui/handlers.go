@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -261,9 +262,38 @@ func (a *app) handleProfileGet(w http.ResponseWriter, r *http.Request) {
 		Notice:      mapProfileNotice(strings.TrimSpace(r.URL.Query().Get("notice"))),
 		Error:       mapProfileError(strings.TrimSpace(r.URL.Query().Get("error"))),
 	}
+	if settings, err := a.profileSvc.StatsSettings(r.Context(), u.ID); err != nil {
+		a.logger.Error("userui: load stats settings failed", "err", err)
+	} else {
+		data.StatsConfirmedOnly = settings.ConfirmedOnly
+	}
 	a.templates.renderProfile(w, http.StatusOK, data)
 }
 
+func (a *app) handleProfileStatsPost(w http.ResponseWriter, r *http.Request) {
+	if a.profileSvc == nil {
+		a.templates.renderError(w, http.StatusServiceUnavailable, "Unavailable", "Profile is unavailable.")
+		return
+	}
+	u, _, ok := a.currentUser(r)
+	if !ok {
+		http.Redirect(w, r, "/app/login", http.StatusFound)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, "/app/profile?error=invalid_form", http.StatusFound)
+		return
+	}
+
+	settings := domain.StatsSettings{ConfirmedOnly: r.FormValue("confirmed_only") != ""}
+	if _, err := a.profileSvc.UpdateStatsSettings(r.Context(), u.ID, settings); err != nil {
+		a.logger.Error("userui: update stats settings failed", "err", err)
+		a.templates.renderError(w, http.StatusInternalServerError, "Error", "Failed to save stats setting")
+		return
+	}
+	http.Redirect(w, r, "/app/profile?notice=stats_saved", http.StatusFound)
+}
+
 func (a *app) handleProfilePost(w http.ResponseWriter, r *http.Request) {
 	if a.profileSvc == nil {
 		a.templates.renderError(w, http.StatusServiceUnavailable, "Unavailable", "Profile is unavailable.")
@@ -517,6 +547,7 @@ func (a *app) handleFriends(w http.ResponseWriter, r *http.Request) {
 			display = f.Username
 		}
 		data.Friends = append(data.Friends, friendCard{
+			ID:          f.ID,
 			Username:    f.Username,
 			DisplayName: display,
 			AvatarURL:   avatarURLForSummary(f),
@@ -535,6 +566,14 @@ func (a *app) handleFriends(w http.ResponseWriter, r *http.Request) {
 	}
 	data.Incoming = overview.Incoming
 	data.Outgoing = overview.Outgoing
+	if a.claimSvc != nil {
+		claims, err := a.claimSvc.List(r.Context(), u.ID)
+		if err != nil {
+			a.logger.Error("userui: list guest claims failed", "err", err)
+		} else {
+			data.Claims = &claims
+		}
+	}
 
 	friendIDs := make(map[string]bool, len(overview.Friends))
 	for _, f := range overview.Friends {
@@ -610,7 +649,7 @@ func (a *app) handleStats(w http.ResponseWriter, r *http.Request) {
 		Title:   "Stats",
 		User:    u,
 		Summary: summary,
-		Formats: formatStats(summary.ByFormat),
+		Formats: formatStats(summary.ByFormat, a.formatOrder(r.Context())),
 		Error:   mapErrorCode(strings.TrimSpace(r.URL.Query().Get("error"))),
 		Notice:  mapNoticeCode(strings.TrimSpace(r.URL.Query().Get("notice"))),
 	}
@@ -626,6 +665,21 @@ func (a *app) handleStats(w http.ResponseWriter, r *http.Request) {
 			Count:    summary.MostOftenBeatsYou.Count,
 		}
 	}
+	if summary.WinConditions != nil {
+		data.WinConditions = winConditionStats(summary, a.formatOrder(r.Context()))
+	}
+	if elims, err := a.matchSvc.Eliminations(r.Context(), u.ID); err != nil {
+		a.logger.Error("userui: elimination stats failed", "err", err)
+	} else if elims.TimesEliminated > 0 || elims.Eliminations > 0 {
+		data.Eliminations = eliminationStats(elims, a.formatOrder(r.Context()))
+	}
+	if a.deckSvc != nil {
+		if decks, err := a.deckSvc.ListStats(r.Context(), u.ID); err != nil {
+			a.logger.Error("userui: deck stats failed", "err", err)
+		} else {
+			data.Decks = deckStatRows(decks)
+		}
+	}
 
 	a.templates.renderStats(w, http.StatusOK, data)
 }
@@ -641,7 +695,12 @@ func (a *app) handleMatchesList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	matches, err := a.matchSvc.ListMatches(r.Context(), u.ID, 25)
+	cursor := strings.TrimSpace(r.URL.Query().Get("cursor"))
+	matches, nextCursor, err := a.matchSvc.ListMatches(r.Context(), u.ID, service.ListMatchesParams{Limit: 25, Cursor: cursor})
+	if errors.Is(err, domain.ErrValidation) {
+		http.Redirect(w, r, "/app/matches", http.StatusFound)
+		return
+	}
 	if err != nil {
 		a.logger.Error("userui: list matches failed", "err", err)
 		a.templates.renderError(w, http.StatusInternalServerError, "Error", "Failed to load matches")
@@ -662,11 +721,13 @@ func (a *app) handleMatchesList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := matchesViewData{
-		Title:   "Matches",
-		User:    u,
-		Matches: rows,
-		Error:   mapErrorCode(strings.TrimSpace(r.URL.Query().Get("error"))),
-		Notice:  mapNoticeCode(strings.TrimSpace(r.URL.Query().Get("notice"))),
+		Title:      "Matches",
+		User:       u,
+		Matches:    rows,
+		IsFirst:    cursor == "",
+		NextCursor: nextCursor,
+		Error:      mapErrorCode(strings.TrimSpace(r.URL.Query().Get("error"))),
+		Notice:     mapNoticeCode(strings.TrimSpace(r.URL.Query().Get("notice"))),
 	}
 
 	a.templates.renderMatches(w, http.StatusOK, data)
@@ -706,17 +767,127 @@ func (a *app) handleMatchesDetail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := matchDetailViewData{
-		Title:    "Match detail",
-		User:     u,
-		Match:    m,
-		PlayedAt: formatPlayedAt(m.PlayedAt, m.CreatedAt),
-		Duration: formatDuration(m.TotalDurationSeconds),
-		AvgTurn:  avgTurn,
+		Title:     "Match detail",
+		User:      u,
+		Match:     m,
+		PlayedAt:  formatPlayedAt(m.PlayedAt, m.CreatedAt),
+		Duration:  formatDuration(m.TotalDurationSeconds),
+		AvgTurn:   avgTurn,
+		CanDelete: m.CreatedBy == u.ID,
+		Error:     mapErrorCode(strings.TrimSpace(r.URL.Query().Get("error"))),
+		Notice:    mapMatchNotice(strings.TrimSpace(r.URL.Query().Get("notice"))),
+	}
+	if m.WinCondition != "" {
+		data.WinCondition = winConditionLabel(m.WinCondition, m.WinConditionTag)
+	}
+	if m.CreatedBy != u.ID {
+		for _, p := range m.Players {
+			if p.User.ID == u.ID && p.Confirmation != "" {
+				data.Confirmation = string(p.Confirmation)
+				data.DisputeReason = p.DisputeReason
+				break
+			}
+		}
+	}
+	if a.eventSvc != nil {
+		if turns, err := a.eventSvc.Replay(r.Context(), u.ID, m.ID); err != nil {
+			a.logger.Error("userui: match events failed", "err", err)
+		} else if len(turns) > 0 {
+			data.Replay = matchReplay(m, turns)
+		}
 	}
 
 	a.templates.renderMatch(w, http.StatusOK, data)
 }
 
+func (a *app) handleMatchesConfirmPost(w http.ResponseWriter, r *http.Request) {
+	a.matchReviewPost(w, r, false)
+}
+
+func (a *app) handleMatchesDisputePost(w http.ResponseWriter, r *http.Request) {
+	a.matchReviewPost(w, r, true)
+}
+
+// matchReviewPost confirms or disputes the match in the path for the signed-in user.
+func (a *app) matchReviewPost(w http.ResponseWriter, r *http.Request, dispute bool) {
+	if a.matchSvc == nil {
+		a.templates.renderError(w, http.StatusServiceUnavailable, "Unavailable", "Matches are unavailable.")
+		return
+	}
+	u, _, ok := a.currentUser(r)
+	if !ok {
+		http.Redirect(w, r, "/app/login", http.StatusFound)
+		return
+	}
+
+	matchID := strings.TrimSpace(r.PathValue("id"))
+	if matchID == "" {
+		a.templates.renderError(w, http.StatusBadRequest, "Invalid", "Match id is required.")
+		return
+	}
+	target := "/app/matches/" + url.PathEscape(matchID)
+	if err := r.ParseForm(); err != nil {
+		http.Redirect(w, r, target+"?error=invalid_form", http.StatusFound)
+		return
+	}
+
+	var err error
+	notice := "match_confirmed"
+	if dispute {
+		notice = "match_disputed"
+		_, err = a.matchSvc.DisputeMatch(r.Context(), u.ID, matchID, r.FormValue("reason"))
+	} else {
+		_, err = a.matchSvc.ConfirmMatch(r.Context(), u.ID, matchID)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			a.templates.renderError(w, http.StatusNotFound, "Not found", "Match not found.")
+		case errors.Is(err, domain.ErrValidation):
+			http.Redirect(w, r, target+"?error=dispute_reason", http.StatusFound)
+		default:
+			a.logger.Error("userui: review match failed", "err", err)
+			a.templates.renderError(w, http.StatusInternalServerError, "Error", "Failed to update match")
+		}
+		return
+	}
+
+	http.Redirect(w, r, target+"?notice="+notice, http.StatusFound)
+}
+
+func (a *app) handleMatchesDeletePost(w http.ResponseWriter, r *http.Request) {
+	if a.matchSvc == nil {
+		a.templates.renderError(w, http.StatusServiceUnavailable, "Unavailable", "Matches are unavailable.")
+		return
+	}
+	u, _, ok := a.currentUser(r)
+	if !ok {
+		http.Redirect(w, r, "/app/login", http.StatusFound)
+		return
+	}
+
+	matchID := strings.TrimSpace(r.PathValue("id"))
+	if matchID == "" {
+		a.templates.renderError(w, http.StatusBadRequest, "Invalid", "Match id is required.")
+		return
+	}
+
+	if err := a.matchSvc.DeleteMatch(r.Context(), u.ID, matchID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			a.templates.renderError(w, http.StatusNotFound, "Not found", "Match not found.")
+		case errors.Is(err, domain.ErrForbidden):
+			http.Redirect(w, r, "/app/matches/"+url.PathEscape(matchID)+"?error=match_not_creator", http.StatusFound)
+		default:
+			a.logger.Error("userui: delete match failed", "err", err)
+			a.templates.renderError(w, http.StatusInternalServerError, "Error", "Failed to delete match")
+		}
+		return
+	}
+
+	http.Redirect(w, r, "/app/matches?notice=match_deleted", http.StatusFound)
+}
+
 func (a *app) handleFriendRequest(w http.ResponseWriter, r *http.Request) {
 	if a.friendsSvc == nil {
 		a.templates.renderError(w, http.StatusServiceUnavailable, "Unavailable", uiUnavailableMsg)
@@ -930,6 +1101,14 @@ func mapNoticeCode(code string) string {
 		return "Friend request declined."
 	case "request_cancelled":
 		return "Friend request canceled."
+	case "match_deleted":
+		return "Match deleted."
+	case "claim_sent":
+		return "Guest history offered. It moves over once your friend accepts."
+	case "claim_accepted":
+		return "Guest history added to your matches."
+	case "claim_removed":
+		return "Guest history offer removed."
 	default:
 		return ""
 	}
@@ -962,6 +1141,27 @@ func mapErrorCode(code string) string {
 		return "Friend request not found."
 	case "invalid_request":
 		return "Invalid request."
+	case "match_not_creator":
+		return "Only the player who recorded this match can change it."
+	case "dispute_reason":
+		return "Tell the other players what is wrong with the result (up to 500 characters)."
+	case "claim_invalid":
+		return "Pick a friend and a guest name you used in matches you recorded."
+	case "claim_not_friend":
+		return "You can only offer guest history to friends."
+	case "claim_not_found":
+		return "Guest history offer not found."
+	default:
+		return ""
+	}
+}
+
+func mapMatchNotice(code string) string {
+	switch code {
+	case "match_confirmed":
+		return "Thanks. The result is confirmed."
+	case "match_disputed":
+		return "Dispute recorded. The player who recorded the match can see your reason."
 	default:
 		return ""
 	}
@@ -973,6 +1173,8 @@ func mapProfileNotice(code string) string {
 		return "Profile updated."
 	case "avatar_saved":
 		return "Avatar updated."
+	case "stats_saved":
+		return "Stats setting saved."
 	default:
 		return ""
 	}
@@ -1003,26 +1205,44 @@ func (a *app) friendStats(ctx context.Context, userID string, friends []domain.U
 			return nil, err
 		}
 		items = append(items, domain.FriendStatsListItem{
-			Friend:   friend,
-			Total:    stats.Total,
-			Wins:     stats.Wins,
-			Losses:   stats.Losses,
-			CoLosses: stats.CoLosses,
+			Friend:       friend,
+			Total:        stats.Total,
+			Wins:         stats.Wins,
+			Losses:       stats.Losses,
+			Draws:        stats.Draws,
+			CoLosses:     stats.CoLosses,
+			AsTeammates:  stats.AsTeammates,
+			WinsTogether: stats.WinsTogether,
 		})
 	}
 	return items, nil
 }
 
-func formatStats(input map[string]domain.StatsSummary) []formatStatRow {
-	if len(input) == 0 {
-		return nil
+// formatOrder lists format slugs in the registry's picker order, disabled formats included.
+func (a *app) formatOrder(ctx context.Context) []string {
+	if a.formatSvc != nil {
+		formats, err := a.formatSvc.List(ctx, true)
+		if err == nil {
+			order := make([]string, 0, len(formats))
+			for _, f := range formats {
+				order = append(order, string(f.Slug))
+			}
+			return order
+		}
+		a.logger.Error("userui: list formats failed", "err", err)
 	}
-	order := []string{
+	return []string{
 		string(domain.FormatCommander),
 		string(domain.FormatBrawl),
 		string(domain.FormatStandard),
 		string(domain.FormatModern),
 	}
+}
+
+func formatStats(input map[string]domain.StatsSummary, order []string) []formatStatRow {
+	if len(input) == 0 {
+		return nil
+	}
 	rows := make([]formatStatRow, 0, len(input))
 	seen := make(map[string]bool, len(input))
 	for _, key := range order {
@@ -1054,6 +1274,221 @@ func formatStats(input map[string]domain.StatsSummary) []formatStatRow {
 	return rows
 }
 
+func eliminationStats(stats domain.EliminationStats, order []string) *eliminationSection {
+	section := &eliminationSection{
+		Overall: eliminationStatRow("", stats),
+		Victims: eliminationOpponentRows(stats.Eliminated),
+		Killers: eliminationOpponentRows(stats.EliminatedBy),
+	}
+	seen := make(map[string]bool, len(stats.ByFormat))
+	for _, key := range order {
+		if formatStats, ok := stats.ByFormat[key]; ok {
+			seen[key] = true
+			section.Formats = append(section.Formats, eliminationStatRow(key, formatStats))
+		}
+	}
+	for key, formatStats := range stats.ByFormat {
+		if !seen[key] {
+			section.Formats = append(section.Formats, eliminationStatRow(key, formatStats))
+		}
+	}
+	return section
+}
+
+// winConditionStats only lists formats with at least one tagged match.
+func winConditionStats(summary domain.StatsSummary, order []string) *winConditionSection {
+	section := &winConditionSection{Overall: winConditionStatRow("", summary.WinConditions)}
+	seen := make(map[string]bool, len(summary.ByFormat))
+	for _, key := range order {
+		if formatStats, ok := summary.ByFormat[key]; ok && formatStats.WinConditions != nil {
+			seen[key] = true
+			section.Formats = append(section.Formats, winConditionStatRow(key, formatStats.WinConditions))
+		}
+	}
+	for key, formatStats := range summary.ByFormat {
+		if !seen[key] && formatStats.WinConditions != nil {
+			section.Formats = append(section.Formats, winConditionStatRow(key, formatStats.WinConditions))
+		}
+	}
+	return section
+}
+
+func winConditionStatRow(format string, stats *domain.WinConditionStats) winConditionRow {
+	return winConditionRow{
+		Format: format,
+		Wins:   winConditionChips(stats.Wins),
+		Losses: winConditionChips(stats.Losses),
+	}
+}
+
+func winConditionChips(counts []domain.WinConditionCount) []winConditionChip {
+	chips := make([]winConditionChip, 0, len(counts))
+	for _, c := range counts {
+		chips = append(chips, winConditionChip{Label: winConditionLabel(c.Condition, c.Tag), Count: c.Count})
+	}
+	return chips
+}
+
+// winConditionLabel shows custom endings by their tag.
+func winConditionLabel(condition domain.WinCondition, tag string) string {
+	switch condition {
+	case domain.WinConditionCombat:
+		return "Combat"
+	case domain.WinConditionCommanderDamage:
+		return "Commander damage"
+	case domain.WinConditionCombo:
+		return "Combo"
+	case domain.WinConditionMill:
+		return "Mill"
+	case domain.WinConditionPoison:
+		return "Poison"
+	case domain.WinConditionConcession:
+		return "Concession"
+	case domain.WinConditionAltWin:
+		return "Alt-win card"
+	case domain.WinConditionCustom:
+		return tag
+	default:
+		return string(condition)
+	}
+}
+
+// deckStatRows skips decks that have not been played yet.
+// matchReplay lays out the game log as one row per turn with a column per seat.
+func matchReplay(m domain.Match, turns []domain.MatchReplayTurn) *matchReplayView {
+	labels := make(map[int]string)
+	for _, p := range m.Players {
+		if p.SeatIndex == nil {
+			continue
+		}
+		switch {
+		case p.GuestName != "":
+			labels[*p.SeatIndex] = p.GuestName
+		case p.User.Username != "":
+			labels[*p.SeatIndex] = "@" + p.User.Username
+		default:
+			labels[*p.SeatIndex] = fmt.Sprintf("Seat %d", *p.SeatIndex+1)
+		}
+	}
+
+	view := &matchReplayView{Turns: make([]matchReplayRow, 0, len(turns))}
+	if len(turns) > 0 {
+		for _, st := range turns[0].Seats {
+			view.Seats = append(view.Seats, labels[st.SeatIndex])
+		}
+	}
+	for _, t := range turns {
+		row := matchReplayRow{Turn: t.Turn}
+		for _, st := range t.Seats {
+			cell := matchReplayCell{Life: st.Life, Poison: st.Poison, Eliminated: st.Eliminated}
+			sources := make([]int, 0, len(st.CommanderDamage))
+			for src := range st.CommanderDamage {
+				sources = append(sources, src)
+			}
+			sort.Ints(sources)
+			parts := make([]string, 0, len(sources))
+			for _, src := range sources {
+				parts = append(parts, fmt.Sprintf("%d from %s", st.CommanderDamage[src], labels[src]))
+			}
+			cell.CommanderDamage = strings.Join(parts, ", ")
+			row.Cells = append(row.Cells, cell)
+		}
+		for _, e := range t.Events {
+			row.Events = append(row.Events, describeMatchEvent(e, labels))
+		}
+		view.Turns = append(view.Turns, row)
+	}
+	return view
+}
+
+func describeMatchEvent(e domain.MatchEvent, labels map[int]string) string {
+	change := func(what string) string {
+		if e.Total != nil {
+			return fmt.Sprintf("%s %s to %d", labels[e.SeatIndex], what, *e.Total)
+		}
+		return fmt.Sprintf("%s %s %+d", labels[e.SeatIndex], what, e.Amount)
+	}
+	switch e.Kind {
+	case domain.MatchEventLife:
+		return change("life")
+	case domain.MatchEventPoison:
+		return change("poison")
+	case domain.MatchEventCommanderDamage:
+		src := ""
+		if e.SourceSeatIndex != nil {
+			src = labels[*e.SourceSeatIndex]
+		}
+		return change("commander damage from " + src)
+	case domain.MatchEventEliminated:
+		return labels[e.SeatIndex] + " eliminated"
+	case domain.MatchEventTurnPass:
+		return labels[e.SeatIndex] + " starts their turn"
+	default:
+		return string(e.Kind)
+	}
+}
+
+func deckStatRows(stats []domain.DeckStats) []deckStatRow {
+	rows := make([]deckStatRow, 0, len(stats))
+	for _, st := range stats {
+		if st.MatchesPlayed == 0 {
+			continue
+		}
+		colors := st.Deck.ColorIdentity
+		if colors == "" {
+			colors = "C"
+		}
+		rows = append(rows, deckStatRow{
+			Name:          st.Deck.Name,
+			Format:        string(st.Deck.Format),
+			ColorIdentity: colors,
+			Archived:      st.Deck.Archived,
+			MatchesPlayed: st.MatchesPlayed,
+			Wins:          st.Wins,
+			Losses:        st.Losses,
+			WinPct:        fmt.Sprintf("%.0f%%", st.WinPct*100),
+			AvgPlace:      fmt.Sprintf("%.1f", st.AvgPlace),
+		})
+	}
+	return rows
+}
+
+func eliminationStatRow(format string, stats domain.EliminationStats) eliminationRow {
+	row := eliminationRow{
+		Format:          format,
+		Eliminations:    stats.Eliminations,
+		TimesEliminated: stats.TimesEliminated,
+		AvgTurn:         "—",
+	}
+	if stats.AvgEliminationTurn > 0 {
+		row.AvgTurn = fmt.Sprintf("%.1f", stats.AvgEliminationTurn)
+	}
+	if stats.MostEliminated != nil {
+		r := eliminationOpponentRow(*stats.MostEliminated)
+		row.MostEliminated = &r
+	}
+	if stats.MostEliminatedBy != nil {
+		r := eliminationOpponentRow(*stats.MostEliminatedBy)
+		row.MostEliminatedBy = &r
+	}
+	return row
+}
+
+func eliminationOpponentRows(opps []domain.EliminationOpponent) []opponentStatRow {
+	rows := make([]opponentStatRow, 0, len(opps))
+	for _, opp := range opps {
+		rows = append(rows, eliminationOpponentRow(opp))
+	}
+	return rows
+}
+
+func eliminationOpponentRow(opp domain.EliminationOpponent) opponentStatRow {
+	if opp.Opponent != nil {
+		return opponentStatRow{Username: "@" + opp.Opponent.Username, Count: opp.Count}
+	}
+	return opponentStatRow{Username: opp.GuestName + " (guest)", Count: opp.Count}
+}
+
 func formatDuration(seconds int) string {
 	if seconds <= 0 {
 		return "—"
@@ -1081,8 +1516,14 @@ func formatPlayedAt(playedAt *time.Time, createdAt time.Time) string {
 }
 
 func matchWinner(match domain.Match) string {
+	switch match.Outcome {
+	case domain.MatchOutcomeDraw:
+		return "Draw"
+	case domain.MatchOutcomeAbandoned:
+		return "Abandoned"
+	}
 	if match.WinnerID == "" {
-		return "—"
+		return teamWinners(match)
 	}
 	for _, p := range match.Players {
 		if p.User.ID == match.WinnerID {
@@ -1092,6 +1533,25 @@ func matchWinner(match domain.Match) string {
 	return "—"
 }
 
+// teamWinners names every member of the winning team, or "—" when the match has no teams.
+func teamWinners(match domain.Match) string {
+	var names []string
+	for _, p := range match.Players {
+		if p.Team == nil || !p.IsWinner {
+			continue
+		}
+		if p.User.ID != "" {
+			names = append(names, "@"+p.User.Username)
+		} else {
+			names = append(names, p.GuestName)
+		}
+	}
+	if len(names) == 0 {
+		return "—"
+	}
+	return strings.Join(names, " & ")
+}
+
 func avatarURL(u domain.User) string {
 	updatedAt := u.AvatarUpdatedAt
 	if updatedAt == nil {
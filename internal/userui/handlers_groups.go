@@ -0,0 +1,357 @@
+package userui
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"MtgLeaderwebserver/internal/domain"
+	"MtgLeaderwebserver/internal/service"
+)
+
+func (a *app) handleGroups(w http.ResponseWriter, r *http.Request) {
+	if a.groupSvc == nil {
+		a.templates.renderError(w, http.StatusServiceUnavailable, "Unavailable", "Playgroups are unavailable.")
+		return
+	}
+	u, _, ok := a.currentUser(r)
+	if !ok {
+		http.Redirect(w, r, "/app/login", http.StatusFound)
+		return
+	}
+
+	groups, err := a.groupSvc.List(r.Context(), u.ID)
+	if err != nil {
+		a.logger.Error("userui: list groups failed", "err", err)
+		a.templates.renderError(w, http.StatusInternalServerError, "Error", "Failed to load playgroups")
+		return
+	}
+	invites, err := a.groupSvc.Invites(r.Context(), u.ID)
+	if err != nil {
+		a.logger.Error("userui: list group invites failed", "err", err)
+		a.templates.renderError(w, http.StatusInternalServerError, "Error", "Failed to load playgroups")
+		return
+	}
+
+	a.templates.renderGroups(w, http.StatusOK, groupsViewData{
+		Title:   "Playgroups",
+		User:    u,
+		Groups:  groups,
+		Invites: invites,
+		Error:   mapGroupError(strings.TrimSpace(r.URL.Query().Get("error"))),
+		Notice:  mapGroupNotice(strings.TrimSpace(r.URL.Query().Get("notice"))),
+	})
+}
+
+func (a *app) handleGroupDetail(w http.ResponseWriter, r *http.Request) {
+	if a.groupSvc == nil {
+		a.templates.renderError(w, http.StatusServiceUnavailable, "Unavailable", "Playgroups are unavailable.")
+		return
+	}
+	u, _, ok := a.currentUser(r)
+	if !ok {
+		http.Redirect(w, r, "/app/login", http.StatusFound)
+		return
+	}
+
+	groupID := strings.TrimSpace(r.PathValue("id"))
+	group, err := a.groupSvc.Get(r.Context(), u.ID, groupID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			a.templates.renderError(w, http.StatusNotFound, "Not found", "Playgroup not found.")
+			return
+		}
+		a.logger.Error("userui: get group failed", "err", err)
+		a.templates.renderError(w, http.StatusInternalServerError, "Error", "Failed to load playgroup")
+		return
+	}
+
+	data := groupViewData{
+		Title:  group.Name,
+		User:   u,
+		Group:  group,
+		Error:  mapGroupError(strings.TrimSpace(r.URL.Query().Get("error"))),
+		Notice: mapGroupNotice(strings.TrimSpace(r.URL.Query().Get("notice"))),
+	}
+
+	members, err := a.groupSvc.Members(r.Context(), u.ID, groupID)
+	if err != nil {
+		a.logger.Error("userui: list group members failed", "err", err)
+		a.templates.renderError(w, http.StatusInternalServerError, "Error", "Failed to load playgroup")
+		return
+	}
+	data.Members = make([]groupMemberRow, 0, len(members))
+	for _, m := range members {
+		display := strings.TrimSpace(m.User.DisplayName)
+		if display == "" {
+			display = m.User.Username
+		}
+		data.Members = append(data.Members, groupMemberRow{
+			ID:          m.User.ID,
+			Username:    m.User.Username,
+			DisplayName: display,
+			AvatarURL:   avatarURLForSummary(m.User),
+			Role:        string(m.Role),
+			IsSelf:      m.User.ID == u.ID,
+			CanRemove:   m.User.ID != u.ID && (group.Role == domain.GroupRoleOwner || (group.Role == domain.GroupRoleAdmin && m.Role == domain.GroupRoleMember)),
+		})
+	}
+
+	if group.Role.CanManage() {
+		invites, err := a.groupSvc.GroupInvites(r.Context(), u.ID, groupID)
+		if err != nil {
+			a.logger.Error("userui: list group invites failed", "err", err)
+		} else {
+			data.Invites = invites
+		}
+	}
+
+	stats, err := a.groupSvc.Stats(r.Context(), u.ID, groupID, "")
+	if err != nil {
+		a.logger.Error("userui: group stats failed", "err", err)
+		if data.Error == "" {
+			data.Error = "Group stats unavailable."
+		}
+	} else {
+		data.MatchesPlayed = stats.MatchesPlayed
+		data.Standings = make([]groupStandingRow, 0, len(stats.Players))
+		for _, p := range stats.Players {
+			row := groupStandingRow{
+				Name:          p.GuestName,
+				IsGuest:       p.User == nil,
+				MatchesPlayed: p.MatchesPlayed,
+				Wins:          p.Wins,
+				WinPct:        fmt.Sprintf("%.0f%%", p.WinPct*100),
+				AvgPlace:      fmt.Sprintf("%.2f", p.AvgPlace),
+			}
+			if p.User != nil {
+				row.Name = "@" + p.User.Username
+			}
+			data.Standings = append(data.Standings, row)
+		}
+	}
+
+	if a.matchSvc != nil {
+		matches, _, err := a.matchSvc.ListMatches(r.Context(), u.ID, service.ListMatchesParams{GroupID: groupID, Limit: 10})
+		if err != nil {
+			a.logger.Error("userui: list group matches failed", "err", err)
+		} else {
+			data.Matches = make([]matchListItem, 0, len(matches))
+			for _, m := range matches {
+				data.Matches = append(data.Matches, matchListItem{
+					ID:        m.ID,
+					PlayedAt:  formatPlayedAt(m.PlayedAt, m.CreatedAt),
+					Format:    string(m.Format),
+					Duration:  formatDuration(m.TotalDurationSeconds),
+					TurnCount: m.TurnCount,
+					Winner:    matchWinner(m),
+					Players:   len(m.Players),
+				})
+			}
+		}
+	}
+
+	a.templates.renderGroup(w, http.StatusOK, data)
+}
+
+func (a *app) handleGroupCreate(w http.ResponseWriter, r *http.Request) {
+	u, ok := a.groupFormUser(w, r)
+	if !ok {
+		return
+	}
+
+	group, err := a.groupSvc.Create(r.Context(), u.ID, r.FormValue("name"), r.FormValue("description"))
+	if err != nil {
+		a.redirectGroupError(w, r, "", err, "Failed to create playgroup")
+		return
+	}
+	redirectGroups(w, r, group.ID, "group_created", "")
+}
+
+func (a *app) handleGroupJoin(w http.ResponseWriter, r *http.Request) {
+	u, ok := a.groupFormUser(w, r)
+	if !ok {
+		return
+	}
+
+	group, err := a.groupSvc.Join(r.Context(), u.ID, r.FormValue("code"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			redirectGroups(w, r, "", "", "join_code_invalid")
+			return
+		}
+		a.redirectGroupError(w, r, "", err, "Failed to join playgroup")
+		return
+	}
+	redirectGroups(w, r, group.ID, "group_joined", "")
+}
+
+func (a *app) handleGroupInviteAccept(w http.ResponseWriter, r *http.Request) {
+	u, ok := a.groupFormUser(w, r)
+	if !ok {
+		return
+	}
+
+	group, err := a.groupSvc.AcceptInvite(r.Context(), u.ID, strings.TrimSpace(r.FormValue("id")))
+	if err != nil {
+		a.redirectGroupError(w, r, "", err, "Failed to accept invite")
+		return
+	}
+	redirectGroups(w, r, group.ID, "group_joined", "")
+}
+
+func (a *app) handleGroupInviteDecline(w http.ResponseWriter, r *http.Request) {
+	u, ok := a.groupFormUser(w, r)
+	if !ok {
+		return
+	}
+
+	groupID := strings.TrimSpace(r.FormValue("group_id"))
+	if err := a.groupSvc.DeclineInvite(r.Context(), u.ID, strings.TrimSpace(r.FormValue("id"))); err != nil {
+		a.redirectGroupError(w, r, groupID, err, "Failed to decline invite")
+		return
+	}
+	redirectGroups(w, r, groupID, "invite_removed", "")
+}
+
+func (a *app) handleGroupInvite(w http.ResponseWriter, r *http.Request) {
+	u, ok := a.groupFormUser(w, r)
+	if !ok {
+		return
+	}
+
+	groupID := strings.TrimSpace(r.PathValue("id"))
+	if _, err := a.groupSvc.Invite(r.Context(), u.ID, groupID, r.FormValue("username")); err != nil {
+		a.redirectGroupError(w, r, groupID, err, "Failed to send invite")
+		return
+	}
+	redirectGroups(w, r, groupID, "invite_sent", "")
+}
+
+func (a *app) handleGroupJoinCode(w http.ResponseWriter, r *http.Request) {
+	u, ok := a.groupFormUser(w, r)
+	if !ok {
+		return
+	}
+
+	groupID := strings.TrimSpace(r.PathValue("id"))
+	if _, err := a.groupSvc.RotateJoinCode(r.Context(), u.ID, groupID); err != nil {
+		a.redirectGroupError(w, r, groupID, err, "Failed to reset join code")
+		return
+	}
+	redirectGroups(w, r, groupID, "join_code_reset", "")
+}
+
+func (a *app) handleGroupMemberRemove(w http.ResponseWriter, r *http.Request) {
+	u, ok := a.groupFormUser(w, r)
+	if !ok {
+		return
+	}
+
+	groupID := strings.TrimSpace(r.PathValue("id"))
+	memberID := strings.TrimSpace(r.FormValue("user_id"))
+	if err := a.groupSvc.RemoveMember(r.Context(), u.ID, groupID, memberID); err != nil {
+		a.redirectGroupError(w, r, groupID, err, "Failed to remove member")
+		return
+	}
+	if memberID == u.ID {
+		redirectGroups(w, r, "", "group_left", "")
+		return
+	}
+	redirectGroups(w, r, groupID, "member_removed", "")
+}
+
+// groupFormUser resolves the signed-in user for a groups form post.
+func (a *app) groupFormUser(w http.ResponseWriter, r *http.Request) (domain.User, bool) {
+	if a.groupSvc == nil {
+		a.templates.renderError(w, http.StatusServiceUnavailable, "Unavailable", "Playgroups are unavailable.")
+		return domain.User{}, false
+	}
+	u, _, ok := a.currentUser(r)
+	if !ok {
+		http.Redirect(w, r, "/app/login", http.StatusFound)
+		return domain.User{}, false
+	}
+	if err := r.ParseForm(); err != nil {
+		redirectGroups(w, r, "", "", "invalid_form")
+		return domain.User{}, false
+	}
+	return u, true
+}
+
+func (a *app) redirectGroupError(w http.ResponseWriter, r *http.Request, groupID string, err error, failMsg string) {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		redirectGroups(w, r, groupID, "", "invalid_request")
+	case errors.Is(err, domain.ErrAlreadyGroupMember):
+		redirectGroups(w, r, groupID, "", "already_member")
+	case errors.Is(err, domain.ErrForbidden):
+		redirectGroups(w, r, groupID, "", "group_forbidden")
+	case errors.Is(err, domain.ErrNotFound):
+		redirectGroups(w, r, groupID, "", "group_not_found")
+	default:
+		a.logger.Error("userui: group action failed", "err", err)
+		a.templates.renderError(w, http.StatusInternalServerError, "Error", failMsg)
+	}
+}
+
+func redirectGroups(w http.ResponseWriter, r *http.Request, groupID, notice, errCode string) {
+	values := url.Values{}
+	if notice != "" {
+		values.Set("notice", notice)
+	}
+	if errCode != "" {
+		values.Set("error", errCode)
+	}
+
+	target := "/app/groups"
+	if groupID != "" {
+		target += "/" + url.PathEscape(groupID)
+	}
+	if len(values) > 0 {
+		target = target + "?" + values.Encode()
+	}
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+func mapGroupNotice(code string) string {
+	switch code {
+	case "group_created":
+		return "Playgroup created."
+	case "group_joined":
+		return "You joined the playgroup."
+	case "group_left":
+		return "You left the playgroup."
+	case "invite_sent":
+		return "Invite sent."
+	case "invite_removed":
+		return "Invite removed."
+	case "join_code_reset":
+		return "Join code reset. The old code no longer works."
+	case "member_removed":
+		return "Member removed."
+	default:
+		return ""
+	}
+}
+
+func mapGroupError(code string) string {
+	switch code {
+	case "invalid_form":
+		return "Invalid form submission."
+	case "invalid_request":
+		return "Check the form and try again."
+	case "join_code_invalid":
+		return "No playgroup uses that join code."
+	case "already_member":
+		return "That player is already in the playgroup."
+	case "group_forbidden":
+		return "Only the group's owner or admins can do that."
+	case "group_not_found":
+		return "Playgroup, player, or invite not found."
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,80 @@
+package userui
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"MtgLeaderwebserver/internal/domain"
+)
+
+func (a *app) handleGuestClaimCreate(w http.ResponseWriter, r *http.Request) {
+	u, ok := a.guestClaimFormUser(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := a.claimSvc.Create(r.Context(), u.ID, r.FormValue("guest_name"), r.FormValue("guest_id"), r.FormValue("user_id")); err != nil {
+		a.redirectGuestClaimError(w, r, err, "Failed to offer guest history")
+		return
+	}
+	redirectFriends(w, r, "", "", "claim_sent", "")
+}
+
+func (a *app) handleGuestClaimAccept(w http.ResponseWriter, r *http.Request) {
+	u, ok := a.guestClaimFormUser(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := a.claimSvc.Accept(r.Context(), u.ID, strings.TrimSpace(r.FormValue("id"))); err != nil {
+		a.redirectGuestClaimError(w, r, err, "Failed to accept guest history")
+		return
+	}
+	redirectFriends(w, r, "", "", "claim_accepted", "")
+}
+
+func (a *app) handleGuestClaimDecline(w http.ResponseWriter, r *http.Request) {
+	u, ok := a.guestClaimFormUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := a.claimSvc.Decline(r.Context(), u.ID, strings.TrimSpace(r.FormValue("id"))); err != nil {
+		a.redirectGuestClaimError(w, r, err, "Failed to remove guest history offer")
+		return
+	}
+	redirectFriends(w, r, "", "", "claim_removed", "")
+}
+
+// guestClaimFormUser resolves the signed-in user for a guest claim form post.
+func (a *app) guestClaimFormUser(w http.ResponseWriter, r *http.Request) (domain.User, bool) {
+	if a.claimSvc == nil {
+		a.templates.renderError(w, http.StatusServiceUnavailable, "Unavailable", uiUnavailableMsg)
+		return domain.User{}, false
+	}
+	u, _, ok := a.currentUser(r)
+	if !ok {
+		http.Redirect(w, r, "/app/login", http.StatusFound)
+		return domain.User{}, false
+	}
+	if err := r.ParseForm(); err != nil {
+		redirectFriends(w, r, "", "", "", "invalid_form")
+		return domain.User{}, false
+	}
+	return u, true
+}
+
+func (a *app) redirectGuestClaimError(w http.ResponseWriter, r *http.Request, err error, failMsg string) {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		redirectFriends(w, r, "", "", "", "claim_invalid")
+	case errors.Is(err, domain.ErrForbidden):
+		redirectFriends(w, r, "", "", "", "claim_not_friend")
+	case errors.Is(err, domain.ErrNotFound):
+		redirectFriends(w, r, "", "", "", "claim_not_found")
+	default:
+		a.logger.Error("userui: guest claim action failed", "err", err)
+		a.templates.renderError(w, http.StatusInternalServerError, "Error", failMsg)
+	}
+}
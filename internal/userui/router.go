@@ -20,6 +20,11 @@ type Opts struct {
 	Matches      *service.MatchService
 	Reset        *service.PasswordResetService
 	Profile      *service.ProfileService
+	Groups       *service.GroupService
+	GuestClaims  *service.GuestClaimService
+	Decks        *service.DeckService
+	MatchEvents  *service.MatchEventService
+	Formats      *service.FormatService
 	AvatarDir    string
 	CookieCodec  auth.CookieCodec
 	CookieSecure bool
@@ -44,6 +49,11 @@ func New(opts Opts) http.Handler {
 		matchSvc:     opts.Matches,
 		resetSvc:     opts.Reset,
 		profileSvc:   opts.Profile,
+		groupSvc:     opts.Groups,
+		claimSvc:     opts.GuestClaims,
+		deckSvc:      opts.Decks,
+		eventSvc:     opts.MatchEvents,
+		formatSvc:    opts.Formats,
 		avatarDir:    opts.AvatarDir,
 		cookieCodec:  opts.CookieCodec,
 		cookieSecure: opts.CookieSecure,
@@ -69,6 +79,18 @@ func New(opts Opts) http.Handler {
 	mux.HandleFunc("GET /app/stats", app.requireAuth(app.handleStats))
 	mux.HandleFunc("GET /app/matches", app.requireAuth(app.handleMatchesList))
 	mux.HandleFunc("GET /app/matches/{id}", app.requireAuth(app.handleMatchesDetail))
+	mux.HandleFunc("POST /app/matches/{id}/delete", app.requireAuth(app.handleMatchesDeletePost))
+	mux.HandleFunc("POST /app/matches/{id}/confirm", app.requireAuth(app.handleMatchesConfirmPost))
+	mux.HandleFunc("POST /app/matches/{id}/dispute", app.requireAuth(app.handleMatchesDisputePost))
+	mux.HandleFunc("GET /app/groups", app.requireAuth(app.handleGroups))
+	mux.HandleFunc("POST /app/groups", app.requireAuth(app.handleGroupCreate))
+	mux.HandleFunc("POST /app/groups/join", app.requireAuth(app.handleGroupJoin))
+	mux.HandleFunc("POST /app/groups/invites/accept", app.requireAuth(app.handleGroupInviteAccept))
+	mux.HandleFunc("POST /app/groups/invites/decline", app.requireAuth(app.handleGroupInviteDecline))
+	mux.HandleFunc("GET /app/groups/{id}", app.requireAuth(app.handleGroupDetail))
+	mux.HandleFunc("POST /app/groups/{id}/invites", app.requireAuth(app.handleGroupInvite))
+	mux.HandleFunc("POST /app/groups/{id}/join-code", app.requireAuth(app.handleGroupJoinCode))
+	mux.HandleFunc("POST /app/groups/{id}/members/remove", app.requireAuth(app.handleGroupMemberRemove))
 	mux.HandleFunc("GET /app/login", app.handleLoginGet)
 	mux.HandleFunc("POST /app/login", app.handleLoginPost)
 	mux.HandleFunc("GET /app/register", app.handleRegisterGet)
@@ -78,6 +100,7 @@ func New(opts Opts) http.Handler {
 	mux.HandleFunc("GET /app/profile", app.requireAuth(app.handleProfileGet))
 	mux.HandleFunc("POST /app/profile", app.requireAuth(app.handleProfilePost))
 	mux.HandleFunc("POST /app/profile/avatar", app.requireAuth(app.handleProfileAvatarPost))
+	mux.HandleFunc("POST /app/profile/stats", app.requireAuth(app.handleProfileStatsPost))
 	mux.HandleFunc("POST /app/profile/delete", app.requireAuth(app.handleProfileDeletePost))
 	mux.HandleFunc("GET /app/wiki", app.handleWikiRedirect)
 	mux.HandleFunc("GET /app/wiki/", app.handleWikiRedirect)
@@ -88,6 +111,9 @@ func New(opts Opts) http.Handler {
 	mux.HandleFunc("POST /app/friends/requests/accept", app.requireAuth(app.handleFriendAccept))
 	mux.HandleFunc("POST /app/friends/requests/decline", app.requireAuth(app.handleFriendDecline))
 	mux.HandleFunc("POST /app/friends/requests/cancel", app.requireAuth(app.handleFriendCancel))
+	mux.HandleFunc("POST /app/friends/guest-claims", app.requireAuth(app.handleGuestClaimCreate))
+	mux.HandleFunc("POST /app/friends/guest-claims/accept", app.requireAuth(app.handleGuestClaimAccept))
+	mux.HandleFunc("POST /app/friends/guest-claims/decline", app.requireAuth(app.handleGuestClaimDecline))
 
 	staticFS, err := fs.Sub(assets, "static")
 	if err != nil {
@@ -124,6 +150,11 @@ type app struct {
 	matchSvc   *service.MatchService
 	resetSvc   *service.PasswordResetService
 	profileSvc *service.ProfileService
+	groupSvc   *service.GroupService
+	claimSvc   *service.GuestClaimService
+	deckSvc    *service.DeckService
+	eventSvc   *service.MatchEventService
+	formatSvc  *service.FormatService
 	avatarDir  string
 
 	cookieCodec  auth.CookieCodec
@@ -17,6 +17,8 @@ type templates struct {
 	matches  *template.Template
 	match    *template.Template
 	profile  *template.Template
+	groups   *template.Template
+	group    *template.Template
 	reset    *template.Template
 	errorT   *template.Template
 }
@@ -65,17 +67,19 @@ type friendsViewData struct {
 	Stats    []domain.FriendStatsListItem
 	Incoming []domain.FriendRequest
 	Outgoing []domain.FriendRequest
+	Claims   *domain.GuestClaimsOverview
 	Error    string
 	Notice   string
 }
 
 type profileViewData struct {
-	Title       string
-	User        domain.User
-	DisplayName string
-	AvatarURL   string
-	Error       string
-	Notice      string
+	Title              string
+	User               domain.User
+	DisplayName        string
+	AvatarURL          string
+	StatsConfirmedOnly bool
+	Error              string
+	Notice             string
 }
 
 type statsViewData struct {
@@ -85,10 +89,57 @@ type statsViewData struct {
 	Formats           []formatStatRow
 	MostOftenBeat     *opponentStatRow
 	MostOftenBeatsYou *opponentStatRow
+	Eliminations      *eliminationSection
+	WinConditions     *winConditionSection
+	Decks             []deckStatRow
 	Error             string
 	Notice            string
 }
 
+type deckStatRow struct {
+	Name          string
+	Format        string
+	ColorIdentity string
+	Archived      bool
+	MatchesPlayed int
+	Wins          int
+	Losses        int
+	WinPct        string
+	AvgPlace      string
+}
+
+type eliminationSection struct {
+	Overall eliminationRow
+	Formats []eliminationRow
+	Victims []opponentStatRow
+	Killers []opponentStatRow
+}
+
+type eliminationRow struct {
+	Format           string
+	Eliminations     int
+	TimesEliminated  int
+	AvgTurn          string
+	MostEliminated   *opponentStatRow
+	MostEliminatedBy *opponentStatRow
+}
+
+type winConditionSection struct {
+	Overall winConditionRow
+	Formats []winConditionRow
+}
+
+type winConditionRow struct {
+	Format string
+	Wins   []winConditionChip
+	Losses []winConditionChip
+}
+
+type winConditionChip struct {
+	Label string
+	Count int
+}
+
 type formatStatRow struct {
 	Format         string
 	MatchesPlayed  int
@@ -103,11 +154,13 @@ type opponentStatRow struct {
 }
 
 type matchesViewData struct {
-	Title   string
-	User    domain.User
-	Matches []matchListItem
-	Error   string
-	Notice  string
+	Title      string
+	User       domain.User
+	Matches    []matchListItem
+	IsFirst    bool
+	NextCursor string
+	Error      string
+	Notice     string
 }
 
 type matchListItem struct {
@@ -121,13 +174,82 @@ type matchListItem struct {
 }
 
 type matchDetailViewData struct {
-	Title    string
-	User     domain.User
-	Match    domain.Match
-	PlayedAt string
-	Duration string
-	AvgTurn  string
-	Error    string
+	Title     string
+	User      domain.User
+	Match     domain.Match
+	PlayedAt  string
+	Duration  string
+	AvgTurn   string
+	CanDelete bool
+	// WinCondition is empty when the match was not tagged with how it ended.
+	WinCondition string
+	// Confirmation is the viewer's own review state when someone else recorded the match.
+	Confirmation  string
+	DisputeReason string
+	// Replay is nil when the match has no game log.
+	Replay *matchReplayView
+	Error  string
+	Notice string
+}
+
+type matchReplayView struct {
+	Seats []string
+	Turns []matchReplayRow
+}
+
+type matchReplayRow struct {
+	Turn   int
+	Cells  []matchReplayCell
+	Events []string
+}
+
+type matchReplayCell struct {
+	Life            int
+	Poison          int
+	CommanderDamage string
+	Eliminated      bool
+}
+
+type groupsViewData struct {
+	Title   string
+	User    domain.User
+	Groups  []domain.Group
+	Invites []domain.GroupInvite
+	Error   string
+	Notice  string
+}
+
+type groupViewData struct {
+	Title   string
+	User    domain.User
+	Group   domain.Group
+	Members []groupMemberRow
+	Invites []domain.GroupInvite
+	Matches []matchListItem
+	Error   string
+	Notice  string
+
+	MatchesPlayed int
+	Standings     []groupStandingRow
+}
+
+type groupStandingRow struct {
+	Name          string
+	IsGuest       bool
+	MatchesPlayed int
+	Wins          int
+	WinPct        string
+	AvgPlace      string
+}
+
+type groupMemberRow struct {
+	ID          string
+	Username    string
+	DisplayName string
+	AvatarURL   string
+	Role        string
+	IsSelf      bool
+	CanRemove   bool
 }
 
 type searchResult struct {
@@ -140,6 +262,7 @@ type searchResult struct {
 }
 
 type friendCard struct {
+	ID          string
 	Username    string
 	DisplayName string
 	AvatarURL   string
@@ -186,6 +309,14 @@ func parseTemplates() (*templates, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parse profile: %w", err)
 	}
+	groupsT, err := parse("templates/layout.html", "templates/groups.html")
+	if err != nil {
+		return nil, fmt.Errorf("parse groups: %w", err)
+	}
+	groupT, err := parse("templates/layout.html", "templates/group.html")
+	if err != nil {
+		return nil, fmt.Errorf("parse group: %w", err)
+	}
 	resetT, err := parse("templates/reset.html")
 	if err != nil {
 		return nil, fmt.Errorf("parse reset: %w", err)
@@ -204,6 +335,8 @@ func parseTemplates() (*templates, error) {
 		matches:  matchesT,
 		match:    matchT,
 		profile:  profile,
+		groups:   groupsT,
+		group:    groupT,
 		reset:    resetT,
 		errorT:   errorT,
 	}, nil
@@ -257,6 +390,18 @@ func (t *templates) renderProfile(w http.ResponseWriter, status int, data any) {
 	_ = t.profile.ExecuteTemplate(w, "profile.html", data)
 }
 
+func (t *templates) renderGroups(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_ = t.groups.ExecuteTemplate(w, "groups.html", data)
+}
+
+func (t *templates) renderGroup(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_ = t.group.ExecuteTemplate(w, "group.html", data)
+}
+
 func (t *templates) renderReset(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.WriteHeader(status)
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE matches
+  ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX matches_deleted_at_idx ON matches (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS matches_deleted_at_idx;
+
+ALTER TABLE matches
+  DROP COLUMN IF EXISTS deleted_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE INDEX matches_sort_keyset_idx ON matches ((COALESCE(played_at, created_at)) DESC, id DESC) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS matches_sort_keyset_idx;
+
+-- +goose StatementEnd
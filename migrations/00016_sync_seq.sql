@@ -0,0 +1,103 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE SEQUENCE sync_seq;
+
+ALTER TABLE users ADD COLUMN sync_seq BIGINT NOT NULL DEFAULT nextval('sync_seq');
+ALTER TABLE friendships ADD COLUMN sync_seq BIGINT NOT NULL DEFAULT nextval('sync_seq');
+ALTER TABLE matches ADD COLUMN sync_seq BIGINT NOT NULL DEFAULT nextval('sync_seq');
+
+CREATE INDEX users_sync_seq_idx ON users (sync_seq);
+CREATE INDEX friendships_sync_seq_idx ON friendships (sync_seq);
+CREATE INDEX matches_sync_seq_idx ON matches (sync_seq);
+
+-- Rows that disappear from a user's view (deleted friendships, seats removed from a match).
+CREATE TABLE sync_removals (
+  seq BIGINT PRIMARY KEY DEFAULT nextval('sync_seq'),
+  user_id UUID NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('friendship', 'match')),
+  ref_id UUID NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now())
+);
+
+CREATE INDEX sync_removals_user_seq_idx ON sync_removals (user_id, seq);
+
+CREATE FUNCTION sync_seq_bump() RETURNS trigger AS $$
+BEGIN
+  NEW.sync_seq := nextval('sync_seq');
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_sync_seq_trg
+  BEFORE UPDATE ON users
+  FOR EACH ROW
+  WHEN (OLD.updated_at IS DISTINCT FROM NEW.updated_at OR OLD.avatar_updated_at IS DISTINCT FROM NEW.avatar_updated_at)
+  EXECUTE FUNCTION sync_seq_bump();
+
+CREATE TRIGGER friendships_sync_seq_trg
+  BEFORE UPDATE ON friendships
+  FOR EACH ROW
+  EXECUTE FUNCTION sync_seq_bump();
+
+CREATE TRIGGER matches_sync_seq_trg
+  BEFORE UPDATE ON matches
+  FOR EACH ROW
+  EXECUTE FUNCTION sync_seq_bump();
+
+CREATE FUNCTION friendships_sync_removed() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO sync_removals (user_id, kind, ref_id)
+  VALUES (OLD.requester_id, 'friendship', OLD.addressee_id),
+         (OLD.addressee_id, 'friendship', OLD.requester_id);
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER friendships_sync_removed_trg
+  AFTER DELETE ON friendships
+  FOR EACH ROW
+  EXECUTE FUNCTION friendships_sync_removed();
+
+CREATE FUNCTION match_participants_sync_touch() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    IF OLD.user_id IS NOT NULL THEN
+      INSERT INTO sync_removals (user_id, kind, ref_id) VALUES (OLD.user_id, 'match', OLD.match_id);
+    END IF;
+    UPDATE matches SET sync_seq = nextval('sync_seq') WHERE id = OLD.match_id;
+    RETURN OLD;
+  END IF;
+  UPDATE matches SET sync_seq = nextval('sync_seq') WHERE id = NEW.match_id;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER match_participants_sync_touch_trg
+  AFTER INSERT OR UPDATE OR DELETE ON match_participants
+  FOR EACH ROW
+  EXECUTE FUNCTION match_participants_sync_touch();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS match_participants_sync_touch_trg ON match_participants;
+DROP FUNCTION IF EXISTS match_participants_sync_touch();
+DROP TRIGGER IF EXISTS friendships_sync_removed_trg ON friendships;
+DROP FUNCTION IF EXISTS friendships_sync_removed();
+DROP TRIGGER IF EXISTS matches_sync_seq_trg ON matches;
+DROP TRIGGER IF EXISTS friendships_sync_seq_trg ON friendships;
+DROP TRIGGER IF EXISTS users_sync_seq_trg ON users;
+DROP FUNCTION IF EXISTS sync_seq_bump();
+
+DROP TABLE IF EXISTS sync_removals;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS sync_seq;
+ALTER TABLE friendships DROP COLUMN IF EXISTS sync_seq;
+ALTER TABLE users DROP COLUMN IF EXISTS sync_seq;
+
+DROP SEQUENCE IF EXISTS sync_seq;
+
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE matches
+  ADD COLUMN starting_seat_index INT,
+  ADD CONSTRAINT matches_starting_seat_index_chk CHECK (starting_seat_index IS NULL OR starting_seat_index >= 0);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE matches
+  DROP CONSTRAINT IF EXISTS matches_starting_seat_index_chk,
+  DROP COLUMN IF EXISTS starting_seat_index;
+
+-- +goose StatementEnd
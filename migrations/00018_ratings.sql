@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Ratings are derived data: they are rebuilt from matches whenever matches.sync_seq moves past
+-- rating_runs.source_seq or the server's rating engine version changes.
+CREATE TABLE ratings (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  format TEXT NOT NULL,
+  rating DOUBLE PRECISION NOT NULL,
+  peak_rating DOUBLE PRECISION NOT NULL,
+  matches_rated INT NOT NULL DEFAULT 0,
+  last_played_at TIMESTAMPTZ,
+  PRIMARY KEY (user_id, format)
+);
+
+CREATE INDEX ratings_format_rating_idx ON ratings (format, rating DESC);
+
+CREATE TABLE rating_history (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  format TEXT NOT NULL,
+  match_id UUID NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+  played_at TIMESTAMPTZ NOT NULL,
+  place INT NOT NULL,
+  pod_size INT NOT NULL,
+  rating_before DOUBLE PRECISION NOT NULL,
+  rating_after DOUBLE PRECISION NOT NULL,
+  PRIMARY KEY (user_id, format, match_id)
+);
+
+CREATE INDEX rating_history_user_played_idx ON rating_history (user_id, format, played_at DESC);
+
+CREATE TABLE rating_runs (
+  id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+  source_seq BIGINT NOT NULL,
+  engine_version INT NOT NULL,
+  computed_at TIMESTAMPTZ NOT NULL
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS rating_runs;
+DROP TABLE IF EXISTS rating_history;
+DROP TABLE IF EXISTS ratings;
+
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE seasons (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  format TEXT NULL,
+  starts_at TIMESTAMPTZ NOT NULL,
+  ends_at TIMESTAMPTZ NOT NULL,
+  points INT[] NOT NULL,
+  archived_at TIMESTAMPTZ NULL,
+  archived_matches INT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now()),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now()),
+  CONSTRAINT seasons_dates_chk CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX seasons_owner_starts_idx ON seasons (owner_id, starts_at DESC);
+
+-- Frozen standings written when a season is archived.
+CREATE TABLE season_standings (
+  season_id UUID NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+  position INT NOT NULL,
+  rank INT NOT NULL,
+  user_id UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+  guest_name TEXT NULL,
+  points INT NOT NULL,
+  matches_played INT NOT NULL,
+  wins INT NOT NULL,
+  avg_place DOUBLE PRECISION NOT NULL,
+  PRIMARY KEY (season_id, position)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS season_standings;
+DROP TABLE IF EXISTS seasons;
+
+-- +goose StatementEnd
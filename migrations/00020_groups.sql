@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE groups (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  join_code TEXT NOT NULL UNIQUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now()),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now())
+);
+
+CREATE TABLE group_members (
+  group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+  joined_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now()),
+  PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX group_members_user_id_idx ON group_members (user_id);
+CREATE UNIQUE INDEX group_members_one_owner_uq ON group_members (group_id) WHERE role = 'owner';
+
+CREATE TABLE group_invites (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  invited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now()),
+  CONSTRAINT group_invites_group_user_uq UNIQUE (group_id, user_id)
+);
+
+CREATE INDEX group_invites_user_id_idx ON group_invites (user_id);
+
+ALTER TABLE matches ADD COLUMN group_id UUID NULL REFERENCES groups(id) ON DELETE SET NULL;
+CREATE INDEX matches_group_id_idx ON matches (group_id) WHERE group_id IS NOT NULL;
+
+-- A group season scores the group's matches; owner_id stays the member who created it.
+ALTER TABLE seasons ADD COLUMN group_id UUID NULL REFERENCES groups(id) ON DELETE CASCADE;
+CREATE INDEX seasons_group_starts_idx ON seasons (group_id, starts_at DESC) WHERE group_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS seasons_group_starts_idx;
+ALTER TABLE seasons DROP COLUMN IF EXISTS group_id;
+DROP INDEX IF EXISTS matches_group_id_idx;
+ALTER TABLE matches DROP COLUMN IF EXISTS group_id;
+DROP TABLE IF EXISTS group_invites;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
+
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Existing seats were never reviewed by the players they name; treat them as confirmed
+-- so history keeps counting, then make new seats start out pending.
+ALTER TABLE match_participants
+  ADD COLUMN confirmation TEXT NOT NULL DEFAULT 'confirmed',
+  ADD COLUMN dispute_reason TEXT,
+  ADD COLUMN confirmation_updated_at TIMESTAMPTZ,
+  ADD CONSTRAINT match_participants_confirmation_chk CHECK (confirmation IN ('pending', 'confirmed', 'disputed'));
+
+ALTER TABLE match_participants ALTER COLUMN confirmation SET DEFAULT 'pending';
+
+CREATE INDEX match_participants_unconfirmed_idx ON match_participants (match_id) WHERE confirmation <> 'confirmed';
+
+ALTER TABLE users ADD COLUMN stats_confirmed_only BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE users DROP COLUMN IF EXISTS stats_confirmed_only;
+
+DROP INDEX IF EXISTS match_participants_unconfirmed_idx;
+
+ALTER TABLE match_participants
+  DROP CONSTRAINT IF EXISTS match_participants_confirmation_chk,
+  DROP COLUMN IF EXISTS confirmation_updated_at,
+  DROP COLUMN IF EXISTS dispute_reason,
+  DROP COLUMN IF EXISTS confirmation;
+
+-- +goose StatementEnd
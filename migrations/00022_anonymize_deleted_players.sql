@@ -0,0 +1,85 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Matches outlive the account that recorded them; nobody can edit them once created_by is NULL.
+ALTER TABLE matches ALTER COLUMN created_by DROP NOT NULL;
+ALTER TABLE matches DROP CONSTRAINT matches_created_by_fkey;
+ALTER TABLE matches
+  ADD CONSTRAINT matches_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+-- Same for playgroups: the group and its match tags stay when whoever created it leaves the app.
+ALTER TABLE groups ALTER COLUMN created_by DROP NOT NULL;
+ALTER TABLE groups DROP CONSTRAINT groups_created_by_fkey;
+ALTER TABLE groups
+  ADD CONSTRAINT groups_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+-- Deleting a user turns their seats into "Deleted player" guest seats instead of cascading them away.
+-- Legacy matches only list players in match_players, so they are copied into match_participants first.
+CREATE FUNCTION users_anonymize_matches() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO match_participants (match_id, seat_index, user_id, place, eliminated_turn_number, confirmation)
+  SELECT mp.match_id,
+         (row_number() OVER (PARTITION BY mp.match_id ORDER BY mp.user_id) - 1)::int,
+         mp.user_id,
+         COALESCE(r.rank, CASE WHEN m.winner_id = mp.user_id THEN 1 ELSE 2 END),
+         r.elimination_turn,
+         'confirmed'
+  FROM match_players mp
+  JOIN matches m ON m.id = mp.match_id
+  LEFT JOIN match_player_results r ON r.match_id = mp.match_id AND r.user_id = mp.user_id
+  WHERE mp.match_id IN (SELECT match_id FROM match_players WHERE user_id = OLD.id)
+    AND NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id);
+
+  UPDATE match_participants
+  SET user_id = NULL,
+      guest_name = 'Deleted player',
+      display_name = 'Deleted player',
+      confirmation = 'confirmed',
+      dispute_reason = NULL
+  WHERE user_id = OLD.id;
+
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_anonymize_matches_trg
+  BEFORE DELETE ON users
+  FOR EACH ROW
+  EXECUTE FUNCTION users_anonymize_matches();
+
+-- Earlier deletions cascaded seats away. Every recorded match has a place-1 seat, so a match
+-- without one lost its winner: give the seat back as a "Deleted player", in the first free seat.
+INSERT INTO match_participants (match_id, seat_index, guest_name, display_name, place, confirmation)
+SELECT m.id,
+       (SELECT MIN(s.seat)
+        FROM generate_series(0, (SELECT MAX(p.seat_index) + 1 FROM match_participants p WHERE p.match_id = m.id)) AS s(seat)
+        WHERE NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = m.id AND p.seat_index = s.seat)),
+       'Deleted player',
+       'Deleted player',
+       1,
+       'confirmed'
+FROM matches m
+WHERE EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = m.id)
+  AND NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = m.id AND p.place = 1);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS users_anonymize_matches_trg ON users;
+DROP FUNCTION IF EXISTS users_anonymize_matches();
+
+DELETE FROM groups WHERE created_by IS NULL;
+ALTER TABLE groups DROP CONSTRAINT groups_created_by_fkey;
+ALTER TABLE groups
+  ADD CONSTRAINT groups_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE groups ALTER COLUMN created_by SET NOT NULL;
+
+DELETE FROM matches WHERE created_by IS NULL;
+ALTER TABLE matches DROP CONSTRAINT matches_created_by_fkey;
+ALTER TABLE matches
+  ADD CONSTRAINT matches_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE matches ALTER COLUMN created_by SET NOT NULL;
+
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A match creator offers a guest name they used to a registered friend. Accepting turns the
+-- creator's guest seats with that name into the friend's seats.
+CREATE TABLE guest_claims (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  guest_name TEXT NOT NULL,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now())
+);
+
+CREATE UNIQUE INDEX guest_claims_creator_guest_uq ON guest_claims (created_by, lower(guest_name));
+CREATE INDEX guest_claims_user_id_idx ON guest_claims (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS guest_claims;
+
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Saved guests per user. Aliases are the lower-cased, trimmed guest names that map to a roster
+-- entry; a guest seat in one of the owner's matches whose name matches an alias links to it.
+CREATE TABLE guests (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  display_name TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now()),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now())
+);
+
+CREATE INDEX guests_owner_id_idx ON guests (owner_id);
+
+CREATE TABLE guest_aliases (
+  owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  alias TEXT NOT NULL CHECK (alias <> '' AND alias = lower(btrim(alias))),
+  guest_id UUID NOT NULL REFERENCES guests(id) ON DELETE CASCADE,
+  PRIMARY KEY (owner_id, alias)
+);
+
+CREATE INDEX guest_aliases_guest_id_idx ON guest_aliases (guest_id);
+
+ALTER TABLE match_participants
+  ADD COLUMN guest_id UUID NULL REFERENCES guests(id) ON DELETE SET NULL,
+  ADD CONSTRAINT match_participants_guest_id_chk CHECK (guest_id IS NULL OR user_id IS NULL);
+
+CREATE INDEX match_participants_guest_id_idx ON match_participants (guest_id) WHERE guest_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS match_participants_guest_id_idx;
+
+ALTER TABLE match_participants
+  DROP CONSTRAINT IF EXISTS match_participants_guest_id_chk,
+  DROP COLUMN IF EXISTS guest_id;
+
+DROP TABLE IF EXISTS guest_aliases;
+DROP TABLE IF EXISTS guests;
+
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Decks belong to one user. color_identity is a subset of WUBRG in that order; '' is colorless.
+CREATE TABLE decks (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  format TEXT NOT NULL,
+  commanders TEXT[] NOT NULL DEFAULT '{}',
+  color_identity TEXT NOT NULL DEFAULT '' CHECK (color_identity ~ '^W?U?B?R?G?$'),
+  archived BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now()),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now())
+);
+
+CREATE INDEX decks_owner_id_idx ON decks (owner_id);
+
+-- The deck a registered player used in a match. The service only accepts the seat owner's decks.
+ALTER TABLE match_participants
+  ADD COLUMN deck_id UUID NULL REFERENCES decks(id) ON DELETE SET NULL;
+
+CREATE INDEX match_participants_deck_id_idx ON match_participants (deck_id) WHERE deck_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS match_participants_deck_id_idx;
+ALTER TABLE match_participants DROP COLUMN IF EXISTS deck_id;
+DROP TABLE IF EXISTS decks;
+
+-- +goose StatementEnd
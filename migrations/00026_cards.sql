@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- One row per Oracle card, loaded from a local Scryfall bulk file by cmd/cardimport.
+-- can_be_commander covers the card's type and rules text; legality is checked per format.
+CREATE TABLE cards (
+  id UUID PRIMARY KEY,
+  name TEXT NOT NULL,
+  type_line TEXT NOT NULL DEFAULT '',
+  color_identity TEXT NOT NULL DEFAULT '' CHECK (color_identity ~ '^W?U?B?R?G?$'),
+  legalities JSONB NOT NULL DEFAULT '{}',
+  can_be_commander BOOLEAN NOT NULL DEFAULT false,
+  imported_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now())
+);
+
+CREATE INDEX cards_name_lower_idx ON cards (lower(name) text_pattern_ops);
+
+-- Oracle ids of the commanders a player used, in the order they were sent.
+ALTER TABLE match_participants
+  ADD COLUMN commander_card_ids UUID[] NOT NULL DEFAULT '{}';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE match_participants DROP COLUMN IF EXISTS commander_card_ids;
+DROP TABLE IF EXISTS cards;
+
+-- +goose StatementEnd
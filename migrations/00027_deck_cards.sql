@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The card list of a deck, in the order it was imported. card_id is set when the name matched
+-- the card database at import time; unmatched cards keep their name only.
+CREATE TABLE deck_cards (
+  deck_id UUID NOT NULL REFERENCES decks(id) ON DELETE CASCADE,
+  position INT NOT NULL,
+  section TEXT NOT NULL CHECK (section IN ('commander', 'companion', 'main', 'sideboard')),
+  quantity INT NOT NULL CHECK (quantity >= 1),
+  name TEXT NOT NULL,
+  set_code TEXT NOT NULL DEFAULT '',
+  collector_number TEXT NOT NULL DEFAULT '',
+  card_id UUID NULL REFERENCES cards(id) ON DELETE SET NULL,
+  PRIMARY KEY (deck_id, position)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS deck_cards;
+
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Append-only game log recorded by the device that tracked the match. seq is assigned by the
+-- client, starts at 1 and has no gaps, so re-sent events can be recognised and skipped.
+CREATE TABLE match_events (
+  match_id UUID NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+  seq INT NOT NULL CHECK (seq >= 1),
+  kind TEXT NOT NULL CHECK (kind IN ('life', 'poison', 'commander_damage', 'eliminated', 'turn_pass')),
+  turn INT NOT NULL CHECK (turn >= 0),
+  seat_index INT NOT NULL,
+  source_seat_index INT NULL,
+  amount INT NOT NULL DEFAULT 0,
+  total INT NULL,
+  occurred_at TIMESTAMPTZ NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now()),
+  PRIMARY KEY (match_id, seq)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS match_events;
+
+-- +goose StatementEnd
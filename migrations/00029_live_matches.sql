@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A game in progress, hosted by created_by. Finishing it records match_id; abandoning it records
+-- nothing.
+CREATE TABLE live_matches (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  format TEXT NOT NULL,
+  group_id UUID NULL REFERENCES groups(id) ON DELETE SET NULL,
+  starting_seat_index INT NULL CHECK (starting_seat_index >= 0),
+  status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'finished', 'abandoned')),
+  match_id UUID NULL REFERENCES matches(id) ON DELETE SET NULL,
+  started_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now()),
+  finished_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX live_matches_created_by_active_idx ON live_matches (created_by) WHERE status = 'active';
+
+CREATE TABLE live_match_seats (
+  live_match_id UUID NOT NULL REFERENCES live_matches(id) ON DELETE CASCADE,
+  seat_index INT NOT NULL CHECK (seat_index >= 0),
+  user_id UUID NULL REFERENCES users(id) ON DELETE CASCADE,
+  guest_name TEXT NOT NULL DEFAULT '',
+  guest_id UUID NULL REFERENCES guests(id) ON DELETE SET NULL,
+  display_name TEXT NOT NULL DEFAULT '',
+  deck_id UUID NULL REFERENCES decks(id) ON DELETE SET NULL,
+  commander_card_ids UUID[] NOT NULL DEFAULT '{}',
+  joined_at TIMESTAMPTZ NULL,
+  PRIMARY KEY (live_match_id, seat_index)
+);
+
+CREATE INDEX live_match_seats_user_id_idx ON live_match_seats (user_id) WHERE user_id IS NOT NULL;
+
+-- Updates pushed to the match's streams. seq and turn are assigned by the server; seq is the SSE
+-- event id clients resume from.
+CREATE TABLE live_match_events (
+  live_match_id UUID NOT NULL REFERENCES live_matches(id) ON DELETE CASCADE,
+  seq INT NOT NULL CHECK (seq >= 1),
+  kind TEXT NOT NULL CHECK (kind IN ('life', 'poison', 'commander_damage', 'eliminated', 'turn_pass')),
+  turn INT NOT NULL CHECK (turn >= 0),
+  seat_index INT NOT NULL,
+  source_seat_index INT NULL,
+  amount INT NOT NULL DEFAULT 0,
+  total INT NULL,
+  created_by UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now()),
+  PRIMARY KEY (live_match_id, seq)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS live_match_events;
+DROP TABLE IF EXISTS live_match_seats;
+DROP TABLE IF EXISTS live_matches;
+
+-- +goose StatementEnd
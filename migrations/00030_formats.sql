@@ -0,0 +1,64 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The game formats players can pick. legality is the card database legality key checked for the
+-- format's commanders; player counts are the defaults offered by the apps' pickers.
+CREATE TABLE formats (
+  slug TEXT PRIMARY KEY CHECK (slug ~ '^[a-z0-9][a-z0-9_-]*$'),
+  name TEXT NOT NULL,
+  multiplayer BOOLEAN NOT NULL DEFAULT true,
+  min_players INT NOT NULL DEFAULT 2 CHECK (min_players >= 1),
+  max_players INT NOT NULL DEFAULT 8,
+  default_players INT NOT NULL DEFAULT 4,
+  starting_life INT NOT NULL DEFAULT 20 CHECK (starting_life >= 1),
+  commander BOOLEAN NOT NULL DEFAULT false,
+  legality TEXT NOT NULL DEFAULT '',
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  sort_order INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now()),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT date_trunc('milliseconds', now()),
+  CONSTRAINT formats_players_chk CHECK (min_players <= default_players AND default_players <= max_players)
+);
+
+-- Other names accepted for a format, such as edh for commander.
+CREATE TABLE format_aliases (
+  alias TEXT PRIMARY KEY CHECK (alias ~ '^[a-z0-9][a-z0-9_-]*$'),
+  format TEXT NOT NULL REFERENCES formats(slug) ON DELETE CASCADE
+);
+
+CREATE INDEX format_aliases_format_idx ON format_aliases (format);
+
+INSERT INTO formats (slug, name, multiplayer, min_players, max_players, default_players, starting_life, commander, legality, sort_order) VALUES
+  ('commander', 'Commander', true, 2, 8, 4, 40, true, 'commander', 10),
+  ('brawl', 'Brawl', true, 2, 6, 2, 25, true, 'brawl', 20),
+  ('standard', 'Standard', false, 2, 2, 2, 20, false, 'standard', 30),
+  ('modern', 'Modern', false, 2, 2, 2, 20, false, 'modern', 40);
+
+INSERT INTO format_aliases (alias, format) VALUES ('edh', 'commander');
+
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS matches_format_chk;
+ALTER TABLE matches
+  ADD CONSTRAINT matches_format_fk FOREIGN KEY (format) REFERENCES formats(slug);
+ALTER TABLE decks
+  ADD CONSTRAINT decks_format_fk FOREIGN KEY (format) REFERENCES formats(slug);
+ALTER TABLE live_matches
+  ADD CONSTRAINT live_matches_format_fk FOREIGN KEY (format) REFERENCES formats(slug);
+ALTER TABLE seasons
+  ADD CONSTRAINT seasons_format_fk FOREIGN KEY (format) REFERENCES formats(slug);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE seasons DROP CONSTRAINT IF EXISTS seasons_format_fk;
+ALTER TABLE live_matches DROP CONSTRAINT IF EXISTS live_matches_format_fk;
+ALTER TABLE decks DROP CONSTRAINT IF EXISTS decks_format_fk;
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS matches_format_fk;
+ALTER TABLE matches
+  ADD CONSTRAINT matches_format_chk CHECK (format IN ('commander', 'brawl', 'standard', 'modern'));
+
+DROP TABLE IF EXISTS format_aliases;
+DROP TABLE IF EXISTS formats;
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Team matches put every seat on a numbered team. Members of a team share its place, so a team
+-- win gives every member place 1. NULL means the match is free-for-all.
+ALTER TABLE match_participants
+  ADD COLUMN team INT CHECK (team >= 1);
+
+-- Formats whose matches are always played in teams.
+ALTER TABLE formats
+  ADD COLUMN teams BOOLEAN NOT NULL DEFAULT false;
+
+-- Star is recorded free-for-all: each player's enemies differ, so there are no fixed teams.
+INSERT INTO formats (slug, name, multiplayer, min_players, max_players, default_players, starting_life, commander, legality, sort_order, teams) VALUES
+  ('two-headed-giant', 'Two-Headed Giant', true, 4, 4, 4, 30, false, 'two-headed-giant', 50, true),
+  ('archenemy', 'Archenemy', true, 3, 5, 4, 20, false, 'archenemy', 60, true),
+  ('star', 'Star', true, 5, 5, 5, 20, false, 'star', 70, false)
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO format_aliases (alias, format) VALUES ('2hg', 'two-headed-giant')
+ON CONFLICT (alias) DO NOTHING;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE formats DROP COLUMN IF EXISTS teams;
+ALTER TABLE match_participants DROP COLUMN IF EXISTS team;
+
+-- +goose StatementEnd
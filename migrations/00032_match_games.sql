@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Best-of-N 1v1 matches record each game. The match's places are derived from the games, so
+-- match-level stats keep reading match_participants. best_of is NULL for single-game matches.
+ALTER TABLE matches
+  ADD COLUMN best_of INT NULL CHECK (best_of IN (1, 3, 5, 7));
+
+-- mulligans is indexed by seat_index: mulligans[1] is seat 0.
+CREATE TABLE match_games (
+  match_id UUID NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+  game_number INT NOT NULL CHECK (game_number >= 1),
+  winner_seat_index INT NOT NULL CHECK (winner_seat_index >= 0),
+  on_play_seat_index INT NULL CHECK (on_play_seat_index >= 0),
+  mulligans INT[] NOT NULL DEFAULT '{}',
+  duration_seconds INT NOT NULL DEFAULT 0 CHECK (duration_seconds >= 0),
+  PRIMARY KEY (match_id, game_number)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS match_games;
+ALTER TABLE matches DROP COLUMN IF EXISTS best_of;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- How a match ended. Drawn matches have several seats (or teams) sharing place 1 and no
+-- winner_id. Abandoned matches have no places, so every "has a place 1" check leaves them out.
+ALTER TABLE matches
+  ADD COLUMN outcome TEXT NOT NULL DEFAULT 'win' CHECK (outcome IN ('win', 'draw', 'abandoned'));
+
+ALTER TABLE match_participants
+  ALTER COLUMN place DROP NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- Abandoned seats get a shared last place; with no place 1 the match still counts as unfinished.
+UPDATE match_participants p
+SET place = (SELECT COUNT(*) FROM match_participants o WHERE o.match_id = p.match_id)
+WHERE p.place IS NULL;
+
+ALTER TABLE match_participants
+  ALTER COLUMN place SET NOT NULL;
+ALTER TABLE matches DROP COLUMN IF EXISTS outcome;
+
+-- +goose StatementEnd
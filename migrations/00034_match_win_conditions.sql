@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- How a match ended. win_condition_tag names a custom ending and is only set with 'custom';
+-- winning_card is the card that won, as typed by the player.
+ALTER TABLE matches
+  ADD COLUMN win_condition TEXT NULL CHECK (win_condition IN (
+    'combat', 'commander_damage', 'combo', 'mill', 'poison', 'concession', 'alt_win', 'custom'
+  )),
+  ADD COLUMN win_condition_tag TEXT NULL,
+  ADD COLUMN winning_card TEXT NULL,
+  ADD CONSTRAINT matches_win_condition_tag_chk CHECK (
+    CASE WHEN win_condition = 'custom' THEN win_condition_tag IS NOT NULL ELSE win_condition_tag IS NULL END
+  );
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE matches
+  DROP CONSTRAINT IF EXISTS matches_win_condition_tag_chk,
+  DROP COLUMN IF EXISTS winning_card,
+  DROP COLUMN IF EXISTS win_condition_tag,
+  DROP COLUMN IF EXISTS win_condition;
+
+-- +goose StatementEnd
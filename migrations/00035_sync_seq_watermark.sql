@@ -0,0 +1,85 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- sync_seq values are taken when a row is written, not when its transaction commits, so a
+-- transaction holding a low seq can commit after one holding a higher seq. Writers hold a shared
+-- advisory lock from their first sync_seq until commit; sync_seq_watermark() takes it exclusively,
+-- which waits for those writers, and returns the highest seq that is now committed (or rolled back).
+CREATE FUNCTION sync_next_seq() RETURNS BIGINT AS $$
+BEGIN
+  PERFORM pg_advisory_xact_lock_shared(hashtext('sync_seq'));
+  RETURN nextval('sync_seq');
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION sync_seq_watermark() RETURNS BIGINT AS $$
+DECLARE
+  seq BIGINT;
+BEGIN
+  PERFORM pg_advisory_xact_lock(hashtext('sync_seq'));
+  SELECT CASE WHEN is_called THEN last_value ELSE 0 END INTO seq FROM sync_seq;
+  RETURN seq;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE users ALTER COLUMN sync_seq SET DEFAULT sync_next_seq();
+ALTER TABLE friendships ALTER COLUMN sync_seq SET DEFAULT sync_next_seq();
+ALTER TABLE matches ALTER COLUMN sync_seq SET DEFAULT sync_next_seq();
+ALTER TABLE sync_removals ALTER COLUMN seq SET DEFAULT sync_next_seq();
+
+CREATE OR REPLACE FUNCTION sync_seq_bump() RETURNS trigger AS $$
+BEGIN
+  NEW.sync_seq := sync_next_seq();
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION match_participants_sync_touch() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    IF OLD.user_id IS NOT NULL THEN
+      INSERT INTO sync_removals (user_id, kind, ref_id) VALUES (OLD.user_id, 'match', OLD.match_id);
+    END IF;
+    UPDATE matches SET sync_seq = sync_next_seq() WHERE id = OLD.match_id;
+    RETURN OLD;
+  END IF;
+  UPDATE matches SET sync_seq = sync_next_seq() WHERE id = NEW.match_id;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+CREATE OR REPLACE FUNCTION match_participants_sync_touch() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    IF OLD.user_id IS NOT NULL THEN
+      INSERT INTO sync_removals (user_id, kind, ref_id) VALUES (OLD.user_id, 'match', OLD.match_id);
+    END IF;
+    UPDATE matches SET sync_seq = nextval('sync_seq') WHERE id = OLD.match_id;
+    RETURN OLD;
+  END IF;
+  UPDATE matches SET sync_seq = nextval('sync_seq') WHERE id = NEW.match_id;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION sync_seq_bump() RETURNS trigger AS $$
+BEGIN
+  NEW.sync_seq := nextval('sync_seq');
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE sync_removals ALTER COLUMN seq SET DEFAULT nextval('sync_seq');
+ALTER TABLE matches ALTER COLUMN sync_seq SET DEFAULT nextval('sync_seq');
+ALTER TABLE friendships ALTER COLUMN sync_seq SET DEFAULT nextval('sync_seq');
+ALTER TABLE users ALTER COLUMN sync_seq SET DEFAULT nextval('sync_seq');
+
+DROP FUNCTION IF EXISTS sync_seq_watermark();
+DROP FUNCTION IF EXISTS sync_next_seq();
+
+-- +goose StatementEnd
@@ -0,0 +1,119 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Group seasons belong to the group and outlive the member who created them; personal seasons
+-- are still deleted with their owner (by users_hand_over_groups below).
+ALTER TABLE seasons ALTER COLUMN owner_id DROP NOT NULL;
+ALTER TABLE seasons DROP CONSTRAINT seasons_owner_id_fkey;
+ALTER TABLE seasons
+  ADD CONSTRAINT seasons_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE SET NULL,
+  ADD CONSTRAINT seasons_owner_chk CHECK (owner_id IS NOT NULL OR group_id IS NOT NULL);
+
+-- A deleted owner hands each group to the longest-standing admin, or else the longest-standing
+-- member. Groups left without members are deleted.
+CREATE FUNCTION users_hand_over_groups() RETURNS trigger AS $$
+DECLARE
+  gid UUID;
+BEGIN
+  DELETE FROM seasons WHERE owner_id = OLD.id AND group_id IS NULL;
+
+  FOR gid IN
+    DELETE FROM group_members WHERE user_id = OLD.id AND role = 'owner' RETURNING group_id
+  LOOP
+    UPDATE group_members
+    SET role = 'owner'
+    WHERE (group_id, user_id) = (
+      SELECT gm.group_id, gm.user_id
+      FROM group_members gm
+      WHERE gm.group_id = gid
+      ORDER BY gm.role = 'admin' DESC, gm.joined_at, gm.user_id
+      LIMIT 1
+    );
+    IF NOT FOUND THEN
+      DELETE FROM groups WHERE id = gid;
+    END IF;
+  END LOOP;
+
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_hand_over_groups_trg
+  BEFORE DELETE ON users
+  FOR EACH ROW
+  EXECUTE FUNCTION users_hand_over_groups();
+
+-- Each deleted user gets their own tombstone name, so guest head-to-head (grouped by name) keeps
+-- different deleted players apart.
+CREATE OR REPLACE FUNCTION users_anonymize_matches() RETURNS trigger AS $$
+DECLARE
+  tombstone TEXT := 'Deleted player ' || upper(substr(md5(OLD.id::text), 1, 6));
+BEGIN
+  INSERT INTO match_participants (match_id, seat_index, user_id, place, eliminated_turn_number, confirmation)
+  SELECT mp.match_id,
+         (row_number() OVER (PARTITION BY mp.match_id ORDER BY mp.user_id) - 1)::int,
+         mp.user_id,
+         COALESCE(r.rank, CASE WHEN m.winner_id = mp.user_id THEN 1 ELSE 2 END),
+         r.elimination_turn,
+         'confirmed'
+  FROM match_players mp
+  JOIN matches m ON m.id = mp.match_id
+  LEFT JOIN match_player_results r ON r.match_id = mp.match_id AND r.user_id = mp.user_id
+  WHERE mp.match_id IN (SELECT match_id FROM match_players WHERE user_id = OLD.id)
+    AND NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id);
+
+  UPDATE match_participants
+  SET user_id = NULL,
+      guest_name = tombstone,
+      display_name = tombstone,
+      confirmation = 'confirmed',
+      dispute_reason = NULL
+  WHERE user_id = OLD.id;
+
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+CREATE OR REPLACE FUNCTION users_anonymize_matches() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO match_participants (match_id, seat_index, user_id, place, eliminated_turn_number, confirmation)
+  SELECT mp.match_id,
+         (row_number() OVER (PARTITION BY mp.match_id ORDER BY mp.user_id) - 1)::int,
+         mp.user_id,
+         COALESCE(r.rank, CASE WHEN m.winner_id = mp.user_id THEN 1 ELSE 2 END),
+         r.elimination_turn,
+         'confirmed'
+  FROM match_players mp
+  JOIN matches m ON m.id = mp.match_id
+  LEFT JOIN match_player_results r ON r.match_id = mp.match_id AND r.user_id = mp.user_id
+  WHERE mp.match_id IN (SELECT match_id FROM match_players WHERE user_id = OLD.id)
+    AND NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id);
+
+  UPDATE match_participants
+  SET user_id = NULL,
+      guest_name = 'Deleted player',
+      display_name = 'Deleted player',
+      confirmation = 'confirmed',
+      dispute_reason = NULL
+  WHERE user_id = OLD.id;
+
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS users_hand_over_groups_trg ON users;
+DROP FUNCTION IF EXISTS users_hand_over_groups();
+
+DELETE FROM seasons WHERE owner_id IS NULL;
+ALTER TABLE seasons DROP CONSTRAINT IF EXISTS seasons_owner_chk;
+ALTER TABLE seasons DROP CONSTRAINT seasons_owner_id_fkey;
+ALTER TABLE seasons
+  ADD CONSTRAINT seasons_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE seasons ALTER COLUMN owner_id SET NOT NULL;
+
+-- +goose StatementEnd
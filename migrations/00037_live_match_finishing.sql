@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A live match is 'finishing' while its result is being recorded: updates and abandoning are
+-- refused, so the match is built from a log that can no longer change.
+ALTER TABLE live_matches DROP CONSTRAINT live_matches_status_check;
+ALTER TABLE live_matches
+  ADD CONSTRAINT live_matches_status_check CHECK (status IN ('active', 'finishing', 'finished', 'abandoned'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+UPDATE live_matches SET status = 'active' WHERE status = 'finishing';
+ALTER TABLE live_matches DROP CONSTRAINT live_matches_status_check;
+ALTER TABLE live_matches
+  ADD CONSTRAINT live_matches_status_check CHECK (status IN ('active', 'finished', 'abandoned'));
+
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Archived standings keep the saved guest a row was tallied for, like the live standings do.
+ALTER TABLE season_standings
+  ADD COLUMN guest_id UUID NULL REFERENCES guests(id) ON DELETE SET NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE season_standings DROP COLUMN IF EXISTS guest_id;
+
+-- +goose StatementEnd
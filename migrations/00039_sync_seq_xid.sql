@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Replaces the advisory lock from 00035, which made every sync wait for in-flight writes. A
+-- sync_seq now carries the writing transaction's id in its high bits (the low 24 bits keep seqs
+-- unique within a transaction), so seqs order by transaction. Every transaction older than the
+-- snapshot's xmin has finished, so no seq at or below the watermark can still appear later.
+-- Seqs taken before this migration are all far below the new ones.
+CREATE OR REPLACE FUNCTION sync_next_seq() RETURNS BIGINT AS $$
+BEGIN
+  RETURN (pg_current_xact_id()::text::bigint << 24) | (nextval('sync_seq') & 16777215);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION sync_seq_watermark() RETURNS BIGINT AS $$
+BEGIN
+  RETURN (pg_snapshot_xmin(pg_current_snapshot())::text::bigint << 24) - 1;
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+CREATE OR REPLACE FUNCTION sync_next_seq() RETURNS BIGINT AS $$
+BEGIN
+  PERFORM pg_advisory_xact_lock_shared(hashtext('sync_seq'));
+  RETURN nextval('sync_seq');
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION sync_seq_watermark() RETURNS BIGINT AS $$
+DECLARE
+  seq BIGINT;
+BEGIN
+  PERFORM pg_advisory_xact_lock(hashtext('sync_seq'));
+  SELECT CASE WHEN is_called THEN last_value ELSE 0 END INTO seq FROM sync_seq;
+  RETURN seq;
+END;
+$$ LANGUAGE plpgsql;
+
+-- +goose StatementEnd
@@ -0,0 +1,78 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Archived standings name a deleted player with the same tombstone as their match seats, instead
+-- of being left with neither a user nor a guest name.
+CREATE OR REPLACE FUNCTION users_anonymize_matches() RETURNS trigger AS $$
+DECLARE
+  tombstone TEXT := 'Deleted player ' || upper(substr(md5(OLD.id::text), 1, 6));
+BEGIN
+  INSERT INTO match_participants (match_id, seat_index, user_id, place, eliminated_turn_number, confirmation)
+  SELECT mp.match_id,
+         (row_number() OVER (PARTITION BY mp.match_id ORDER BY mp.user_id) - 1)::int,
+         mp.user_id,
+         COALESCE(r.rank, CASE WHEN m.winner_id = mp.user_id THEN 1 ELSE 2 END),
+         r.elimination_turn,
+         'confirmed'
+  FROM match_players mp
+  JOIN matches m ON m.id = mp.match_id
+  LEFT JOIN match_player_results r ON r.match_id = mp.match_id AND r.user_id = mp.user_id
+  WHERE mp.match_id IN (SELECT match_id FROM match_players WHERE user_id = OLD.id)
+    AND NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id);
+
+  UPDATE match_participants
+  SET user_id = NULL,
+      guest_name = tombstone,
+      display_name = tombstone,
+      confirmation = 'confirmed',
+      dispute_reason = NULL
+  WHERE user_id = OLD.id;
+
+  UPDATE season_standings
+  SET user_id = NULL,
+      guest_name = tombstone
+  WHERE user_id = OLD.id;
+
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE season_standings
+SET guest_name = 'Deleted player'
+WHERE user_id IS NULL AND guest_name IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+CREATE OR REPLACE FUNCTION users_anonymize_matches() RETURNS trigger AS $$
+DECLARE
+  tombstone TEXT := 'Deleted player ' || upper(substr(md5(OLD.id::text), 1, 6));
+BEGIN
+  INSERT INTO match_participants (match_id, seat_index, user_id, place, eliminated_turn_number, confirmation)
+  SELECT mp.match_id,
+         (row_number() OVER (PARTITION BY mp.match_id ORDER BY mp.user_id) - 1)::int,
+         mp.user_id,
+         COALESCE(r.rank, CASE WHEN m.winner_id = mp.user_id THEN 1 ELSE 2 END),
+         r.elimination_turn,
+         'confirmed'
+  FROM match_players mp
+  JOIN matches m ON m.id = mp.match_id
+  LEFT JOIN match_player_results r ON r.match_id = mp.match_id AND r.user_id = mp.user_id
+  WHERE mp.match_id IN (SELECT match_id FROM match_players WHERE user_id = OLD.id)
+    AND NOT EXISTS (SELECT 1 FROM match_participants p WHERE p.match_id = mp.match_id);
+
+  UPDATE match_participants
+  SET user_id = NULL,
+      guest_name = tombstone,
+      display_name = tombstone,
+      confirmation = 'confirmed',
+      dispute_reason = NULL
+  WHERE user_id = OLD.id;
+
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+-- +goose StatementEnd
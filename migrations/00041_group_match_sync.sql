@@ -0,0 +1,77 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Group members can see the group's matches, so delta sync sends them too. A membership's
+-- sync_seq lets a new member receive matches recorded before they joined; existing memberships
+-- get a fresh seq so current members receive the group matches they have not seen yet.
+ALTER TABLE group_members ADD COLUMN sync_seq BIGINT NOT NULL DEFAULT sync_next_seq();
+
+-- Leaving a group, or a match leaving it, hides those matches from the members who have no seat.
+CREATE FUNCTION group_members_sync_removed() RETURNS trigger AS $$
+BEGIN
+  -- Deleted users and groups need no removals (groups_sync_removed covers the members of a group).
+  IF NOT EXISTS (SELECT 1 FROM users WHERE id = OLD.user_id)
+     OR NOT EXISTS (SELECT 1 FROM groups WHERE id = OLD.group_id) THEN
+    RETURN OLD;
+  END IF;
+  INSERT INTO sync_removals (user_id, kind, ref_id)
+  SELECT OLD.user_id, 'match', m.id
+  FROM matches m
+  WHERE m.group_id = OLD.group_id;
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER group_members_sync_removed_trg
+  AFTER DELETE ON group_members
+  FOR EACH ROW
+  EXECUTE FUNCTION group_members_sync_removed();
+
+CREATE FUNCTION matches_group_sync_removed() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO sync_removals (user_id, kind, ref_id)
+  SELECT gm.user_id, 'match', OLD.id
+  FROM group_members gm
+  WHERE gm.group_id = OLD.group_id;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER matches_group_sync_removed_trg
+  AFTER UPDATE OF group_id ON matches
+  FOR EACH ROW
+  WHEN (OLD.group_id IS NOT NULL AND OLD.group_id IS DISTINCT FROM NEW.group_id)
+  EXECUTE FUNCTION matches_group_sync_removed();
+
+-- Deleting a group cascades to its members and clears its matches in no fixed order, so the
+-- removals are written before either happens.
+CREATE FUNCTION groups_sync_removed() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO sync_removals (user_id, kind, ref_id)
+  SELECT gm.user_id, 'match', m.id
+  FROM group_members gm
+  JOIN matches m ON m.group_id = gm.group_id
+  WHERE gm.group_id = OLD.id;
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER groups_sync_removed_trg
+  BEFORE DELETE ON groups
+  FOR EACH ROW
+  EXECUTE FUNCTION groups_sync_removed();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS groups_sync_removed_trg ON groups;
+DROP FUNCTION IF EXISTS groups_sync_removed();
+DROP TRIGGER IF EXISTS matches_group_sync_removed_trg ON matches;
+DROP FUNCTION IF EXISTS matches_group_sync_removed();
+DROP TRIGGER IF EXISTS group_members_sync_removed_trg ON group_members;
+DROP FUNCTION IF EXISTS group_members_sync_removed();
+ALTER TABLE group_members DROP COLUMN IF EXISTS sync_seq;
+
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A claim can name a saved guest, so accepting it converts every seat linked to that guest or
+-- recorded under one of its aliases, not only seats with the claimed name.
+ALTER TABLE guest_claims ADD COLUMN guest_id UUID NULL REFERENCES guests(id) ON DELETE SET NULL;
+
+UPDATE guest_claims c
+SET guest_id = a.guest_id
+FROM guest_aliases a
+WHERE a.owner_id = c.created_by AND a.alias = lower(btrim(c.guest_name));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE guest_claims DROP COLUMN IF EXISTS guest_id;
+
+-- +goose StatementEnd